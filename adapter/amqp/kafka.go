@@ -2,13 +2,14 @@ package amqp
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/IBM/sarama"
 	"go.uber.org/zap"
 
+	"go-hexagonal/adapter/cloudevents"
 	"go-hexagonal/domain/event"
 	"go-hexagonal/util/log"
 )
@@ -17,12 +18,40 @@ import (
 type KafkaConfig struct {
 	Brokers []string
 	Topic   string
+	// GroupID is the consumer group handlers consume in, the bus only produces when it is empty
+	GroupID string
+	// Source is the CloudEvents source attribute of published events, DefaultEventSource when empty
+	Source string
+	// Mode is the CloudEvents mode events are published in, binary by default
+	Mode cloudevents.Mode
+	// RetryPolicy applies to each handler of a consumed event separately, event.DefaultRetryPolicy when zero
+	RetryPolicy event.RetryPolicy
+	// DeadLetterSink receives consumed events a handler still fails on after all retries, they are
+	// dropped when nil
+	DeadLetterSink event.DeadLetterSink
 }
 
+// DefaultEventSource is the CloudEvents source of events published by this service
+const DefaultEventSource = "go-hexagonal"
+
+// newCodec creates the CloudEvents codec for the configured source
+func newCodec(source string) *cloudevents.Codec {
+	if source == "" {
+		source = DefaultEventSource
+	}
+	return cloudevents.NewCodec(source, event.DefaultTypeRegistry)
+}
+
+// Ensure KafkaEventBus implements event.EventBus
+var _ event.EventBus = (*KafkaEventBus)(nil)
+
 // KafkaEventBus implements event.EventBus using Kafka
 type KafkaEventBus struct {
-	producer sarama.SyncProducer
-	topic    string
+	producer   sarama.SyncProducer
+	topic      string
+	codec      *cloudevents.Codec
+	mode       cloudevents.Mode
+	subscriber *KafkaSubscriber
 }
 
 // NewKafkaEventBus creates a new Kafka event bus
@@ -37,33 +66,29 @@ func NewKafkaEventBus(cfg *KafkaConfig) (*KafkaEventBus, error) {
 		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
 	}
 
-	return &KafkaEventBus{
+	bus := &KafkaEventBus{
 		producer: producer,
 		topic:    cfg.Topic,
-	}, nil
+		codec:    newCodec(cfg.Source),
+		mode:     cfg.Mode,
+	}
+
+	if cfg.GroupID != "" {
+		bus.subscriber, err = NewKafkaSubscriber(cfg)
+		if err != nil {
+			_ = producer.Close()
+			return nil, err
+		}
+	}
+
+	return bus, nil
 }
 
-// Publish publishes an event to Kafka
+// Publish publishes an event to Kafka as a CloudEvent
 func (k *KafkaEventBus) Publish(ctx context.Context, event event.Event) error {
-	payload, err := json.Marshal(event)
+	msg, err := k.message(event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %w", err)
-	}
-
-	msg := &sarama.ProducerMessage{
-		Topic:     k.topic,
-		Value:     sarama.StringEncoder(payload),
-		Timestamp: time.Now(),
-		Headers: []sarama.RecordHeader{
-			{
-				Key:   []byte("event_name"),
-				Value: []byte(event.EventName()),
-			},
-			{
-				Key:   []byte("event_id"),
-				Value: []byte(event.EventID()),
-			},
-		},
+		return err
 	}
 
 	partition, offset, err := k.producer.SendMessage(msg)
@@ -81,10 +106,66 @@ func (k *KafkaEventBus) Publish(ctx context.Context, event event.Event) error {
 	return nil
 }
 
-// Close closes the Kafka producer
+// message encodes an event as a producer message in the configured CloudEvents mode
+func (k *KafkaEventBus) message(evt event.Event) (*sarama.ProducerMessage, error) {
+	encoded, err := k.codec.Encode(evt, k.mode, cloudevents.KafkaBinding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	// Sort the headers so the same event always produces the same message
+	names := make([]string, 0, len(encoded.Headers))
+	for name := range encoded.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := make([]sarama.RecordHeader, 0, len(names))
+	for _, name := range names {
+		headers = append(headers, sarama.RecordHeader{
+			Key:   []byte(name),
+			Value: []byte(encoded.Headers[name]),
+		})
+	}
+
+	return &sarama.ProducerMessage{
+		Topic:     k.topic,
+		Value:     sarama.ByteEncoder(encoded.Body),
+		Timestamp: time.Now(),
+		Headers:   headers,
+	}, nil
+}
+
+// Subscribe registers an event handler for events consumed from Kafka, it is ignored when no consumer group is configured
+func (k *KafkaEventBus) Subscribe(handler event.EventHandler) {
+	if k.subscriber != nil {
+		k.subscriber.Subscribe(handler)
+	}
+}
+
+// Unsubscribe removes an event handler
+func (k *KafkaEventBus) Unsubscribe(handler event.EventHandler) {
+	if k.subscriber != nil {
+		k.subscriber.Unsubscribe(handler)
+	}
+}
+
+// Run consumes events for the subscribed handlers until the context is canceled
+func (k *KafkaEventBus) Run(ctx context.Context) error {
+	if k.subscriber == nil {
+		return fmt.Errorf("kafka event bus has no consumer group configured")
+	}
+	return k.subscriber.Run(ctx)
+}
+
+// Close closes the Kafka producer and leaves the consumer group
 func (k *KafkaEventBus) Close() error {
+	var subscriberErr error
+	if k.subscriber != nil {
+		subscriberErr = k.subscriber.Close()
+	}
 	if err := k.producer.Close(); err != nil {
 		return fmt.Errorf("failed to close Kafka producer: %w", err)
 	}
-	return nil
+	return subscriberErr
 }
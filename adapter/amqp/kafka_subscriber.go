@@ -0,0 +1,316 @@
+package amqp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+
+	"go-hexagonal/adapter/cloudevents"
+	"go-hexagonal/domain/event"
+	"go-hexagonal/util/log"
+)
+
+// DefaultConsumerRetryBackoff is the pause before consuming again after a handler failed
+const DefaultConsumerRetryBackoff = time.Second
+
+// Message header keys set by producers predating the CloudEvents envelope
+const (
+	headerEventName = "event_name"
+	headerEventID   = "event_id"
+)
+
+// KafkaSubscriber consumes events from Kafka as part of a consumer group and dispatches them
+// to the subscribed handlers. A failing handler is retried in place, without calling the handlers
+// that succeeded again, and the event is dead-lettered for it once the retries are exhausted.
+// An offset is marked only after every interested handler processed or gave up on the message
+// and is committed in the background; the session only ends, to deliver the message again, when
+// it is revoked while retrying or the dead letter cannot be recorded.
+type KafkaSubscriber struct {
+	group        sarama.ConsumerGroup
+	topics       []string
+	registry     *event.TypeRegistry
+	codec        *cloudevents.Codec
+	retryBackoff time.Duration
+	retry        event.RetryPolicy
+	deadLetter   event.DeadLetterSink
+
+	mu       sync.RWMutex
+	handlers []event.EventHandler
+}
+
+// NewKafkaSubscriber creates a consumer group subscriber for the configured topic
+func NewKafkaSubscriber(cfg *KafkaConfig) (*KafkaSubscriber, error) {
+	return newKafkaSubscriber(cfg, newConsumerConfig())
+}
+
+// newKafkaSubscriber creates a subscriber with the given client configuration
+func newKafkaSubscriber(cfg *KafkaConfig, config *sarama.Config) (*KafkaSubscriber, error) {
+	if cfg.GroupID == "" {
+		return nil, fmt.Errorf("kafka consumer group ID is required")
+	}
+
+	group, err := sarama.NewConsumerGroup(cfg.Brokers, cfg.GroupID, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka consumer group: %w", err)
+	}
+
+	retry := cfg.RetryPolicy
+	if retry == (event.RetryPolicy{}) {
+		retry = event.DefaultRetryPolicy()
+	}
+
+	return &KafkaSubscriber{
+		group:        group,
+		topics:       []string{cfg.Topic},
+		registry:     event.DefaultTypeRegistry,
+		codec:        newCodec(cfg.Source),
+		retryBackoff: DefaultConsumerRetryBackoff,
+		retry:        retry,
+		deadLetter:   cfg.DeadLetterSink,
+		handlers:     make([]event.EventHandler, 0),
+	}, nil
+}
+
+// newConsumerConfig returns the client configuration for consumers. Marked offsets are committed
+// periodically and when a session ends, instead of after every message.
+func newConsumerConfig() *sarama.Config {
+	config := sarama.NewConfig()
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.Offsets.AutoCommit.Enable = true
+	config.Consumer.Return.Errors = true
+	return config
+}
+
+// Subscribe registers an event handler
+func (s *KafkaSubscriber) Subscribe(handler event.EventHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers = append(s.handlers, handler)
+}
+
+// Unsubscribe removes an event handler
+func (s *KafkaSubscriber) Unsubscribe(handler event.EventHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, h := range s.handlers {
+		if h == handler {
+			s.handlers = append(s.handlers[:i], s.handlers[i+1:]...)
+			break
+		}
+	}
+}
+
+// Run consumes events until the context is canceled or the subscriber is closed
+func (s *KafkaSubscriber) Run(ctx context.Context) error {
+	go s.logErrors()
+
+	for {
+		err := s.group.Consume(ctx, s.topics, s)
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to consume from Kafka: %w", err)
+		}
+
+		// The session ended, e.g. on a rebalance or a dead letter that could not be recorded; back off before rejoining
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.retryBackoff):
+		}
+	}
+}
+
+// Close leaves the consumer group
+func (s *KafkaSubscriber) Close() error {
+	if err := s.group.Close(); err != nil {
+		return fmt.Errorf("failed to close Kafka consumer group: %w", err)
+	}
+	return nil
+}
+
+// Setup is run at the beginning of a new session
+func (s *KafkaSubscriber) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// Cleanup is run at the end of a session
+func (s *KafkaSubscriber) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim dispatches the messages of a partition in order
+func (s *KafkaSubscriber) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if err := s.handleMessage(session.Context(), msg); err != nil {
+				// Returning ends the session without marking the message, it is consumed again
+				return err
+			}
+			session.MarkMessage(msg, "")
+
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+// handleMessage decodes a message and passes it to the interested handlers. It fails only when the
+// message must be consumed again.
+func (s *KafkaSubscriber) handleMessage(ctx context.Context, msg *sarama.ConsumerMessage) error {
+	evt, err := s.decode(msg)
+	if err != nil {
+		// A message that cannot be decoded never will be, skip it instead of blocking the partition
+		log.Logger.Error("Dropping undecodable Kafka message",
+			zap.String("topic", msg.Topic),
+			zap.Int32("partition", msg.Partition),
+			zap.Int64("offset", msg.Offset),
+			zap.Error(err))
+		return nil
+	}
+
+	s.mu.RLock()
+	handlers := make([]event.EventHandler, len(s.handlers))
+	copy(handlers, s.handlers)
+	s.mu.RUnlock()
+
+	// Events raised by the handlers are traced back to this one
+	ctx = event.ContextForEvent(ctx, evt)
+
+	for _, handler := range handlers {
+		if !handler.InterestedIn(evt.EventName()) {
+			continue
+		}
+		attempts, err := s.handleWithRetry(ctx, handler, evt)
+		if err == nil {
+			continue
+		}
+
+		log.Logger.Error("Failed to handle Kafka event",
+			zap.String("event_name", evt.EventName()),
+			zap.String("event_id", evt.EventID()),
+			zap.String("handler", event.HandlerName(handler)),
+			zap.Int("attempts", attempts),
+			zap.Int32("partition", msg.Partition),
+			zap.Int64("offset", msg.Offset),
+			zap.Error(err))
+		if ctx.Err() != nil {
+			// The partition was revoked while retrying, its next owner delivers the message again
+			return fmt.Errorf("failed to handle event %s: %w", evt.EventID(), err)
+		}
+		if err := s.sendToDeadLetter(ctx, handler, evt, attempts, err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleWithRetry calls a handler until it succeeds, the retry policy is exhausted or the session
+// ends, returning the number of attempts made and the last error
+func (s *KafkaSubscriber) handleWithRetry(ctx context.Context, handler event.EventHandler, evt event.Event) (int, error) {
+	for attempt := 1; ; attempt++ {
+		err := handler.HandleEvent(ctx, evt)
+		if err == nil || attempt >= s.retry.Attempts() {
+			return attempt, err
+		}
+
+		select {
+		case <-time.After(s.retry.Backoff(attempt)):
+		case <-ctx.Done():
+			return attempt, err
+		}
+	}
+}
+
+// sendToDeadLetter hands an event a handler gave up on to the dead letter sink, without a sink it is dropped
+func (s *KafkaSubscriber) sendToDeadLetter(ctx context.Context, handler event.EventHandler, evt event.Event, attempts int, cause error) error {
+	if s.deadLetter == nil {
+		return nil
+	}
+
+	if err := s.deadLetter.Add(ctx, event.NewDeadLetter(evt, event.HandlerName(handler), attempts, cause)); err != nil {
+		return fmt.Errorf("failed to dead-letter event %s: %w", evt.EventID(), err)
+	}
+	return nil
+}
+
+// decode rebuilds the typed event of a CloudEvents message in either mode
+func (s *KafkaSubscriber) decode(msg *sarama.ConsumerMessage) (event.Event, error) {
+	headers := make(map[string]string, len(msg.Headers))
+	for _, header := range msg.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+
+	evt, err := s.codec.Decode(&cloudevents.Message{Headers: headers, Body: msg.Value}, cloudevents.KafkaBinding)
+	if errors.Is(err, cloudevents.ErrNotCloudEvent) {
+		return s.decodeLegacy(msg)
+	}
+	return evt, err
+}
+
+// decodeLegacy rebuilds the typed event of a message holding the raw JSON of an event,
+// as published before the CloudEvents envelope; the event_name header selects the type
+func (s *KafkaSubscriber) decodeLegacy(msg *sarama.ConsumerMessage) (event.Event, error) {
+	var envelope struct {
+		ID          string          `json:"id"`
+		Name        string          `json:"name"`
+		Aggregate   string          `json:"aggregate"`
+		OccurredOn  time.Time       `json:"occurred_on"`
+		Version     int             `json:"version"`
+		Correlation string          `json:"correlation_id"`
+		Causation   string          `json:"causation_id"`
+		Payload     json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(msg.Value, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	base := event.BaseEvent{
+		ID:          envelope.ID,
+		Name:        envelope.Name,
+		Aggregate:   envelope.Aggregate,
+		OccurredOn:  envelope.OccurredOn,
+		Version:     envelope.Version,
+		Correlation: envelope.Correlation,
+		Causation:   envelope.Causation,
+	}
+	for _, header := range msg.Headers {
+		switch string(header.Key) {
+		case headerEventName:
+			base.Name = string(header.Value)
+		case headerEventID:
+			base.ID = string(header.Value)
+		}
+	}
+	if base.Name == "" {
+		return nil, fmt.Errorf("message has no event name")
+	}
+
+	payload := []byte(envelope.Payload)
+	if len(payload) == 0 {
+		payload = []byte("null")
+	}
+
+	return s.registry.Decode(base, payload)
+}
+
+// logErrors logs the errors reported by the consumer group until it is closed
+func (s *KafkaSubscriber) logErrors() {
+	for err := range s.group.Errors() {
+		log.Logger.Error("Kafka consumer error", zap.Error(err))
+	}
+}
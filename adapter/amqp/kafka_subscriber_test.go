@@ -0,0 +1,306 @@
+package amqp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go-hexagonal/adapter/cloudevents"
+	"go-hexagonal/domain/event"
+	"go-hexagonal/util/log"
+)
+
+const (
+	testTopic = "events"
+	testGroup = "example-consumers"
+)
+
+func TestMain(m *testing.M) {
+	log.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// recordingHandler records the events it handles and fails while fail is set or failures remain
+type recordingHandler struct {
+	mu       sync.Mutex
+	events   []event.Event
+	fail     bool
+	failures int
+	handled  chan event.Event
+}
+
+func newRecordingHandler() *recordingHandler {
+	return &recordingHandler{handled: make(chan event.Event, 10)}
+}
+
+func (h *recordingHandler) HandleEvent(ctx context.Context, evt event.Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, evt)
+	h.handled <- evt
+	if h.failures > 0 {
+		h.failures--
+		return errors.New("handler failed")
+	}
+	if h.fail {
+		return errors.New("handler failed")
+	}
+	return nil
+}
+
+// failingSink fails to record every dead letter
+type failingSink struct{}
+
+func (failingSink) Add(context.Context, *event.DeadLetter) error {
+	return errors.New("sink unavailable")
+}
+
+func (h *recordingHandler) InterestedIn(eventName string) bool {
+	return eventName == event.ExampleCreatedEventName
+}
+
+// encodeEvent encodes an event as a structured CloudEvent, the mock broker serves no headers
+func encodeEvent(t *testing.T, evt event.Event) sarama.Encoder {
+	msg, err := newCodec("").Encode(evt, cloudevents.ModeStructured, cloudevents.KafkaBinding)
+	require.NoError(t, err)
+	return sarama.ByteEncoder(msg.Body)
+}
+
+// newTestSubscriber starts a mock broker serving one partition with the given messages
+func newTestSubscriber(t *testing.T, messages ...sarama.Encoder) (*KafkaSubscriber, *sarama.MockBroker) {
+	broker := sarama.NewMockBroker(t, 0)
+	t.Cleanup(broker.Close)
+
+	fetch := sarama.NewMockFetchResponse(t, 1)
+	for offset, message := range messages {
+		fetch.SetMessage(testTopic, 0, int64(offset), message)
+	}
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(testTopic, 0, broker.BrokerID()),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset(testTopic, 0, sarama.OffsetOldest, 0).
+			SetOffset(testTopic, 0, sarama.OffsetNewest, int64(len(messages))),
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorGroup, testGroup, broker),
+		"HeartbeatRequest": sarama.NewMockHeartbeatResponse(t),
+		"JoinGroupRequest": sarama.NewMockJoinGroupResponse(t).
+			SetGroupProtocol(sarama.RangeBalanceStrategyName),
+		"SyncGroupRequest": sarama.NewMockSyncGroupResponse(t).
+			SetMemberAssignment(&sarama.ConsumerGroupMemberAssignment{
+				Topics: map[string][]int32{testTopic: {0}},
+			}),
+		"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+			SetOffset(testGroup, testTopic, 0, 0, "", sarama.ErrNoError).
+			SetError(sarama.ErrNoError),
+		"OffsetCommitRequest": sarama.NewMockOffsetCommitResponse(t),
+		"LeaveGroupRequest":   sarama.NewMockLeaveGroupResponse(t),
+		"FetchRequest":        fetch,
+	})
+
+	config := newConsumerConfig()
+	config.Version = sarama.V2_0_0_0
+	config.Consumer.Group.Rebalance.Retry.Backoff = 0
+
+	subscriber, err := newKafkaSubscriber(&KafkaConfig{
+		Brokers: []string{broker.Addr()},
+		Topic:   testTopic,
+		GroupID: testGroup,
+	}, config)
+	require.NoError(t, err)
+	subscriber.retryBackoff = 10 * time.Millisecond
+	subscriber.retry = event.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	return subscriber, broker
+}
+
+// runSubscriber consumes in the background until the test ends
+func runSubscriber(t *testing.T, subscriber *KafkaSubscriber) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- subscriber.Run(ctx) }()
+
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+		require.NoError(t, subscriber.Close())
+	})
+}
+
+// committedOffsets returns the offsets committed for the test partition
+func committedOffsets(broker *sarama.MockBroker) []int64 {
+	var offsets []int64
+	for _, exchange := range broker.History() {
+		request, ok := exchange.Request.(*sarama.OffsetCommitRequest)
+		if !ok {
+			continue
+		}
+		if offset, _, err := request.Offset(testTopic, 0); err == nil {
+			offsets = append(offsets, offset)
+		}
+	}
+	return offsets
+}
+
+func receive(t *testing.T, handler *recordingHandler) event.Event {
+	select {
+	case evt := <-handler.handled:
+		return evt
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return nil
+	}
+}
+
+func TestKafkaSubscriber_DispatchesTypedEvents(t *testing.T) {
+	created := event.NewExampleCreatedEvent(1, "name", "alias")
+	deleted := event.NewExampleDeletedEvent(1)
+	subscriber, broker := newTestSubscriber(t, encodeEvent(t, created), encodeEvent(t, deleted))
+
+	handler := newRecordingHandler()
+	subscriber.Subscribe(handler)
+	runSubscriber(t, subscriber)
+
+	received := receive(t, handler)
+	typed, ok := received.(event.ExampleCreatedEvent)
+	require.True(t, ok)
+	assert.Equal(t, created.EventID(), typed.EventID())
+	assert.Equal(t, event.ExampleCreatedPayload{ID: 1, Name: "name", Alias: "alias"}, typed.Payload)
+
+	// Both messages are committed, including the one no handler is interested in
+	assert.Eventually(t, func() bool {
+		offsets := committedOffsets(broker)
+		return len(offsets) > 0 && offsets[len(offsets)-1] == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestKafkaSubscriber_RetriesFailedHandlerOnly(t *testing.T) {
+	created := event.NewExampleCreatedEvent(1, "name", "alias")
+	subscriber, broker := newTestSubscriber(t, encodeEvent(t, created))
+
+	succeeding := newRecordingHandler()
+	flaky := newRecordingHandler()
+	flaky.failures = 2
+	subscriber.Subscribe(succeeding)
+	subscriber.Subscribe(flaky)
+	runSubscriber(t, subscriber)
+
+	// The failing handler is retried in place, the one that succeeded is not called again
+	for range 3 {
+		assert.Equal(t, created.EventID(), receive(t, flaky).EventID())
+	}
+	assert.Eventually(t, func() bool {
+		offsets := committedOffsets(broker)
+		return len(offsets) > 0 && offsets[len(offsets)-1] == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	succeeding.mu.Lock()
+	defer succeeding.mu.Unlock()
+	assert.Len(t, succeeding.events, 1)
+}
+
+func TestKafkaSubscriber_DeadLettersAfterRetries(t *testing.T) {
+	created := event.NewExampleCreatedEvent(1, "name", "alias")
+	subscriber, broker := newTestSubscriber(t, encodeEvent(t, created))
+	deadLetters := event.NewInMemoryDeadLetterStore()
+	subscriber.deadLetter = deadLetters
+
+	handler := newRecordingHandler()
+	handler.fail = true
+	subscriber.Subscribe(handler)
+	runSubscriber(t, subscriber)
+
+	// A permanent failure does not block the partition, the message is dead-lettered and committed
+	assert.Eventually(t, func() bool {
+		offsets := committedOffsets(broker)
+		return len(offsets) > 0 && offsets[len(offsets)-1] == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	letters, err := deadLetters.List(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, created.EventID(), letters[0].Event.EventID())
+	assert.Equal(t, 3, letters[0].Attempts)
+}
+
+func TestKafkaSubscriber_RedeliversWhenDeadLetterFails(t *testing.T) {
+	created := event.NewExampleCreatedEvent(1, "name", "alias")
+	subscriber, broker := newTestSubscriber(t, encodeEvent(t, created))
+	subscriber.retry = event.RetryPolicy{MaxAttempts: 1}
+	subscriber.deadLetter = failingSink{}
+
+	handler := newRecordingHandler()
+	handler.fail = true
+	subscriber.Subscribe(handler)
+	runSubscriber(t, subscriber)
+
+	// Without a recorded dead letter the message is not marked and comes back in the next session
+	first := receive(t, handler)
+	second := receive(t, handler)
+	assert.Equal(t, first.EventID(), second.EventID())
+	for _, offset := range committedOffsets(broker) {
+		assert.NotEqual(t, int64(1), offset)
+	}
+}
+
+func TestKafkaSubscriber_DecodeLegacyByHeader(t *testing.T) {
+	subscriber := &KafkaSubscriber{registry: event.DefaultTypeRegistry, codec: newCodec("")}
+	deleted := event.NewExampleDeletedEvent(4)
+	payload, err := json.Marshal(deleted)
+	require.NoError(t, err)
+
+	decoded, err := subscriber.decode(&sarama.ConsumerMessage{
+		Value: payload,
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte(headerEventName), Value: []byte(event.ExampleDeletedEventName)},
+			{Key: []byte(headerEventID), Value: []byte(deleted.EventID())},
+		},
+	})
+	require.NoError(t, err)
+
+	typed, ok := decoded.(event.ExampleDeletedEvent)
+	require.True(t, ok)
+	assert.Equal(t, deleted.EventID(), typed.EventID())
+	assert.Equal(t, event.ExampleDeletedPayload{ID: 4}, typed.Payload)
+
+	_, err = subscriber.decode(&sarama.ConsumerMessage{Value: []byte("not json")})
+	assert.Error(t, err)
+}
+
+func TestKafkaSubscriber_DecodePublishedMessage(t *testing.T) {
+	subscriber := &KafkaSubscriber{registry: event.DefaultTypeRegistry, codec: newCodec("")}
+	updated := event.NewExampleUpdatedEvent(2, "name", "alias")
+
+	for _, mode := range []cloudevents.Mode{cloudevents.ModeBinary, cloudevents.ModeStructured} {
+		t.Run(mode.String(), func(t *testing.T) {
+			bus := &KafkaEventBus{topic: testTopic, codec: newCodec(""), mode: mode}
+			produced, err := bus.message(updated)
+			require.NoError(t, err)
+
+			value, err := produced.Value.Encode()
+			require.NoError(t, err)
+			consumed := &sarama.ConsumerMessage{Topic: testTopic, Value: value}
+			for i := range produced.Headers {
+				consumed.Headers = append(consumed.Headers, &produced.Headers[i])
+			}
+
+			decoded, err := subscriber.decode(consumed)
+			require.NoError(t, err)
+			typed, ok := decoded.(event.ExampleUpdatedEvent)
+			require.True(t, ok)
+			assert.Equal(t, updated.EventID(), typed.EventID())
+			assert.Equal(t, "2", typed.AggregateID())
+			assert.Equal(t, event.ExampleUpdatedPayload{ID: 2, Name: "name", Alias: "alias"}, typed.Payload)
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+
+	"go-hexagonal/adapter/cloudevents"
+	"go-hexagonal/domain/event"
+	"go-hexagonal/util/log"
+)
+
+// NATS JetStream defaults
+const (
+	// DefaultNATSStream is the JetStream stream events are kept in
+	DefaultNATSStream = "EVENTS"
+	// DefaultNATSSubjectPrefix prefixes the subjects events are published on
+	DefaultNATSSubjectPrefix = "events"
+	// DefaultNATSAckWait is how long a delivered message may stay unacknowledged before it is redelivered
+	DefaultNATSAckWait = 30 * time.Second
+	// DefaultNATSNakDelay is the pause before a message a handler failed on is redelivered
+	DefaultNATSNakDelay = time.Second
+	// embeddedServerStartTimeout bounds the start of the embedded server
+	embeddedServerStartTimeout = 10 * time.Second
+)
+
+// NATSConfig represents NATS JetStream configuration
+type NATSConfig struct {
+	// URL is the server URL, ignored when the server is embedded
+	URL string
+	// Embedded runs an in-process server on a random local port instead of connecting to URL
+	Embedded bool
+	// StoreDir is the JetStream storage directory of the embedded server, a temporary directory when empty
+	StoreDir string
+	// Stream is the JetStream stream events are kept in, DefaultNATSStream when empty
+	Stream string
+	// SubjectPrefix prefixes the subjects events are published on, DefaultNATSSubjectPrefix when empty
+	SubjectPrefix string
+	// MemoryStorage keeps the stream in memory instead of on disk
+	MemoryStorage bool
+	// Durable is the durable consumer handlers consume with, the bus only produces when it is empty.
+	// Processes sharing the name share the consumer, each message is delivered to one of them.
+	Durable string
+	// AckWait is how long a delivered message may stay unacknowledged, DefaultNATSAckWait when zero
+	AckWait time.Duration
+	// NakDelay is the pause before a failed message is redelivered, DefaultNATSNakDelay when zero
+	NakDelay time.Duration
+	// MaxDeliver limits the deliveries of a message, unlimited when zero
+	MaxDeliver int
+	// Source is the CloudEvents source attribute of published events, DefaultEventSource when empty
+	Source string
+	// Mode is the CloudEvents mode events are published in, binary by default
+	Mode cloudevents.Mode
+}
+
+// withDefaults returns a copy of the configuration with the defaults filled in
+func (c NATSConfig) withDefaults() NATSConfig {
+	if c.Stream == "" {
+		c.Stream = DefaultNATSStream
+	}
+	if c.SubjectPrefix == "" {
+		c.SubjectPrefix = DefaultNATSSubjectPrefix
+	}
+	if c.AckWait <= 0 {
+		c.AckWait = DefaultNATSAckWait
+	}
+	if c.NakDelay <= 0 {
+		c.NakDelay = DefaultNATSNakDelay
+	}
+	if c.MaxDeliver <= 0 {
+		c.MaxDeliver = -1
+	}
+	return c
+}
+
+// EventSubject maps an event name to the subject it is published on below the prefix, e.g.
+// example.created to events.example.created. Characters NATS reserves in subjects are replaced.
+func EventSubject(prefix, eventName string) string {
+	subject := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\r', '\n', '*', '>':
+			return '_'
+		}
+		return r
+	}, eventName)
+	return prefix + "." + subject
+}
+
+// Ensure NATSEventBus implements event.EventBus
+var _ event.EventBus = (*NATSEventBus)(nil)
+
+// NATSEventBus implements event.EventBus using NATS JetStream. Events are published with their
+// event ID as message ID, so JetStream drops duplicates within its deduplication window. A message
+// is acknowledged after every interested handler processed it, a failing handler naks it for a
+// delayed redelivery.
+type NATSEventBus struct {
+	server *server.Server
+	conn   *nats.Conn
+	js     jetstream.JetStream
+	stream jetstream.Stream
+	cfg    NATSConfig
+	codec  *cloudevents.Codec
+
+	mu       sync.RWMutex
+	handlers []event.EventHandler
+}
+
+// NewNATSEventBus connects to NATS, or starts the embedded server, and creates the stream
+func NewNATSEventBus(ctx context.Context, cfg *NATSConfig) (*NATSEventBus, error) {
+	bus := &NATSEventBus{
+		cfg:      cfg.withDefaults(),
+		codec:    newCodec(cfg.Source),
+		handlers: make([]event.EventHandler, 0),
+	}
+
+	url := bus.cfg.URL
+	if bus.cfg.Embedded {
+		srv, err := startEmbeddedServer(bus.cfg.StoreDir)
+		if err != nil {
+			return nil, err
+		}
+		bus.server = srv
+		url = srv.ClientURL()
+	}
+
+	conn, err := nats.Connect(url)
+	if err != nil {
+		bus.shutdownServer()
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	bus.conn = conn
+
+	bus.js, err = jetstream.New(conn)
+	if err != nil {
+		_ = bus.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	storage := jetstream.FileStorage
+	if bus.cfg.MemoryStorage {
+		storage = jetstream.MemoryStorage
+	}
+	bus.stream, err = bus.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     bus.cfg.Stream,
+		Subjects: []string{bus.cfg.SubjectPrefix + ".>"},
+		Storage:  storage,
+	})
+	if err != nil {
+		_ = bus.Close()
+		return nil, fmt.Errorf("failed to create JetStream stream %s: %w", bus.cfg.Stream, err)
+	}
+
+	return bus, nil
+}
+
+// startEmbeddedServer starts an in-process server with JetStream enabled on a random local port
+func startEmbeddedServer(storeDir string) (*server.Server, error) {
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  storeDir,
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedded NATS server: %w", err)
+	}
+
+	go srv.Start()
+	if !srv.ReadyForConnections(embeddedServerStartTimeout) {
+		srv.Shutdown()
+		return nil, fmt.Errorf("embedded NATS server not ready after %s", embeddedServerStartTimeout)
+	}
+
+	return srv, nil
+}
+
+// URL returns the URL of the connected server, other processes reach the embedded server under it
+func (b *NATSEventBus) URL() string {
+	return b.conn.ConnectedUrl()
+}
+
+// Publish publishes an event to the subject of its name as a CloudEvent
+func (b *NATSEventBus) Publish(ctx context.Context, evt event.Event) error {
+	encoded, err := b.codec.Encode(evt, b.cfg.Mode, cloudevents.NATSBinding)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	msg := nats.NewMsg(EventSubject(b.cfg.SubjectPrefix, evt.EventName()))
+	for name, value := range encoded.Headers {
+		msg.Header.Set(name, value)
+	}
+	msg.Data = encoded.Body
+
+	ack, err := b.js.PublishMsg(ctx, msg, jetstream.WithMsgID(evt.EventID()))
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	log.Logger.Info("Event published to NATS",
+		zap.String("event_name", evt.EventName()),
+		zap.String("event_id", evt.EventID()),
+		zap.String("subject", msg.Subject),
+		zap.Uint64("sequence", ack.Sequence),
+		zap.Bool("duplicate", ack.Duplicate),
+	)
+
+	return nil
+}
+
+// Subscribe registers an event handler for events consumed from the stream, they are only
+// delivered while Run consumes with a durable consumer
+func (b *NATSEventBus) Subscribe(handler event.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Unsubscribe removes an event handler
+func (b *NATSEventBus) Unsubscribe(handler event.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, h := range b.handlers {
+		if h == handler {
+			b.handlers = append(b.handlers[:i], b.handlers[i+1:]...)
+			break
+		}
+	}
+}
+
+// Run consumes events for the subscribed handlers with the durable consumer until the context is canceled
+func (b *NATSEventBus) Run(ctx context.Context) error {
+	if b.cfg.Durable == "" {
+		return fmt.Errorf("nats event bus has no durable consumer configured")
+	}
+
+	consumer, err := b.stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:       b.cfg.Durable,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       b.cfg.AckWait,
+		MaxDeliver:    b.cfg.MaxDeliver,
+		FilterSubject: b.cfg.SubjectPrefix + ".>",
+		DeliverPolicy: jetstream.DeliverAllPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create durable consumer %s: %w", b.cfg.Durable, err)
+	}
+
+	consuming, err := consumer.Consume(func(msg jetstream.Msg) {
+		b.handleMessage(ctx, msg)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to consume from NATS: %w", err)
+	}
+
+	<-ctx.Done()
+	consuming.Stop()
+	<-consuming.Closed()
+
+	return nil
+}
+
+// handleMessage decodes a message, passes it to the interested handlers and acknowledges it
+func (b *NATSEventBus) handleMessage(ctx context.Context, msg jetstream.Msg) {
+	evt, err := b.decode(msg)
+	if err != nil {
+		// A message that cannot be decoded never will be, terminate it instead of redelivering it
+		log.Logger.Error("Dropping undecodable NATS message",
+			zap.String("subject", msg.Subject()),
+			zap.Error(err))
+		b.respond(msg, msg.Term())
+		return
+	}
+
+	b.mu.RLock()
+	handlers := make([]event.EventHandler, len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.RUnlock()
+
+	// Events raised by the handlers are traced back to this one
+	ctx = event.ContextForEvent(ctx, evt)
+
+	for _, handler := range handlers {
+		if !handler.InterestedIn(evt.EventName()) {
+			continue
+		}
+		if err := handler.HandleEvent(ctx, evt); err != nil {
+			log.Logger.Error("Failed to handle NATS event",
+				zap.String("event_name", evt.EventName()),
+				zap.String("event_id", evt.EventID()),
+				zap.String("subject", msg.Subject()),
+				zap.Error(err))
+			b.respond(msg, msg.NakWithDelay(b.cfg.NakDelay))
+			return
+		}
+	}
+
+	b.respond(msg, msg.Ack())
+}
+
+// respond logs a failed acknowledgment, the message is then redelivered after AckWait
+func (b *NATSEventBus) respond(msg jetstream.Msg, err error) {
+	if err != nil && !errors.Is(err, nats.ErrConnectionClosed) {
+		log.Logger.Error("Failed to acknowledge NATS message",
+			zap.String("subject", msg.Subject()),
+			zap.Error(err))
+	}
+}
+
+// decode rebuilds the typed event of a CloudEvents message in either mode
+func (b *NATSEventBus) decode(msg jetstream.Msg) (event.Event, error) {
+	headers := make(map[string]string, len(msg.Headers()))
+	for name := range msg.Headers() {
+		headers[name] = msg.Headers().Get(name)
+	}
+
+	return b.codec.Decode(&cloudevents.Message{Headers: headers, Body: msg.Data()}, cloudevents.NATSBinding)
+}
+
+// Close closes the connection and shuts the embedded server down
+func (b *NATSEventBus) Close() error {
+	var err error
+	if b.conn != nil {
+		if drainErr := b.conn.Drain(); drainErr != nil && !errors.Is(drainErr, nats.ErrConnectionClosed) {
+			err = fmt.Errorf("failed to drain NATS connection: %w", drainErr)
+		}
+	}
+	b.shutdownServer()
+	return err
+}
+
+// shutdownServer shuts the embedded server down, if any
+func (b *NATSEventBus) shutdownServer() {
+	if b.server != nil {
+		b.server.Shutdown()
+		b.server.WaitForShutdown()
+	}
+}
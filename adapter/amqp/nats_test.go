@@ -0,0 +1,166 @@
+package amqp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/adapter/cloudevents"
+	"go-hexagonal/domain/event"
+)
+
+// newEmbeddedNATSBus creates a bus on an embedded server storing its stream in a temporary directory
+func newEmbeddedNATSBus(t *testing.T, durable string) *NATSEventBus {
+	t.Helper()
+	bus, err := NewNATSEventBus(context.Background(), &NATSConfig{
+		Embedded: true,
+		StoreDir: t.TempDir(),
+		Durable:  durable,
+		AckWait:  time.Second,
+		NakDelay: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, bus.Close()) })
+	return bus
+}
+
+// runNATSBus consumes in the background, the returned function stops consuming
+func runNATSBus(t *testing.T, bus *NATSEventBus) func() {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- bus.Run(ctx) }()
+
+	stopped := false
+	stop := func() {
+		if !stopped {
+			stopped = true
+			cancel()
+			assert.NoError(t, <-done)
+		}
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+func TestEventSubject(t *testing.T) {
+	assert.Equal(t, "events.example.created", EventSubject("events", event.ExampleCreatedEventName))
+	assert.Equal(t, "app.order__.placed_", EventSubject("app", "order *.placed>"))
+}
+
+func TestNATSEventBus_PublishAndConsume(t *testing.T) {
+	for _, mode := range []cloudevents.Mode{cloudevents.ModeBinary, cloudevents.ModeStructured} {
+		t.Run(mode.String(), func(t *testing.T) {
+			bus := newEmbeddedNATSBus(t, "example-consumers")
+			bus.cfg.Mode = mode
+			handler := newRecordingHandler()
+			bus.Subscribe(handler)
+			runNATSBus(t, bus)
+
+			published := event.NewExampleCreatedEvent(1, "name", "alias")
+			require.NoError(t, bus.Publish(context.Background(), published))
+			require.NoError(t, bus.Publish(context.Background(), event.NewExampleDeletedEvent(1)))
+			// Publishing the same event again is dropped as a duplicate
+			require.NoError(t, bus.Publish(context.Background(), published))
+
+			evt := waitForEvent(t, handler)
+			created, ok := evt.(event.ExampleCreatedEvent)
+			require.True(t, ok)
+			assert.Equal(t, published.EventID(), created.EventID())
+			assert.Equal(t, published.Payload, created.Payload)
+
+			info, err := bus.stream.Info(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, uint64(2), info.State.Msgs)
+			assert.Equal(t, uint64(1), subjectCount(t, bus, "events.example.created"))
+
+			select {
+			case evt := <-handler.handled:
+				t.Fatalf("unexpected event %s", evt.EventID())
+			case <-time.After(100 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestNATSEventBus_RedeliversAfterNak(t *testing.T) {
+	bus := newEmbeddedNATSBus(t, "example-consumers")
+	handler := newRecordingHandler()
+	handler.fail = true
+	bus.Subscribe(handler)
+	runNATSBus(t, bus)
+
+	published := event.NewExampleCreatedEvent(1, "name", "alias")
+	require.NoError(t, bus.Publish(context.Background(), published))
+
+	assert.Equal(t, published.EventID(), waitForEvent(t, handler).EventID())
+	handler.mu.Lock()
+	handler.fail = false
+	handler.mu.Unlock()
+	assert.Equal(t, published.EventID(), waitForEvent(t, handler).EventID())
+
+	// Acknowledged once handled, it is not delivered a third time
+	select {
+	case evt := <-handler.handled:
+		t.Fatalf("event %s delivered again after its acknowledgment", evt.EventID())
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestNATSEventBus_DurableConsumerResumes(t *testing.T) {
+	bus := newEmbeddedNATSBus(t, "example-consumers")
+	handler := newRecordingHandler()
+	bus.Subscribe(handler)
+	stop := runNATSBus(t, bus)
+
+	first := event.NewExampleCreatedEvent(1, "first", "alias")
+	require.NoError(t, bus.Publish(context.Background(), first))
+	assert.Equal(t, first.EventID(), waitForEvent(t, handler).EventID())
+	stop()
+
+	// Published while no consumer runs, delivered once the durable consumer is back
+	second := event.NewExampleCreatedEvent(2, "second", "alias")
+	require.NoError(t, bus.Publish(context.Background(), second))
+
+	// Another process reaches the embedded server under its URL
+	other, err := NewNATSEventBus(context.Background(), &NATSConfig{URL: bus.URL(), Durable: "example-consumers"})
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, other.Close()) })
+	other.Subscribe(handler)
+	runNATSBus(t, other)
+
+	assert.Equal(t, second.EventID(), waitForEvent(t, handler).EventID())
+}
+
+func TestNATSEventBus_TerminatesUndecodableMessages(t *testing.T) {
+	bus := newEmbeddedNATSBus(t, "example-consumers")
+	handler := newRecordingHandler()
+	bus.Subscribe(handler)
+	runNATSBus(t, bus)
+
+	_, err := bus.js.PublishMsg(context.Background(), &nats.Msg{Subject: "events.example.created", Data: []byte("not an event")})
+	require.NoError(t, err)
+	published := event.NewExampleCreatedEvent(1, "name", "alias")
+	require.NoError(t, bus.Publish(context.Background(), published))
+
+	// The undecodable message does not block the one after it
+	assert.Equal(t, published.EventID(), waitForEvent(t, handler).EventID())
+}
+
+func TestNATSEventBus_ProducerOnly(t *testing.T) {
+	bus := newEmbeddedNATSBus(t, "")
+	assert.EqualError(t, bus.Run(context.Background()), "nats event bus has no durable consumer configured")
+}
+
+// subjectCount returns the number of messages kept for a subject
+func subjectCount(t *testing.T, bus *NATSEventBus, subject string) uint64 {
+	t.Helper()
+	info, err := bus.stream.Info(context.Background(), jetstream.WithSubjectFilter(subject))
+	require.NoError(t, err)
+	return info.State.Subjects[subject]
+}
@@ -0,0 +1,457 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+
+	"go-hexagonal/adapter/cloudevents"
+	redisRepo "go-hexagonal/adapter/repository/redis"
+	"go-hexagonal/domain/event"
+	"go-hexagonal/util/log"
+)
+
+// Redis Streams consumer defaults
+const (
+	// DefaultStreamMaxLen is the approximate number of entries a stream is trimmed to
+	DefaultStreamMaxLen = 100000
+	// DefaultStreamBatchSize is the number of entries read or reclaimed per call
+	DefaultStreamBatchSize = 10
+	// DefaultStreamBlock is how long a read waits for new entries
+	DefaultStreamBlock = 2 * time.Second
+	// DefaultStreamClaimMinIdle is how long an entry stays unacknowledged before another consumer reclaims it
+	DefaultStreamClaimMinIdle = time.Minute
+	// DefaultStreamMaxDeliveries is how often an entry is delivered before failing handlers give up on it
+	DefaultStreamMaxDeliveries = 5
+)
+
+// streamDataField is the stream entry field holding the message body, the other fields hold its headers
+const streamDataField = "data"
+
+// RedisStreamConfig represents Redis Streams configuration
+type RedisStreamConfig struct {
+	Stream string
+	// Group is the consumer group handlers consume in, the bus only produces when it is empty
+	Group string
+	// Consumer names this process within the group, the host name and process ID when empty.
+	// A restarted consumer keeping its name first handles the entries it left unacknowledged.
+	Consumer string
+	// MaxLen caps the stream length with approximate trimming, DefaultStreamMaxLen when zero
+	// and unbounded when negative
+	MaxLen int64
+	// BatchSize is the number of entries read or reclaimed per call, DefaultStreamBatchSize when zero
+	BatchSize int64
+	// Block is how long a read waits for new entries, DefaultStreamBlock when zero
+	Block time.Duration
+	// ClaimMinIdle is how long an entry stays unacknowledged before it is reclaimed from its
+	// consumer, e.g. one that crashed; DefaultStreamClaimMinIdle when zero
+	ClaimMinIdle time.Duration
+	// MaxDeliveries is how often an entry is delivered, as counted by XPENDING, before the handlers
+	// still failing on it give up and it is acknowledged; DefaultStreamMaxDeliveries when zero
+	MaxDeliveries int64
+	// DeadLetterSink receives the events handlers gave up on, they are dropped when nil
+	DeadLetterSink event.DeadLetterSink
+	// Source is the CloudEvents source attribute of published events, DefaultEventSource when empty
+	Source string
+	// Mode is the CloudEvents mode events are published in, binary by default
+	Mode cloudevents.Mode
+}
+
+// withDefaults returns a copy of the configuration with the defaults filled in
+func (c RedisStreamConfig) withDefaults() RedisStreamConfig {
+	if c.Consumer == "" {
+		host, _ := os.Hostname()
+		c.Consumer = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if c.MaxLen == 0 {
+		c.MaxLen = DefaultStreamMaxLen
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultStreamBatchSize
+	}
+	if c.Block <= 0 {
+		c.Block = DefaultStreamBlock
+	}
+	if c.ClaimMinIdle <= 0 {
+		c.ClaimMinIdle = DefaultStreamClaimMinIdle
+	}
+	if c.MaxDeliveries <= 0 {
+		c.MaxDeliveries = DefaultStreamMaxDeliveries
+	}
+	return c
+}
+
+// Ensure RedisStreamEventBus implements event.EventBus
+var _ event.EventBus = (*RedisStreamEventBus)(nil)
+
+// RedisStreamEventBus implements event.EventBus using Redis Streams. Events are consumed in a
+// consumer group, an entry is acknowledged only after every interested handler processed it.
+// Entries left unacknowledged, by a failed handler or a crashed consumer, are reclaimed and
+// delivered again once idle for ClaimMinIdle, so delivery is at least once. After MaxDeliveries
+// the event is dead-lettered for the handlers still failing and the entry acknowledged.
+type RedisStreamEventBus struct {
+	client *redis.Client
+	cfg    RedisStreamConfig
+	codec  *cloudevents.Codec
+
+	mu       sync.RWMutex
+	handlers []event.EventHandler
+}
+
+// NewRedisStreamEventBus creates a Redis Streams event bus on the shared Redis client
+func NewRedisStreamEventBus(client *redisRepo.RedisClient, cfg *RedisStreamConfig) (*RedisStreamEventBus, error) {
+	if client == nil || client.Client == nil {
+		return nil, fmt.Errorf("redis client is required")
+	}
+	if cfg.Stream == "" {
+		return nil, fmt.Errorf("redis stream name is required")
+	}
+
+	return &RedisStreamEventBus{
+		client:   client.Client,
+		cfg:      cfg.withDefaults(),
+		codec:    newCodec(cfg.Source),
+		handlers: make([]event.EventHandler, 0),
+	}, nil
+}
+
+// Publish appends an event to the stream as a CloudEvent, trimming the stream to MaxLen
+func (b *RedisStreamEventBus) Publish(ctx context.Context, evt event.Event) error {
+	encoded, err := b.codec.Encode(evt, b.cfg.Mode, cloudevents.KafkaBinding)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	values := make(map[string]interface{}, len(encoded.Headers)+1)
+	for name, value := range encoded.Headers {
+		values[name] = value
+	}
+	values[streamDataField] = encoded.Body
+
+	args := &redis.XAddArgs{
+		Stream: b.cfg.Stream,
+		Values: values,
+	}
+	if b.cfg.MaxLen > 0 {
+		args.MaxLen = b.cfg.MaxLen
+		args.Approx = true
+	}
+
+	id, err := b.client.XAdd(ctx, args).Result()
+	if err != nil {
+		return fmt.Errorf("failed to add event to stream: %w", err)
+	}
+
+	log.Logger.Info("Event published to Redis stream",
+		zap.String("event_name", evt.EventName()),
+		zap.String("event_id", evt.EventID()),
+		zap.String("stream", b.cfg.Stream),
+		zap.String("entry_id", id),
+	)
+
+	return nil
+}
+
+// Subscribe registers an event handler for events consumed from the stream, they are only
+// delivered while Run consumes in a consumer group
+func (b *RedisStreamEventBus) Subscribe(handler event.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Unsubscribe removes an event handler
+func (b *RedisStreamEventBus) Unsubscribe(handler event.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, h := range b.handlers {
+		if h == handler {
+			b.handlers = append(b.handlers[:i], b.handlers[i+1:]...)
+			break
+		}
+	}
+}
+
+// Run consumes events for the subscribed handlers until the context is canceled. It first
+// handles the entries this consumer left unacknowledged, then alternates between reclaiming
+// idle entries of other consumers and reading new ones.
+func (b *RedisStreamEventBus) Run(ctx context.Context) error {
+	if b.cfg.Group == "" {
+		return fmt.Errorf("redis stream event bus has no consumer group configured")
+	}
+	if err := b.ensureGroup(ctx); err != nil {
+		return err
+	}
+
+	// An ID reads this consumer's pending entries after it, ">" the entries never delivered to the group
+	readFrom := "0"
+	claimFrom := "0-0"
+	for ctx.Err() == nil {
+		next, err := b.reclaim(ctx, claimFrom)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		claimFrom = next
+
+		last, err := b.read(ctx, readFrom)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if readFrom != ">" {
+			readFrom = last
+			if last == "" {
+				readFrom = ">"
+			}
+		}
+	}
+
+	return nil
+}
+
+// ensureGroup creates the consumer group and the stream, starting at the oldest entry
+func (b *RedisStreamEventBus) ensureGroup(ctx context.Context) error {
+	err := b.client.XGroupCreateMkStream(ctx, b.cfg.Stream, b.cfg.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group %s: %w", b.cfg.Group, err)
+	}
+	return nil
+}
+
+// read handles a batch of entries read from the group after the given ID and returns the ID of
+// the last one, empty when none was read
+func (b *RedisStreamEventBus) read(ctx context.Context, from string) (string, error) {
+	// Pending entries are returned at once, only new entries are waited for
+	block := b.cfg.Block
+	if from != ">" {
+		block = -1
+	}
+
+	streams, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    b.cfg.Group,
+		Consumer: b.cfg.Consumer,
+		Streams:  []string{b.cfg.Stream, from},
+		Count:    b.cfg.BatchSize,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read from stream %s: %w", b.cfg.Stream, err)
+	}
+
+	last := ""
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			last = msg.ID
+
+			// New entries are delivered for the first time, pending ones may have been before
+			deliveries := int64(1)
+			if from != ">" {
+				if deliveries, err = b.deliveryCount(ctx, msg.ID); err != nil {
+					return last, err
+				}
+			}
+			b.handleEntry(ctx, msg.ID, msg.Values, deliveries)
+		}
+	}
+	return last, nil
+}
+
+// reclaim claims a batch of entries idle for ClaimMinIdle starting at the given ID, handles them
+// and returns the ID the next call starts at. XAUTOCLAIM is sent as a raw command, the client
+// does not parse the reply Redis 7 sends.
+func (b *RedisStreamEventBus) reclaim(ctx context.Context, from string) (string, error) {
+	reply, err := b.client.Do(ctx, "XAUTOCLAIM", b.cfg.Stream, b.cfg.Group, b.cfg.Consumer,
+		b.cfg.ClaimMinIdle.Milliseconds(), from, "COUNT", b.cfg.BatchSize).Slice()
+	if err != nil {
+		return from, fmt.Errorf("failed to reclaim entries of stream %s: %w", b.cfg.Stream, err)
+	}
+	if len(reply) < 2 {
+		return from, fmt.Errorf("unexpected XAUTOCLAIM reply of %d elements", len(reply))
+	}
+
+	next, _ := reply[0].(string)
+	entries, _ := reply[1].([]interface{})
+	for _, entry := range entries {
+		id, values, ok := parseStreamEntry(entry)
+		if !ok {
+			continue
+		}
+		if values == nil {
+			// Trimmed from the stream while pending, there is nothing left to deliver
+			b.ack(ctx, id)
+			continue
+		}
+
+		deliveries, err := b.deliveryCount(ctx, id)
+		if err != nil {
+			return from, err
+		}
+
+		log.Logger.Warn("Reclaimed idle Redis stream entry",
+			zap.String("stream", b.cfg.Stream),
+			zap.String("entry_id", id),
+			zap.Int64("deliveries", deliveries),
+		)
+		b.handleEntry(ctx, id, values, deliveries)
+	}
+
+	if next == "" {
+		next = "0-0"
+	}
+	return next, nil
+}
+
+// deliveryCount returns how often a pending entry of this consumer was delivered, as XPENDING counts
+// it. An entry no longer pending, e.g. acknowledged meanwhile, counts as delivered once.
+func (b *RedisStreamEventBus) deliveryCount(ctx context.Context, id string) (int64, error) {
+	pending, err := b.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream:   b.cfg.Stream,
+		Group:    b.cfg.Group,
+		Start:    id,
+		End:      id,
+		Count:    1,
+		Consumer: b.cfg.Consumer,
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read delivery count of stream entry %s: %w", id, err)
+	}
+	if len(pending) == 0 {
+		return 1, nil
+	}
+	return pending[0].RetryCount, nil
+}
+
+// handleEntry decodes an entry, passes it to the interested handlers and acknowledges it when
+// all of them succeeded. A failed handler leaves the entry pending until it is reclaimed, unless it
+// was delivered MaxDeliveries times; then the event is dead-lettered for the handler instead.
+func (b *RedisStreamEventBus) handleEntry(ctx context.Context, id string, values map[string]interface{}, deliveries int64) {
+	evt, err := b.decode(values)
+	if err != nil {
+		// An entry that cannot be decoded never will be, acknowledge it instead of reclaiming it forever
+		log.Logger.Error("Dropping undecodable Redis stream entry",
+			zap.String("stream", b.cfg.Stream),
+			zap.String("entry_id", id),
+			zap.Error(err))
+		b.ack(ctx, id)
+		return
+	}
+
+	b.mu.RLock()
+	handlers := make([]event.EventHandler, len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.RUnlock()
+
+	// Events raised by the handlers are traced back to this one
+	handlerCtx := event.ContextForEvent(ctx, evt)
+
+	for _, handler := range handlers {
+		if !handler.InterestedIn(evt.EventName()) {
+			continue
+		}
+		err := handler.HandleEvent(handlerCtx, evt)
+		if err == nil {
+			continue
+		}
+
+		log.Logger.Error("Failed to handle Redis stream event",
+			zap.String("event_name", evt.EventName()),
+			zap.String("event_id", evt.EventID()),
+			zap.String("stream", b.cfg.Stream),
+			zap.String("entry_id", id),
+			zap.Int64("deliveries", deliveries),
+			zap.Error(err))
+		if deliveries < b.cfg.MaxDeliveries {
+			return
+		}
+		if err := b.sendToDeadLetter(ctx, handler, evt, deliveries, err); err != nil {
+			// Keep the entry pending, the dead letter is recorded on a later delivery
+			log.Logger.Error("Failed to dead-letter Redis stream event",
+				zap.String("event_id", evt.EventID()),
+				zap.String("entry_id", id),
+				zap.Error(err))
+			return
+		}
+	}
+
+	b.ack(ctx, id)
+}
+
+// sendToDeadLetter hands an event a handler gave up on to the dead letter sink, without a sink it is dropped
+func (b *RedisStreamEventBus) sendToDeadLetter(ctx context.Context, handler event.EventHandler, evt event.Event, deliveries int64, cause error) error {
+	if b.cfg.DeadLetterSink == nil {
+		return nil
+	}
+	return b.cfg.DeadLetterSink.Add(ctx, event.NewDeadLetter(evt, event.HandlerName(handler), int(deliveries), cause))
+}
+
+// ack acknowledges an entry, a failed acknowledgment only causes a redelivery
+func (b *RedisStreamEventBus) ack(ctx context.Context, id string) {
+	if err := b.client.XAck(ctx, b.cfg.Stream, b.cfg.Group, id).Err(); err != nil {
+		log.Logger.Error("Failed to acknowledge Redis stream entry",
+			zap.String("stream", b.cfg.Stream),
+			zap.String("entry_id", id),
+			zap.Error(err))
+	}
+}
+
+// decode rebuilds the typed event of a stream entry in either CloudEvents mode
+func (b *RedisStreamEventBus) decode(values map[string]interface{}) (event.Event, error) {
+	msg := &cloudevents.Message{Headers: make(map[string]string, len(values))}
+	for name, value := range values {
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("field %s is no string", name)
+		}
+		if name == streamDataField {
+			msg.Body = []byte(text)
+			continue
+		}
+		msg.Headers[name] = text
+	}
+
+	return b.codec.Decode(msg, cloudevents.KafkaBinding)
+}
+
+// parseStreamEntry parses an entry of a raw stream reply, its values are nil for entries
+// deleted from the stream
+func parseStreamEntry(entry interface{}) (string, map[string]interface{}, bool) {
+	fields, ok := entry.([]interface{})
+	if !ok || len(fields) != 2 {
+		return "", nil, false
+	}
+	id, ok := fields[0].(string)
+	if !ok {
+		return "", nil, false
+	}
+
+	pairs, ok := fields[1].([]interface{})
+	if !ok {
+		return id, nil, true
+	}
+	values := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		name, ok := pairs[i].(string)
+		if !ok {
+			continue
+		}
+		values[name] = pairs[i+1]
+	}
+	return id, values, true
+}
@@ -0,0 +1,255 @@
+package amqp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/adapter/cloudevents"
+	redisRepo "go-hexagonal/adapter/repository/redis"
+	"go-hexagonal/domain/event"
+)
+
+const testStream = "events"
+
+// newTestStreamBus creates a bus consuming from the stream as the given consumer
+func newTestStreamBus(t *testing.T, client *redisRepo.RedisClient, consumer string) *RedisStreamEventBus {
+	t.Helper()
+	bus, err := NewRedisStreamEventBus(client, &RedisStreamConfig{
+		Stream:       testStream,
+		Group:        testGroup,
+		Consumer:     consumer,
+		Block:        20 * time.Millisecond,
+		ClaimMinIdle: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	return bus
+}
+
+// runBus consumes in the background until the test ends
+func runBus(t *testing.T, bus *RedisStreamEventBus) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- bus.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+}
+
+// waitForEvent returns the next event handled by the handler
+func waitForEvent(t *testing.T, handler *recordingHandler) event.Event {
+	t.Helper()
+	select {
+	case evt := <-handler.handled:
+		return evt
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event handled")
+		return nil
+	}
+}
+
+// readWithoutAck reads the new entries as the consumer without handling them, as a consumer crashing
+// before it acknowledged them
+func readWithoutAck(t *testing.T, client *redisRepo.RedisClient, consumer string) {
+	t.Helper()
+	require.NoError(t, client.Client.XReadGroup(context.Background(), &redis.XReadGroupArgs{
+		Group:    testGroup,
+		Consumer: consumer,
+		Streams:  []string{testStream, ">"},
+		Block:    -1,
+	}).Err())
+}
+
+// pendingCount returns the number of entries delivered to the group but not acknowledged
+func pendingCount(t *testing.T, client *redisRepo.RedisClient) int64 {
+	t.Helper()
+	pending, err := client.Client.XPending(context.Background(), testStream, testGroup).Result()
+	require.NoError(t, err)
+	return pending.Count
+}
+
+func TestRedisStreamEventBus_PublishAndConsume(t *testing.T) {
+	client := redisRepo.GetRedisClient(t, redisRepo.SetupRedisContainer(t))
+
+	for _, mode := range []cloudevents.Mode{cloudevents.ModeBinary, cloudevents.ModeStructured} {
+		t.Run(mode.String(), func(t *testing.T) {
+			require.NoError(t, client.Client.Del(context.Background(), testStream).Err())
+
+			bus := newTestStreamBus(t, client, "consumer-1")
+			bus.cfg.Mode = mode
+			handler := newRecordingHandler()
+			bus.Subscribe(handler)
+			runBus(t, bus)
+
+			published := event.NewExampleCreatedEvent(1, "name", "alias")
+			require.NoError(t, bus.Publish(context.Background(), published))
+			// Events of no interest are acknowledged without a handler
+			require.NoError(t, bus.Publish(context.Background(), event.NewExampleDeletedEvent(1)))
+
+			evt := waitForEvent(t, handler)
+			created, ok := evt.(event.ExampleCreatedEvent)
+			require.True(t, ok)
+			assert.Equal(t, published.EventID(), created.EventID())
+			assert.Equal(t, published.Payload, created.Payload)
+
+			assert.Eventually(t, func() bool { return pendingCount(t, client) == 0 }, time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestRedisStreamEventBus_ReclaimsFailedEntries(t *testing.T) {
+	client := redisRepo.GetRedisClient(t, redisRepo.SetupRedisContainer(t))
+
+	bus := newTestStreamBus(t, client, "consumer-1")
+	handler := newRecordingHandler()
+	handler.fail = true
+	bus.Subscribe(handler)
+	runBus(t, bus)
+
+	published := event.NewExampleCreatedEvent(1, "name", "alias")
+	require.NoError(t, bus.Publish(context.Background(), published))
+
+	// The failed entry stays pending and is delivered again once idle
+	assert.Equal(t, published.EventID(), waitForEvent(t, handler).EventID())
+	assert.Equal(t, int64(1), pendingCount(t, client))
+
+	handler.mu.Lock()
+	handler.fail = false
+	handler.mu.Unlock()
+	assert.Equal(t, published.EventID(), waitForEvent(t, handler).EventID())
+	assert.Eventually(t, func() bool { return pendingCount(t, client) == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestRedisStreamEventBus_DeadLettersAfterMaxDeliveries(t *testing.T) {
+	client := redisRepo.GetRedisClient(t, redisRepo.SetupRedisContainer(t))
+
+	bus := newTestStreamBus(t, client, "consumer-1")
+	bus.cfg.MaxDeliveries = 3
+	deadLetters := event.NewInMemoryDeadLetterStore()
+	bus.cfg.DeadLetterSink = deadLetters
+	handler := newRecordingHandler()
+	handler.fail = true
+	bus.Subscribe(handler)
+	runBus(t, bus)
+
+	published := event.NewExampleCreatedEvent(1, "name", "alias")
+	require.NoError(t, bus.Publish(context.Background(), published))
+
+	// The entry is reclaimed until its third delivery, then dead-lettered and acknowledged
+	for range 3 {
+		assert.Equal(t, published.EventID(), waitForEvent(t, handler).EventID())
+	}
+	assert.Eventually(t, func() bool { return pendingCount(t, client) == 0 }, time.Second, 10*time.Millisecond)
+
+	letters, err := deadLetters.List(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, published.EventID(), letters[0].Event.EventID())
+	assert.Equal(t, 3, letters[0].Attempts)
+	select {
+	case <-handler.handled:
+		t.Fatal("acknowledged entry was delivered again")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestRedisStreamEventBus_ReclaimsFromCrashedConsumer(t *testing.T) {
+	client := redisRepo.GetRedisClient(t, redisRepo.SetupRedisContainer(t))
+	ctx := context.Background()
+
+	producer := newTestStreamBus(t, client, "crashed")
+	require.NoError(t, producer.ensureGroup(ctx))
+	published := event.NewExampleCreatedEvent(1, "name", "alias")
+	require.NoError(t, producer.Publish(ctx, published))
+
+	readWithoutAck(t, client, "crashed")
+	assert.Equal(t, int64(1), pendingCount(t, client))
+
+	survivor := newTestStreamBus(t, client, "survivor")
+	handler := newRecordingHandler()
+	survivor.Subscribe(handler)
+	runBus(t, survivor)
+
+	assert.Equal(t, published.EventID(), waitForEvent(t, handler).EventID())
+	assert.Eventually(t, func() bool { return pendingCount(t, client) == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestRedisStreamEventBus_RestartHandlesOwnPendingEntries(t *testing.T) {
+	client := redisRepo.GetRedisClient(t, redisRepo.SetupRedisContainer(t))
+	ctx := context.Background()
+
+	bus := newTestStreamBus(t, client, "consumer-1")
+	// Never reclaim, the restarted consumer reads its pending entries on its own
+	bus.cfg.ClaimMinIdle = time.Hour
+	require.NoError(t, bus.ensureGroup(ctx))
+	published := event.NewExampleCreatedEvent(1, "name", "alias")
+	require.NoError(t, bus.Publish(ctx, published))
+	readWithoutAck(t, client, "consumer-1")
+
+	handler := newRecordingHandler()
+	bus.Subscribe(handler)
+	runBus(t, bus)
+
+	assert.Equal(t, published.EventID(), waitForEvent(t, handler).EventID())
+	assert.Eventually(t, func() bool { return pendingCount(t, client) == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestRedisStreamEventBus_DropsUndecodableEntries(t *testing.T) {
+	client := redisRepo.GetRedisClient(t, redisRepo.SetupRedisContainer(t))
+	ctx := context.Background()
+
+	bus := newTestStreamBus(t, client, "consumer-1")
+	handler := newRecordingHandler()
+	bus.Subscribe(handler)
+	require.NoError(t, bus.ensureGroup(ctx))
+
+	require.NoError(t, client.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: testStream,
+		Values: map[string]interface{}{streamDataField: "not an event"},
+	}).Err())
+	_, err := bus.read(ctx, ">")
+	require.NoError(t, err)
+
+	assert.Empty(t, handler.events)
+	assert.Equal(t, int64(0), pendingCount(t, client))
+}
+
+func TestRedisStreamEventBus_TrimsStream(t *testing.T) {
+	client := redisRepo.GetRedisClient(t, redisRepo.SetupRedisContainer(t))
+	ctx := context.Background()
+
+	bus, err := NewRedisStreamEventBus(client, &RedisStreamConfig{Stream: testStream, MaxLen: 5})
+	require.NoError(t, err)
+	for i := 1; i <= 20; i++ {
+		require.NoError(t, bus.Publish(ctx, event.NewExampleCreatedEvent(i, "name", "alias")))
+	}
+
+	// Redis trims approximately to whole macro nodes, miniredis trims exactly
+	length, err := client.Client.XLen(ctx, testStream).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), length)
+
+	assert.EqualError(t, bus.Run(ctx), "redis stream event bus has no consumer group configured")
+}
+
+func TestNewRedisStreamEventBus_Validation(t *testing.T) {
+	_, err := NewRedisStreamEventBus(nil, &RedisStreamConfig{Stream: testStream})
+	assert.Error(t, err)
+
+	client := redisRepo.GetRedisClient(t, redisRepo.SetupRedisContainer(t))
+	_, err = NewRedisStreamEventBus(client, &RedisStreamConfig{})
+	assert.Error(t, err)
+
+	bus, err := NewRedisStreamEventBus(client, &RedisStreamConfig{Stream: testStream})
+	require.NoError(t, err)
+	assert.NotEmpty(t, bus.cfg.Consumer)
+	assert.Equal(t, int64(DefaultStreamMaxLen), bus.cfg.MaxLen)
+	assert.Equal(t, DefaultStreamClaimMinIdle, bus.cfg.ClaimMinIdle)
+	assert.Equal(t, int64(DefaultStreamMaxDeliveries), bus.cfg.MaxDeliveries)
+}
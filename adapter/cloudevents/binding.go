@@ -0,0 +1,148 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go-hexagonal/domain/event"
+)
+
+// Binding describes how a protocol carries events in its messages
+type Binding struct {
+	// HeaderPrefix prefixes the attribute names in binary mode headers
+	HeaderPrefix string
+	// ContentTypeHeader is the header holding the content type of the message body
+	ContentTypeHeader string
+	// escape percent-encodes header values, as HTTP headers only carry printable ASCII
+	escape bool
+}
+
+// Protocol bindings of the CloudEvents spec
+var (
+	// KafkaBinding is the Kafka protocol binding
+	KafkaBinding = Binding{HeaderPrefix: "ce_", ContentTypeHeader: "content-type"}
+	// HTTPBinding is the HTTP protocol binding
+	HTTPBinding = Binding{HeaderPrefix: "ce-", ContentTypeHeader: "Content-Type", escape: true}
+	// NATSBinding is the NATS protocol binding
+	NATSBinding = Binding{HeaderPrefix: "ce-", ContentTypeHeader: "content-type"}
+)
+
+// Message is an encoded event as a protocol sees it
+type Message struct {
+	Headers map[string]string
+	Body    []byte
+}
+
+// Encode lays an event out in a message of the binding in the given mode
+func (c *Codec) Encode(evt event.Event, mode Mode, binding Binding) (*Message, error) {
+	ce, err := c.ToCloudEvent(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mode {
+	case ModeStructured:
+		body, err := json.Marshal(ce)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal CloudEvent %s: %w", ce.ID, err)
+		}
+		return &Message{
+			Headers: map[string]string{binding.ContentTypeHeader: ContentTypeStructured},
+			Body:    body,
+		}, nil
+
+	case ModeBinary:
+		headers := map[string]string{binding.ContentTypeHeader: ce.DataContentType}
+		for name, value := range ce.attributes() {
+			if binding.escape {
+				value = escapeHeader(value)
+			}
+			headers[binding.HeaderPrefix+name] = value
+		}
+		return &Message{
+			Headers: headers,
+			Body:    ce.Data,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown CloudEvents mode %s", mode)
+	}
+}
+
+// Decode rebuilds the event carried by a message of the binding, the mode is detected from the
+// message. Messages that are no CloudEvent return ErrNotCloudEvent.
+func (c *Codec) Decode(msg *Message, binding Binding) (event.Event, error) {
+	ce, err := c.read(msg, binding)
+	if err != nil {
+		return nil, err
+	}
+	return c.FromCloudEvent(ce)
+}
+
+// read extracts the CloudEvent of a message
+func (c *Codec) read(msg *Message, binding Binding) (*CloudEvent, error) {
+	// Header names are case insensitive in HTTP, compare them in lower case for every binding
+	prefix := strings.ToLower(binding.HeaderPrefix)
+	headers := make(map[string]string, len(msg.Headers))
+	for name, value := range msg.Headers {
+		headers[strings.ToLower(name)] = value
+	}
+	contentType := headers[strings.ToLower(binding.ContentTypeHeader)]
+
+	if strings.HasPrefix(contentType, ContentTypeStructured) || (contentType == "" && isStructured(msg.Body)) {
+		var ce CloudEvent
+		if err := json.Unmarshal(msg.Body, &ce); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal CloudEvent: %w", err)
+		}
+		return &ce, nil
+	}
+
+	if _, ok := headers[prefix+"specversion"]; !ok {
+		return nil, ErrNotCloudEvent
+	}
+
+	ce := &CloudEvent{
+		DataContentType: contentType,
+		Data:            msg.Body,
+	}
+	for name, value := range headers {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if binding.escape {
+			unescaped, err := url.PathUnescape(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid header %s: %w", name, err)
+			}
+			value = unescaped
+		}
+		if err := ce.setAttribute(strings.TrimPrefix(name, prefix), value); err != nil {
+			return nil, err
+		}
+	}
+	return ce, nil
+}
+
+// isStructured reports whether a body is an event in the JSON event format, for producers
+// that leave out the content type
+func isStructured(body []byte) bool {
+	var document struct {
+		SpecVersion string `json:"specversion"`
+	}
+	return json.Unmarshal(body, &document) == nil && document.SpecVersion != ""
+}
+
+// escapeHeader percent-encodes the characters the HTTP binding does not allow in header values
+func escapeHeader(value string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(value) {
+		if b <= ' ' || b >= 0x7f || b == '"' || b == '%' {
+			fmt.Fprintf(&escaped, "%%%02X", b)
+			continue
+		}
+		escaped.WriteByte(b)
+	}
+	return escaped.String()
+}
@@ -0,0 +1,300 @@
+// Package cloudevents encodes domain events as CloudEvents 1.0, in structured and binary mode,
+// for the brokers and HTTP endpoints shared with other services
+package cloudevents
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-hexagonal/domain/event"
+)
+
+// SpecVersion is the CloudEvents version events are encoded with
+const SpecVersion = "1.0"
+
+// Content types used by the codec
+const (
+	// ContentTypeJSON is the content type of event data
+	ContentTypeJSON = "application/json"
+	// ContentTypeStructured is the content type of events encoded in structured mode
+	ContentTypeStructured = "application/cloudevents+json"
+)
+
+// Extension attributes carrying the trace IDs and payload schema version of an event
+const (
+	ExtensionCorrelationID = "correlationid"
+	ExtensionCausationID   = "causationid"
+	ExtensionSchemaVersion = "schemaversion"
+)
+
+// ErrNotCloudEvent is returned when decoding a message that is not a CloudEvent
+var ErrNotCloudEvent = errors.New("message is not a CloudEvent")
+
+// Mode selects how an event is laid out in a message
+type Mode int
+
+const (
+	// ModeBinary carries the attributes in headers and the data as the message body
+	ModeBinary Mode = iota
+	// ModeStructured carries the whole event as a JSON document in the message body
+	ModeStructured
+)
+
+// String returns the name of the mode
+func (m Mode) String() string {
+	switch m {
+	case ModeBinary:
+		return "binary"
+	case ModeStructured:
+		return "structured"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// CloudEvent is an event in the CloudEvents 1.0 format
+type CloudEvent struct {
+	ID              string
+	Source          string
+	SpecVersion     string
+	Type            string
+	Subject         string
+	Time            time.Time
+	DataContentType string
+	Data            json.RawMessage
+	// Extensions holds the extension attributes by their lowercase name
+	Extensions map[string]string
+}
+
+// MarshalJSON encodes the event in the JSON event format used by structured mode
+func (ce CloudEvent) MarshalJSON() ([]byte, error) {
+	document := make(map[string]any, len(ce.Extensions)+8)
+	for name, value := range ce.Extensions {
+		document[name] = value
+	}
+	document["specversion"] = ce.SpecVersion
+	document["id"] = ce.ID
+	document["source"] = ce.Source
+	document["type"] = ce.Type
+	if ce.Subject != "" {
+		document["subject"] = ce.Subject
+	}
+	if !ce.Time.IsZero() {
+		document["time"] = ce.Time.UTC().Format(time.RFC3339Nano)
+	}
+	if ce.DataContentType != "" {
+		document["datacontenttype"] = ce.DataContentType
+	}
+	if len(ce.Data) > 0 {
+		document["data"] = ce.Data
+	}
+	return json.Marshal(document)
+}
+
+// UnmarshalJSON decodes an event in the JSON event format, attributes it does not know are kept as extensions
+func (ce *CloudEvent) UnmarshalJSON(data []byte) error {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+
+	decoded := CloudEvent{Extensions: make(map[string]string)}
+	for name, raw := range document {
+		switch name {
+		case "data":
+			decoded.Data = raw
+			continue
+		case "data_base64":
+			var encoded string
+			if err := json.Unmarshal(raw, &encoded); err != nil {
+				return fmt.Errorf("invalid data_base64: %w", err)
+			}
+			bytes, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return fmt.Errorf("invalid data_base64: %w", err)
+			}
+			decoded.Data = bytes
+			continue
+		}
+
+		value := attributeValue(raw)
+		if err := decoded.setAttribute(name, value); err != nil {
+			return err
+		}
+	}
+
+	*ce = decoded
+	return nil
+}
+
+// setAttribute sets a context attribute by its name, unknown names are extensions
+func (ce *CloudEvent) setAttribute(name, value string) error {
+	switch name {
+	case "specversion":
+		ce.SpecVersion = value
+	case "id":
+		ce.ID = value
+	case "source":
+		ce.Source = value
+	case "type":
+		ce.Type = value
+	case "subject":
+		ce.Subject = value
+	case "datacontenttype":
+		ce.DataContentType = value
+	case "time":
+		if value == "" {
+			return nil
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return fmt.Errorf("invalid time attribute: %w", err)
+		}
+		ce.Time = parsed
+	default:
+		if ce.Extensions == nil {
+			ce.Extensions = make(map[string]string)
+		}
+		ce.Extensions[name] = value
+	}
+	return nil
+}
+
+// attributes returns the context attributes that are set, by their name
+func (ce *CloudEvent) attributes() map[string]string {
+	attributes := make(map[string]string, len(ce.Extensions)+6)
+	for name, value := range ce.Extensions {
+		attributes[name] = value
+	}
+	attributes["specversion"] = ce.SpecVersion
+	attributes["id"] = ce.ID
+	attributes["source"] = ce.Source
+	attributes["type"] = ce.Type
+	if ce.Subject != "" {
+		attributes["subject"] = ce.Subject
+	}
+	if !ce.Time.IsZero() {
+		attributes["time"] = ce.Time.UTC().Format(time.RFC3339Nano)
+	}
+	return attributes
+}
+
+// validate checks the attributes every CloudEvent must have
+func (ce *CloudEvent) validate() error {
+	if ce.SpecVersion != SpecVersion {
+		return fmt.Errorf("unsupported CloudEvents spec version %q", ce.SpecVersion)
+	}
+	if ce.ID == "" || ce.Source == "" || ce.Type == "" {
+		return fmt.Errorf("CloudEvent requires the id, source and type attributes")
+	}
+	return nil
+}
+
+// attributeValue returns the string form of an attribute, JSON strings are unquoted
+func attributeValue(raw json.RawMessage) string {
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value
+	}
+	return string(raw)
+}
+
+// isJSON reports whether a content type describes JSON data, an empty content type implies JSON
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == ContentTypeJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+// Codec translates between domain events and CloudEvents
+type Codec struct {
+	source   string
+	registry *event.TypeRegistry
+}
+
+// NewCodec creates a codec stamping events with source, registry gives decoded events their concrete types
+func NewCodec(source string, registry *event.TypeRegistry) *Codec {
+	return &Codec{
+		source:   source,
+		registry: registry,
+	}
+}
+
+// ToCloudEvent maps an event to CloudEvent attributes: the event ID becomes the id, the name the type,
+// the aggregate ID the subject and the occurrence time the time; the payload is the JSON data
+func (c *Codec) ToCloudEvent(evt event.Event) (*CloudEvent, error) {
+	data, err := event.MarshalPayload(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	ce := &CloudEvent{
+		ID:              evt.EventID(),
+		Source:          c.source,
+		SpecVersion:     SpecVersion,
+		Type:            evt.EventName(),
+		Subject:         evt.AggregateID(),
+		Time:            evt.OccurredAt(),
+		DataContentType: ContentTypeJSON,
+		Data:            data,
+		Extensions: map[string]string{
+			ExtensionSchemaVersion: strconv.Itoa(event.SchemaVersionOf(evt)),
+		},
+	}
+	if traceable, ok := evt.(event.Traceable); ok {
+		if id := traceable.CorrelationID(); id != "" {
+			ce.Extensions[ExtensionCorrelationID] = id
+		}
+		if id := traceable.CausationID(); id != "" {
+			ce.Extensions[ExtensionCausationID] = id
+		}
+	}
+
+	return ce, nil
+}
+
+// FromCloudEvent rebuilds the event a CloudEvent describes, with the type registered for its type
+// attribute. Events of an older schema version are upcast, events without one are taken as version 1.
+func (c *Codec) FromCloudEvent(ce *CloudEvent) (event.Event, error) {
+	if err := ce.validate(); err != nil {
+		return nil, err
+	}
+	if !isJSON(ce.DataContentType) {
+		return nil, fmt.Errorf("unsupported CloudEvent data content type %q", ce.DataContentType)
+	}
+
+	var version int
+	if value, ok := ce.Extensions[ExtensionSchemaVersion]; ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return nil, fmt.Errorf("invalid %s attribute %q", ExtensionSchemaVersion, value)
+		}
+		version = parsed
+	}
+
+	data := []byte(ce.Data)
+	if len(data) == 0 {
+		data = []byte("null")
+	}
+
+	return c.registry.Decode(event.BaseEvent{
+		ID:          ce.ID,
+		Name:        ce.Type,
+		Aggregate:   ce.Subject,
+		OccurredOn:  ce.Time,
+		Version:     version,
+		Correlation: ce.Extensions[ExtensionCorrelationID],
+		Causation:   ce.Extensions[ExtensionCausationID],
+	}, data)
+}
@@ -0,0 +1,167 @@
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/event"
+)
+
+const testSource = "/tests/example-service"
+
+func newTestEvent() event.Event {
+	return event.DefaultTypeRegistry.WithMetadata(event.NewExampleUpdatedEvent(3, "name", "alias"), event.Metadata{
+		CorrelationID: "flow-1",
+		CausationID:   "request-1",
+	})
+}
+
+// assertDecoded checks that a decoded event matches the original in type, attributes and payload
+func assertDecoded(t *testing.T, original, decoded event.Event) {
+	typed, ok := decoded.(event.ExampleUpdatedEvent)
+	require.True(t, ok)
+	assert.Equal(t, original.EventID(), typed.EventID())
+	assert.Equal(t, original.EventName(), typed.EventName())
+	assert.Equal(t, original.AggregateID(), typed.AggregateID())
+	assert.True(t, original.OccurredAt().Equal(typed.OccurredAt()))
+	assert.Equal(t, "flow-1", typed.CorrelationID())
+	assert.Equal(t, "request-1", typed.CausationID())
+	assert.Equal(t, event.ExampleUpdatedPayload{ID: 3, Name: "name", Alias: "alias"}, typed.Payload)
+}
+
+func TestCodec_StructuredMode(t *testing.T) {
+	codec := NewCodec(testSource, event.DefaultTypeRegistry)
+	original := newTestEvent()
+
+	msg, err := codec.Encode(original, ModeStructured, KafkaBinding)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"content-type": ContentTypeStructured}, msg.Headers)
+
+	// The event fields are mapped to the spec attributes
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(msg.Body, &document))
+	assert.Equal(t, "1.0", document["specversion"])
+	assert.Equal(t, original.EventID(), document["id"])
+	assert.Equal(t, testSource, document["source"])
+	assert.Equal(t, event.ExampleUpdatedEventName, document["type"])
+	assert.Equal(t, "3", document["subject"])
+	assert.Equal(t, original.OccurredAt().UTC().Format(time.RFC3339Nano), document["time"])
+	assert.Equal(t, ContentTypeJSON, document["datacontenttype"])
+	assert.Equal(t, "flow-1", document[ExtensionCorrelationID])
+	assert.Equal(t, map[string]any{"id": float64(3), "name": "name", "alias": "alias"}, document["data"])
+
+	decoded, err := codec.Decode(msg, KafkaBinding)
+	require.NoError(t, err)
+	assertDecoded(t, original, decoded)
+
+	// Producers leaving out the content type are recognized by the body
+	decoded, err = codec.Decode(&Message{Body: msg.Body}, KafkaBinding)
+	require.NoError(t, err)
+	assertDecoded(t, original, decoded)
+}
+
+func TestCodec_BinaryMode(t *testing.T) {
+	codec := NewCodec(testSource, event.DefaultTypeRegistry)
+	original := newTestEvent()
+
+	msg, err := codec.Encode(original, ModeBinary, KafkaBinding)
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeJSON, msg.Headers["content-type"])
+	assert.Equal(t, "1.0", msg.Headers["ce_specversion"])
+	assert.Equal(t, original.EventID(), msg.Headers["ce_id"])
+	assert.Equal(t, testSource, msg.Headers["ce_source"])
+	assert.Equal(t, event.ExampleUpdatedEventName, msg.Headers["ce_type"])
+	assert.Equal(t, "3", msg.Headers["ce_subject"])
+	assert.Equal(t, "request-1", msg.Headers["ce_causationid"])
+	assert.JSONEq(t, `{"id":3,"name":"name","alias":"alias"}`, string(msg.Body))
+
+	decoded, err := codec.Decode(msg, KafkaBinding)
+	require.NoError(t, err)
+	assertDecoded(t, original, decoded)
+}
+
+func TestCodec_HTTP(t *testing.T) {
+	codec := NewCodec(testSource, event.DefaultTypeRegistry)
+	original := event.DefaultTypeRegistry.WithMetadata(event.NewExampleUpdatedEvent(3, "name", "alias"), event.Metadata{
+		CorrelationID: "flow 1 – ü",
+		CausationID:   "request-1",
+	})
+
+	for _, mode := range []Mode{ModeBinary, ModeStructured} {
+		t.Run(mode.String(), func(t *testing.T) {
+			req, err := codec.NewRequest(context.Background(), http.MethodPost, "http://localhost/events", original, mode)
+			require.NoError(t, err)
+			if mode == ModeBinary {
+				// Header values are percent-encoded
+				assert.Equal(t, "flow%201%20%E2%80%93%20%C3%BC", req.Header.Get("ce-correlationid"))
+			}
+
+			decoded, err := codec.ReadRequest(req)
+			require.NoError(t, err)
+			assert.Equal(t, "flow 1 – ü", decoded.(event.Traceable).CorrelationID())
+			assert.Equal(t, original.EventID(), decoded.EventID())
+		})
+	}
+}
+
+func TestCodec_Decode(t *testing.T) {
+	codec := NewCodec(testSource, event.DefaultTypeRegistry)
+
+	t.Run("Not a CloudEvent", func(t *testing.T) {
+		_, err := codec.Decode(&Message{Body: []byte(`{"id":"1","name":"example.created"}`)}, KafkaBinding)
+		assert.ErrorIs(t, err, ErrNotCloudEvent)
+	})
+
+	t.Run("Unsupported spec version", func(t *testing.T) {
+		_, err := codec.Decode(&Message{
+			Headers: map[string]string{"content-type": ContentTypeStructured},
+			Body:    []byte(`{"specversion":"0.3","id":"1","source":"/other","type":"example.created"}`),
+		}, KafkaBinding)
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing attributes", func(t *testing.T) {
+		_, err := codec.Decode(&Message{
+			Headers: map[string]string{"ce_specversion": "1.0", "ce_id": "1"},
+		}, KafkaBinding)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown type from another service", func(t *testing.T) {
+		decoded, err := codec.Decode(&Message{
+			Body: []byte(`{"specversion":"1.0","id":"1","source":"/billing","type":"invoice.paid","data":{"amount":10}}`),
+		}, KafkaBinding)
+		require.NoError(t, err)
+
+		base, ok := decoded.(event.BaseEvent)
+		require.True(t, ok)
+		assert.Equal(t, "invoice.paid", base.EventName())
+		assert.JSONEq(t, `{"amount":10}`, string(base.Payload.(json.RawMessage)))
+	})
+}
+
+func TestCodec_SchemaVersion(t *testing.T) {
+	codec := NewCodec(testSource, event.DefaultTypeRegistry)
+
+	msg, err := codec.Encode(newTestEvent(), ModeBinary, KafkaBinding)
+	require.NoError(t, err)
+	assert.Equal(t, "1", msg.Headers["ce_schemaversion"])
+
+	t.Run("Newer than supported", func(t *testing.T) {
+		msg.Headers["ce_schemaversion"] = "2"
+		_, err := codec.Decode(msg, KafkaBinding)
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		msg.Headers["ce_schemaversion"] = "v1"
+		_, err := codec.Decode(msg, KafkaBinding)
+		assert.Error(t, err)
+	})
+}
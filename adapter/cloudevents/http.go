@@ -0,0 +1,44 @@
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go-hexagonal/domain/event"
+)
+
+// NewRequest builds an HTTP request delivering an event to url
+func (c *Codec) NewRequest(ctx context.Context, method, url string, evt event.Event, mode Mode) (*http.Request, error) {
+	msg, err := c.Encode(evt, mode, HTTPBinding)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(msg.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for event %s: %w", evt.EventID(), err)
+	}
+	for name, value := range msg.Headers {
+		req.Header.Set(name, value)
+	}
+
+	return req, nil
+}
+
+// ReadRequest decodes the event delivered by an HTTP request, the request body is consumed
+func (c *Codec) ReadRequest(req *http.Request) (event.Event, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	headers := make(map[string]string, len(req.Header))
+	for name := range req.Header {
+		headers[name] = req.Header.Get(name)
+	}
+
+	return c.Decode(&Message{Headers: headers, Body: body}, HTTPBinding)
+}
@@ -52,8 +52,9 @@ func (c *ExampleConverter) FromUpdateRequest(req any) (*model.Example, error) {
 	}
 
 	return &model.Example{
-		Id:    int(updateReq.Id),
-		Name:  updateReq.Name,
-		Alias: updateReq.Alias,
+		Id:      int(updateReq.Id),
+		Name:    updateReq.Name,
+		Alias:   updateReq.Alias,
+		Version: updateReq.Version,
 	}, nil
 }
@@ -0,0 +1,81 @@
+// Package deadletter provides dead letter sinks that live outside the databases
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go-hexagonal/domain/event"
+)
+
+// fileEntry is the JSON line written for each dead letter
+type fileEntry struct {
+	ID          string          `json:"id"`
+	EventID     string          `json:"event_id"`
+	EventName   string          `json:"event_name"`
+	AggregateID string          `json:"aggregate_id"`
+	Version     int             `json:"schema_version"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Payload     json.RawMessage `json:"payload"`
+	Handler     string          `json:"handler"`
+	Error       string          `json:"error"`
+	Attempts    int             `json:"attempts"`
+	FailedAt    time.Time       `json:"failed_at"`
+}
+
+// FileSink appends dead letters to a file as JSON lines, re-added letters appear once per failure
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileSink creates a sink appending to the file at path, the file is created when missing
+func NewFileSink(path string) *FileSink {
+	return &FileSink{
+		path: path,
+	}
+}
+
+// Add appends a dead letter to the file
+func (s *FileSink) Add(ctx context.Context, letter *event.DeadLetter) error {
+	payload, err := event.MarshalPayload(letter.Event)
+	if err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(fileEntry{
+		ID:          letter.ID,
+		EventID:     letter.Event.EventID(),
+		EventName:   letter.Event.EventName(),
+		AggregateID: letter.Event.AggregateID(),
+		Version:     event.SchemaVersionOf(letter.Event),
+		OccurredAt:  letter.Event.OccurredAt(),
+		Payload:     payload,
+		Handler:     letter.Handler,
+		Error:       letter.Error,
+		Attempts:    letter.Attempts,
+		FailedAt:    letter.FailedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open dead letter file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+
+	return nil
+}
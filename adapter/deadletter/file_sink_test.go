@@ -0,0 +1,47 @@
+package deadletter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/event"
+)
+
+func TestFileSink_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead_letters.jsonl")
+	sink := NewFileSink(path)
+
+	created := event.NewExampleCreatedEvent(1, "name", "alias")
+	deleted := event.NewExampleDeletedEvent(1)
+	require.NoError(t, sink.Add(context.Background(), event.NewDeadLetter(created, "handler", 3, errors.New("boom"))))
+	require.NoError(t, sink.Add(context.Background(), event.NewDeadLetter(deleted, "handler", 1, errors.New("boom"))))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var entries []fileEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry fileEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, entries, 2)
+	assert.Equal(t, created.EventID(), entries[0].EventID)
+	assert.Equal(t, "handler", entries[0].Handler)
+	assert.Equal(t, "boom", entries[0].Error)
+	assert.Equal(t, 3, entries[0].Attempts)
+	assert.JSONEq(t, `{"id":1,"name":"name","alias":"alias"}`, string(entries[0].Payload))
+	assert.Equal(t, event.ExampleDeletedEventName, entries[1].EventName)
+}
@@ -6,14 +6,23 @@ package dependency
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/wire"
 	"gorm.io/gorm"
 
 	"go-hexagonal/adapter/converter"
+	"go-hexagonal/adapter/job"
 	"go-hexagonal/adapter/repository"
-	"go-hexagonal/adapter/repository/mysql/entity"
+	"go-hexagonal/adapter/repository/eventsourced"
+	"go-hexagonal/adapter/repository/memory"
+	"go-hexagonal/adapter/repository/mysql"
+	"go-hexagonal/adapter/repository/postgre"
+	redisRepo "go-hexagonal/adapter/repository/redis"
+	"go-hexagonal/adapter/repository/sqlite"
+	"go-hexagonal/application/projection"
 	"go-hexagonal/config"
 	"go-hexagonal/domain/event"
 	"go-hexagonal/domain/repo"
@@ -21,61 +30,83 @@ import (
 )
 
 // RepositoryOption defines an option for repository initialization
-type RepositoryOption func(*repository.ClientContainer)
+type RepositoryOption func(*repository.ClientContainer) error
 
 // WithMySQL returns an option to initialize MySQL
 func WithMySQL() RepositoryOption {
-	return func(c *repository.ClientContainer) {
+	return func(c *repository.ClientContainer) error {
 		if c.MySQL == nil {
 			mysql, err := ProvideMySQL()
 			if err != nil {
-				panic("Failed to initialize MySQL: " + err.Error())
+				return fmt.Errorf("failed to initialize MySQL: %w", err)
 			}
 			c.MySQL = mysql
 		}
+		return nil
 	}
 }
 
 // WithRedis returns an option to initialize Redis
 func WithRedis() RepositoryOption {
-	return func(c *repository.ClientContainer) {
+	return func(c *repository.ClientContainer) error {
 		if c.Redis == nil {
 			redis, err := ProvideRedis()
 			if err != nil {
-				panic("Failed to initialize Redis: " + err.Error())
+				return fmt.Errorf("failed to initialize Redis: %w", err)
 			}
 			c.Redis = redis
 		}
+		return nil
+	}
+}
+
+// WithSQLite returns an option to initialize SQLite
+func WithSQLite() RepositoryOption {
+	return func(c *repository.ClientContainer) error {
+		if c.SQLite == nil {
+			sqlite, err := ProvideSQLite()
+			if err != nil {
+				return fmt.Errorf("failed to initialize SQLite: %w", err)
+			}
+			c.SQLite = sqlite
+		}
+		return nil
 	}
 }
 
 // ServiceOption defines an option for service initialization
-type ServiceOption func(*service.Services, event.EventBus)
+type ServiceOption func(*service.Services, event.EventBus) error
 
 // WithExampleService returns an option to initialize the Example service
 func WithExampleService() ServiceOption {
-	return func(s *service.Services, eventBus event.EventBus) {
+	return func(s *service.Services, eventBus event.EventBus) error {
 		if s.ExampleService == nil {
-			exampleRepo := entity.NewExample()
+			exampleRepo, err := provideExampleRepo()
+			if err != nil {
+				return err
+			}
 			s.ExampleService = provideExampleService(exampleRepo, eventBus)
 		}
+		return nil
 	}
 }
 
 // WithTransactionFactory returns an option to initialize the transaction factory
 func WithTransactionFactory() RepositoryOption {
-	return func(c *repository.ClientContainer) {
+	return func(c *repository.ClientContainer) error {
 		// This is a no-op since the transaction factory doesn't need initialization,
 		// but we include it for consistency and future extensions
+		return nil
 	}
 }
 
 // WithExampleConverter returns an option to initialize the example converter
 func WithExampleConverter() ServiceOption {
-	return func(s *service.Services, _ event.EventBus) {
+	return func(s *service.Services, _ event.EventBus) error {
 		if s.Converter == nil {
 			s.Converter = provideExampleConverter()
 		}
+		return nil
 	}
 }
 
@@ -89,17 +120,82 @@ func InitializeServices(ctx context.Context, opts ...ServiceOption) (*service.Se
 
 	// Apply service options
 	for _, opt := range opts {
-		opt(services, eventBus)
+		if err := opt(services, eventBus); err != nil {
+			return nil, err
+		}
 	}
 
 	return services, nil
 }
 
-// InitializeRepositories initializes repository clients with the given options
+// WithPostgreSQL returns an option to initialize PostgreSQL
+func WithPostgreSQL() RepositoryOption {
+	return func(c *repository.ClientContainer) error {
+		if c.PostgreSQL == nil {
+			postgres, err := ProvidePostgreSQL()
+			if err != nil {
+				return fmt.Errorf("failed to initialize PostgreSQL: %w", err)
+			}
+			c.PostgreSQL = postgres
+		}
+		return nil
+	}
+}
+
+// WithMemory returns an option to keep examples in process memory
+func WithMemory() RepositoryOption {
+	return func(c *repository.ClientContainer) error {
+		if c.Memory == nil {
+			c.Memory = memory.NewStore()
+		}
+		return nil
+	}
+}
+
+// WithPersistence returns an option to initialize the primary store selected by persistence.driver
+func WithPersistence() RepositoryOption {
+	return func(c *repository.ClientContainer) error {
+		driver := config.GlobalConfig.PersistenceDriver()
+		if es := config.GlobalConfig.EventSourcing; es != nil && es.Enabled && driver != config.DriverMySQL && driver != config.DriverPostgres {
+			return fmt.Errorf("%w, got %s", repository.ErrEventSourcingUnsupported, driver)
+		}
+		option, err := persistenceOption(driver)
+		if err != nil {
+			return err
+		}
+		if err := option(c); err != nil {
+			return fmt.Errorf("persistence driver %s: %w", driver, err)
+		}
+		return nil
+	}
+}
+
+// persistenceOption returns the option initializing the store of a persistence driver
+func persistenceOption(driver string) (RepositoryOption, error) {
+	switch driver {
+	case config.DriverMySQL:
+		return WithMySQL(), nil
+	case config.DriverPostgres:
+		return WithPostgreSQL(), nil
+	case config.DriverSQLite:
+		return WithSQLite(), nil
+	case config.DriverMemory:
+		return WithMemory(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q, expected one of %s, %s, %s or %s", repository.ErrUnsupportedPersistenceDriver,
+			driver, config.DriverMySQL, config.DriverPostgres, config.DriverSQLite, config.DriverMemory)
+	}
+}
+
+// InitializeRepositories initializes repository clients with the given options. When an option fails,
+// the clients initialized so far are closed and its error is returned.
 func InitializeRepositories(opts ...RepositoryOption) (*repository.ClientContainer, error) {
 	container := &repository.ClientContainer{}
 	for _, opt := range opts {
-		opt(container)
+		if err := opt(container); err != nil {
+			container.Close(context.Background())
+			return nil, err
+		}
 	}
 	return container, nil
 }
@@ -115,7 +211,19 @@ func ProvideMySQL() (*repository.MySQL, error) {
 		return nil, err
 	}
 
-	return &repository.MySQL{DB: db}, nil
+	// Route reads to the replicas when the configuration lists any
+	cfg := config.GlobalConfig.MySQL
+	replicas, err := repository.OpenReplicaRouter(db, cfg.ReadReplicas, func(replica config.ReplicaConfig) (*gorm.DB, error) {
+		return repository.OpenMySQLDB(cfg.Replica(replica))
+	})
+	if err != nil {
+		if sqlDB, dbErr := db.DB(); dbErr == nil {
+			_ = sqlDB.Close()
+		}
+		return nil, err
+	}
+
+	return &repository.MySQL{DB: db, Replicas: replicas}, nil
 }
 
 // ProvideRedis creates and initializes a Redis client
@@ -128,9 +236,138 @@ func ProvideRedis() (*repository.Redis, error) {
 	return &repository.Redis{DB: client}, nil
 }
 
-// ProvideTransactionFactory creates and initializes a transaction factory
-func ProvideTransactionFactory() repo.TransactionFactory {
-	return repository.NewTransactionFactory()
+// ProvidePostgreSQL creates and initializes a PostgreSQL client
+func ProvidePostgreSQL() (*repository.PostgreSQL, error) {
+	cfg := config.GlobalConfig.Postgre
+	if cfg == nil {
+		return nil, repository.ErrMissingPostgreSQLConfig
+	}
+
+	client, err := postgre.NewPostgreSQLClientFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Route reads to the replicas when the configuration lists any
+	replicas, err := repository.OpenReplicaRouter(client.DB, cfg.ReadReplicas, func(replica config.ReplicaConfig) (*gorm.DB, error) {
+		replicaClient, err := postgre.NewPostgreSQLClientFromConfig(cfg.Replica(replica))
+		if err != nil {
+			return nil, err
+		}
+		return replicaClient.DB, nil
+	})
+	if err != nil {
+		_ = client.Close(context.Background())
+		return nil, err
+	}
+
+	return &repository.PostgreSQL{DB: client.DB, Replicas: replicas}, nil
+}
+
+// ProvideSQLite opens the SQLite database and applies its migrations
+func ProvideSQLite() (*repository.SQLite, error) {
+	if config.GlobalConfig.SQLite == nil {
+		return nil, repository.ErrMissingSQLiteConfig
+	}
+
+	client, err := sqlite.NewSQLiteClient(config.GlobalConfig.SQLite)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Migrate(context.Background()); err != nil {
+		_ = client.Close(context.Background())
+		return nil, err
+	}
+
+	return repository.NewSQLiteClient(client.DB), nil
+}
+
+// ProvideProjectionManager creates the manager of the example read model projections. Read models
+// and checkpoints are kept in Redis; events are replayed from the MySQL or PostgreSQL event store when available.
+func ProvideProjectionManager(clients *repository.ClientContainer) (*projection.Manager, error) {
+	if clients == nil || clients.Redis == nil {
+		return nil, repository.ErrMissingRedisConfig
+	}
+
+	redisClient := redisRepo.WrapClient(clients.Redis.DB)
+	return projection.NewManager(
+		provideEventStore(clients),
+		redisRepo.NewProjectionCheckpointRepo(redisClient),
+		projection.NewExampleDailyCountProjector(redisRepo.NewExampleDailyCountRepo(redisClient)),
+		projection.NewExampleNameIndexProjector(redisRepo.NewExampleNameIndexRepo(redisClient)),
+	), nil
+}
+
+// ProvideScheduler creates the scheduler of the background jobs: the outbox relay delivering the
+// events the example service recorded in the MySQL or PostgreSQL outbox to the event bus, and the
+// cleanup of the inbox records past their retention
+func ProvideScheduler(clients *repository.ClientContainer, eventBus event.EventBus, txFactory repo.TransactionFactory) (*job.Scheduler, error) {
+	scheduler := job.NewScheduler()
+
+	if relay := provideOutboxRelayJob(clients, eventBus, txFactory); relay != nil {
+		schedule := job.DefaultOutboxRelaySchedule
+		if cfg := config.GlobalConfig.Outbox; cfg != nil && cfg.RelaySchedule != "" {
+			schedule = cfg.RelaySchedule
+		}
+		if err := scheduler.AddJob(schedule, relay); err != nil {
+			return nil, err
+		}
+	}
+
+	if inboxRepo := provideInboxRepo(clients); inboxRepo != nil {
+		schedule := job.DefaultInboxCleanupSchedule
+		if cfg := config.GlobalConfig.Inbox; cfg != nil && cfg.CleanupSchedule != "" {
+			schedule = cfg.CleanupSchedule
+		}
+		if err := scheduler.AddJob(schedule, job.NewInboxCleanupJob(inboxRepo, inboxRetention())); err != nil {
+			return nil, err
+		}
+	}
+
+	return scheduler, nil
+}
+
+// ProvideStoreType returns the store type the use cases open their transactions on, the primary store
+// persistence.driver selects
+func ProvideStoreType() repo.StoreType {
+	switch config.GlobalConfig.PersistenceDriver() {
+	case config.DriverPostgres:
+		return repo.PostgresStore
+	case config.DriverSQLite:
+		return repo.SQLiteStore
+	case config.DriverMemory:
+		return repo.MemoryStore
+	default:
+		return repo.MySQLStore
+	}
+}
+
+// ProvideTransactionFactory creates a transaction factory over the initialized SQL clients.
+// With the memory store, SQLite, or PostgreSQL without MySQL, the factory only opens transactions for that
+// primary store, the one ProvideStoreType returns. Without any SQL client, use cases run with no-op transactions.
+func ProvideTransactionFactory(clients *repository.ClientContainer) repo.TransactionFactory {
+	if clients != nil && clients.Memory != nil {
+		return memory.NewTransactionFactory(clients.Memory)
+	}
+	if clients != nil && clients.SQLite != nil {
+		return sqlite.NewTransactionFactory(&sqlite.SQLiteClient{DB: clients.SQLite.DB})
+	}
+	if clients != nil && clients.PostgreSQL != nil && clients.MySQL == nil {
+		return postgre.NewTransactionFactory(&postgre.PostgreSQLClient{DB: clients.PostgreSQL.DB, Replicas: clients.PostgreSQL.Replicas})
+	}
+
+	stores := make(map[repository.StoreType]any)
+	if clients != nil && clients.MySQL != nil {
+		stores[repository.MySQLStore] = clients.MySQL
+	}
+	if clients != nil && clients.PostgreSQL != nil {
+		stores[repository.PostgreSQLStore] = clients.PostgreSQL
+	}
+
+	if len(stores) == 0 {
+		return repo.NewNoOpTransactionFactory()
+	}
+	return repository.NewTransactionFactory(stores)
 }
 
 // ProvideExampleConverter creates and initializes an example converter
@@ -170,23 +407,165 @@ type RedisRepository interface {
 	Close(ctx context.Context) error
 }
 
-// provideEventBus creates and configures the event bus
+// provideEventBus creates and configures the event bus. With a MySQL or PostgreSQL client, every
+// published event is saved to its event store first, so projections can replay it.
 func provideEventBus() *event.InMemoryEventBus {
 	eventBus := event.NewInMemoryEventBus()
+	if store := provideEventStore(repository.Clients); store != nil {
+		eventBus = event.NewStoredInMemoryEventBus(store)
+	}
 
 	// Register event handlers
 	loggingHandler := event.NewLoggingEventHandler()
-	exampleHandler := event.NewExampleEventHandler()
+	var exampleHandler event.EventHandler = event.NewExampleEventHandler()
+	if inbox := provideInbox(repository.Clients); inbox != nil {
+		// Redelivered events, e.g. relayed again from the outbox, are handled once
+		exampleHandler = event.Chain(exampleHandler, inbox.Middleware())
+	}
 	eventBus.Subscribe(loggingHandler)
 	eventBus.Subscribe(exampleHandler)
 
 	return eventBus
 }
 
+// provideExampleRepo selects the example repository over the primary store: the in-memory one, else the
+// event-sourced one when enabled in the configuration, else the SQLite, PostgreSQL or MySQL one. It fails
+// when no store is initialized, or event sourcing is enabled without a MySQL or PostgreSQL client.
+func provideExampleRepo() (repo.IExampleRepo, error) {
+	clients := repository.Clients
+	if clients != nil && clients.Memory != nil {
+		return memory.NewExampleRepo(clients.Memory), nil
+	}
+	cfg := config.GlobalConfig
+	if cfg != nil && cfg.EventSourcing != nil && cfg.EventSourcing.Enabled {
+		streams := provideEventStreamRepo(clients)
+		if streams == nil || clients.SQLite != nil {
+			return nil, repository.ErrEventSourcingUnsupported
+		}
+		return eventsourced.NewExampleRepo(streams, cfg.EventSourcing.SnapshotInterval), nil
+	}
+	if clients != nil && clients.SQLite != nil {
+		return sqlite.NewExampleRepo(&sqlite.SQLiteClient{DB: clients.SQLite.DB}), nil
+	}
+	if clients != nil && clients.PostgreSQL != nil && clients.MySQL == nil {
+		return postgre.NewExampleRepo(&postgre.PostgreSQLClient{DB: clients.PostgreSQL.DB, Replicas: clients.PostgreSQL.Replicas}), nil
+	}
+	if clients != nil && clients.MySQL != nil {
+		return mysql.NewExampleRepo(&mysql.MySQLClient{DB: clients.MySQL.DB, Replicas: clients.MySQL.Replicas}), nil
+	}
+	return nil, repository.ErrNoExampleStore
+}
+
+// provideEventStreamRepo creates the event stream repository over the MySQL or PostgreSQL client, nil without either
+func provideEventStreamRepo(clients *repository.ClientContainer) repo.IEventStreamRepo {
+	switch {
+	case clients == nil:
+		return nil
+	case clients.MySQL != nil:
+		return mysql.NewEventStreamRepo(&mysql.MySQLClient{DB: clients.MySQL.DB})
+	case clients.PostgreSQL != nil:
+		return postgre.NewEventStreamRepo(&postgre.PostgreSQLClient{DB: clients.PostgreSQL.DB})
+	default:
+		return nil
+	}
+}
+
+// provideEventStore creates the event store over the MySQL or PostgreSQL client, nil without either
+func provideEventStore(clients *repository.ClientContainer) event.EventLog {
+	switch {
+	case clients == nil:
+		return nil
+	case clients.MySQL != nil:
+		return mysql.NewEventStore(&mysql.MySQLClient{DB: clients.MySQL.DB})
+	case clients.PostgreSQL != nil:
+		return postgre.NewEventStore(&postgre.PostgreSQLClient{DB: clients.PostgreSQL.DB})
+	default:
+		return nil
+	}
+}
+
+// provideOutboxRepo creates the outbox repository over the MySQL or PostgreSQL client the examples are
+// kept in, nil with any other primary store
+func provideOutboxRepo(clients *repository.ClientContainer) repo.IOutboxRepo {
+	switch {
+	case clients == nil || clients.Memory != nil || clients.SQLite != nil:
+		return nil
+	case clients.MySQL != nil:
+		return mysql.NewOutboxRepo(&mysql.MySQLClient{DB: clients.MySQL.DB})
+	case clients.PostgreSQL != nil:
+		return postgre.NewOutboxRepo(&postgre.PostgreSQLClient{DB: clients.PostgreSQL.DB})
+	default:
+		return nil
+	}
+}
+
+// provideOutboxRelayJob creates the relay of the outbox provideOutboxRepo selects, dead-lettering the events
+// of parked messages in the same database; nil without an outbox
+func provideOutboxRelayJob(clients *repository.ClientContainer, eventBus event.EventBus, txFactory repo.TransactionFactory) *job.OutboxRelayJob {
+	outbox := provideOutboxRepo(clients)
+	if outbox == nil {
+		return nil
+	}
+
+	// The relay runs its transactions on the configured primary store, the one the outbox is kept in
+	relay := job.NewOutboxRelayJob(outbox, eventBus, txFactory, ProvideStoreType())
+	if clients.MySQL != nil {
+		relay.WithDeadLetterSink(mysql.NewDeadLetterStore(&mysql.MySQLClient{DB: clients.MySQL.DB}))
+	} else {
+		relay.WithDeadLetterSink(postgre.NewDeadLetterStore(&postgre.PostgreSQLClient{DB: clients.PostgreSQL.DB}))
+	}
+	if cfg := config.GlobalConfig.Outbox; cfg != nil {
+		relay.WithBatchSize(cfg.BatchSize).WithMaxAttempts(cfg.MaxAttempts)
+	}
+
+	return relay
+}
+
+// provideInboxRepo creates the inbox repository over the MySQL or PostgreSQL client the examples are
+// kept in, else over the Redis client; nil with the memory or SQLite store
+func provideInboxRepo(clients *repository.ClientContainer) repo.IInboxRepo {
+	switch {
+	case clients == nil || clients.Memory != nil || clients.SQLite != nil:
+		return nil
+	case clients.MySQL != nil:
+		return mysql.NewInboxRepo(&mysql.MySQLClient{DB: clients.MySQL.DB})
+	case clients.PostgreSQL != nil:
+		return postgre.NewInboxRepo(&postgre.PostgreSQLClient{DB: clients.PostgreSQL.DB})
+	case clients.Redis != nil:
+		return redisRepo.NewInboxRepo(redisRepo.WrapClient(clients.Redis.DB), inboxRetention())
+	default:
+		return nil
+	}
+}
+
+// provideInbox creates the inbox over the repository provideInboxRepo selects, nil without one.
+// SQL inboxes record an event in the transaction its handler runs in.
+func provideInbox(clients *repository.ClientContainer) *service.Inbox {
+	inboxRepo := provideInboxRepo(clients)
+	switch {
+	case inboxRepo == nil:
+		return nil
+	case clients.MySQL != nil || clients.PostgreSQL != nil:
+		return service.NewInbox(inboxRepo, ProvideTransactionFactory(clients), ProvideStoreType())
+	default:
+		return service.NewInbox(inboxRepo, nil, "")
+	}
+}
+
+// inboxRetention returns the configured inbox retention, DefaultInboxRetention when unset
+func inboxRetention() time.Duration {
+	if cfg := config.GlobalConfig.Inbox; cfg != nil && cfg.Retention != "" {
+		return config.GetDuration(cfg.Retention)
+	}
+	return job.DefaultInboxRetention
+}
+
 // provideExampleService creates and configures the example service
 func provideExampleService(repo repo.IExampleRepo, eventBus event.EventBus) *service.ExampleService {
-	exampleService := service.NewExampleService(repo)
+	exampleService := service.NewExampleService(repo, provideExampleCacheRepo())
 	exampleService.EventBus = eventBus
+	// With an outbox, events are recorded in the transaction of the change and relayed to the bus by the outbox relay job
+	exampleService.Outbox = provideOutboxRepo(repository.Clients)
 	return exampleService
 }
 
@@ -195,6 +574,14 @@ func provideExampleConverter() service.Converter {
 	return converter.NewExampleConverter()
 }
 
+// provideExampleCacheRepo creates the example cache, an in-memory one with the memory store, else none
+func provideExampleCacheRepo() repo.IExampleCacheRepo {
+	if repository.Clients != nil && repository.Clients.Memory != nil {
+		return memory.NewExampleCacheRepo(memory.DefaultCacheTTL)
+	}
+	return nil
+}
+
 // Deprecated: Use the new InitializeServices with options pattern instead
 func provideServices(exampleService *service.ExampleService, eventBus event.EventBus) *service.Services {
 	return service.NewServices(exampleService, eventBus)
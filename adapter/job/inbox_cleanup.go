@@ -0,0 +1,62 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/util/log"
+)
+
+const (
+	// DefaultInboxRetention is how long processed events are remembered by the inbox
+	DefaultInboxRetention = 7 * 24 * time.Hour
+	// DefaultInboxCleanupSchedule is the schedule of the cleanup runs when the configuration sets none
+	DefaultInboxCleanupSchedule = "@every 1h"
+)
+
+// InboxCleanupJob removes inbox records older than the retention period. Redeliveries of an event
+// after its record was removed are handled again, so the retention must exceed the redelivery window.
+type InboxCleanupJob struct {
+	inbox     repo.IInboxRepo
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewInboxCleanupJob creates a cleanup job, a non-positive retention uses DefaultInboxRetention
+func NewInboxCleanupJob(inbox repo.IInboxRepo, retention time.Duration) *InboxCleanupJob {
+	if retention <= 0 {
+		retention = DefaultInboxRetention
+	}
+
+	return &InboxCleanupJob{
+		inbox:     inbox,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Name returns the job name
+func (j *InboxCleanupJob) Name() string {
+	return "inbox_cleanup"
+}
+
+// Run deletes the records of events processed before the retention period
+func (j *InboxCleanupJob) Run(ctx context.Context) error {
+	deleted, err := j.inbox.DeleteBefore(ctx, nil, j.now().Add(-j.retention))
+	if err != nil {
+		return fmt.Errorf("failed to clean up inbox: %w", err)
+	}
+
+	if deleted > 0 {
+		log.Logger.Debug("Inbox records removed",
+			zap.String("job", j.Name()),
+			zap.Int64("count", deleted),
+		)
+	}
+
+	return nil
+}
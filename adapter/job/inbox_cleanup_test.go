@@ -0,0 +1,62 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/util/log"
+)
+
+// memoryInbox keeps inbox records in memory
+type memoryInbox struct {
+	messages []*repo.InboxMessage
+}
+
+func (i *memoryInbox) Processed(ctx context.Context, tr repo.Transaction, consumer, eventID string) (bool, error) {
+	for _, message := range i.messages {
+		if message.Consumer == consumer && message.EventID == eventID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (i *memoryInbox) Save(ctx context.Context, tr repo.Transaction, message *repo.InboxMessage) error {
+	i.messages = append(i.messages, message)
+	return nil
+}
+
+func (i *memoryInbox) DeleteBefore(ctx context.Context, tr repo.Transaction, before time.Time) (int64, error) {
+	var kept []*repo.InboxMessage
+	for _, message := range i.messages {
+		if !message.ProcessedAt.Before(before) {
+			kept = append(kept, message)
+		}
+	}
+	deleted := int64(len(i.messages) - len(kept))
+	i.messages = kept
+	return deleted, nil
+}
+
+func TestInboxCleanupJob_RemovesExpiredRecords(t *testing.T) {
+	log.Logger = zap.NewNop()
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	inbox := &memoryInbox{messages: []*repo.InboxMessage{
+		{Consumer: "counter", EventID: "old", ProcessedAt: now.Add(-48 * time.Hour)},
+		{Consumer: "counter", EventID: "recent", ProcessedAt: now.Add(-time.Hour)},
+	}}
+
+	job := NewInboxCleanupJob(inbox, 24*time.Hour)
+	job.now = func() time.Time { return now }
+	require.NoError(t, job.Run(context.Background()))
+
+	require.Len(t, inbox.messages, 1)
+	assert.Equal(t, "recent", inbox.messages[0].EventID)
+	assert.Equal(t, DefaultInboxRetention, NewInboxCleanupJob(inbox, 0).retention)
+}
@@ -0,0 +1,193 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"go-hexagonal/domain/event"
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/util/log"
+)
+
+// Outbox relay defaults
+const (
+	// DefaultOutboxBatchSize is the number of outbox messages delivered per transaction
+	DefaultOutboxBatchSize = 100
+	// DefaultOutboxMaxAttempts is the number of failed deliveries after which a message is parked
+	DefaultOutboxMaxAttempts = 5
+	// DefaultOutboxRelaySchedule is the schedule of the relay runs when the configuration sets none
+	DefaultOutboxRelaySchedule = "@every 1s"
+)
+
+// OutboxRelayJob delivers recorded outbox messages to an event bus.
+// A message is marked published only after the bus accepted it, so delivery is at least once
+// and consumers deduplicate on the event ID. A message failing max attempts times is parked,
+// and its event dead-lettered when a sink is set, so it does not hold up the outbox.
+type OutboxRelayJob struct {
+	outbox      repo.IOutboxRepo
+	bus         event.EventBus
+	txFactory   repo.TransactionFactory
+	store       repo.StoreType
+	batchSize   int
+	maxAttempts int
+	deadLetter  event.DeadLetterSink
+}
+
+// NewOutboxRelayJob creates a relay draining the outbox kept in the given store into the bus
+func NewOutboxRelayJob(outbox repo.IOutboxRepo, bus event.EventBus, txFactory repo.TransactionFactory, store repo.StoreType) *OutboxRelayJob {
+	return &OutboxRelayJob{
+		outbox:      outbox,
+		bus:         bus,
+		txFactory:   txFactory,
+		store:       store,
+		batchSize:   DefaultOutboxBatchSize,
+		maxAttempts: DefaultOutboxMaxAttempts,
+	}
+}
+
+// WithBatchSize sets the number of messages delivered per transaction
+func (j *OutboxRelayJob) WithBatchSize(size int) *OutboxRelayJob {
+	if size > 0 {
+		j.batchSize = size
+	}
+	return j
+}
+
+// WithMaxAttempts sets the number of failed deliveries after which a message is parked
+func (j *OutboxRelayJob) WithMaxAttempts(attempts int) *OutboxRelayJob {
+	if attempts > 0 {
+		j.maxAttempts = attempts
+	}
+	return j
+}
+
+// WithDeadLetterSink sets the sink receiving the events of parked messages
+func (j *OutboxRelayJob) WithDeadLetterSink(sink event.DeadLetterSink) *OutboxRelayJob {
+	j.deadLetter = sink
+	return j
+}
+
+// Name returns the job name
+func (j *OutboxRelayJob) Name() string {
+	return "outbox_relay"
+}
+
+// Run drains pending messages batch by batch until none are left. A batch with failed deliveries
+// ends the run after the rest of it was delivered, the failed messages are retried by the next run.
+func (j *OutboxRelayJob) Run(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		fetched, err := j.relayBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if fetched < j.batchSize {
+			return nil
+		}
+	}
+}
+
+// relayBatch delivers one batch inside a transaction holding the row locks, so concurrent relays
+// skip these messages, and returns the number of messages fetched. A failed delivery holds back the
+// later messages of its aggregate to keep their recorded order, the other messages are delivered.
+func (j *OutboxRelayJob) relayBatch(ctx context.Context) (int, error) {
+	tx, err := j.txFactory.NewTransaction(ctx, j.store, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create outbox transaction: %w", err)
+	}
+	if err := tx.Begin(); err != nil {
+		return 0, fmt.Errorf("failed to begin outbox transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// Only the outbox works in the relay transaction, handlers of the published events do not join it
+	txCtx := repo.ContextWithTransaction(ctx, tx)
+	messages, err := j.outbox.FetchPending(txCtx, tx, j.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch outbox messages: %w", err)
+	}
+
+	delivered := 0
+	var failures []error
+	held := make(map[string]bool)
+	for _, message := range messages {
+		if held[message.AggregateID] {
+			continue
+		}
+
+		evt, publishErr := message.Event()
+		if publishErr == nil {
+			publishErr = j.bus.Publish(ctx, evt)
+		}
+		if publishErr != nil {
+			held[message.AggregateID] = true
+			failures = append(failures, fmt.Errorf("failed to deliver outbox message %d: %w", message.ID, publishErr))
+			if err := j.recordFailure(ctx, txCtx, tx, message, evt, publishErr); err != nil {
+				return len(messages), err
+			}
+			continue
+		}
+
+		if err := j.outbox.MarkPublished(txCtx, tx, message.ID); err != nil {
+			return len(messages), fmt.Errorf("failed to mark outbox message %d published: %w", message.ID, err)
+		}
+		delivered++
+	}
+
+	// Messages published before a failed commit are delivered again by the next run
+	if err := tx.Commit(); err != nil {
+		return len(messages), fmt.Errorf("failed to commit outbox transaction: %w", err)
+	}
+	committed = true
+
+	if delivered > 0 {
+		log.Logger.Debug("Outbox messages delivered",
+			zap.String("job", j.Name()),
+			zap.Int("count", delivered),
+		)
+	}
+
+	return len(messages), errors.Join(failures...)
+}
+
+// recordFailure counts a failed delivery of a message. Once the message failed max attempts times
+// it is parked, after its event, if it could be decoded, was handed to the dead letter sink.
+func (j *OutboxRelayJob) recordFailure(ctx, txCtx context.Context, tx repo.Transaction, message *repo.OutboxMessage, evt event.Event, cause error) error {
+	attempts := message.Attempts + 1
+	if attempts < j.maxAttempts {
+		if err := j.outbox.MarkFailed(txCtx, tx, message.ID, cause.Error()); err != nil {
+			return fmt.Errorf("failed to record outbox delivery failure: %w", err)
+		}
+		return nil
+	}
+
+	if j.deadLetter != nil && evt != nil {
+		if err := j.deadLetter.Add(ctx, event.NewDeadLetter(evt, j.Name(), attempts, cause)); err != nil {
+			return fmt.Errorf("failed to dead-letter outbox message %d: %w", message.ID, err)
+		}
+	}
+	if err := j.outbox.Park(txCtx, tx, message.ID, cause.Error()); err != nil {
+		return fmt.Errorf("failed to park outbox message %d: %w", message.ID, err)
+	}
+
+	log.Logger.Warn("Outbox message parked after failed deliveries",
+		zap.String("job", j.Name()),
+		zap.Int64("message_id", message.ID),
+		zap.String("event_id", message.EventID),
+		zap.Int("attempts", attempts),
+		zap.Error(cause),
+	)
+	return nil
+}
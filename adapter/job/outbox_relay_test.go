@@ -0,0 +1,167 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go-hexagonal/domain/event"
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/util/log"
+)
+
+// memoryOutbox keeps outbox messages in memory
+type memoryOutbox struct {
+	messages []*repo.OutboxMessage
+	fetches  int
+}
+
+func (o *memoryOutbox) Save(ctx context.Context, tr repo.Transaction, messages ...*repo.OutboxMessage) error {
+	for _, message := range messages {
+		message.ID = int64(len(o.messages) + 1)
+		o.messages = append(o.messages, message)
+	}
+	return nil
+}
+
+func (o *memoryOutbox) FetchPending(ctx context.Context, tr repo.Transaction, limit int) ([]*repo.OutboxMessage, error) {
+	o.fetches++
+	var pending []*repo.OutboxMessage
+	for _, message := range o.messages {
+		if message.PublishedAt == nil && message.ParkedAt == nil && len(pending) < limit {
+			pending = append(pending, message)
+		}
+	}
+	return pending, nil
+}
+
+func (o *memoryOutbox) MarkPublished(ctx context.Context, tr repo.Transaction, id int64) error {
+	now := o.messages[id-1].OccurredAt
+	o.messages[id-1].PublishedAt = &now
+	return nil
+}
+
+func (o *memoryOutbox) MarkFailed(ctx context.Context, tr repo.Transaction, id int64, reason string) error {
+	o.messages[id-1].Attempts++
+	o.messages[id-1].LastError = reason
+	return nil
+}
+
+func (o *memoryOutbox) Park(ctx context.Context, tr repo.Transaction, id int64, reason string) error {
+	now := o.messages[id-1].OccurredAt
+	o.messages[id-1].ParkedAt = &now
+	return o.MarkFailed(ctx, tr, id, reason)
+}
+
+// recordingBus records published events and fails on the configured event ID
+type recordingBus struct {
+	event.NoopEventBus
+	published []event.Event
+	failOn    string
+	// inTransaction counts events published with a transaction in their context
+	inTransaction int
+}
+
+func (b *recordingBus) Publish(ctx context.Context, evt event.Event) error {
+	if _, ok := repo.TransactionFromContext(ctx); ok {
+		b.inTransaction++
+	}
+	if evt.EventID() == b.failOn {
+		return errors.New("broker unavailable")
+	}
+	b.published = append(b.published, evt)
+	return nil
+}
+
+// newTestOutbox records an update of a distinct example per message, or of the given examples
+func newTestOutbox(t *testing.T, count int, exampleIDs ...int) *memoryOutbox {
+	log.Logger = zap.NewNop()
+	outbox := &memoryOutbox{}
+	for i := 1; i <= count; i++ {
+		id := i
+		if len(exampleIDs) >= i {
+			id = exampleIDs[i-1]
+		}
+		message, err := repo.NewOutboxMessage(event.NewExampleUpdatedEvent(id, "name", "alias"))
+		require.NoError(t, err)
+		require.NoError(t, outbox.Save(context.Background(), nil, message))
+	}
+	return outbox
+}
+
+func TestOutboxRelayJob_DeliversInBatches(t *testing.T) {
+	outbox := newTestOutbox(t, 5)
+	bus := &recordingBus{}
+	relay := NewOutboxRelayJob(outbox, bus, repo.NewNoOpTransactionFactory(), repo.MySQLStore).WithBatchSize(2)
+
+	require.NoError(t, relay.Run(context.Background()))
+
+	require.Len(t, bus.published, 5)
+	for i, evt := range bus.published {
+		// Events keep their recorded IDs and order so consumers can deduplicate
+		assert.Equal(t, outbox.messages[i].EventID, evt.EventID())
+		assert.NotNil(t, outbox.messages[i].PublishedAt)
+	}
+	assert.Equal(t, 3, outbox.fetches)
+}
+
+func TestOutboxRelayJob_DeliversPastFailedDelivery(t *testing.T) {
+	outbox := newTestOutbox(t, 4, 1, 2, 3, 2)
+	bus := &recordingBus{failOn: outbox.messages[1].EventID}
+	relay := NewOutboxRelayJob(outbox, bus, repo.NewNoOpTransactionFactory(), repo.MySQLStore)
+
+	err := relay.Run(context.Background())
+
+	assert.Error(t, err)
+	require.Len(t, bus.published, 2)
+	assert.NotNil(t, outbox.messages[0].PublishedAt)
+	assert.Nil(t, outbox.messages[1].PublishedAt)
+	assert.Equal(t, 1, outbox.messages[1].Attempts)
+	assert.Contains(t, outbox.messages[1].LastError, "broker unavailable")
+	// Messages of other aggregates are delivered, later ones of the failing aggregate wait to keep their order
+	assert.NotNil(t, outbox.messages[2].PublishedAt)
+	assert.Nil(t, outbox.messages[3].PublishedAt)
+	assert.Equal(t, 0, outbox.messages[3].Attempts)
+
+	// The next run retries the failed message
+	bus.failOn = ""
+	require.NoError(t, relay.Run(context.Background()))
+	assert.Len(t, bus.published, 4)
+	assert.Equal(t, outbox.messages[1].EventID, bus.published[2].EventID())
+
+	// Handlers of the published events do not run in the relay transaction
+	assert.Zero(t, bus.inTransaction)
+}
+
+func TestOutboxRelayJob_ParksPoisonMessage(t *testing.T) {
+	outbox := newTestOutbox(t, 2)
+	bus := &recordingBus{failOn: outbox.messages[0].EventID}
+	deadLetters := event.NewInMemoryDeadLetterStore()
+	relay := NewOutboxRelayJob(outbox, bus, repo.NewNoOpTransactionFactory(), repo.MySQLStore).
+		WithMaxAttempts(2).
+		WithDeadLetterSink(deadLetters)
+	ctx := context.Background()
+
+	assert.Error(t, relay.Run(ctx))
+	assert.Nil(t, outbox.messages[0].ParkedAt)
+
+	// The last allowed attempt parks the message and dead-letters its event
+	assert.Error(t, relay.Run(ctx))
+	assert.NotNil(t, outbox.messages[0].ParkedAt)
+	assert.Equal(t, 2, outbox.messages[0].Attempts)
+	letters, err := deadLetters.List(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, outbox.messages[0].EventID, letters[0].Event.EventID())
+	assert.Equal(t, relay.Name(), letters[0].Handler)
+	assert.Equal(t, 2, letters[0].Attempts)
+
+	// A parked message is no longer fetched, so it does not hold up the outbox
+	require.NoError(t, relay.Run(ctx))
+	require.Len(t, bus.published, 1)
+	assert.Equal(t, outbox.messages[1].EventID, bus.published[0].EventID())
+}
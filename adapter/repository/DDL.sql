@@ -1,3 +1,8 @@
+-- Creates the schema of a new database. Existing databases are upgraded with the migrations in
+-- ./migrations, e.g. migrate -path migrations/mysql -database "mysql://...?multiStatements=true" up
+-- Migration 000003 needs the name keys backfilled first: migrate to version 2, run
+-- `go-hexagonal backfill-name-keys --store mysql` (or postgres), then migrate up.
+
 CREATE DATABASE `go-hexagonal`;
 
 USE `go-hexagonal`;
@@ -7,11 +12,116 @@ DROP TABLE IF EXISTS `example`;
 CREATE TABLE `example` (
     `id` INT(11) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',
     `name` VARCHAR(255) NOT NULL COMMENT 'Name',
+    `name_key` VARCHAR(255) COLLATE utf8mb4_bin NOT NULL COMMENT 'Case-folded, NFKC-normalized name',
     `alias` VARCHAR(255) DEFAULT NULL COMMENT 'Alias',
     `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Creation time',
     `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Update time',
     `deleted_at` TIMESTAMP NULL DEFAULT NULL COMMENT 'Deletion time',
+    `version` INT(11) UNSIGNED NOT NULL DEFAULT 1 COMMENT 'Optimistic locking version',
+    `live_name_key` VARCHAR(255) COLLATE utf8mb4_bin GENERATED ALWAYS AS (IF(`deleted_at` IS NULL, `name_key`, NULL)) VIRTUAL COMMENT 'Name key of rows that are not soft deleted',
     PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_live_name_key` (`live_name_key`),
     KEY `idx_name` (`name`),
-    KEY `idx_deleted_at` (`deleted_at`)
+    KEY `idx_deleted_at` (`deleted_at`),
+    KEY `idx_created_at_id` (`created_at`, `id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Hexagonal example table';
+
+DROP TABLE IF EXISTS `outbox`;
+
+CREATE TABLE `outbox` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',
+    `event_id` VARCHAR(64) NOT NULL COMMENT 'Event ID, used by consumers for deduplication',
+    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',
+    `aggregate_id` VARCHAR(64) NOT NULL COMMENT 'Aggregate ID',
+    `schema_version` INT NOT NULL DEFAULT 1 COMMENT 'Schema version of the payload',
+    `correlation_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'ID shared by everything done for the originating request',
+    `causation_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'ID of the request or event that caused the event',
+    `payload` JSON NOT NULL COMMENT 'Event payload',
+    `occurred_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event occurred',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Creation time',
+    `published_at` TIMESTAMP NULL DEFAULT NULL COMMENT 'Delivery time, NULL while pending',
+    `parked_at` TIMESTAMP NULL DEFAULT NULL COMMENT 'Time delivery was given up after too many failed attempts',
+    `attempts` INT(11) UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Failed delivery attempts',
+    `last_error` VARCHAR(1024) DEFAULT NULL COMMENT 'Last delivery error',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_event_id` (`event_id`),
+    KEY `idx_published_at_id` (`published_at`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Domain events awaiting delivery';
+
+DROP TABLE IF EXISTS `event_store`;
+
+CREATE TABLE `event_store` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',
+    `event_id` VARCHAR(64) NOT NULL COMMENT 'Event ID',
+    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',
+    `aggregate_id` VARCHAR(64) NOT NULL COMMENT 'Aggregate ID',
+    `schema_version` INT NOT NULL DEFAULT 1 COMMENT 'Schema version of the payload',
+    `correlation_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'ID shared by everything done for the originating request',
+    `causation_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'ID of the request or event that caused the event',
+    `payload` JSON NOT NULL COMMENT 'Event payload',
+    `occurred_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event occurred',
+    `processed_at` TIMESTAMP(6) NULL DEFAULT NULL COMMENT 'Time the handlers processed the event',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Creation time',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_event_id` (`event_id`),
+    KEY `idx_occurred_at` (`occurred_at`),
+    KEY `idx_event_name_occurred_at` (`event_name`, `occurred_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Published domain events kept for replay';
+
+DROP TABLE IF EXISTS `dead_letter`;
+
+CREATE TABLE `dead_letter` (
+    `id` VARCHAR(64) NOT NULL COMMENT 'Dead letter ID',
+    `event_id` VARCHAR(64) NOT NULL COMMENT 'Event ID',
+    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',
+    `aggregate_id` VARCHAR(64) NOT NULL COMMENT 'Aggregate ID',
+    `schema_version` INT NOT NULL DEFAULT 1 COMMENT 'Schema version of the payload',
+    `payload` JSON NOT NULL COMMENT 'Event payload',
+    `occurred_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event occurred',
+    `handler` VARCHAR(255) NOT NULL COMMENT 'Name of the failing handler',
+    `error` VARCHAR(1024) NOT NULL COMMENT 'Last handler error',
+    `attempts` INT(11) UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Handler attempts',
+    `failed_at` TIMESTAMP(6) NOT NULL COMMENT 'Time of the last failure',
+    PRIMARY KEY (`id`),
+    KEY `idx_failed_at` (`failed_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Events handlers gave up on after all retries';
+
+DROP TABLE IF EXISTS `event_stream`;
+
+CREATE TABLE `event_stream` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',
+    `stream_id` VARCHAR(128) NOT NULL COMMENT 'Stream ID, the aggregate type and ID',
+    `version` INT UNSIGNED NOT NULL COMMENT 'Position of the event in its stream, starting at 1',
+    `event_id` VARCHAR(64) NOT NULL COMMENT 'Event ID',
+    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',
+    `schema_version` INT NOT NULL DEFAULT 1 COMMENT 'Schema version of the payload',
+    `payload` JSON NOT NULL COMMENT 'Event payload',
+    `occurred_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event occurred',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Creation time',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_stream_id_version` (`stream_id`, `version`),
+    UNIQUE KEY `uk_event_id` (`event_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Append-only event streams of event-sourced aggregates';
+
+DROP TABLE IF EXISTS `stream_snapshot`;
+
+CREATE TABLE `stream_snapshot` (
+    `stream_id` VARCHAR(128) NOT NULL COMMENT 'Stream ID',
+    `version` INT UNSIGNED NOT NULL COMMENT 'Stream version the state was taken at',
+    `state` JSON NOT NULL COMMENT 'Aggregate state',
+    `created_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the snapshot was taken',
+    PRIMARY KEY (`stream_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Latest snapshot of each event stream';
+
+DROP TABLE IF EXISTS `inbox`;
+
+CREATE TABLE `inbox` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',
+    `consumer` VARCHAR(255) NOT NULL COMMENT 'Name of the handler that processed the event',
+    `event_id` VARCHAR(64) NOT NULL COMMENT 'ID of the processed event',
+    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',
+    `processed_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event was processed',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_consumer_event_id` (`consumer`, `event_id`),
+    KEY `idx_processed_at` (`processed_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Events processed by each consumer, for idempotent handling';
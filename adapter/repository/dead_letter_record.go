@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"go-hexagonal/domain/event"
+)
+
+// DeadLetterRecord is the persisted form of a dead letter kept by the SQL dead letter stores
+type DeadLetterRecord struct {
+	ID          string
+	EventID     string
+	EventName   string
+	AggregateID string
+	// SchemaVersion is the schema version of the payload
+	SchemaVersion int
+	// Payload is the JSON encoded event payload
+	Payload    string
+	OccurredAt time.Time
+	Handler    string
+	Error      string
+	Attempts   int
+	FailedAt   time.Time
+}
+
+// TableName returns the table name for dead letters
+func (DeadLetterRecord) TableName() string {
+	return "dead_letter"
+}
+
+// NewDeadLetterRecord converts a dead letter to its persisted form
+func NewDeadLetterRecord(letter *event.DeadLetter) (*DeadLetterRecord, error) {
+	payload, err := event.MarshalPayload(letter.Event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeadLetterRecord{
+		ID:            letter.ID,
+		EventID:       letter.Event.EventID(),
+		EventName:     letter.Event.EventName(),
+		AggregateID:   letter.Event.AggregateID(),
+		SchemaVersion: event.SchemaVersionOf(letter.Event),
+		Payload:       string(payload),
+		OccurredAt:    letter.Event.OccurredAt(),
+		Handler:       letter.Handler,
+		Error:         letter.Error,
+		Attempts:      letter.Attempts,
+		FailedAt:      letter.FailedAt,
+	}, nil
+}
+
+// DeadLetter rebuilds the dead letter with the event type registered for its name, upcast to the current schema version
+func (r *DeadLetterRecord) DeadLetter(registry *event.TypeRegistry) (*event.DeadLetter, error) {
+	evt, err := registry.Decode(event.BaseEvent{
+		ID:         r.EventID,
+		Name:       r.EventName,
+		Aggregate:  r.AggregateID,
+		OccurredOn: r.OccurredAt,
+		Version:    r.SchemaVersion,
+	}, []byte(r.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return &event.DeadLetter{
+		ID:       r.ID,
+		Event:    evt,
+		Handler:  r.Handler,
+		Error:    r.Error,
+		Attempts: r.Attempts,
+		FailedAt: r.FailedAt,
+	}, nil
+}
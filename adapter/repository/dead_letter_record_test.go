@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/event"
+)
+
+func TestDeadLetterRecord_RoundTrip(t *testing.T) {
+	original := event.NewDeadLetter(event.NewExampleDeletedEvent(5), "handler", 3, errors.New("boom"))
+
+	record, err := NewDeadLetterRecord(original)
+	require.NoError(t, err)
+	assert.Equal(t, original.ID, record.ID)
+	assert.JSONEq(t, `{"id":5}`, record.Payload)
+
+	letter, err := record.DeadLetter(event.DefaultTypeRegistry)
+	require.NoError(t, err)
+	assert.Equal(t, original.ID, letter.ID)
+	assert.Equal(t, "handler", letter.Handler)
+	assert.Equal(t, "boom", letter.Error)
+	assert.Equal(t, 3, letter.Attempts)
+
+	// The event is rebuilt with its concrete type
+	deleted, ok := letter.Event.(event.ExampleDeletedEvent)
+	require.True(t, ok)
+	assert.Equal(t, original.Event.EventID(), deleted.EventID())
+	assert.Equal(t, event.ExampleDeletedPayload{ID: 5}, deleted.Payload)
+}
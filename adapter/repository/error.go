@@ -16,6 +16,9 @@ var (
 	// ErrMissingPostgreSQLConfig is returned when PostgreSQL configuration is missing
 	ErrMissingPostgreSQLConfig = RepositoryError("PostgreSQL configuration is missing")
 
+	// ErrMissingSQLiteConfig is returned when SQLite configuration is missing
+	ErrMissingSQLiteConfig = RepositoryError("SQLite configuration is missing")
+
 	// ErrMissingRedisConfig is returned when Redis configuration is missing
 	ErrMissingRedisConfig = RepositoryError("Redis configuration is missing")
 
@@ -27,4 +30,13 @@ var (
 
 	// ErrUnsupportedStoreType is returned when using an unsupported store type
 	ErrUnsupportedStoreType = RepositoryError("unsupported store type")
+
+	// ErrUnsupportedPersistenceDriver is returned when persistence.driver names no known store
+	ErrUnsupportedPersistenceDriver = RepositoryError("unsupported persistence driver")
+
+	// ErrEventSourcingUnsupported is returned when event sourcing is enabled over a store without event streams
+	ErrEventSourcingUnsupported = RepositoryError("event sourcing requires the mysql or postgres persistence driver")
+
+	// ErrNoExampleStore is returned when no store to keep the examples in is initialized
+	ErrNoExampleStore = RepositoryError("no store is initialized for the examples")
 )
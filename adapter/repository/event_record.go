@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"time"
+
+	"go-hexagonal/domain/event"
+)
+
+// EventRecord is the persisted form of an event kept by the SQL event stores
+type EventRecord struct {
+	ID          int64
+	EventID     string
+	EventName   string
+	AggregateID string
+	// SchemaVersion is the schema version of the payload
+	SchemaVersion int
+	// CorrelationID and CausationID trace the event back to what caused it
+	CorrelationID string
+	CausationID   string
+	// Payload is the JSON encoded event payload
+	Payload    string
+	OccurredAt time.Time
+	// ProcessedAt is set once the handlers have processed the event
+	ProcessedAt *time.Time
+	CreatedAt   time.Time
+}
+
+// TableName returns the table name for stored events
+func (EventRecord) TableName() string {
+	return "event_store"
+}
+
+// NewEventRecord converts an event to its persisted form
+func NewEventRecord(evt event.Event) (*EventRecord, error) {
+	payload, err := event.MarshalPayload(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &EventRecord{
+		EventID:       evt.EventID(),
+		EventName:     evt.EventName(),
+		AggregateID:   evt.AggregateID(),
+		SchemaVersion: event.SchemaVersionOf(evt),
+		Payload:       string(payload),
+		OccurredAt:    evt.OccurredAt(),
+		CreatedAt:     time.Now(),
+	}
+	if traceable, ok := evt.(event.Traceable); ok {
+		record.CorrelationID = traceable.CorrelationID()
+		record.CausationID = traceable.CausationID()
+	}
+
+	return record, nil
+}
+
+// Event rebuilds the stored event with the type registered for its name, upcast to the current schema version
+func (r *EventRecord) Event(registry *event.TypeRegistry) (event.Event, error) {
+	return registry.Decode(event.BaseEvent{
+		ID:          r.EventID,
+		Name:        r.EventName,
+		Aggregate:   r.AggregateID,
+		OccurredOn:  r.OccurredAt,
+		Version:     r.SchemaVersion,
+		Correlation: r.CorrelationID,
+		Causation:   r.CausationID,
+	}, []byte(r.Payload))
+}
+
+// DecodeEventRecords rebuilds stored events in order
+func DecodeEventRecords(records []*EventRecord, registry *event.TypeRegistry) ([]event.Event, error) {
+	events := make([]event.Event, 0, len(records))
+	for _, record := range records {
+		evt, err := record.Event(registry)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, evt)
+	}
+
+	return events, nil
+}
+
+// DecodeStoredEvents rebuilds stored events in order, with their record IDs as sequences
+func DecodeStoredEvents(records []*EventRecord, registry *event.TypeRegistry) ([]event.StoredEvent, error) {
+	events := make([]event.StoredEvent, 0, len(records))
+	for _, record := range records {
+		evt, err := record.Event(registry)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event.StoredEvent{Sequence: record.ID, StoredAt: record.CreatedAt, Event: evt})
+	}
+
+	return events, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/event"
+)
+
+func TestEventRecord_RoundTrip(t *testing.T) {
+	original := event.NewExampleCreatedEvent(3, "name", "alias")
+
+	record, err := NewEventRecord(original)
+	require.NoError(t, err)
+	assert.Equal(t, original.EventID(), record.EventID)
+	assert.Equal(t, event.ExampleCreatedEventName, record.EventName)
+	assert.JSONEq(t, `{"id":3,"name":"name","alias":"alias"}`, record.Payload)
+
+	events, err := DecodeEventRecords([]*EventRecord{record}, event.DefaultTypeRegistry)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	// The stored event is rebuilt with its concrete type
+	created, ok := events[0].(event.ExampleCreatedEvent)
+	require.True(t, ok)
+	assert.Equal(t, original.EventID(), created.EventID())
+	assert.True(t, original.OccurredAt().Equal(created.OccurredAt()))
+	assert.Equal(t, event.ExampleCreatedPayload{ID: 3, Name: "name", Alias: "alias"}, created.Payload)
+}
+
+func TestEventRecord_UpcastsOlderVersion(t *testing.T) {
+	type nameV1 struct {
+		Name string `json:"name"`
+	}
+
+	// Version 2 of the created event added the alias
+	registry := event.NewTypeRegistry()
+	event.RegisterEventVersion[event.ExampleCreatedPayload](registry, event.ExampleCreatedEventName, 2, func(base event.BaseEvent) event.Event {
+		return event.ExampleCreatedEvent{BaseEvent: base}
+	})
+	event.RegisterUpcaster(registry, event.ExampleCreatedEventName, 1, func(old nameV1) (event.ExampleCreatedPayload, error) {
+		return event.ExampleCreatedPayload{Name: old.Name, Alias: old.Name}, nil
+	})
+
+	record := &EventRecord{
+		EventID:       "1",
+		EventName:     event.ExampleCreatedEventName,
+		AggregateID:   "3",
+		SchemaVersion: 1,
+		Payload:       `{"name":"name"}`,
+	}
+
+	evt, err := record.Event(registry)
+	require.NoError(t, err)
+	created, ok := evt.(event.ExampleCreatedEvent)
+	require.True(t, ok)
+	assert.Equal(t, 2, created.SchemaVersion())
+	assert.Equal(t, event.ExampleCreatedPayload{Name: "name", Alias: "name"}, created.Payload)
+}
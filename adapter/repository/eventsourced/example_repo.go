@@ -0,0 +1,642 @@
+// Package eventsourced provides repositories that keep aggregates as append-only event streams
+// and rebuild them on load, instead of storing their current state
+package eventsourced
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/domain/event"
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+// DefaultSnapshotInterval is the number of events between snapshots when none is configured
+const DefaultSnapshotInterval = 50
+
+// exampleStreamPrefix prefixes the stream IDs of examples, followed by the example ID
+const exampleStreamPrefix = "example-"
+
+// examplePurgedEvent is the tombstone closing the stream of a purged example, the stream is kept
+// but no longer holds an example
+const examplePurgedEvent = "example.purged"
+
+// Register streams hold a single example ID, the current value is kept in their snapshot. Their IDs
+// must not start with exampleStreamPrefix, listings would take them for examples.
+const (
+	// exampleNameStreamPrefix prefixes the streams reserving a name key for the live example using
+	// it, followed by the hash of the key. Claims append at the version read, so of two concurrent
+	// claims of a name only one is stored.
+	exampleNameStreamPrefix = "example_name-"
+	// exampleSequenceStreamID is the stream allocating example IDs, its value is the last ID handed
+	// out, so IDs of purged examples are never reused
+	exampleSequenceStreamID = "example_sequence"
+
+	exampleNameClaimedEvent  = "example.name_claimed"
+	exampleNameReleasedEvent = "example.name_released"
+	exampleIDAllocatedEvent  = "example.id_allocated"
+)
+
+// maxCreateAttempts bounds the retries of a create whose ID was taken by a concurrent create
+const maxCreateAttempts = 5
+
+// Ensure ExampleRepo implements repo.IExampleRepo
+var _ repo.IExampleRepo = (*ExampleRepo)(nil)
+
+// ExampleRepo implements the example repository on top of event streams. Every change is appended
+// to the example's stream as its integration event, the stream version is the example's version.
+// Examples are rebuilt from their latest snapshot and the events that follow it.
+//
+// There is no read table, listings and counts rebuild every stored example, so their cost grows
+// with the number of streams. ListWithTotal serves a page and its total from a single rebuild.
+type ExampleRepo struct {
+	streams          repo.IEventStreamRepo
+	registry         *event.TypeRegistry
+	snapshotInterval int
+}
+
+// NewExampleRepo creates an event-sourced example repository. A snapshot is taken every
+// snapshotInterval events, 0 uses DefaultSnapshotInterval and a negative interval disables snapshots.
+func NewExampleRepo(streams repo.IEventStreamRepo, snapshotInterval int) repo.IExampleRepo {
+	if snapshotInterval == 0 {
+		snapshotInterval = DefaultSnapshotInterval
+	}
+
+	return &ExampleRepo{
+		streams:          streams,
+		registry:         event.DefaultTypeRegistry,
+		snapshotInterval: snapshotInterval,
+	}
+}
+
+// exampleSnapshot is the state of an example kept in snapshots
+type exampleSnapshot struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Alias     string     `json:"alias"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// registerState is the value of a register stream kept in its snapshot
+type registerState struct {
+	ExampleID int `json:"example_id"`
+}
+
+// Create starts the stream of a new example under the next ID of the sequence, reserving its name
+// in the same transaction
+func (r *ExampleRepo) Create(ctx context.Context, tr repo.Transaction, example *model.Example) (*model.Example, error) {
+	nameStreamID := exampleNameStreamID(model.NormalizeExampleName(example.Name))
+	holder, nameVersion, err := r.readRegister(ctx, tr, nameStreamID)
+	if err != nil {
+		return nil, err
+	}
+	if holder != 0 {
+		return nil, model.NewExampleNameTakenError(example.Name)
+	}
+
+	id, err := r.allocateID(ctx, tr)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeName(ctx, tr, nameStreamID, nameVersion, example.Name, id); err != nil {
+		return nil, err
+	}
+
+	state, err := r.append(ctx, tr, nil, event.NewExampleCreatedEvent(id, example.Name, example.Alias))
+	if err != nil {
+		return nil, err
+	}
+
+	example.Id = state.Id
+	example.NameKey = state.NameKey
+	example.CreatedAt = state.CreatedAt
+	example.UpdatedAt = state.UpdatedAt
+	example.Version = state.Version
+	return example, nil
+}
+
+// Update appends the example's new name and alias if its version still matches the stream.
+// On success the example's version is advanced to the stream version.
+func (r *ExampleRepo) Update(ctx context.Context, tr repo.Transaction, example *model.Example) error {
+	current, err := r.GetByID(ctx, tr, example.Id)
+	if err != nil {
+		return err
+	}
+	if current.Version != example.Version {
+		return model.ErrExampleModified
+	}
+
+	// Only a rename can collide with another example, it moves the reservation to the new name
+	if model.NormalizeExampleName(example.Name) != current.NameKey {
+		if err := r.claimName(ctx, tr, example.Name, example.Id); err != nil {
+			return err
+		}
+		if err := r.releaseName(ctx, tr, current.NameKey, example.Id); err != nil {
+			return err
+		}
+	}
+
+	state, err := r.append(ctx, tr, current, event.NewExampleUpdatedEvent(example.Id, example.Name, example.Alias))
+	if err != nil {
+		return modifiedOnConflict(err)
+	}
+
+	example.NameKey = state.NameKey
+	example.UpdatedAt = state.UpdatedAt
+	example.Version = state.Version
+	return nil
+}
+
+// Delete appends the deletion of a live example and frees its name
+func (r *ExampleRepo) Delete(ctx context.Context, tr repo.Transaction, id int) error {
+	current, err := r.GetByID(ctx, tr, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.append(ctx, tr, current, event.NewExampleDeletedEvent(id)); err != nil {
+		return modifiedOnConflict(err)
+	}
+	return r.releaseName(ctx, tr, current.NameKey, id)
+}
+
+// Restore appends the restoration of a soft deleted example
+func (r *ExampleRepo) Restore(ctx context.Context, tr repo.Transaction, id int) error {
+	current, err := r.GetByIDWithDeleted(ctx, tr, id)
+	if err != nil {
+		return err
+	}
+	if !current.IsDeleted() {
+		return repo.ErrNotFound
+	}
+
+	// A live example may have taken the name while this one was deleted
+	if err := r.claimName(ctx, tr, current.Name, id); err != nil {
+		return err
+	}
+
+	_, err = r.append(ctx, tr, current, event.NewExampleRestoredEvent(id))
+	return modifiedOnConflict(err)
+}
+
+// Purge closes the stream of an example with a tombstone and frees the name of a live one.
+// The stream stays in the log, its ID stays allocated.
+func (r *ExampleRepo) Purge(ctx context.Context, tr repo.Transaction, id int) error {
+	current, err := r.GetByIDWithDeleted(ctx, tr, id)
+	if err != nil {
+		return err
+	}
+	if !current.IsDeleted() {
+		if err := r.releaseName(ctx, tr, current.NameKey, id); err != nil {
+			return err
+		}
+	}
+
+	streamID := exampleStreamID(id)
+	tombstone, err := repo.NewStreamEvent(streamID, event.NewBaseEvent(examplePurgedEvent, streamID, registerState{ExampleID: id}))
+	if err != nil {
+		return err
+	}
+
+	return modifiedOnConflict(r.streams.Append(ctx, tr, streamID, current.Version, tombstone))
+}
+
+// GetByID rebuilds a live example
+func (r *ExampleRepo) GetByID(ctx context.Context, tr repo.Transaction, id int) (*model.Example, error) {
+	example, err := r.GetByIDWithDeleted(ctx, tr, id)
+	if err != nil {
+		return nil, err
+	}
+	if example.IsDeleted() {
+		return nil, repo.ErrNotFound
+	}
+
+	return example, nil
+}
+
+// GetByIDWithDeleted rebuilds an example even if it is soft deleted
+func (r *ExampleRepo) GetByIDWithDeleted(ctx context.Context, tr repo.Transaction, id int) (*model.Example, error) {
+	return r.load(ctx, tr, exampleStreamID(id))
+}
+
+// FindByName returns the live example holding the name, ignoring case and Unicode representation
+func (r *ExampleRepo) FindByName(ctx context.Context, tr repo.Transaction, name string) (*model.Example, error) {
+	holder, _, err := r.readRegister(ctx, tr, exampleNameStreamID(model.NormalizeExampleName(name)))
+	if err != nil {
+		return nil, err
+	}
+	if holder == 0 {
+		return nil, repo.ErrNotFound
+	}
+
+	return r.GetByID(ctx, tr, holder)
+}
+
+// ListWithTotal rebuilds all examples once and returns the page matching the query together with
+// the number of examples matching its filter
+func (r *ExampleRepo) ListWithTotal(ctx context.Context, tr repo.Transaction, query repo.ExampleListQuery) ([]*model.Example, int64, error) {
+	examples, err := r.loadAll(ctx, tr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := int64(len(repository.FilterExamples(examples, query.Filter)))
+	return repository.ListExamples(examples, query), total, nil
+}
+
+// List rebuilds all examples and returns the page matching the query
+func (r *ExampleRepo) List(ctx context.Context, tr repo.Transaction, query repo.ExampleListQuery) ([]*model.Example, error) {
+	examples, err := r.loadAll(ctx, tr)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.ListExamples(examples, query), nil
+}
+
+// Count returns the number of examples matching the filter
+func (r *ExampleRepo) Count(ctx context.Context, tr repo.Transaction, filter repo.ExampleFilter) (int64, error) {
+	examples, err := r.loadAll(ctx, tr)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(repository.FilterExamples(examples, filter))), nil
+}
+
+// ListByCursor rebuilds all examples and returns the page following the cursor
+func (r *ExampleRepo) ListByCursor(ctx context.Context, tr repo.Transaction, query repo.ExampleKeysetQuery) ([]*model.Example, error) {
+	examples, err := r.loadAll(ctx, tr)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.ListExamplesByCursor(examples, query), nil
+}
+
+// append adds an event to the stream of current, nil for a new example, and returns the state
+// after it. A snapshot is taken whenever the new version is a multiple of the snapshot interval.
+func (r *ExampleRepo) append(ctx context.Context, tr repo.Transaction, current *model.Example, evt event.Event) (*model.Example, error) {
+	expectedVersion := 0
+	if current != nil {
+		expectedVersion = current.Version
+	}
+
+	state, err := apply(current, evt, expectedVersion+1)
+	if err != nil {
+		return nil, err
+	}
+
+	streamID := exampleStreamID(state.Id)
+	streamEvent, err := repo.NewStreamEvent(streamID, evt)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.streams.Append(ctx, tr, streamID, expectedVersion, streamEvent); err != nil {
+		return nil, err
+	}
+
+	if r.snapshotInterval > 0 && state.Version%r.snapshotInterval == 0 {
+		if err := r.saveSnapshot(ctx, tr, streamID, state); err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+// load rebuilds the example of a stream from its snapshot and the events after it, a purged
+// example is not found
+func (r *ExampleRepo) load(ctx context.Context, tr repo.Transaction, streamID string) (*model.Example, error) {
+	var example *model.Example
+
+	snapshot, err := r.streams.LoadSnapshot(ctx, tr, streamID)
+	switch {
+	case err == nil:
+		if example, err = decodeSnapshot(snapshot); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, repo.ErrNotFound):
+		return nil, err
+	}
+
+	afterVersion := 0
+	if example != nil {
+		afterVersion = example.Version
+	}
+
+	events, err := r.streams.Load(ctx, tr, streamID, afterVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, streamEvent := range events {
+		if streamEvent.EventName == examplePurgedEvent {
+			return nil, repo.ErrNotFound
+		}
+		evt, err := streamEvent.Event(r.registry)
+		if err != nil {
+			return nil, err
+		}
+		if example, err = apply(example, evt, streamEvent.Version); err != nil {
+			return nil, fmt.Errorf("failed to apply event %s of stream %s: %w", streamEvent.EventID, streamID, err)
+		}
+	}
+
+	if example == nil {
+		return nil, repo.ErrNotFound
+	}
+
+	return example, nil
+}
+
+// loadAll rebuilds every example, soft deleted ones included. It replays every example stream from
+// its latest snapshot.
+func (r *ExampleRepo) loadAll(ctx context.Context, tr repo.Transaction) ([]*model.Example, error) {
+	streamIDs, err := r.streams.StreamIDs(ctx, tr, exampleStreamPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	examples := make([]*model.Example, 0, len(streamIDs))
+	for _, streamID := range streamIDs {
+		example, err := r.load(ctx, tr, streamID)
+		if errors.Is(err, repo.ErrNotFound) {
+			// Purged
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		examples = append(examples, example)
+	}
+
+	return examples, nil
+}
+
+// allocateID appends the next example ID to the sequence. A sequence that was never written starts
+// after the highest ID in use, streams may predate it.
+func (r *ExampleRepo) allocateID(ctx context.Context, tr repo.Transaction) (int, error) {
+	for attempt := 0; attempt < maxCreateAttempts; attempt++ {
+		last, version, err := r.readRegister(ctx, tr, exampleSequenceStreamID)
+		if err != nil {
+			return 0, err
+		}
+		if version == 0 {
+			if last, err = r.highestID(ctx, tr); err != nil {
+				return 0, err
+			}
+		}
+
+		err = r.writeRegister(ctx, tr, exampleSequenceStreamID, version, exampleIDAllocatedEvent, last+1, last+1)
+		if errors.Is(err, repo.ErrStreamVersionConflict) {
+			// A concurrent create took the ID, try the next one
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		return last + 1, nil
+	}
+
+	return 0, fmt.Errorf("failed to allocate an example ID after %d attempts", maxCreateAttempts)
+}
+
+// highestID returns the highest ID of the stored examples, 0 if there are none
+func (r *ExampleRepo) highestID(ctx context.Context, tr repo.Transaction) (int, error) {
+	streamIDs, err := r.streams.StreamIDs(ctx, tr, exampleStreamPrefix)
+	if err != nil {
+		return 0, err
+	}
+
+	highest := 0
+	for _, streamID := range streamIDs {
+		id, err := strconv.Atoi(strings.TrimPrefix(streamID, exampleStreamPrefix))
+		if err != nil {
+			return 0, fmt.Errorf("invalid example stream ID %s: %w", streamID, err)
+		}
+		highest = max(highest, id)
+	}
+
+	return highest, nil
+}
+
+// claimName reserves a name for the example id, unless another example holds it
+func (r *ExampleRepo) claimName(ctx context.Context, tr repo.Transaction, name string, id int) error {
+	streamID := exampleNameStreamID(model.NormalizeExampleName(name))
+	holder, version, err := r.readRegister(ctx, tr, streamID)
+	if err != nil {
+		return err
+	}
+	if holder == id {
+		return nil
+	}
+	if holder != 0 {
+		return model.NewExampleNameTakenError(name)
+	}
+
+	return r.writeName(ctx, tr, streamID, version, name, id)
+}
+
+// writeName records the claim of a name read free at version
+func (r *ExampleRepo) writeName(ctx context.Context, tr repo.Transaction, streamID string, version int, name string, id int) error {
+	err := r.writeRegister(ctx, tr, streamID, version, exampleNameClaimedEvent, id, id)
+	if errors.Is(err, repo.ErrStreamVersionConflict) {
+		// A concurrent create or rename claimed the name first
+		return model.NewExampleNameTakenError(name)
+	}
+
+	return err
+}
+
+// releaseName frees a name key held by the example id, a key held by another example is left alone
+func (r *ExampleRepo) releaseName(ctx context.Context, tr repo.Transaction, nameKey string, id int) error {
+	streamID := exampleNameStreamID(nameKey)
+	holder, version, err := r.readRegister(ctx, tr, streamID)
+	if err != nil || holder != id {
+		return err
+	}
+
+	return modifiedOnConflict(r.writeRegister(ctx, tr, streamID, version, exampleNameReleasedEvent, id, 0))
+}
+
+// readRegister returns the example ID a register stream holds, 0 if none, and the stream version
+func (r *ExampleRepo) readRegister(ctx context.Context, tr repo.Transaction, streamID string) (int, int, error) {
+	value, version := 0, 0
+
+	snapshot, err := r.streams.LoadSnapshot(ctx, tr, streamID)
+	switch {
+	case err == nil:
+		var state registerState
+		if err := json.Unmarshal([]byte(snapshot.State), &state); err != nil {
+			return 0, 0, fmt.Errorf("failed to decode snapshot of stream %s: %w", streamID, err)
+		}
+		value, version = state.ExampleID, snapshot.Version
+	case !errors.Is(err, repo.ErrNotFound):
+		return 0, 0, err
+	}
+
+	// The snapshot is taken with every change, events after it are only found if taking it failed
+	events, err := r.streams.Load(ctx, tr, streamID, version)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, streamEvent := range events {
+		var payload registerState
+		if err := json.Unmarshal([]byte(streamEvent.Payload), &payload); err != nil {
+			return 0, 0, fmt.Errorf("failed to decode event %s of stream %s: %w", streamEvent.EventID, streamID, err)
+		}
+		value, version = payload.ExampleID, streamEvent.Version
+		if streamEvent.EventName == exampleNameReleasedEvent {
+			value = 0
+		}
+	}
+
+	return value, version, nil
+}
+
+// writeRegister appends a change of a register stream read at version, recording exampleID in the
+// event, and snapshots its new value
+func (r *ExampleRepo) writeRegister(ctx context.Context, tr repo.Transaction, streamID string, version int, eventName string, exampleID, value int) error {
+	streamEvent, err := repo.NewStreamEvent(streamID, event.NewBaseEvent(eventName, streamID, registerState{ExampleID: exampleID}))
+	if err != nil {
+		return err
+	}
+	if err := r.streams.Append(ctx, tr, streamID, version, streamEvent); err != nil {
+		return err
+	}
+
+	state, err := json.Marshal(registerState{ExampleID: value})
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot of stream %s: %w", streamID, err)
+	}
+
+	return r.streams.SaveSnapshot(ctx, tr, &repo.StreamSnapshot{
+		StreamID: streamID,
+		Version:  version + 1,
+		State:    string(state),
+	})
+}
+
+// saveSnapshot records the state of an example at its version
+func (r *ExampleRepo) saveSnapshot(ctx context.Context, tr repo.Transaction, streamID string, example *model.Example) error {
+	state, err := json.Marshal(exampleSnapshot{
+		ID:        example.Id,
+		Name:      example.Name,
+		Alias:     example.Alias,
+		CreatedAt: example.CreatedAt,
+		UpdatedAt: example.UpdatedAt,
+		DeletedAt: example.DeletedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot of stream %s: %w", streamID, err)
+	}
+
+	return r.streams.SaveSnapshot(ctx, tr, &repo.StreamSnapshot{
+		StreamID: streamID,
+		Version:  example.Version,
+		State:    string(state),
+	})
+}
+
+// decodeSnapshot rebuilds the example a snapshot was taken of
+func decodeSnapshot(snapshot *repo.StreamSnapshot) (*model.Example, error) {
+	var state exampleSnapshot
+	if err := json.Unmarshal([]byte(snapshot.State), &state); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot of stream %s: %w", snapshot.StreamID, err)
+	}
+
+	return &model.Example{
+		Id:        state.ID,
+		Name:      state.Name,
+		NameKey:   model.NormalizeExampleName(state.Name),
+		Alias:     state.Alias,
+		CreatedAt: state.CreatedAt,
+		UpdatedAt: state.UpdatedAt,
+		Version:   snapshot.Version,
+		DeletedAt: state.DeletedAt,
+	}, nil
+}
+
+// apply returns the state of an example after an event of its stream, current is nil before the first event
+func apply(current *model.Example, evt event.Event, version int) (*model.Example, error) {
+	if created, ok := evt.(event.ExampleCreatedEvent); ok {
+		if current != nil {
+			return nil, fmt.Errorf("example %d is created twice", current.Id)
+		}
+		payload, ok := created.Payload.(event.ExampleCreatedPayload)
+		if !ok {
+			return nil, fmt.Errorf("unexpected payload %T", created.Payload)
+		}
+		return &model.Example{
+			Id:        payload.ID,
+			Name:      payload.Name,
+			NameKey:   model.NormalizeExampleName(payload.Name),
+			Alias:     payload.Alias,
+			CreatedAt: created.OccurredAt(),
+			UpdatedAt: created.OccurredAt(),
+			Version:   version,
+		}, nil
+	}
+
+	if current == nil {
+		return nil, fmt.Errorf("event %s precedes the creation of the example", evt.EventName())
+	}
+
+	// Work on a copy, the caller's example stays untouched if the append fails
+	next := *current
+	next.Version = version
+
+	switch e := evt.(type) {
+	case event.ExampleUpdatedEvent:
+		payload, ok := e.Payload.(event.ExampleUpdatedPayload)
+		if !ok {
+			return nil, fmt.Errorf("unexpected payload %T", e.Payload)
+		}
+		next.Name = payload.Name
+		next.NameKey = model.NormalizeExampleName(payload.Name)
+		next.Alias = payload.Alias
+		next.UpdatedAt = e.OccurredAt()
+	case event.ExampleDeletedEvent:
+		deletedAt := e.OccurredAt()
+		next.DeletedAt = &deletedAt
+	case event.ExampleRestoredEvent:
+		next.DeletedAt = nil
+		next.UpdatedAt = e.OccurredAt()
+	default:
+		return nil, fmt.Errorf("unexpected event %s in example stream", evt.EventName())
+	}
+
+	return &next, nil
+}
+
+// modifiedOnConflict reports a stream that moved on as a concurrent modification of the example
+func modifiedOnConflict(err error) error {
+	if errors.Is(err, repo.ErrStreamVersionConflict) {
+		return model.ErrExampleModified
+	}
+	return err
+}
+
+// exampleStreamID returns the stream ID of an example
+func exampleStreamID(id int) string {
+	return exampleStreamPrefix + strconv.Itoa(id)
+}
+
+// exampleNameStreamID returns the ID of the stream reserving a name key. The key is hashed, stream IDs
+// are shorter than names may be.
+func exampleNameStreamID(nameKey string) string {
+	sum := sha256.Sum256([]byte(nameKey))
+	return exampleNameStreamPrefix + hex.EncodeToString(sum[:])
+}
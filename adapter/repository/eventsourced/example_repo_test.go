@@ -0,0 +1,301 @@
+package eventsourced
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/domain/repo/repotest"
+)
+
+// countingStreams records the versions stream loads start after
+type countingStreams struct {
+	*MemoryEventStreamRepo
+	loadedAfter []int
+}
+
+func (s *countingStreams) Load(ctx context.Context, tr repo.Transaction, streamID string, afterVersion int) ([]*repo.StreamEvent, error) {
+	s.loadedAfter = append(s.loadedAfter, afterVersion)
+	return s.MemoryEventStreamRepo.Load(ctx, tr, streamID, afterVersion)
+}
+
+func newTestRepo(snapshotInterval int) (*ExampleRepo, *MemoryEventStreamRepo) {
+	streams := NewMemoryEventStreamRepo()
+	return NewExampleRepo(streams, snapshotInterval).(*ExampleRepo), streams
+}
+
+func TestExampleRepo_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	r, streams := newTestRepo(-1)
+
+	created, err := r.Create(ctx, nil, &model.Example{Name: "first", Alias: "one"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, created.Id)
+	assert.Equal(t, 1, created.Version)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	second, err := r.Create(ctx, nil, &model.Example{Name: "second"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.Id)
+
+	// Names are unique among live examples
+	_, err = r.Create(ctx, nil, &model.Example{Name: "FIRST"})
+	assert.ErrorIs(t, err, model.ErrExampleNameTaken)
+
+	created.Name = "renamed"
+	require.NoError(t, r.Update(ctx, nil, created))
+	assert.Equal(t, 2, created.Version)
+
+	loaded, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", loaded.Name)
+	assert.Equal(t, "one", loaded.Alias)
+	assert.Equal(t, 2, loaded.Version)
+	assert.True(t, loaded.CreatedAt.Equal(created.CreatedAt))
+
+	require.NoError(t, r.Delete(ctx, nil, created.Id))
+	_, err = r.GetByID(ctx, nil, created.Id)
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+	assert.ErrorIs(t, r.Delete(ctx, nil, created.Id), repo.ErrNotFound)
+
+	deleted, err := r.GetByIDWithDeleted(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.True(t, deleted.IsDeleted())
+	assert.Equal(t, 3, deleted.Version)
+
+	// The name of a deleted example is free again, restoring it would collide
+	taken, err := r.Create(ctx, nil, &model.Example{Name: "renamed"})
+	require.NoError(t, err)
+	assert.ErrorIs(t, r.Restore(ctx, nil, created.Id), model.ErrExampleNameTaken)
+
+	require.NoError(t, r.Purge(ctx, nil, taken.Id))
+	require.NoError(t, r.Restore(ctx, nil, created.Id))
+	restored, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 4, restored.Version)
+	assert.ErrorIs(t, r.Restore(ctx, nil, created.Id), repo.ErrNotFound)
+
+	// Every change is kept in the stream
+	events, err := streams.Load(ctx, nil, exampleStreamID(created.Id), 0)
+	require.NoError(t, err)
+	names := make([]string, 0, len(events))
+	for _, evt := range events {
+		names = append(names, evt.EventName)
+	}
+	assert.Equal(t, []string{"example.created", "example.updated", "example.deleted", "example.restored"}, names)
+
+	require.NoError(t, r.Purge(ctx, nil, created.Id))
+	_, err = r.GetByIDWithDeleted(ctx, nil, created.Id)
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+	assert.ErrorIs(t, r.Purge(ctx, nil, created.Id), repo.ErrNotFound)
+
+	// Purging closes the stream with a tombstone, the log is never rewritten
+	events, err = streams.Load(ctx, nil, exampleStreamID(created.Id), 0)
+	require.NoError(t, err)
+	require.Len(t, events, 5)
+	assert.Equal(t, examplePurgedEvent, events[4].EventName)
+}
+
+func TestExampleRepo_UpdateConflict(t *testing.T) {
+	ctx := context.Background()
+	r, _ := newTestRepo(-1)
+
+	_, err := r.Create(ctx, nil, &model.Example{Name: "shared"})
+	require.NoError(t, err)
+
+	first, err := r.GetByID(ctx, nil, 1)
+	require.NoError(t, err)
+	stale, err := r.GetByID(ctx, nil, 1)
+	require.NoError(t, err)
+
+	first.Alias = "first"
+	require.NoError(t, r.Update(ctx, nil, first))
+
+	stale.Alias = "stale"
+	assert.ErrorIs(t, r.Update(ctx, nil, stale), model.ErrExampleModified)
+	assert.Equal(t, 1, stale.Version)
+
+	loaded, err := r.GetByID(ctx, nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "first", loaded.Alias)
+
+	assert.ErrorIs(t, r.Update(ctx, nil, &model.Example{Id: 9, Name: "missing", Version: 1}), repo.ErrNotFound)
+}
+
+func TestExampleRepo_Snapshots(t *testing.T) {
+	ctx := context.Background()
+	streams := &countingStreams{MemoryEventStreamRepo: NewMemoryEventStreamRepo()}
+	r := NewExampleRepo(streams, 3)
+
+	example, err := r.Create(ctx, nil, &model.Example{Name: "v0"})
+	require.NoError(t, err)
+	for i := 1; i <= 3; i++ {
+		example.Name = fmt.Sprintf("v%d", i)
+		require.NoError(t, r.Update(ctx, nil, example))
+	}
+	assert.Equal(t, 4, example.Version)
+
+	snapshot, err := streams.LoadSnapshot(ctx, nil, exampleStreamID(example.Id))
+	require.NoError(t, err)
+	assert.Equal(t, 3, snapshot.Version)
+
+	streams.loadedAfter = nil
+	fromSnapshot, err := r.GetByID(ctx, nil, example.Id)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, streams.loadedAfter)
+
+	// Rebuilding from the snapshot gives the same example as replaying the whole stream
+	replay := NewExampleRepo(streams.MemoryEventStreamRepo, -1)
+	replayed, err := replay.GetByID(ctx, nil, example.Id)
+	require.NoError(t, err)
+	assert.Equal(t, replayed.Name, fromSnapshot.Name)
+	assert.Equal(t, replayed.NameKey, fromSnapshot.NameKey)
+	assert.Equal(t, replayed.Version, fromSnapshot.Version)
+	assert.True(t, replayed.CreatedAt.Equal(fromSnapshot.CreatedAt))
+	assert.True(t, replayed.UpdatedAt.Equal(fromSnapshot.UpdatedAt))
+	assert.Equal(t, "v3", fromSnapshot.Name)
+}
+
+func TestExampleRepo_Queries(t *testing.T) {
+	ctx := context.Background()
+	r, _ := newTestRepo(0)
+
+	for _, name := range []string{"alpha", "beta", "gamma", "alphabet"} {
+		_, err := r.Create(ctx, nil, &model.Example{Name: name})
+		require.NoError(t, err)
+	}
+	require.NoError(t, r.Delete(ctx, nil, 2))
+
+	found, err := r.FindByName(ctx, nil, "GAMMA")
+	require.NoError(t, err)
+	assert.Equal(t, 3, found.Id)
+	_, err = r.FindByName(ctx, nil, "beta")
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+
+	count, err := r.Count(ctx, nil, repo.ExampleFilter{Name: "alpha"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = r.Count(ctx, nil, repo.ExampleFilter{IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+
+	listed, err := r.List(ctx, nil, repo.ExampleListQuery{SortBy: repo.ExampleSortByName, SortDesc: true, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	assert.Equal(t, "gamma", listed[0].Name)
+	assert.Equal(t, "alphabet", listed[1].Name)
+
+	page, err := r.ListByCursor(ctx, nil, repo.ExampleKeysetQuery{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, []int{1, 3}, []int{page[0].Id, page[1].Id})
+}
+
+func TestExampleRepo_ListWithTotal(t *testing.T) {
+	ctx := context.Background()
+	streams := &countingStreams{MemoryEventStreamRepo: NewMemoryEventStreamRepo()}
+	r := NewExampleRepo(streams, 0).(*ExampleRepo)
+
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		_, err := r.Create(ctx, nil, &model.Example{Name: name})
+		require.NoError(t, err)
+	}
+	require.NoError(t, r.Purge(ctx, nil, 2))
+
+	// The page and the total come from one rebuild of each example stream
+	streams.loadedAfter = nil
+	listed, total, err := r.ListWithTotal(ctx, nil, repo.ExampleListQuery{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "alpha", listed[0].Name)
+	assert.Len(t, streams.loadedAfter, 3)
+}
+
+func TestExampleRepo_Contract(t *testing.T) {
+	// The in-memory streams ignore transactions, every write takes effect immediately
+	repotest.RunExampleRepoTests(t, repo.MemoryStore, func(t *testing.T) (repo.IExampleRepo, repo.TransactionFactory) {
+		r, _ := newTestRepo(3)
+		return r, nil
+	})
+}
+
+// failingStreams fails loading the streams reserving names, so name lookups cannot complete
+type failingStreams struct {
+	*MemoryEventStreamRepo
+	err error
+}
+
+func (s *failingStreams) Load(ctx context.Context, tr repo.Transaction, streamID string, afterVersion int) ([]*repo.StreamEvent, error) {
+	if s.err != nil && strings.HasPrefix(streamID, exampleNameStreamPrefix) {
+		return nil, s.err
+	}
+	return s.MemoryEventStreamRepo.Load(ctx, tr, streamID, afterVersion)
+}
+
+func TestExampleRepo_RestoreLookupError(t *testing.T) {
+	ctx := context.Background()
+	streams := &failingStreams{MemoryEventStreamRepo: NewMemoryEventStreamRepo()}
+	r := NewExampleRepo(streams, -1)
+
+	created, err := r.Create(ctx, nil, &model.Example{Name: "restored"})
+	require.NoError(t, err)
+	require.NoError(t, r.Delete(ctx, nil, created.Id))
+
+	// A failing lookup is reported as is, not as a taken name
+	streams.err = errors.New("store unavailable")
+	err = r.Restore(ctx, nil, created.Id)
+	assert.ErrorIs(t, err, streams.err)
+	assert.NotErrorIs(t, err, model.ErrExampleNameTaken)
+}
+
+func TestExampleRepo_Reservations(t *testing.T) {
+	ctx := context.Background()
+	r, streams := newTestRepo(-1)
+
+	first, err := r.Create(ctx, nil, &model.Example{Name: "first"})
+	require.NoError(t, err)
+	second, err := r.Create(ctx, nil, &model.Example{Name: "second"})
+	require.NoError(t, err)
+
+	// The ID of a purged example is not handed out again
+	require.NoError(t, r.Purge(ctx, nil, second.Id))
+	third, err := r.Create(ctx, nil, &model.Example{Name: "second"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, third.Id)
+
+	// A rename moves the reservation, the old name is free and the new one taken
+	first.Name = "renamed"
+	require.NoError(t, r.Update(ctx, nil, first))
+	_, err = r.FindByName(ctx, nil, "first")
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+	found, err := r.FindByName(ctx, nil, "RENAMED")
+	require.NoError(t, err)
+	assert.Equal(t, first.Id, found.Id)
+	_, err = r.Create(ctx, nil, &model.Example{Name: "renamed"})
+	assert.ErrorIs(t, err, model.ErrExampleNameTaken)
+
+	// A claim appended at a stale version loses against the one stored first
+	nameStreamID := exampleNameStreamID(model.NormalizeExampleName("contested"))
+	require.NoError(t, r.writeName(ctx, nil, nameStreamID, 0, "contested", 8))
+	assert.ErrorIs(t, r.writeName(ctx, nil, nameStreamID, 0, "contested", 9), model.ErrExampleNameTaken)
+
+	// The sequence of streams written before it existed starts after the highest stored ID
+	legacy := NewMemoryEventStreamRepo()
+	for id, streamEvents := range streams.streams {
+		if strings.HasPrefix(id, exampleStreamPrefix) {
+			legacy.streams[id] = streamEvents
+		}
+	}
+	created, err := NewExampleRepo(legacy, -1).Create(ctx, nil, &model.Example{Name: "fourth"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, created.Id)
+}
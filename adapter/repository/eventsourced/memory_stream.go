@@ -0,0 +1,114 @@
+package eventsourced
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go-hexagonal/domain/repo"
+)
+
+// Ensure MemoryEventStreamRepo implements repo.IEventStreamRepo
+var _ repo.IEventStreamRepo = (*MemoryEventStreamRepo)(nil)
+
+// MemoryEventStreamRepo keeps event streams in memory, for tests and single process setups.
+// Transactions are ignored, every call takes effect immediately.
+type MemoryEventStreamRepo struct {
+	mu        sync.RWMutex
+	streams   map[string][]repo.StreamEvent
+	snapshots map[string]repo.StreamSnapshot
+	nextID    int64
+}
+
+// NewMemoryEventStreamRepo creates an empty in-memory event stream repository
+func NewMemoryEventStreamRepo() *MemoryEventStreamRepo {
+	return &MemoryEventStreamRepo{
+		streams:   make(map[string][]repo.StreamEvent),
+		snapshots: make(map[string]repo.StreamSnapshot),
+	}
+}
+
+// Append adds events to the end of a stream if it is still at the expected version
+func (r *MemoryEventStreamRepo) Append(_ context.Context, _ repo.Transaction, streamID string, expectedVersion int, events ...*repo.StreamEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stream := r.streams[streamID]
+	if len(stream) != expectedVersion {
+		return repo.ErrStreamVersionConflict
+	}
+
+	now := time.Now()
+	for i, evt := range events {
+		r.nextID++
+		evt.ID = r.nextID
+		evt.StreamID = streamID
+		evt.Version = expectedVersion + i + 1
+		evt.CreatedAt = now
+		stream = append(stream, *evt)
+	}
+	r.streams[streamID] = stream
+
+	return nil
+}
+
+// Load returns copies of the events of a stream after the given version in order
+func (r *MemoryEventStreamRepo) Load(_ context.Context, _ repo.Transaction, streamID string, afterVersion int) ([]*repo.StreamEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stream := r.streams[streamID]
+	events := make([]*repo.StreamEvent, 0, max(len(stream)-afterVersion, 0))
+	for i := max(afterVersion, 0); i < len(stream); i++ {
+		evt := stream[i]
+		events = append(events, &evt)
+	}
+
+	return events, nil
+}
+
+// StreamIDs returns the IDs of all streams starting with prefix, sorted
+func (r *MemoryEventStreamRepo) StreamIDs(_ context.Context, _ repo.Transaction, prefix string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	streamIDs := make([]string, 0, len(r.streams))
+	for streamID := range r.streams {
+		if strings.HasPrefix(streamID, prefix) {
+			streamIDs = append(streamIDs, streamID)
+		}
+	}
+	slices.Sort(streamIDs)
+
+	return streamIDs, nil
+}
+
+// SaveSnapshot replaces the snapshot of a stream
+func (r *MemoryEventStreamRepo) SaveSnapshot(_ context.Context, _ repo.Transaction, snapshot *repo.StreamSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot.CreatedAt = time.Now()
+	r.snapshots[snapshot.StreamID] = *snapshot
+
+	return nil
+}
+
+// LoadSnapshot returns a copy of the snapshot of a stream
+func (r *MemoryEventStreamRepo) LoadSnapshot(_ context.Context, _ repo.Transaction, streamID string) (*repo.StreamSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot, ok := r.snapshots[streamID]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+
+	return &snapshot, nil
+}
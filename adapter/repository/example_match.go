@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+// The helpers below evaluate example queries in memory, for repositories that cannot push them
+// down to a database. They follow the semantics of the SQL helpers above.
+
+// MatchExampleFilter reports whether an example matches the filter, text matches ignore case
+func MatchExampleFilter(example *model.Example, filter repo.ExampleFilter) bool {
+	if !filter.IncludeDeleted && example.IsDeleted() {
+		return false
+	}
+	if filter.Name != "" && !containsFold(example.Name, filter.Name) {
+		return false
+	}
+	if filter.Alias != "" && !containsFold(example.Alias, filter.Alias) {
+		return false
+	}
+	if filter.CreatedAfter != nil && example.CreatedAt.Before(*filter.CreatedAfter) {
+		return false
+	}
+	if filter.CreatedBefore != nil && !example.CreatedAt.Before(*filter.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// FilterExamples returns the examples matching the filter, in their original order
+func FilterExamples(examples []*model.Example, filter repo.ExampleFilter) []*model.Example {
+	matched := make([]*model.Example, 0, len(examples))
+	for _, example := range examples {
+		if MatchExampleFilter(example, filter) {
+			matched = append(matched, example)
+		}
+	}
+	return matched
+}
+
+// ListExamples returns the page of examples described by the list query
+func ListExamples(examples []*model.Example, query repo.ExampleListQuery) []*model.Example {
+	matched := FilterExamples(examples, query.Filter)
+
+	sortBy := query.SortBy
+	if !sortBy.IsValid() {
+		sortBy = repo.ExampleSortByID
+	}
+
+	slices.SortStableFunc(matched, func(a, b *model.Example) int {
+		order := compareExamplesBy(a, b, sortBy)
+		if order == 0 {
+			// The primary key is the tie-breaker, as in ApplyExampleOrder
+			order = cmp.Compare(a.Id, b.Id)
+		}
+		if query.SortDesc {
+			return -order
+		}
+		return order
+	})
+
+	return page(matched, query.Offset, query.Limit)
+}
+
+// ListExamplesByCursor returns the examples strictly after (or before, when query.Backward is set)
+// the cursor in (created_at, id) order, in display order like the repositories return them
+func ListExamplesByCursor(examples []*model.Example, query repo.ExampleKeysetQuery) []*model.Example {
+	matched := FilterExamples(examples, query.Filter)
+
+	// Scan ascending when reading forward through an ascending list or backward through a descending one
+	ascending := query.SortDesc == query.Backward
+
+	slices.SortStableFunc(matched, func(a, b *model.Example) int {
+		order := compareByCreation(a, b)
+		if !ascending {
+			return -order
+		}
+		return order
+	})
+
+	if query.Cursor != nil {
+		cursor := &model.Example{Id: query.Cursor.ID, CreatedAt: query.Cursor.CreatedAt}
+		matched = slices.DeleteFunc(matched, func(example *model.Example) bool {
+			order := compareByCreation(example, cursor)
+			if ascending {
+				return order <= 0
+			}
+			return order >= 0
+		})
+	}
+
+	scanned := page(matched, 0, query.Limit)
+
+	// Backward reads are scanned in reverse, restore display order
+	if query.Backward {
+		slices.Reverse(scanned)
+	}
+
+	return scanned
+}
+
+// compareExamplesBy compares two examples by a sort field
+func compareExamplesBy(a, b *model.Example, field repo.ExampleSortField) int {
+	switch field {
+	case repo.ExampleSortByName:
+		return strings.Compare(a.Name, b.Name)
+	case repo.ExampleSortByCreatedAt:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case repo.ExampleSortByUpdatedAt:
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	default:
+		return cmp.Compare(a.Id, b.Id)
+	}
+}
+
+// compareByCreation compares two examples in (created_at, id) order
+func compareByCreation(a, b *model.Example) int {
+	if order := a.CreatedAt.Compare(b.CreatedAt); order != 0 {
+		return order
+	}
+	return cmp.Compare(a.Id, b.Id)
+}
+
+// page applies an offset and a limit, a limit of 0 returns everything after the offset
+func page(examples []*model.Example, offset, limit int) []*model.Example {
+	if offset >= len(examples) {
+		return []*model.Example{}
+	}
+	if offset > 0 {
+		examples = examples[offset:]
+	}
+	if limit > 0 && limit < len(examples) {
+		examples = examples[:limit]
+	}
+	return examples
+}
+
+// containsFold reports whether substr is within s, ignoring case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
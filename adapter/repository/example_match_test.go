@@ -0,0 +1,73 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+func newMatchExamples() []*model.Example {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deletedAt := start
+	return []*model.Example{
+		{Id: 1, Name: "Beta", CreatedAt: start},
+		{Id: 2, Name: "alpha", CreatedAt: start.Add(time.Hour)},
+		{Id: 3, Name: "gamma", CreatedAt: start.Add(time.Hour)},
+		{Id: 4, Name: "ALPHABET", CreatedAt: start.Add(2 * time.Hour), DeletedAt: &deletedAt},
+	}
+}
+
+func ids(examples []*model.Example) []int {
+	result := make([]int, 0, len(examples))
+	for _, example := range examples {
+		result = append(result, example.Id)
+	}
+	return result
+}
+
+func TestFilterExamples(t *testing.T) {
+	examples := newMatchExamples()
+
+	assert.Equal(t, []int{2}, ids(repository.FilterExamples(examples, repo.ExampleFilter{Name: "ALP"})))
+	assert.Equal(t, []int{2, 4}, ids(repository.FilterExamples(examples, repo.ExampleFilter{Name: "alp", IncludeDeleted: true})))
+
+	after := examples[1].CreatedAt
+	assert.Equal(t, []int{2, 3}, ids(repository.FilterExamples(examples, repo.ExampleFilter{CreatedAfter: &after})))
+}
+
+func TestListExamples(t *testing.T) {
+	examples := newMatchExamples()
+
+	// Ties on the sort field are broken by ID
+	listed := repository.ListExamples(examples, repo.ExampleListQuery{SortBy: repo.ExampleSortByCreatedAt, SortDesc: true})
+	assert.Equal(t, []int{3, 2, 1}, ids(listed))
+
+	listed = repository.ListExamples(examples, repo.ExampleListQuery{SortBy: repo.ExampleSortByName, Offset: 1, Limit: 1})
+	assert.Equal(t, []int{2}, ids(listed))
+
+	assert.Empty(t, repository.ListExamples(examples, repo.ExampleListQuery{Offset: 10}))
+}
+
+func TestListExamplesByCursor(t *testing.T) {
+	examples := newMatchExamples()
+	cursor := repo.NewExampleCursor(examples[1])
+
+	forward := repository.ListExamplesByCursor(examples, repo.ExampleKeysetQuery{Cursor: cursor, Limit: 5})
+	assert.Equal(t, []int{3}, ids(forward))
+
+	// Backward pages come back in display order
+	backward := repository.ListExamplesByCursor(examples, repo.ExampleKeysetQuery{
+		Cursor:   repo.NewExampleCursor(examples[2]),
+		Backward: true,
+		Limit:    5,
+	})
+	assert.Equal(t, []int{1, 2}, ids(backward))
+
+	descending := repository.ListExamplesByCursor(examples, repo.ExampleKeysetQuery{SortDesc: true, Limit: 2})
+	assert.Equal(t, []int{3, 2}, ids(descending))
+}
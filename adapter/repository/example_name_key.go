@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-hexagonal/domain/model"
+)
+
+// maxRenamedExampleNameBase is the length a duplicate name is cut to before its ID is appended, so the
+// renamed example still fits the name column
+const maxRenamedExampleNameBase = 240
+
+// exampleNameKeyRow is the part of an example row the name key backfill reads
+type exampleNameKeyRow struct {
+	ID        int
+	Name      string
+	NameKey   *string
+	DeletedAt *time.Time
+}
+
+// BackfillExampleNameKeys sets the name key of every example to its name normalized with
+// model.NormalizeExampleName, as the application computes it. Live examples whose key an older live
+// example already holds are renamed with their ID appended. It returns the number of updated rows.
+//
+// It runs between the migration adding the name_key column and the one making it unique.
+func BackfillExampleNameKeys(ctx context.Context, db *gorm.DB) (int, error) {
+	updated := 0
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []exampleNameKeyRow
+		if err := tx.Table("example").Select("id, name, name_key, deleted_at").Order("id ASC").Find(&rows).Error; err != nil {
+			return fmt.Errorf("failed to read examples: %w", err)
+		}
+
+		// The oldest live example keeps its name
+		taken := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			name, key := row.Name, model.NormalizeExampleName(row.Name)
+			if row.DeletedAt == nil {
+				if taken[key] {
+					name = renameDuplicateExampleName(row.Name, row.ID, taken)
+					key = model.NormalizeExampleName(name)
+				}
+				taken[key] = true
+			}
+
+			updates := make(map[string]any)
+			if name != row.Name {
+				updates["name"] = name
+				updates["version"] = gorm.Expr("version + 1")
+			}
+			if row.NameKey == nil || *row.NameKey != key {
+				updates["name_key"] = key
+			}
+			if len(updates) == 0 {
+				continue
+			}
+
+			if err := tx.Table("example").Where("id = ?", row.ID).Updates(updates).Error; err != nil {
+				return fmt.Errorf("failed to update example %d: %w", row.ID, err)
+			}
+			updated++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
+
+// renameDuplicateExampleName appends the example ID to a name whose key is taken, and a counter as
+// well while the result is taken too
+func renameDuplicateExampleName(name string, id int, taken map[string]bool) string {
+	base := []rune(name)
+	if len(base) > maxRenamedExampleNameBase {
+		base = base[:maxRenamedExampleNameBase]
+	}
+
+	renamed := fmt.Sprintf("%s (%d)", string(base), id)
+	for n := 2; taken[model.NormalizeExampleName(renamed)]; n++ {
+		renamed = fmt.Sprintf("%s (%d-%d)", string(base), id, n)
+	}
+
+	return renamed
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestBackfillExampleNameKeys(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	// The example table as the migration adding the name_key column leaves it
+	require.NoError(t, db.Exec(`CREATE TABLE example (
+		id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		name_key TEXT NULL,
+		deleted_at DATETIME NULL,
+		version INTEGER NOT NULL DEFAULT 1
+	)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO example (id, name, name_key, deleted_at) VALUES
+		(1, 'Ｆｏｏ', NULL, NULL),
+		(2, 'foo', NULL, NULL),
+		(3, 'FOO', NULL, '2024-01-01 00:00:00'),
+		(4, 'foo (5)', NULL, NULL),
+		(5, 'Foo', NULL, NULL),
+		(6, 'bar', 'bar', NULL)`).Error)
+
+	updated, err := BackfillExampleNameKeys(context.Background(), db)
+	require.NoError(t, err)
+	assert.Equal(t, 5, updated)
+
+	type row struct {
+		ID      int
+		Name    string
+		NameKey string
+		Version int
+	}
+	var rows []row
+	require.NoError(t, db.Table("example").Order("id ASC").Find(&rows).Error)
+
+	// Full-width and case variants share a key, the oldest live example keeps its name and a
+	// rename never lands on a name already in use
+	assert.Equal(t, []row{
+		{ID: 1, Name: "Ｆｏｏ", NameKey: "foo", Version: 1},
+		{ID: 2, Name: "foo (2)", NameKey: "foo (2)", Version: 2},
+		{ID: 3, Name: "FOO", NameKey: "foo", Version: 1},
+		{ID: 4, Name: "foo (5)", NameKey: "foo (5)", Version: 1},
+		{ID: 5, Name: "Foo (5-2)", NameKey: "foo (5-2)", Version: 2},
+		{ID: 6, Name: "bar", NameKey: "bar", Version: 1},
+	}, rows)
+
+	// Running it again finds nothing to do
+	updated, err = BackfillExampleNameKeys(context.Background(), db)
+	require.NoError(t, err)
+	assert.Equal(t, 0, updated)
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+
+	"go-hexagonal/domain/repo"
+)
+
+// likeEscaper escapes LIKE wildcards so user input is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ApplyExampleFilter adds the WHERE clauses described by the filter to the query.
+// likeOperator allows dialects to choose a case-insensitive operator (e.g. ILIKE).
+func ApplyExampleFilter(db *gorm.DB, filter repo.ExampleFilter, likeOperator string) *gorm.DB {
+	return applyExampleFilter(db, filter, likeOperator, "")
+}
+
+// ApplyExampleFilterWithEscape is ApplyExampleFilter for dialects without a default LIKE escape
+// character, such as SQLite, declaring the backslash the patterns are escaped with
+func ApplyExampleFilterWithEscape(db *gorm.DB, filter repo.ExampleFilter, likeOperator string) *gorm.DB {
+	return applyExampleFilter(db, filter, likeOperator, ` ESCAPE '\'`)
+}
+
+// applyExampleFilter adds the filter clauses, escapeClause follows every LIKE pattern
+func applyExampleFilter(db *gorm.DB, filter repo.ExampleFilter, likeOperator, escapeClause string) *gorm.DB {
+	if likeOperator == "" {
+		likeOperator = "LIKE"
+	}
+
+	if !filter.IncludeDeleted {
+		db = db.Where("deleted_at IS NULL")
+	}
+	if filter.Name != "" {
+		db = db.Where("name "+likeOperator+" ?"+escapeClause, containsPattern(filter.Name))
+	}
+	if filter.Alias != "" {
+		db = db.Where("alias "+likeOperator+" ?"+escapeClause, containsPattern(filter.Alias))
+	}
+	if filter.CreatedAfter != nil {
+		db = db.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		db = db.Where("created_at < ?", *filter.CreatedBefore)
+	}
+
+	return db
+}
+
+// ApplyExampleOrder adds ORDER BY, OFFSET and LIMIT clauses for the list query.
+// The primary key is always appended as a tie-breaker so pages are stable.
+func ApplyExampleOrder(db *gorm.DB, query repo.ExampleListQuery) *gorm.DB {
+	sortBy := query.SortBy
+	if !sortBy.IsValid() {
+		sortBy = repo.ExampleSortByID
+	}
+
+	direction := " ASC"
+	if query.SortDesc {
+		direction = " DESC"
+	}
+
+	db = db.Order(string(sortBy) + direction)
+	if sortBy != repo.ExampleSortByID {
+		db = db.Order("id" + direction)
+	}
+
+	if query.Offset > 0 {
+		db = db.Offset(query.Offset)
+	}
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+
+	return db
+}
+
+// ApplyExampleKeyset adds the seek predicate, ORDER BY and LIMIT clauses for keyset pagination.
+// Rows are returned in scan order, which is the reverse of the display order for backward reads.
+func ApplyExampleKeyset(db *gorm.DB, query repo.ExampleKeysetQuery) *gorm.DB {
+	// Scan ascending when reading forward through an ascending list or backward through a descending one
+	ascending := query.SortDesc == query.Backward
+
+	comparison, direction := ">", " ASC"
+	if !ascending {
+		comparison, direction = "<", " DESC"
+	}
+
+	if query.Cursor != nil {
+		// Expanded row comparison so both MySQL and PostgreSQL can use the (created_at, id) index
+		db = db.Where("created_at "+comparison+" ? OR (created_at = ? AND id "+comparison+" ?)",
+			query.Cursor.CreatedAt, query.Cursor.CreatedAt, query.Cursor.ID)
+	}
+
+	db = db.Order("created_at" + direction).Order("id" + direction)
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+
+	return db
+}
+
+// PrefixPattern builds a LIKE pattern matching values starting with prefix
+func PrefixPattern(prefix string) string {
+	return likeEscaper.Replace(prefix) + "%"
+}
+
+// containsPattern builds a LIKE pattern matching the value anywhere in the column
+func containsPattern(value string) string {
+	return "%" + likeEscaper.Replace(value) + "%"
+}
@@ -0,0 +1,183 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+// newDryRunDB opens a MySQL dialect in dry-run mode so statements are built but never executed
+func newDryRunDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pass@tcp(127.0.0.1:3306)/test?parseTime=true",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		NamingStrategy:       schema.NamingStrategy{SingularTable: true},
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestApplyExampleFilter(t *testing.T) {
+	db := newDryRunDB(t)
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := after.AddDate(0, 1, 0)
+
+	filter := repo.ExampleFilter{
+		Name:          "50%_off",
+		Alias:         "demo",
+		CreatedAfter:  &after,
+		CreatedBefore: &before,
+	}
+
+	var examples []*model.Example
+	stmt := repository.ApplyExampleFilter(db.Model(&model.Example{}), filter, "").Find(&examples).Statement
+
+	sql := stmt.SQL.String()
+	assert.Contains(t, sql, "name LIKE ?")
+	assert.Contains(t, sql, "alias LIKE ?")
+	assert.Contains(t, sql, "created_at >= ?")
+	assert.Contains(t, sql, "created_at < ?")
+	assert.Equal(t, []any{`%50\%\_off%`, "%demo%", after, before}, stmt.Vars)
+}
+
+func TestApplyExampleFilter_Empty(t *testing.T) {
+	db := newDryRunDB(t)
+
+	var examples []*model.Example
+	stmt := repository.ApplyExampleFilter(db.Model(&model.Example{}), repo.ExampleFilter{}, "ILIKE").Find(&examples).Statement
+
+	// Soft deleted examples are hidden by default
+	assert.Contains(t, stmt.SQL.String(), "WHERE deleted_at IS NULL")
+	assert.Empty(t, stmt.Vars)
+}
+
+func TestApplyExampleFilter_IncludeDeleted(t *testing.T) {
+	db := newDryRunDB(t)
+
+	var examples []*model.Example
+	stmt := repository.ApplyExampleFilter(db.Model(&model.Example{}), repo.ExampleFilter{IncludeDeleted: true}, "").Find(&examples).Statement
+
+	assert.NotContains(t, stmt.SQL.String(), "WHERE")
+	assert.Empty(t, stmt.Vars)
+}
+
+func TestApplyExampleOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    repo.ExampleListQuery
+		expected string
+		vars     []any
+	}{
+		{
+			name:     "default order by id",
+			query:    repo.ExampleListQuery{Limit: 10},
+			expected: "ORDER BY id ASC LIMIT ?",
+			vars:     []any{10},
+		},
+		{
+			name:     "sort by name descending with tie-breaker",
+			query:    repo.ExampleListQuery{SortBy: repo.ExampleSortByName, SortDesc: true, Offset: 20, Limit: 10},
+			expected: "ORDER BY name DESC,id DESC LIMIT ? OFFSET ?",
+			vars:     []any{10, 20},
+		},
+		{
+			name:     "invalid sort field falls back to id",
+			query:    repo.ExampleListQuery{SortBy: "password", Limit: 5},
+			expected: "ORDER BY id ASC LIMIT ?",
+			vars:     []any{5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newDryRunDB(t)
+
+			var examples []*model.Example
+			stmt := repository.ApplyExampleOrder(db.Model(&model.Example{}), tt.query).Find(&examples).Statement
+
+			assert.Contains(t, stmt.SQL.String(), tt.expected)
+			assert.Equal(t, tt.vars, stmt.Vars)
+		})
+	}
+}
+
+func TestApplyExampleKeyset(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cursor := &repo.ExampleCursor{ID: 42, CreatedAt: createdAt}
+
+	tests := []struct {
+		name     string
+		query    repo.ExampleKeysetQuery
+		expected string
+		vars     []any
+	}{
+		{
+			name:     "first page ascending",
+			query:    repo.ExampleKeysetQuery{Limit: 11},
+			expected: "ORDER BY created_at ASC,id ASC LIMIT ?",
+			vars:     []any{11},
+		},
+		{
+			name:     "forward through ascending list",
+			query:    repo.ExampleKeysetQuery{Cursor: cursor, Limit: 11},
+			expected: "WHERE created_at > ? OR (created_at = ? AND id > ?) ORDER BY created_at ASC,id ASC LIMIT ?",
+			vars:     []any{createdAt, createdAt, 42, 11},
+		},
+		{
+			name:     "backward through ascending list",
+			query:    repo.ExampleKeysetQuery{Cursor: cursor, Backward: true, Limit: 11},
+			expected: "WHERE created_at < ? OR (created_at = ? AND id < ?) ORDER BY created_at DESC,id DESC LIMIT ?",
+			vars:     []any{createdAt, createdAt, 42, 11},
+		},
+		{
+			name:     "forward through descending list",
+			query:    repo.ExampleKeysetQuery{Cursor: cursor, SortDesc: true, Limit: 11},
+			expected: "WHERE created_at < ? OR (created_at = ? AND id < ?) ORDER BY created_at DESC,id DESC LIMIT ?",
+			vars:     []any{createdAt, createdAt, 42, 11},
+		},
+		{
+			name:     "backward through descending list",
+			query:    repo.ExampleKeysetQuery{Cursor: cursor, SortDesc: true, Backward: true, Limit: 11},
+			expected: "WHERE created_at > ? OR (created_at = ? AND id > ?) ORDER BY created_at ASC,id ASC LIMIT ?",
+			vars:     []any{createdAt, createdAt, 42, 11},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newDryRunDB(t)
+
+			var examples []*model.Example
+			stmt := repository.ApplyExampleKeyset(db.Model(&model.Example{}), tt.query).Find(&examples).Statement
+
+			assert.Contains(t, stmt.SQL.String(), tt.expected)
+			assert.Equal(t, tt.vars, stmt.Vars)
+		})
+	}
+}
+
+func TestApplyExampleKeyset_WithFilter(t *testing.T) {
+	db := newDryRunDB(t)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var examples []*model.Example
+	query := repo.ExampleKeysetQuery{Cursor: &repo.ExampleCursor{ID: 42, CreatedAt: createdAt}, Limit: 11}
+	stmt := repository.ApplyExampleKeyset(
+		repository.ApplyExampleFilter(db.Model(&model.Example{}), query.Filter, ""), query,
+	).Find(&examples).Statement
+
+	// The seek predicate must stay grouped so the deleted filter applies to both branches
+	assert.Contains(t, stmt.SQL.String(), "WHERE deleted_at IS NULL AND (created_at > ? OR (created_at = ? AND id > ?))")
+}
@@ -2,7 +2,6 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"time"
 
@@ -33,7 +32,7 @@ func NewTransactionFactory(clients map[StoreType]any) repo.TransactionFactory {
 // NewTransaction creates a new transaction for the specified store
 func (f *TransactionFactoryImpl) NewTransaction(ctx context.Context, store repo.StoreType, opts any) (repo.Transaction, error) {
 	// Convert domain StoreType to adapter StoreType
-	adapterStore := StoreType(store)
+	adapterStore := toAdapterStoreType(store)
 
 	// Get the client for the store type
 	client, ok := f.clients[adapterStore]
@@ -42,10 +41,7 @@ func (f *TransactionFactoryImpl) NewTransaction(ctx context.Context, store repo.
 	}
 
 	// Convert options to SQL options if applicable
-	var sqlOpts *sql.TxOptions
-	if opt, ok := opts.(*sql.TxOptions); ok {
-		sqlOpts = opt
-	}
+	sqlOpts := SQLTxOptions(opts)
 
 	// Create transaction
 	tx, err := NewTransaction(ctx, adapterStore, client, sqlOpts)
@@ -61,17 +57,21 @@ func OpenGormDB() (*gorm.DB, error) {
 	if config.GlobalConfig.MySQL == nil {
 		return nil, ErrMissingMySQLConfig
 	}
+	return OpenMySQLDB(config.GlobalConfig.MySQL)
+}
 
+// OpenMySQLDB creates a new GORM database connection to the MySQL database of cfg
+func OpenMySQLDB(cfg *config.MySQLConfig) (*gorm.DB, error) {
 	// Construct DSN from configuration
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=%v&loc=%s",
-		config.GlobalConfig.MySQL.User,
-		config.GlobalConfig.MySQL.Password,
-		config.GlobalConfig.MySQL.Host,
-		config.GlobalConfig.MySQL.Port,
-		config.GlobalConfig.MySQL.Database,
-		config.GlobalConfig.MySQL.CharSet,
-		config.GlobalConfig.MySQL.ParseTime,
-		config.GlobalConfig.MySQL.TimeZone,
+		cfg.User,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
+		cfg.Database,
+		cfg.CharSet,
+		cfg.ParseTime,
+		cfg.TimeZone,
 	)
 
 	// Configure GORM
@@ -94,10 +94,10 @@ func OpenGormDB() (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to get SQL DB: %w", err)
 	}
 
-	sqlDB.SetMaxIdleConns(config.GlobalConfig.MySQL.MaxIdleConns)
-	sqlDB.SetMaxOpenConns(config.GlobalConfig.MySQL.MaxOpenConns)
-	sqlDB.SetConnMaxLifetime(config.GetDuration(config.GlobalConfig.MySQL.MaxLifeTime))
-	sqlDB.SetConnMaxIdleTime(config.GetDuration(config.GlobalConfig.MySQL.MaxIdleTime))
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(config.GetDuration(cfg.MaxLifeTime))
+	sqlDB.SetConnMaxIdleTime(config.GetDuration(cfg.MaxIdleTime))
 
 	return db, nil
 }
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+// DefaultCacheTTL is how long a cached example is served
+const DefaultCacheTTL = 30 * time.Minute
+
+// ErrCacheMiss is returned when a requested item is not found in cache
+var ErrCacheMiss = errors.New("cache miss")
+
+// cacheEntry is a cached example and its expiry
+type cacheEntry struct {
+	example   *model.Example
+	expiresAt time.Time
+}
+
+// ExampleCacheRepo implements the example cache repository in process memory
+type ExampleCacheRepo struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.RWMutex
+	examples map[int]cacheEntry
+	names    map[string]int
+}
+
+// NewExampleCacheRepo creates an in-memory example cache, entries expire after ttl, DefaultCacheTTL when not positive
+func NewExampleCacheRepo(ttl time.Duration) repo.IExampleCacheRepo {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &ExampleCacheRepo{
+		ttl:      ttl,
+		now:      time.Now,
+		examples: make(map[int]cacheEntry),
+		names:    make(map[string]int),
+	}
+}
+
+// HealthCheck always succeeds, the cache lives in the process
+func (c *ExampleCacheRepo) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// GetByID gets an example by ID from the cache
+func (c *ExampleCacheRepo) GetByID(ctx context.Context, id int) (*model.Example, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.lookup(id)
+}
+
+// GetByName gets an example by name from the cache
+func (c *ExampleCacheRepo) GetByName(ctx context.Context, name string) (*model.Example, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	id, ok := c.names[name]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	return c.lookup(id)
+}
+
+// Set adds or updates an example in the cache, unless a newer version is already cached
+func (c *ExampleCacheRepo) Set(ctx context.Context, example *model.Example) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if current, ok := c.examples[example.Id]; ok {
+		if now.Before(current.expiresAt) && current.example.Version > example.Version {
+			return nil
+		}
+		delete(c.names, current.example.Name)
+	}
+
+	c.examples[example.Id] = cacheEntry{example: copyExample(example), expiresAt: now.Add(c.ttl)}
+	c.names[example.Name] = example.Id
+
+	return nil
+}
+
+// Delete removes an example from the cache
+func (c *ExampleCacheRepo) Delete(ctx context.Context, id int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if current, ok := c.examples[id]; ok {
+		delete(c.names, current.example.Name)
+		delete(c.examples, id)
+	}
+
+	return nil
+}
+
+// Invalidate removes all examples from the cache
+func (c *ExampleCacheRepo) Invalidate(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.examples = make(map[int]cacheEntry)
+	c.names = make(map[string]int)
+
+	return nil
+}
+
+// lookup returns a copy of the cached example unless it expired, the caller holds the lock
+func (c *ExampleCacheRepo) lookup(id int) (*model.Example, error) {
+	entry, ok := c.examples[id]
+	if !ok || !c.now().Before(entry.expiresAt) {
+		return nil, ErrCacheMiss
+	}
+	return copyExample(entry.example), nil
+}
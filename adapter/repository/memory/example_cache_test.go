@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/model"
+)
+
+func TestExampleCacheRepo(t *testing.T) {
+	cache := NewExampleCacheRepo(time.Minute).(*ExampleCacheRepo)
+	ctx := context.Background()
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	require.NoError(t, cache.HealthCheck(ctx))
+	_, err := cache.GetByID(ctx, 1)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	example := &model.Example{Id: 1, Name: "name", Version: 2}
+	require.NoError(t, cache.Set(ctx, example))
+	example.Name = "changed after caching"
+
+	cached, err := cache.GetByName(ctx, "name")
+	require.NoError(t, err)
+	assert.Equal(t, 2, cached.Version)
+
+	// An older version does not replace a newer one, a newer one replaces the name mapping
+	require.NoError(t, cache.Set(ctx, &model.Example{Id: 1, Name: "name", Version: 1}))
+	cached, err = cache.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cached.Version)
+
+	require.NoError(t, cache.Set(ctx, &model.Example{Id: 1, Name: "renamed", Version: 3}))
+	_, err = cache.GetByName(ctx, "name")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	cached, err = cache.GetByName(ctx, "renamed")
+	require.NoError(t, err)
+	assert.Equal(t, 3, cached.Version)
+
+	// Entries expire after the TTL
+	now = now.Add(time.Minute)
+	_, err = cache.GetByID(ctx, 1)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	require.NoError(t, cache.Set(ctx, &model.Example{Id: 2, Name: "other", Version: 1}))
+	require.NoError(t, cache.Delete(ctx, 2))
+	_, err = cache.GetByName(ctx, "other")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	require.NoError(t, cache.Set(ctx, &model.Example{Id: 3, Name: "third", Version: 1}))
+	require.NoError(t, cache.Invalidate(ctx))
+	_, err = cache.GetByID(ctx, 3)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
@@ -0,0 +1,376 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+// ExampleRepo implements the example repository in memory. Calls with a memory transaction, passed
+// or carried by the context, work on its snapshot; other calls take effect immediately.
+type ExampleRepo struct {
+	store *Store
+}
+
+// NewExampleRepo creates a new in-memory example repository
+func NewExampleRepo(store *Store) repo.IExampleRepo {
+	return &ExampleRepo{
+		store: store,
+	}
+}
+
+// Create creates a new example
+func (r *ExampleRepo) Create(ctx context.Context, tr repo.Transaction, example *model.Example) (*model.Example, error) {
+	err := r.write(ctx, tr, func(t table) error {
+		nameKey := model.NormalizeExampleName(example.Name)
+		if err := checkLiveName(t, nameKey, 0, example.Name); err != nil {
+			return err
+		}
+
+		// Set timestamps
+		now := time.Now()
+		example.CreatedAt = now
+		example.UpdatedAt = now
+
+		// New records start at the first version
+		if example.Version == 0 {
+			example.Version = 1
+		}
+
+		example.Id = t.allocateID()
+		example.NameKey = nameKey
+		t.put(copyExample(example))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return example, nil
+}
+
+// Update updates an existing example if its version still matches the stored one.
+// On success the example's version is advanced to the persisted value.
+func (r *ExampleRepo) Update(ctx context.Context, tr repo.Transaction, example *model.Example) error {
+	return r.write(ctx, tr, func(t table) error {
+		current := t.get(example.Id)
+		if current == nil || current.DeletedAt != nil {
+			return repo.ErrNotFound
+		}
+		if current.Version != example.Version {
+			return model.ErrExampleModified
+		}
+
+		nameKey := model.NormalizeExampleName(example.Name)
+		if err := checkLiveName(t, nameKey, example.Id, example.Name); err != nil {
+			return err
+		}
+
+		example.UpdatedAt = time.Now()
+
+		updated := copyExample(current)
+		updated.Name = example.Name
+		updated.NameKey = nameKey
+		updated.Alias = example.Alias
+		updated.UpdatedAt = example.UpdatedAt
+		updated.Version++
+		t.put(updated)
+
+		example.Version++
+		return nil
+	})
+}
+
+// Delete soft deletes an example by ID
+func (r *ExampleRepo) Delete(ctx context.Context, tr repo.Transaction, id int) error {
+	return r.write(ctx, tr, func(t table) error {
+		current := t.get(id)
+		if current == nil || current.DeletedAt != nil {
+			return repo.ErrNotFound
+		}
+
+		deleted := copyExample(current)
+		now := time.Now()
+		deleted.DeletedAt = &now
+		deleted.Version++
+		t.put(deleted)
+		return nil
+	})
+}
+
+// Restore clears the deletion mark of a soft deleted example
+func (r *ExampleRepo) Restore(ctx context.Context, tr repo.Transaction, id int) error {
+	return r.write(ctx, tr, func(t table) error {
+		current := t.get(id)
+		if current == nil || current.DeletedAt == nil {
+			return repo.ErrNotFound
+		}
+
+		// A live example may have taken the name while this one was deleted
+		if err := checkLiveName(t, current.NameKey, id, ""); err != nil {
+			return err
+		}
+
+		restored := copyExample(current)
+		restored.DeletedAt = nil
+		restored.UpdatedAt = time.Now()
+		restored.Version++
+		t.put(restored)
+		return nil
+	})
+}
+
+// Purge permanently removes an example by ID
+func (r *ExampleRepo) Purge(ctx context.Context, tr repo.Transaction, id int) error {
+	return r.write(ctx, tr, func(t table) error {
+		if t.get(id) == nil {
+			return repo.ErrNotFound
+		}
+		t.remove(id)
+		return nil
+	})
+}
+
+// GetByID retrieves an example by ID
+func (r *ExampleRepo) GetByID(ctx context.Context, tr repo.Transaction, id int) (*model.Example, error) {
+	var example *model.Example
+	err := r.read(ctx, tr, func(t table) error {
+		current := t.get(id)
+		if current == nil || current.DeletedAt != nil {
+			return repo.ErrNotFound
+		}
+		example = copyExample(current)
+		return nil
+	})
+	return example, err
+}
+
+// GetByIDWithDeleted retrieves an example by ID even if it is soft deleted
+func (r *ExampleRepo) GetByIDWithDeleted(ctx context.Context, tr repo.Transaction, id int) (*model.Example, error) {
+	var example *model.Example
+	err := r.read(ctx, tr, func(t table) error {
+		current := t.get(id)
+		if current == nil {
+			return repo.ErrNotFound
+		}
+		example = copyExample(current)
+		return nil
+	})
+	return example, err
+}
+
+// FindByName retrieves a live example by name, ignoring case and Unicode representation
+func (r *ExampleRepo) FindByName(ctx context.Context, tr repo.Transaction, name string) (*model.Example, error) {
+	nameKey := model.NormalizeExampleName(name)
+
+	var example *model.Example
+	err := r.read(ctx, tr, func(t table) error {
+		for _, current := range t.rows() {
+			if current.DeletedAt == nil && current.NameKey == nameKey {
+				example = copyExample(current)
+				return nil
+			}
+		}
+		return repo.ErrNotFound
+	})
+	return example, err
+}
+
+// List retrieves examples matching the query
+func (r *ExampleRepo) List(ctx context.Context, tr repo.Transaction, query repo.ExampleListQuery) ([]*model.Example, error) {
+	var examples []*model.Example
+	err := r.read(ctx, tr, func(t table) error {
+		examples = filterExamples(t, query.Filter)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortExamples(examples, query.SortBy, query.SortDesc)
+
+	// Apply offset and limit
+	if query.Offset > 0 {
+		examples = examples[min(query.Offset, len(examples)):]
+	}
+	if query.Limit > 0 && len(examples) > query.Limit {
+		examples = examples[:query.Limit]
+	}
+
+	return examples, nil
+}
+
+// Count returns the number of examples matching the filter
+func (r *ExampleRepo) Count(ctx context.Context, tr repo.Transaction, filter repo.ExampleFilter) (int64, error) {
+	var total int64
+	err := r.read(ctx, tr, func(t table) error {
+		total = int64(len(filterExamples(t, filter)))
+		return nil
+	})
+	return total, err
+}
+
+// ListByCursor retrieves a page of examples using keyset pagination
+func (r *ExampleRepo) ListByCursor(ctx context.Context, tr repo.Transaction, query repo.ExampleKeysetQuery) ([]*model.Example, error) {
+	var examples []*model.Example
+	err := r.read(ctx, tr, func(t table) error {
+		examples = filterExamples(t, query.Filter)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Scan ascending when reading forward through an ascending list or backward through a descending one
+	ascending := query.SortDesc == query.Backward
+
+	examples = slices.DeleteFunc(examples, func(example *model.Example) bool {
+		return query.Cursor != nil && !afterCursor(example, query.Cursor, ascending)
+	})
+	sortExamples(examples, repo.ExampleSortByCreatedAt, !ascending)
+	if query.Limit > 0 && len(examples) > query.Limit {
+		examples = examples[:query.Limit]
+	}
+
+	// Backward reads are scanned in reverse, restore display order
+	if query.Backward {
+		slices.Reverse(examples)
+	}
+
+	return examples, nil
+}
+
+// transaction returns the memory transaction passed or carried by the context, if any
+func (r *ExampleRepo) transaction(ctx context.Context, tr repo.Transaction) *Transaction {
+	if tx, ok := tr.(*Transaction); ok {
+		return tx
+	}
+	// Fall back to the unit of work carried by the context, e.g. when called with a no-op transaction
+	if ctxTr, found := repo.TransactionFromContext(ctx); found {
+		if tx, ok := ctxTr.(*Transaction); ok {
+			return tx
+		}
+	}
+	return nil
+}
+
+// read runs fn on the view of the transaction, or on the committed rows
+func (r *ExampleRepo) read(ctx context.Context, tr repo.Transaction, fn func(t table) error) error {
+	if tx := r.transaction(ctx, tr); tx != nil {
+		return tx.read(fn)
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return fn(r.store)
+}
+
+// write runs fn on the view of the transaction, or commits its changes immediately
+func (r *ExampleRepo) write(ctx context.Context, tr repo.Transaction, fn func(t table) error) error {
+	if tx := r.transaction(ctx, tr); tx != nil {
+		return tx.write(fn)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return fn(r.store)
+}
+
+// checkLiveName returns the name taken error if a live example other than exceptID uses the name key,
+// name identifies the example in the conflict message when known
+func checkLiveName(t table, nameKey string, exceptID int, name string) error {
+	for _, example := range t.rows() {
+		if example.Id != exceptID && example.DeletedAt == nil && example.NameKey == nameKey {
+			if name == "" {
+				return model.ErrExampleNameTaken
+			}
+			return model.NewExampleNameTakenError(name)
+		}
+	}
+	return nil
+}
+
+// filterExamples returns copies of the rows matching the filter
+func filterExamples(t table, filter repo.ExampleFilter) []*model.Example {
+	examples := make([]*model.Example, 0)
+	for _, example := range t.rows() {
+		if matchesFilter(example, filter) {
+			examples = append(examples, copyExample(example))
+		}
+	}
+	return examples
+}
+
+// matchesFilter reports whether the example matches the filter, substrings match ignoring case
+// like the case-insensitive collations of the SQL stores
+func matchesFilter(example *model.Example, filter repo.ExampleFilter) bool {
+	if !filter.IncludeDeleted && example.DeletedAt != nil {
+		return false
+	}
+	if filter.Name != "" && !containsFold(example.Name, filter.Name) {
+		return false
+	}
+	if filter.Alias != "" && !containsFold(example.Alias, filter.Alias) {
+		return false
+	}
+	if filter.CreatedAfter != nil && example.CreatedAt.Before(*filter.CreatedAfter) {
+		return false
+	}
+	if filter.CreatedBefore != nil && !example.CreatedAt.Before(*filter.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// containsFold reports whether substr is within s, ignoring case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// sortExamples orders the examples by the field, with the ID as tie-breaker in the same direction
+func sortExamples(examples []*model.Example, sortBy repo.ExampleSortField, desc bool) {
+	if !sortBy.IsValid() {
+		sortBy = repo.ExampleSortByID
+	}
+
+	slices.SortFunc(examples, func(a, b *model.Example) int {
+		result := compareField(a, b, sortBy)
+		if result == 0 {
+			result = a.Id - b.Id
+		}
+		if desc {
+			return -result
+		}
+		return result
+	})
+}
+
+// compareField compares two examples by a sort field
+func compareField(a, b *model.Example, sortBy repo.ExampleSortField) int {
+	switch sortBy {
+	case repo.ExampleSortByName:
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case repo.ExampleSortByCreatedAt:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case repo.ExampleSortByUpdatedAt:
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	default:
+		return 0
+	}
+}
+
+// afterCursor reports whether the example follows the cursor in the (created_at, id) scan order
+func afterCursor(example *model.Example, cursor *repo.ExampleCursor, ascending bool) bool {
+	result := example.CreatedAt.Compare(cursor.CreatedAt)
+	if result == 0 {
+		result = example.Id - cursor.ID
+	}
+	if ascending {
+		return result > 0
+	}
+	return result < 0
+}
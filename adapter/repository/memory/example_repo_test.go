@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/domain/repo/repotest"
+)
+
+func TestExampleRepo_CRUD(t *testing.T) {
+	r := NewExampleRepo(NewStore())
+	ctx := context.Background()
+
+	created, err := r.Create(ctx, nil, &model.Example{Name: "Straße", Alias: "alias"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, created.Id)
+	assert.Equal(t, 1, created.Version)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	// Callers get copies, changing them does not change the stored example
+	found, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	found.Alias = "changed without update"
+	found, err = r.FindByName(ctx, nil, "STRASSE")
+	require.NoError(t, err)
+	assert.Equal(t, "alias", found.Alias)
+
+	_, err = r.Create(ctx, nil, &model.Example{Name: "strasse"})
+	assert.True(t, model.IsExampleNameTakenError(err))
+
+	stale := *found
+	found.Alias = "updated"
+	require.NoError(t, r.Update(ctx, nil, found))
+	assert.Equal(t, 2, found.Version)
+	assert.ErrorIs(t, r.Update(ctx, nil, &stale), model.ErrExampleModified)
+	assert.ErrorIs(t, r.Update(ctx, nil, &model.Example{Id: 99, Name: "missing", Version: 1}), repo.ErrNotFound)
+
+	require.NoError(t, r.Delete(ctx, nil, created.Id))
+	assert.ErrorIs(t, r.Delete(ctx, nil, created.Id), repo.ErrNotFound)
+	_, err = r.GetByID(ctx, nil, created.Id)
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+
+	// The name is free again once its example is deleted, which then cannot be restored
+	other, err := r.Create(ctx, nil, &model.Example{Name: "STRASSE"})
+	require.NoError(t, err)
+	assert.ErrorIs(t, r.Restore(ctx, nil, created.Id), model.ErrExampleNameTaken)
+	require.NoError(t, r.Purge(ctx, nil, other.Id))
+	require.NoError(t, r.Restore(ctx, nil, created.Id))
+	assert.ErrorIs(t, r.Restore(ctx, nil, created.Id), repo.ErrNotFound)
+
+	restored, err := r.GetByIDWithDeleted(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+	assert.Equal(t, 4, restored.Version)
+
+	require.NoError(t, r.Purge(ctx, nil, created.Id))
+	assert.ErrorIs(t, r.Purge(ctx, nil, created.Id), repo.ErrNotFound)
+}
+
+func TestExampleRepo_ListAndCount(t *testing.T) {
+	r := NewExampleRepo(NewStore())
+	ctx := context.Background()
+
+	for _, name := range []string{"banana", "Apple", "cherry", "apricot"} {
+		_, err := r.Create(ctx, nil, &model.Example{Name: name, Alias: "fruit"})
+		require.NoError(t, err)
+	}
+	require.NoError(t, r.Delete(ctx, nil, 3))
+
+	examples, err := r.List(ctx, nil, repo.ExampleListQuery{SortBy: repo.ExampleSortByName})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Apple", "apricot", "banana"}, names(examples))
+
+	examples, err = r.List(ctx, nil, repo.ExampleListQuery{Filter: repo.ExampleFilter{Name: "AP"}, SortDesc: true, Offset: 1, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Apple"}, names(examples))
+
+	total, err := r.Count(ctx, nil, repo.ExampleFilter{Alias: "fruit", IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+
+	future := time.Now().Add(time.Hour)
+	total, err = r.Count(ctx, nil, repo.ExampleFilter{CreatedAfter: &future})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestExampleRepo_ListByCursor(t *testing.T) {
+	r := NewExampleRepo(NewStore())
+	ctx := context.Background()
+
+	for i := 1; i <= 5; i++ {
+		_, err := r.Create(ctx, nil, &model.Example{Name: fmt.Sprintf("example %d", i)})
+		require.NoError(t, err)
+	}
+
+	page, err := r.ListByCursor(ctx, nil, repo.ExampleKeysetQuery{SortDesc: true, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example 5", "example 4"}, names(page))
+
+	page, err = r.ListByCursor(ctx, nil, repo.ExampleKeysetQuery{Cursor: repo.NewExampleCursor(page[1]), SortDesc: true, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example 3", "example 2"}, names(page))
+
+	page, err = r.ListByCursor(ctx, nil, repo.ExampleKeysetQuery{Cursor: repo.NewExampleCursor(page[0]), SortDesc: true, Backward: true, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example 5", "example 4"}, names(page))
+}
+
+// names returns the names of the examples in order
+func names(examples []*model.Example) []string {
+	result := make([]string, 0, len(examples))
+	for _, example := range examples {
+		result = append(result, example.Name)
+	}
+	return result
+}
+
+func TestExampleRepo_Contract(t *testing.T) {
+	repotest.RunExampleRepoTests(t, repo.MemoryStore, func(t *testing.T) (repo.IExampleRepo, repo.TransactionFactory) {
+		store := NewStore()
+		return NewExampleRepo(store), NewTransactionFactory(store)
+	})
+}
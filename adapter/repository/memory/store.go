@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"sync"
+
+	"go-hexagonal/domain/model"
+)
+
+// Store keeps the examples of a process in memory, for development and tests without any database.
+// Stored rows are never modified in place, a change replaces the row, so transactions can take
+// snapshots by copying the row references.
+type Store struct {
+	mu       sync.RWMutex
+	examples map[int]*model.Example
+	lastID   int
+}
+
+// NewStore creates an empty in-memory store
+func NewStore() *Store {
+	return &Store{
+		examples: make(map[int]*model.Example),
+	}
+}
+
+// table is a view of the example rows, either the store itself or the snapshot of a transaction
+type table interface {
+	// get returns the row with the ID, nil if there is none
+	get(id int) *model.Example
+	// put adds or replaces a row
+	put(example *model.Example)
+	// remove deletes the row with the ID
+	remove(id int)
+	// rows returns every row in no particular order
+	rows() []*model.Example
+	// allocateID returns the ID of a new row, IDs are not reused even when the row is rolled back
+	allocateID() int
+}
+
+// get returns the committed row, the caller holds the lock
+func (s *Store) get(id int) *model.Example {
+	return s.examples[id]
+}
+
+// put commits a row, the caller holds the write lock
+func (s *Store) put(example *model.Example) {
+	s.examples[example.Id] = example
+}
+
+// remove deletes a committed row, the caller holds the write lock
+func (s *Store) remove(id int) {
+	delete(s.examples, id)
+}
+
+// rows returns the committed rows, the caller holds the lock
+func (s *Store) rows() []*model.Example {
+	rows := make([]*model.Example, 0, len(s.examples))
+	for _, example := range s.examples {
+		rows = append(rows, example)
+	}
+	return rows
+}
+
+// allocateID returns the next ID, the caller holds the write lock
+func (s *Store) allocateID() int {
+	s.lastID++
+	return s.lastID
+}
+
+// snapshot returns the references of the committed rows
+func (s *Store) snapshot() map[int]*model.Example {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := make(map[int]*model.Example, len(s.examples))
+	for id, example := range s.examples {
+		rows[id] = example
+	}
+	return rows
+}
+
+// Len returns the number of stored examples, soft deleted ones included
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.examples)
+}
+
+// Reset removes every example, IDs keep increasing
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.examples = make(map[int]*model.Example)
+}
+
+// copyExample returns a copy of the row, callers never share the stored one
+func copyExample(example *model.Example) *model.Example {
+	if example == nil {
+		return nil
+	}
+	copied := *example
+	if example.DeletedAt != nil {
+		deletedAt := *example.DeletedAt
+		copied.DeletedAt = &deletedAt
+	}
+	return &copied
+}
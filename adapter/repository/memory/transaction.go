@@ -0,0 +1,246 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+// Transaction errors
+const (
+	// ErrTransactionNotActive is returned when a transaction is used before Begin or after it ended
+	ErrTransactionNotActive = repo.RepoError("memory transaction is not active")
+	// ErrReadOnlyTransaction is returned when a read-only transaction is asked to write
+	ErrReadOnlyTransaction = repo.RepoError("memory transaction is read-only")
+)
+
+// Ensure Transaction implements repo.Transaction
+var _ repo.Transaction = (*Transaction)(nil)
+
+// Transaction works on a snapshot of the store taken when it begins, with snapshot isolation:
+// it does not see changes committed after it began, and its own changes stay invisible to others
+// until it commits. The first of two transactions changing the same example to commit wins,
+// the other fails with model.ErrExampleModified. Rolling back discards the changes.
+type Transaction struct {
+	*repo.BaseTransaction
+	state *transactionState
+}
+
+// transactionState is shared by the copies WithContext returns
+type transactionState struct {
+	store *Store
+
+	mu     sync.Mutex
+	active bool
+	ended  bool
+	// base references the rows committed when the transaction began
+	base map[int]*model.Example
+	// writes holds the rows written by the transaction, nil marks a removed row
+	writes map[int]*model.Example
+}
+
+// Begin takes the snapshot the transaction works on
+func (tx *Transaction) Begin() error {
+	s := tx.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.active || s.ended {
+		return fmt.Errorf("memory transaction already began")
+	}
+	s.base = s.store.snapshot()
+	s.writes = make(map[int]*model.Example)
+	s.active = true
+
+	return tx.BaseTransaction.Begin()
+}
+
+// Commit applies the changes to the store, unless a concurrent transaction changed the same examples
+// or took one of their names first
+func (tx *Transaction) Commit() error {
+	s := tx.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.active {
+		return ErrTransactionNotActive
+	}
+	base, writes := s.base, s.writes
+	s.end()
+
+	store := s.store
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// First committer wins, a row changed since the snapshot was written by a concurrent transaction
+	for id := range writes {
+		if store.get(id) != base[id] {
+			return model.ErrExampleModified
+		}
+	}
+	if err := checkCommittedNames(store, writes); err != nil {
+		return err
+	}
+
+	for id, example := range writes {
+		if example == nil {
+			store.remove(id)
+		} else {
+			store.put(example)
+		}
+	}
+
+	return tx.BaseTransaction.Commit()
+}
+
+// Rollback discards the changes, it does nothing once the transaction ended
+func (tx *Transaction) Rollback() error {
+	s := tx.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.active {
+		return nil
+	}
+	s.end()
+
+	return tx.BaseTransaction.Rollback()
+}
+
+// WithContext returns a new transaction with the given context sharing the snapshot and changes
+func (tx *Transaction) WithContext(ctx context.Context) repo.Transaction {
+	return &Transaction{
+		BaseTransaction: repo.NewBaseTransaction(ctx, tx.StoreType(), tx.Options()),
+		state:           tx.state,
+	}
+}
+
+// read runs fn on the view of the transaction
+func (tx *Transaction) read(fn func(t table) error) error {
+	s := tx.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.active {
+		return ErrTransactionNotActive
+	}
+	return fn(s)
+}
+
+// write runs fn on the view of the transaction, unless it is read-only
+func (tx *Transaction) write(fn func(t table) error) error {
+	if tx.Options().ReadOnly {
+		return ErrReadOnlyTransaction
+	}
+	return tx.read(fn)
+}
+
+// end releases the snapshot and changes, the caller holds the lock
+func (s *transactionState) end() {
+	s.active = false
+	s.ended = true
+	s.base = nil
+	s.writes = nil
+}
+
+// get returns the row as seen by the transaction
+func (s *transactionState) get(id int) *model.Example {
+	if example, ok := s.writes[id]; ok {
+		return example
+	}
+	return s.base[id]
+}
+
+// put records a written row
+func (s *transactionState) put(example *model.Example) {
+	s.writes[example.Id] = example
+}
+
+// remove records a removed row
+func (s *transactionState) remove(id int) {
+	s.writes[id] = nil
+}
+
+// rows returns the rows as seen by the transaction
+func (s *transactionState) rows() []*model.Example {
+	rows := make([]*model.Example, 0, len(s.base)+len(s.writes))
+	for id, example := range s.base {
+		if _, written := s.writes[id]; !written {
+			rows = append(rows, example)
+		}
+	}
+	for _, example := range s.writes {
+		if example != nil {
+			rows = append(rows, example)
+		}
+	}
+	return rows
+}
+
+// allocateID takes the next ID from the store, like an auto-increment column it is not given back on rollback
+func (s *transactionState) allocateID() int {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	return s.store.allocateID()
+}
+
+// checkCommittedNames verifies the live rows written keep names unique among the committed rows,
+// the caller holds the store's write lock
+func checkCommittedNames(store *Store, writes map[int]*model.Example) error {
+	taken := make(map[string]bool)
+	for _, example := range store.rows() {
+		if _, written := writes[example.Id]; !written && example.DeletedAt == nil {
+			taken[example.NameKey] = true
+		}
+	}
+
+	for _, example := range writes {
+		if example != nil && example.DeletedAt == nil && taken[example.NameKey] {
+			return model.NewExampleNameTakenError(example.Name)
+		}
+	}
+
+	return nil
+}
+
+// TransactionFactory opens transactions on the in-memory store. It accepts only the memory store
+// type, requests for any other store are rejected.
+type TransactionFactory struct {
+	store *Store
+}
+
+// NewTransactionFactory creates a transaction factory over the in-memory store
+func NewTransactionFactory(store *Store) repo.TransactionFactory {
+	return &TransactionFactory{
+		store: store,
+	}
+}
+
+// NewTransaction creates a new transaction, opts may be *sql.TxOptions or *repo.TransactionOptions
+func (f *TransactionFactory) NewTransaction(ctx context.Context, store repo.StoreType, opts any) (repo.Transaction, error) {
+	if store != repo.MemoryStore {
+		return nil, fmt.Errorf("no client found for store type: %s", store)
+	}
+
+	options := repo.DefaultTransactionOptions()
+	switch opt := opts.(type) {
+	case *sql.TxOptions:
+		if opt != nil {
+			options.ReadOnly = opt.ReadOnly
+		}
+	case *repo.TransactionOptions:
+		if opt != nil {
+			options = opt
+		}
+	}
+
+	return &Transaction{
+		BaseTransaction: repo.NewBaseTransaction(ctx, repo.MemoryStore, options),
+		state:           &transactionState{store: f.store},
+	}, nil
+}
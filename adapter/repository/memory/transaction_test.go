@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+// beginTransaction opens a transaction on the factory
+func beginTransaction(t *testing.T, factory repo.TransactionFactory, opts any) repo.Transaction {
+	t.Helper()
+	tx, err := factory.NewTransaction(context.Background(), repo.MemoryStore, opts)
+	require.NoError(t, err)
+	require.NoError(t, tx.Begin())
+	return tx
+}
+
+func TestTransaction_RollbackDiscardsChanges(t *testing.T) {
+	store := NewStore()
+	r, factory := NewExampleRepo(store), NewTransactionFactory(store)
+	ctx := context.Background()
+
+	existing, err := r.Create(ctx, nil, &model.Example{Name: "existing"})
+	require.NoError(t, err)
+
+	tx := beginTransaction(t, factory, nil)
+	assert.Equal(t, repo.MemoryStore, tx.StoreType())
+
+	created, err := r.Create(ctx, tx, &model.Example{Name: "created"})
+	require.NoError(t, err)
+	require.NoError(t, r.Delete(ctx, tx, existing.Id))
+
+	// The transaction sees its own changes, nobody else does
+	_, err = r.GetByID(ctx, tx, created.Id)
+	require.NoError(t, err)
+	_, err = r.GetByID(ctx, tx, existing.Id)
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+	_, err = r.GetByID(ctx, nil, created.Id)
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+
+	require.NoError(t, tx.Rollback())
+	_, err = r.GetByID(ctx, nil, existing.Id)
+	require.NoError(t, err)
+	_, err = r.GetByIDWithDeleted(ctx, nil, created.Id)
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+	assert.Equal(t, 1, store.Len())
+
+	// An ended transaction cannot be used any more, rolling back again does nothing
+	_, err = r.GetByID(ctx, tx, existing.Id)
+	assert.ErrorIs(t, err, ErrTransactionNotActive)
+	assert.NoError(t, tx.Rollback())
+	assert.ErrorIs(t, tx.Commit(), ErrTransactionNotActive)
+}
+
+func TestTransaction_CommitAppliesChanges(t *testing.T) {
+	store := NewStore()
+	r, factory := NewExampleRepo(store), NewTransactionFactory(store)
+	ctx := context.Background()
+
+	tx := beginTransaction(t, factory, nil)
+
+	// Called with a no-op transaction, the repository joins the one carried by the context
+	txCtx := repo.ContextWithTransaction(ctx, tx)
+	created, err := r.Create(txCtx, repo.NewNoopTransaction(nil), &model.Example{Name: "created"})
+	require.NoError(t, err)
+	created.Alias = "updated"
+	require.NoError(t, r.Update(txCtx, tx.WithContext(txCtx), created))
+
+	require.NoError(t, tx.Commit())
+	found, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", found.Alias)
+	assert.Equal(t, 2, found.Version)
+}
+
+func TestTransaction_SnapshotIsolation(t *testing.T) {
+	store := NewStore()
+	r, factory := NewExampleRepo(store), NewTransactionFactory(store)
+	ctx := context.Background()
+
+	example, err := r.Create(ctx, nil, &model.Example{Name: "name", Alias: "before"})
+	require.NoError(t, err)
+
+	tx := beginTransaction(t, factory, nil)
+
+	// Changes committed after the transaction began stay invisible to it
+	changed := *example
+	changed.Alias = "after"
+	require.NoError(t, r.Update(ctx, nil, &changed))
+	_, err = r.Create(ctx, nil, &model.Example{Name: "later"})
+	require.NoError(t, err)
+
+	seen, err := r.GetByID(ctx, tx, example.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "before", seen.Alias)
+	total, err := r.Count(ctx, tx, repo.ExampleFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.NoError(t, tx.Commit())
+}
+
+func TestTransaction_FirstCommitterWins(t *testing.T) {
+	store := NewStore()
+	r, factory := NewExampleRepo(store), NewTransactionFactory(store)
+	ctx := context.Background()
+
+	example, err := r.Create(ctx, nil, &model.Example{Name: "name"})
+	require.NoError(t, err)
+
+	first := beginTransaction(t, factory, nil)
+	second := beginTransaction(t, factory, nil)
+
+	// Both read version 1, so both updates pass the version check in their snapshot
+	fromFirst, err := r.GetByID(ctx, first, example.Id)
+	require.NoError(t, err)
+	fromFirst.Alias = "first"
+	require.NoError(t, r.Update(ctx, first, fromFirst))
+
+	fromSecond, err := r.GetByID(ctx, second, example.Id)
+	require.NoError(t, err)
+	fromSecond.Alias = "second"
+	require.NoError(t, r.Update(ctx, second, fromSecond))
+
+	require.NoError(t, first.Commit())
+	assert.ErrorIs(t, second.Commit(), model.ErrExampleModified)
+
+	found, err := r.GetByID(ctx, nil, example.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "first", found.Alias)
+}
+
+func TestTransaction_NamesStayUniqueAcrossCommits(t *testing.T) {
+	store := NewStore()
+	r, factory := NewExampleRepo(store), NewTransactionFactory(store)
+	ctx := context.Background()
+
+	first := beginTransaction(t, factory, nil)
+	second := beginTransaction(t, factory, nil)
+
+	_, err := r.Create(ctx, first, &model.Example{Name: "Name"})
+	require.NoError(t, err)
+	_, err = r.Create(ctx, second, &model.Example{Name: "NAME"})
+	require.NoError(t, err)
+
+	require.NoError(t, first.Commit())
+	err = second.Commit()
+	assert.True(t, model.IsExampleNameTakenError(err))
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestTransaction_ReadOnly(t *testing.T) {
+	store := NewStore()
+	r, factory := NewExampleRepo(store), NewTransactionFactory(store)
+	ctx := context.Background()
+
+	for _, opts := range []any{&sql.TxOptions{ReadOnly: true}, &repo.TransactionOptions{ReadOnly: true}} {
+		tx := beginTransaction(t, factory, opts)
+		assert.True(t, tx.Options().ReadOnly)
+
+		_, err := r.Create(ctx, tx, &model.Example{Name: "name"})
+		assert.ErrorIs(t, err, ErrReadOnlyTransaction)
+		_, err = r.List(ctx, tx, repo.ExampleListQuery{})
+		assert.NoError(t, err)
+		require.NoError(t, tx.Commit())
+	}
+
+	_, err := factory.NewTransaction(ctx, repo.RedisStore, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,3 @@
+ALTER TABLE `example`
+    DROP KEY `idx_created_at_id`,
+    DROP COLUMN `version`;
@@ -0,0 +1,3 @@
+ALTER TABLE `example`
+    ADD COLUMN `version` INT(11) UNSIGNED NOT NULL DEFAULT 1 COMMENT 'Optimistic locking version' AFTER `deleted_at`,
+    ADD KEY `idx_created_at_id` (`created_at`, `id`);
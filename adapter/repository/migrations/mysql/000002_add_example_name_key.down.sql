@@ -0,0 +1,2 @@
+ALTER TABLE `example`
+    DROP COLUMN `name_key`;
@@ -0,0 +1,4 @@
+ALTER TABLE `example`
+    DROP KEY `uk_live_name_key`,
+    DROP COLUMN `live_name_key`,
+    MODIFY COLUMN `name_key` VARCHAR(255) COLLATE utf8mb4_bin NULL COMMENT 'Case-folded, NFKC-normalized name';
@@ -0,0 +1,5 @@
+-- Requires the name keys backfilled by `go-hexagonal backfill-name-keys`, fails while one is missing
+ALTER TABLE `example`
+    MODIFY COLUMN `name_key` VARCHAR(255) COLLATE utf8mb4_bin NOT NULL COMMENT 'Case-folded, NFKC-normalized name',
+    ADD COLUMN `live_name_key` VARCHAR(255) COLLATE utf8mb4_bin GENERATED ALWAYS AS (IF(`deleted_at` IS NULL, `name_key`, NULL)) VIRTUAL COMMENT 'Name key of rows that are not soft deleted' AFTER `version`,
+    ADD UNIQUE KEY `uk_live_name_key` (`live_name_key`);
@@ -0,0 +1,19 @@
+CREATE TABLE `outbox` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',
+    `event_id` VARCHAR(64) NOT NULL COMMENT 'Event ID, used by consumers for deduplication',
+    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',
+    `aggregate_id` VARCHAR(64) NOT NULL COMMENT 'Aggregate ID',
+    `schema_version` INT NOT NULL DEFAULT 1 COMMENT 'Schema version of the payload',
+    `correlation_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'ID shared by everything done for the originating request',
+    `causation_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'ID of the request or event that caused the event',
+    `payload` JSON NOT NULL COMMENT 'Event payload',
+    `occurred_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event occurred',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Creation time',
+    `published_at` TIMESTAMP NULL DEFAULT NULL COMMENT 'Delivery time, NULL while pending',
+    `parked_at` TIMESTAMP NULL DEFAULT NULL COMMENT 'Time delivery was given up after too many failed attempts',
+    `attempts` INT(11) UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Failed delivery attempts',
+    `last_error` VARCHAR(1024) DEFAULT NULL COMMENT 'Last delivery error',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_event_id` (`event_id`),
+    KEY `idx_published_at_id` (`published_at`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Domain events awaiting delivery';
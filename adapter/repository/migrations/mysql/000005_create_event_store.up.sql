@@ -0,0 +1,17 @@
+CREATE TABLE `event_store` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',
+    `event_id` VARCHAR(64) NOT NULL COMMENT 'Event ID',
+    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',
+    `aggregate_id` VARCHAR(64) NOT NULL COMMENT 'Aggregate ID',
+    `schema_version` INT NOT NULL DEFAULT 1 COMMENT 'Schema version of the payload',
+    `correlation_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'ID shared by everything done for the originating request',
+    `causation_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'ID of the request or event that caused the event',
+    `payload` JSON NOT NULL COMMENT 'Event payload',
+    `occurred_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event occurred',
+    `processed_at` TIMESTAMP(6) NULL DEFAULT NULL COMMENT 'Time the handlers processed the event',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Creation time',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_event_id` (`event_id`),
+    KEY `idx_occurred_at` (`occurred_at`),
+    KEY `idx_event_name_occurred_at` (`event_name`, `occurred_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Published domain events kept for replay';
@@ -0,0 +1,15 @@
+CREATE TABLE `dead_letter` (
+    `id` VARCHAR(64) NOT NULL COMMENT 'Dead letter ID',
+    `event_id` VARCHAR(64) NOT NULL COMMENT 'Event ID',
+    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',
+    `aggregate_id` VARCHAR(64) NOT NULL COMMENT 'Aggregate ID',
+    `schema_version` INT NOT NULL DEFAULT 1 COMMENT 'Schema version of the payload',
+    `payload` JSON NOT NULL COMMENT 'Event payload',
+    `occurred_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event occurred',
+    `handler` VARCHAR(255) NOT NULL COMMENT 'Name of the failing handler',
+    `error` VARCHAR(1024) NOT NULL COMMENT 'Last handler error',
+    `attempts` INT(11) UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Handler attempts',
+    `failed_at` TIMESTAMP(6) NOT NULL COMMENT 'Time of the last failure',
+    PRIMARY KEY (`id`),
+    KEY `idx_failed_at` (`failed_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Events handlers gave up on after all retries';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `stream_snapshot`;
+DROP TABLE IF EXISTS `event_stream`;
@@ -0,0 +1,22 @@
+CREATE TABLE `event_stream` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',
+    `stream_id` VARCHAR(128) NOT NULL COMMENT 'Stream ID, the aggregate type and ID',
+    `version` INT UNSIGNED NOT NULL COMMENT 'Position of the event in its stream, starting at 1',
+    `event_id` VARCHAR(64) NOT NULL COMMENT 'Event ID',
+    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',
+    `schema_version` INT NOT NULL DEFAULT 1 COMMENT 'Schema version of the payload',
+    `payload` JSON NOT NULL COMMENT 'Event payload',
+    `occurred_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event occurred',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Creation time',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_stream_id_version` (`stream_id`, `version`),
+    UNIQUE KEY `uk_event_id` (`event_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Append-only event streams of event-sourced aggregates';
+
+CREATE TABLE `stream_snapshot` (
+    `stream_id` VARCHAR(128) NOT NULL COMMENT 'Stream ID',
+    `version` INT UNSIGNED NOT NULL COMMENT 'Stream version the state was taken at',
+    `state` JSON NOT NULL COMMENT 'Aggregate state',
+    `created_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the snapshot was taken',
+    PRIMARY KEY (`stream_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Latest snapshot of each event stream';
@@ -0,0 +1,10 @@
+CREATE TABLE `inbox` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',
+    `consumer` VARCHAR(255) NOT NULL COMMENT 'Name of the handler that processed the event',
+    `event_id` VARCHAR(64) NOT NULL COMMENT 'ID of the processed event',
+    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',
+    `processed_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event was processed',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_consumer_event_id` (`consumer`, `event_id`),
+    KEY `idx_processed_at` (`processed_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Events processed by each consumer, for idempotent handling';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_example_created_at_id;
+ALTER TABLE example DROP COLUMN version;
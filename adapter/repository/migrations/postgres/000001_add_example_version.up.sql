@@ -0,0 +1,4 @@
+ALTER TABLE example ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+COMMENT ON COLUMN example.version IS 'Optimistic locking version';
+
+CREATE INDEX idx_example_created_at_id ON example(created_at, id);
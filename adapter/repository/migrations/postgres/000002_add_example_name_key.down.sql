@@ -0,0 +1 @@
+ALTER TABLE example DROP COLUMN name_key;
@@ -0,0 +1,4 @@
+-- The keys of the existing names are set by `go-hexagonal backfill-name-keys` before 000003 makes
+-- them unique, it normalizes them the way the application does
+ALTER TABLE example ADD COLUMN name_key VARCHAR(255);
+COMMENT ON COLUMN example.name_key IS 'Case-folded, NFKC-normalized name';
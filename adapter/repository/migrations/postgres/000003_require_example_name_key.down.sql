@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uk_example_live_name_key;
+ALTER TABLE example ALTER COLUMN name_key DROP NOT NULL;
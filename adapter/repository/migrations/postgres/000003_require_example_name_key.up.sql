@@ -0,0 +1,3 @@
+-- Requires the name keys backfilled by `go-hexagonal backfill-name-keys`, fails while one is missing
+ALTER TABLE example ALTER COLUMN name_key SET NOT NULL;
+CREATE UNIQUE INDEX uk_example_live_name_key ON example(name_key) WHERE deleted_at IS NULL;
@@ -0,0 +1,23 @@
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_id VARCHAR(64) NOT NULL,
+    event_name VARCHAR(255) NOT NULL,
+    aggregate_id VARCHAR(64) NOT NULL,
+    schema_version INT NOT NULL DEFAULT 1,
+    correlation_id VARCHAR(128) NOT NULL DEFAULT '',
+    causation_id VARCHAR(128) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP,
+    parked_at TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error VARCHAR(1024)
+);
+
+CREATE UNIQUE INDEX uk_outbox_event_id ON outbox(event_id);
+CREATE INDEX idx_outbox_pending ON outbox(id) WHERE published_at IS NULL AND parked_at IS NULL;
+COMMENT ON TABLE outbox IS 'Domain events awaiting delivery';
+COMMENT ON COLUMN outbox.event_id IS 'Event ID, used by consumers for deduplication';
+COMMENT ON COLUMN outbox.published_at IS 'Delivery time, NULL while pending';
+COMMENT ON COLUMN outbox.parked_at IS 'Time delivery was given up after too many failed attempts';
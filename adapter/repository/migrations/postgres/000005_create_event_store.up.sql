@@ -0,0 +1,19 @@
+CREATE TABLE event_store (
+    id BIGSERIAL PRIMARY KEY,
+    event_id VARCHAR(64) NOT NULL,
+    event_name VARCHAR(255) NOT NULL,
+    aggregate_id VARCHAR(64) NOT NULL,
+    schema_version INT NOT NULL DEFAULT 1,
+    correlation_id VARCHAR(128) NOT NULL DEFAULT '',
+    causation_id VARCHAR(128) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    processed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX uk_event_store_event_id ON event_store(event_id);
+CREATE INDEX idx_event_store_occurred_at ON event_store(occurred_at);
+CREATE INDEX idx_event_store_event_name_occurred_at ON event_store(event_name, occurred_at);
+COMMENT ON TABLE event_store IS 'Published domain events kept for replay';
+COMMENT ON COLUMN event_store.processed_at IS 'Time the handlers processed the event';
@@ -0,0 +1,16 @@
+CREATE TABLE dead_letter (
+    id VARCHAR(64) PRIMARY KEY,
+    event_id VARCHAR(64) NOT NULL,
+    event_name VARCHAR(255) NOT NULL,
+    aggregate_id VARCHAR(64) NOT NULL,
+    schema_version INT NOT NULL DEFAULT 1,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    handler VARCHAR(255) NOT NULL,
+    error VARCHAR(1024) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    failed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_dead_letter_failed_at ON dead_letter(failed_at);
+COMMENT ON TABLE dead_letter IS 'Events handlers gave up on after all retries';
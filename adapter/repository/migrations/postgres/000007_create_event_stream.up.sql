@@ -0,0 +1,24 @@
+CREATE TABLE event_stream (
+    id BIGSERIAL PRIMARY KEY,
+    stream_id VARCHAR(128) NOT NULL,
+    version INTEGER NOT NULL,
+    event_id VARCHAR(64) NOT NULL,
+    event_name VARCHAR(255) NOT NULL,
+    schema_version INT NOT NULL DEFAULT 1,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX uk_event_stream_stream_id_version ON event_stream(stream_id, version);
+CREATE UNIQUE INDEX uk_event_stream_event_id ON event_stream(event_id);
+COMMENT ON TABLE event_stream IS 'Append-only event streams of event-sourced aggregates';
+
+CREATE TABLE stream_snapshot (
+    stream_id VARCHAR(128) PRIMARY KEY,
+    version INTEGER NOT NULL,
+    state JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+COMMENT ON TABLE stream_snapshot IS 'Latest snapshot of each event stream';
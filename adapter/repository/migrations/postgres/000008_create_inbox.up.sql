@@ -0,0 +1,11 @@
+CREATE TABLE inbox (
+    id BIGSERIAL PRIMARY KEY,
+    consumer VARCHAR(255) NOT NULL,
+    event_id VARCHAR(64) NOT NULL,
+    event_name VARCHAR(255) NOT NULL,
+    processed_at TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX uk_inbox_consumer_event_id ON inbox(consumer, event_id);
+CREATE INDEX idx_inbox_processed_at ON inbox(processed_at);
+COMMENT ON TABLE inbox IS 'Events processed by each consumer, for idempotent handling';
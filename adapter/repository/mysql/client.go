@@ -19,6 +19,8 @@ import (
 // MySQLClient represents a MySQL database client using GORM
 type MySQLClient struct {
 	DB *gorm.DB
+	// Replicas routes reads to the read replicas, nil when none are configured
+	Replicas *repository.ReplicaRouter
 }
 
 // NewMySQLClient creates a new MySQL client
@@ -40,6 +42,14 @@ func (c *MySQLClient) GetDB(ctx context.Context) *gorm.DB {
 	return c.DB.WithContext(ctx)
 }
 
+// GetReadDB returns the GORM database instance for reads with context, a replica when replicas are configured
+func (c *MySQLClient) GetReadDB(ctx context.Context) *gorm.DB {
+	if c.Replicas == nil {
+		return c.GetDB(ctx)
+	}
+	return c.Replicas.Reader(ctx)
+}
+
 // SetDB sets the GORM database instance
 func (c *MySQLClient) SetDB(db *gorm.DB) {
 	c.DB = db
@@ -47,6 +57,12 @@ func (c *MySQLClient) SetDB(db *gorm.DB) {
 
 // Close closes the MySQL database connection
 func (c *MySQLClient) Close(ctx context.Context) error {
+	if c.Replicas != nil {
+		if err := c.Replicas.Close(); err != nil {
+			return err
+		}
+	}
+
 	sqlDB, err := c.GetDB(ctx).DB()
 	if err != nil {
 		return fmt.Errorf("failed to get MySQL DB: %w", err)
@@ -48,6 +48,24 @@ func (e *Example) Delete(ctx context.Context, tr repo.Transaction, id int) error
 	return nil
 }
 
+// Restore implements IExampleRepo.Restore
+func (e *Example) Restore(ctx context.Context, tr repo.Transaction, id int) error {
+	// Implement actual database logic for restoring
+	return nil
+}
+
+// Purge implements IExampleRepo.Purge
+func (e *Example) Purge(ctx context.Context, tr repo.Transaction, id int) error {
+	// Implement actual database logic for purging
+	return nil
+}
+
+// GetByIDWithDeleted implements IExampleRepo.GetByIDWithDeleted
+func (e *Example) GetByIDWithDeleted(ctx context.Context, tr repo.Transaction, id int) (*model.Example, error) {
+	// Implement actual database logic for fetching including deleted records
+	return e.GetByID(ctx, tr, id)
+}
+
 // FindByName implements IExampleRepo.FindByName
 func (e *Example) FindByName(ctx context.Context, tr repo.Transaction, name string) (*model.Example, error) {
 	// Implement actual database logic for finding by name
@@ -60,6 +78,24 @@ func (e *Example) FindByName(ctx context.Context, tr repo.Transaction, name stri
 	}, nil
 }
 
+// List implements IExampleRepo.List
+func (e *Example) List(ctx context.Context, tr repo.Transaction, query repo.ExampleListQuery) ([]*model.Example, error) {
+	// Implement actual database logic for listing
+	return []*model.Example{}, nil
+}
+
+// Count implements IExampleRepo.Count
+func (e *Example) Count(ctx context.Context, tr repo.Transaction, filter repo.ExampleFilter) (int64, error) {
+	// Implement actual database logic for counting
+	return 0, nil
+}
+
+// ListByCursor implements IExampleRepo.ListByCursor
+func (e *Example) ListByCursor(ctx context.Context, tr repo.Transaction, query repo.ExampleKeysetQuery) ([]*model.Example, error) {
+	// Implement actual database logic for keyset listing
+	return []*model.Example{}, nil
+}
+
 // WithTransaction implements IExampleRepo.WithTransaction
 func (e *Example) WithTransaction(ctx context.Context, tx repo.Transaction) repo.IExampleRepo {
 	// Return the same repository for now, as it's a mock
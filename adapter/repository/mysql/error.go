@@ -0,0 +1,31 @@
+package mysql
+
+import (
+	"errors"
+
+	driver "github.com/go-sql-driver/mysql"
+
+	"go-hexagonal/domain/model"
+)
+
+// erDupEntry is the MySQL server error number for a duplicate key on a unique index
+const erDupEntry = 1062
+
+// translateError maps MySQL driver errors to domain errors.
+// name, when known, identifies the example in the conflict message.
+func translateError(err error, name string) error {
+	if isDuplicateKey(err) {
+		// The only unique index besides the primary key is the live name key
+		if name == "" {
+			return model.ErrExampleNameTaken
+		}
+		return model.NewExampleNameTakenError(name)
+	}
+	return err
+}
+
+// isDuplicateKey reports whether err is a unique index violation
+func isDuplicateKey(err error) bool {
+	var mysqlErr *driver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == erDupEntry
+}
@@ -0,0 +1,28 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+
+	"go-hexagonal/domain/model"
+)
+
+func TestTranslateError(t *testing.T) {
+	duplicate := &driver.MySQLError{Number: erDupEntry, Message: "Duplicate entry 'test' for key 'uk_live_name_key'"}
+
+	err := translateError(fmt.Errorf("insert: %w", duplicate), "Test")
+	assert.True(t, model.IsExampleNameTakenError(err))
+	assert.Contains(t, err.Error(), "Test")
+
+	assert.ErrorIs(t, translateError(duplicate, ""), model.ErrExampleNameTaken)
+
+	other := &driver.MySQLError{Number: 1213, Message: "Deadlock found"}
+	assert.Same(t, other, translateError(other, "Test"))
+
+	plain := errors.New("connection refused")
+	assert.Equal(t, plain, translateError(plain, "Test"))
+}
@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/domain/event"
+	"go-hexagonal/domain/repo"
+)
+
+// EventStore implements the event store for MySQL
+type EventStore struct {
+	client   *MySQLClient
+	registry *event.TypeRegistry
+}
+
+// NewEventStore creates a new MySQL event store decoding events with the default type registry
+func NewEventStore(client *MySQLClient) event.EventLog {
+	return &EventStore{
+		client:   client,
+		registry: event.DefaultTypeRegistry,
+	}
+}
+
+// SaveEvent persists an event, events that are already stored are left untouched
+func (s *EventStore) SaveEvent(ctx context.Context, evt event.Event) error {
+	record, err := repository.NewEventRecord(evt)
+	if err != nil {
+		return err
+	}
+
+	return s.client.GetDB(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(record).Error
+}
+
+// GetEvents retrieves events that occurred at or after since
+func (s *EventStore) GetEvents(ctx context.Context, eventType string, since time.Time) ([]event.Event, error) {
+	return s.find(s.query(ctx, eventType).Where("occurred_at >= ?", since))
+}
+
+// GetEventsBetween retrieves events that occurred in [from, to)
+func (s *EventStore) GetEventsBetween(ctx context.Context, eventType string, from, to time.Time) ([]event.Event, error) {
+	return s.find(s.query(ctx, eventType).Where("occurred_at >= ? AND occurred_at < ?", from, to))
+}
+
+// ReadEvents retrieves up to limit events stored after the given sequence, the sequence is the record ID
+func (s *EventStore) ReadEvents(ctx context.Context, after int64, limit int) ([]event.StoredEvent, error) {
+	var records []*repository.EventRecord
+	err := s.client.GetDB(ctx).
+		Where("id > ?", after).
+		Order("id ASC").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.DecodeStoredEvents(records, s.registry)
+}
+
+// CountEvents counts the events with the given names stored after the given sequence in a single query
+func (s *EventStore) CountEvents(ctx context.Context, after int64, eventNames []string) (int64, time.Time, error) {
+	if len(eventNames) == 0 {
+		return 0, time.Time{}, nil
+	}
+
+	var result struct {
+		Count  int64
+		Latest sql.NullTime
+	}
+	err := s.client.GetDB(ctx).Model(&repository.EventRecord{}).
+		Select("COUNT(*) AS count, MAX(occurred_at) AS latest").
+		Where("id > ? AND event_name IN ?", after, eventNames).
+		Scan(&result).Error
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return result.Count, result.Latest.Time, nil
+}
+
+// MarkProcessed records when an event was processed
+func (s *EventStore) MarkProcessed(ctx context.Context, eventID string) error {
+	result := s.client.GetDB(ctx).Model(&repository.EventRecord{}).
+		Where("event_id = ?", eventID).
+		Update("processed_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+// query starts a query for events of the given type, or of all types when it is empty
+func (s *EventStore) query(ctx context.Context, eventType string) *gorm.DB {
+	db := s.client.GetDB(ctx).Model(&repository.EventRecord{})
+	if eventType != "" {
+		db = db.Where("event_name = ?", eventType)
+	}
+	return db
+}
+
+// find loads the matching events in the order they occurred
+func (s *EventStore) find(db *gorm.DB) ([]event.Event, error) {
+	var records []*repository.EventRecord
+	if err := db.Order("occurred_at ASC, id ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return repository.DecodeEventRecords(records, s.registry)
+}
@@ -0,0 +1,27 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/domain/repo/repotest"
+)
+
+func TestExampleRepo_Contract(t *testing.T) {
+	// Skip this test in CI environments or when running quick tests
+	if testing.Short() {
+		t.Skip("Skipping MySQL container test in short mode")
+	}
+
+	// One container serves every subtest, each starts from an empty table
+	client := GetTestDB(t, SetupMySQLContainer(t))
+	factory := repository.NewTransactionFactory(map[repository.StoreType]any{repository.MySQLStore: client})
+
+	repotest.RunExampleRepoTests(t, repo.MySQLStore, func(t *testing.T) (repo.IExampleRepo, repo.TransactionFactory) {
+		require.NoError(t, client.DB.Exec("TRUNCATE TABLE example").Error)
+		return NewExampleRepo(client), factory
+	})
+}
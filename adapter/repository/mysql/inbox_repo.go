@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/domain/repo"
+)
+
+// InboxRepo implements the inbox repository for MySQL
+type InboxRepo struct {
+	client *MySQLClient
+}
+
+// NewInboxRepo creates a new MySQL inbox repository
+func NewInboxRepo(client *MySQLClient) repo.IInboxRepo {
+	return &InboxRepo{
+		client: client,
+	}
+}
+
+// Processed reports whether the consumer already processed the event
+func (r *InboxRepo) Processed(ctx context.Context, tr repo.Transaction, consumer, eventID string) (bool, error) {
+	var count int64
+	if err := r.getDB(ctx, tr).Model(&repo.InboxMessage{}).
+		Where("consumer = ? AND event_id = ?", consumer, eventID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Save records a processed event, the unique (consumer, event_id) key rejects duplicates
+func (r *InboxRepo) Save(ctx context.Context, tr repo.Transaction, message *repo.InboxMessage) error {
+	if message.ProcessedAt.IsZero() {
+		message.ProcessedAt = time.Now()
+	}
+
+	if err := r.getDB(ctx, tr).Create(message).Error; err != nil {
+		if isDuplicateKey(err) {
+			return repo.ErrInboxDuplicate
+		}
+		return err
+	}
+
+	return nil
+}
+
+// DeleteBefore removes the records of events processed before the given time
+func (r *InboxRepo) DeleteBefore(ctx context.Context, tr repo.Transaction, before time.Time) (int64, error) {
+	result := r.getDB(ctx, tr).Where("processed_at < ?", before).Delete(&repo.InboxMessage{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// getDB returns the appropriate database connection based on transaction
+func (r *InboxRepo) getDB(ctx context.Context, tr repo.Transaction) *gorm.DB {
+	// Fall back to the unit of work carried by the context, e.g. when called with a no-op transaction
+	if _, ok := tr.(*repository.Transaction); !ok {
+		if ctxTr, found := repo.TransactionFromContext(ctx); found {
+			tr = ctxTr
+		}
+	}
+
+	if sqlTr, ok := tr.(*repository.Transaction); ok && sqlTr.Session != nil {
+		return sqlTr.Session.WithContext(tr.Context())
+	}
+	return r.client.GetDB(ctx)
+}
@@ -0,0 +1,128 @@
+package mysql
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/domain/repo"
+)
+
+// maxOutboxErrorLength bounds the stored delivery error to the column size
+const maxOutboxErrorLength = 1024
+
+// OutboxRepo implements the outbox repository for MySQL
+type OutboxRepo struct {
+	client *MySQLClient
+}
+
+// NewOutboxRepo creates a new MySQL outbox repository
+func NewOutboxRepo(client *MySQLClient) repo.IOutboxRepo {
+	return &OutboxRepo{
+		client: client,
+	}
+}
+
+// Save records outbox messages
+func (r *OutboxRepo) Save(ctx context.Context, tr repo.Transaction, messages ...*repo.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, message := range messages {
+		message.CreatedAt = now
+	}
+
+	return r.getDB(ctx, tr).Create(messages).Error
+}
+
+// FetchPending returns unpublished messages that are not parked in insertion order, locking them for the transaction
+func (r *OutboxRepo) FetchPending(ctx context.Context, tr repo.Transaction, limit int) ([]*repo.OutboxMessage, error) {
+	var messages []*repo.OutboxMessage
+
+	db := r.getDB(ctx, tr).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("published_at IS NULL AND parked_at IS NULL").
+		Order("id ASC")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// MarkPublished flags a message as delivered
+func (r *OutboxRepo) MarkPublished(ctx context.Context, tr repo.Transaction, id int64) error {
+	result := r.getDB(ctx, tr).Model(&repo.OutboxMessage{}).
+		Where("id = ?", id).
+		Update("published_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt
+func (r *OutboxRepo) MarkFailed(ctx context.Context, tr repo.Transaction, id int64, reason string) error {
+	return r.recordFailure(ctx, tr, id, reason, false)
+}
+
+// Park records the last failed delivery attempt and takes the message out of delivery
+func (r *OutboxRepo) Park(ctx context.Context, tr repo.Transaction, id int64, reason string) error {
+	return r.recordFailure(ctx, tr, id, reason, true)
+}
+
+// recordFailure counts a failed delivery attempt, parking the message if asked to
+func (r *OutboxRepo) recordFailure(ctx context.Context, tr repo.Transaction, id int64, reason string, park bool) error {
+	if len(reason) > maxOutboxErrorLength {
+		reason = strings.ToValidUTF8(reason[:maxOutboxErrorLength], "")
+	}
+
+	updates := map[string]any{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": reason,
+	}
+	if park {
+		updates["parked_at"] = time.Now()
+	}
+
+	result := r.getDB(ctx, tr).Model(&repo.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+// getDB returns the appropriate database connection based on transaction
+func (r *OutboxRepo) getDB(ctx context.Context, tr repo.Transaction) *gorm.DB {
+	// Fall back to the unit of work carried by the context, e.g. when called with a no-op transaction
+	if _, ok := tr.(*repository.Transaction); !ok {
+		if ctxTr, found := repo.TransactionFromContext(ctx); found {
+			tr = ctxTr
+		}
+	}
+
+	if sqlTr, ok := tr.(*repository.Transaction); ok && sqlTr.Session != nil {
+		return sqlTr.Session.WithContext(tr.Context())
+	}
+	return r.client.GetDB(ctx)
+}
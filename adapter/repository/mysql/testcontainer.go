@@ -37,14 +37,101 @@ func SetupMySQLContainer(t *testing.T) *config.MySQLConfig {
 	initSQL := "CREATE TABLE IF NOT EXISTS `example` (\n" +
 		"    `id` INT(11) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',\n" +
 		"    `name` VARCHAR(255) NOT NULL COMMENT 'Name',\n" +
+		"    `name_key` VARCHAR(255) COLLATE utf8mb4_bin NOT NULL COMMENT 'Case-folded, NFKC-normalized name',\n" +
 		"    `alias` VARCHAR(255) DEFAULT NULL COMMENT 'Alias',\n" +
 		"    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Creation time',\n" +
 		"    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Update time',\n" +
 		"    `deleted_at` TIMESTAMP NULL DEFAULT NULL COMMENT 'Deletion time',\n" +
+		"    `version` INT(11) UNSIGNED NOT NULL DEFAULT 1 COMMENT 'Optimistic locking version',\n" +
+		"    `live_name_key` VARCHAR(255) COLLATE utf8mb4_bin GENERATED ALWAYS AS (IF(`deleted_at` IS NULL, `name_key`, NULL)) VIRTUAL COMMENT 'Name key of rows that are not soft deleted',\n" +
 		"    PRIMARY KEY (`id`),\n" +
+		"    UNIQUE KEY `uk_live_name_key` (`live_name_key`),\n" +
 		"    KEY `idx_name` (`name`),\n" +
-		"    KEY `idx_deleted_at` (`deleted_at`)\n" +
-		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Example table for Hexagonal Architecture';"
+		"    KEY `idx_deleted_at` (`deleted_at`),\n" +
+		"    KEY `idx_created_at_id` (`created_at`, `id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Example table for Hexagonal Architecture';\n\n" +
+		"CREATE TABLE IF NOT EXISTS `outbox` (\n" +
+		"    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',\n" +
+		"    `event_id` VARCHAR(64) NOT NULL COMMENT 'Event ID, used by consumers for deduplication',\n" +
+		"    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',\n" +
+		"    `aggregate_id` VARCHAR(64) NOT NULL COMMENT 'Aggregate ID',\n" +
+		"    `schema_version` INT NOT NULL DEFAULT 1 COMMENT 'Schema version of the payload',\n" +
+		"    `correlation_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'ID shared by everything done for the originating request',\n" +
+		"    `causation_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'ID of the request or event that caused the event',\n" +
+		"    `payload` JSON NOT NULL COMMENT 'Event payload',\n" +
+		"    `occurred_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event occurred',\n" +
+		"    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Creation time',\n" +
+		"    `published_at` TIMESTAMP NULL DEFAULT NULL COMMENT 'Delivery time, NULL while pending',\n" +
+		"    `parked_at` TIMESTAMP NULL DEFAULT NULL COMMENT 'Time delivery was given up after too many failed attempts',\n" +
+		"    `attempts` INT(11) UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Failed delivery attempts',\n" +
+		"    `last_error` VARCHAR(1024) DEFAULT NULL COMMENT 'Last delivery error',\n" +
+		"    PRIMARY KEY (`id`),\n" +
+		"    UNIQUE KEY `uk_event_id` (`event_id`),\n" +
+		"    KEY `idx_published_at_id` (`published_at`, `id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Domain events awaiting delivery';\n\n" +
+		"CREATE TABLE IF NOT EXISTS `event_store` (\n" +
+		"    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',\n" +
+		"    `event_id` VARCHAR(64) NOT NULL COMMENT 'Event ID',\n" +
+		"    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',\n" +
+		"    `aggregate_id` VARCHAR(64) NOT NULL COMMENT 'Aggregate ID',\n" +
+		"    `schema_version` INT NOT NULL DEFAULT 1 COMMENT 'Schema version of the payload',\n" +
+		"    `correlation_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'ID shared by everything done for the originating request',\n" +
+		"    `causation_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'ID of the request or event that caused the event',\n" +
+		"    `payload` JSON NOT NULL COMMENT 'Event payload',\n" +
+		"    `occurred_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event occurred',\n" +
+		"    `processed_at` TIMESTAMP(6) NULL DEFAULT NULL COMMENT 'Time the handlers processed the event',\n" +
+		"    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Creation time',\n" +
+		"    PRIMARY KEY (`id`),\n" +
+		"    UNIQUE KEY `uk_event_id` (`event_id`),\n" +
+		"    KEY `idx_occurred_at` (`occurred_at`),\n" +
+		"    KEY `idx_event_name_occurred_at` (`event_name`, `occurred_at`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Published domain events kept for replay';\n\n" +
+		"CREATE TABLE IF NOT EXISTS `dead_letter` (\n" +
+		"    `id` VARCHAR(64) NOT NULL COMMENT 'Dead letter ID',\n" +
+		"    `event_id` VARCHAR(64) NOT NULL COMMENT 'Event ID',\n" +
+		"    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',\n" +
+		"    `aggregate_id` VARCHAR(64) NOT NULL COMMENT 'Aggregate ID',\n" +
+		"    `schema_version` INT NOT NULL DEFAULT 1 COMMENT 'Schema version of the payload',\n" +
+		"    `payload` JSON NOT NULL COMMENT 'Event payload',\n" +
+		"    `occurred_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event occurred',\n" +
+		"    `handler` VARCHAR(255) NOT NULL COMMENT 'Name of the failing handler',\n" +
+		"    `error` VARCHAR(1024) NOT NULL COMMENT 'Last handler error',\n" +
+		"    `attempts` INT(11) UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Handler attempts',\n" +
+		"    `failed_at` TIMESTAMP(6) NOT NULL COMMENT 'Time of the last failure',\n" +
+		"    PRIMARY KEY (`id`),\n" +
+		"    KEY `idx_failed_at` (`failed_at`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Events handlers gave up on after all retries';\n\n" +
+		"CREATE TABLE IF NOT EXISTS `event_stream` (\n" +
+		"    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',\n" +
+		"    `stream_id` VARCHAR(128) NOT NULL COMMENT 'Stream ID, the aggregate type and ID',\n" +
+		"    `version` INT UNSIGNED NOT NULL COMMENT 'Position of the event in its stream, starting at 1',\n" +
+		"    `event_id` VARCHAR(64) NOT NULL COMMENT 'Event ID',\n" +
+		"    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',\n" +
+		"    `schema_version` INT NOT NULL DEFAULT 1 COMMENT 'Schema version of the payload',\n" +
+		"    `payload` JSON NOT NULL COMMENT 'Event payload',\n" +
+		"    `occurred_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event occurred',\n" +
+		"    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Creation time',\n" +
+		"    PRIMARY KEY (`id`),\n" +
+		"    UNIQUE KEY `uk_stream_id_version` (`stream_id`, `version`),\n" +
+		"    UNIQUE KEY `uk_event_id` (`event_id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Append-only event streams of event-sourced aggregates';\n\n" +
+		"CREATE TABLE IF NOT EXISTS `stream_snapshot` (\n" +
+		"    `stream_id` VARCHAR(128) NOT NULL COMMENT 'Stream ID',\n" +
+		"    `version` INT UNSIGNED NOT NULL COMMENT 'Stream version the state was taken at',\n" +
+		"    `state` JSON NOT NULL COMMENT 'Aggregate state',\n" +
+		"    `created_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the snapshot was taken',\n" +
+		"    PRIMARY KEY (`stream_id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Latest snapshot of each event stream';\n\n" +
+		"CREATE TABLE IF NOT EXISTS `inbox` (\n" +
+		"    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Primary key ID',\n" +
+		"    `consumer` VARCHAR(255) NOT NULL COMMENT 'Name of the handler that processed the event',\n" +
+		"    `event_id` VARCHAR(64) NOT NULL COMMENT 'ID of the processed event',\n" +
+		"    `event_name` VARCHAR(255) NOT NULL COMMENT 'Event name',\n" +
+		"    `processed_at` TIMESTAMP(6) NOT NULL COMMENT 'Time the event was processed',\n" +
+		"    PRIMARY KEY (`id`),\n" +
+		"    UNIQUE KEY `uk_consumer_event_id` (`consumer`, `event_id`),\n" +
+		"    KEY `idx_processed_at` (`processed_at`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Events processed by each consumer, for idempotent handling';"
 
 	if _, err := tempFile.WriteString(initSQL); err != nil {
 		t.Fatalf("Failed to write to temp file: %v", err)
@@ -20,6 +20,8 @@ import (
 // PostgreSQLClient represents a PostgreSQL database client using GORM
 type PostgreSQLClient struct {
 	DB *gorm.DB
+	// Replicas routes reads to the read replicas, nil when none are configured
+	Replicas *repository.ReplicaRouter
 }
 
 // NewPostgreSQLClient creates a new PostgreSQL client
@@ -36,11 +38,61 @@ func NewPostgreSQLClient(dsn string) (*PostgreSQLClient, error) {
 	return &PostgreSQLClient{DB: db}, nil
 }
 
+// NewPostgreSQLClientFromConfig creates a PostgreSQL client for the configuration, sizing its pool from it when set
+func NewPostgreSQLClientFromConfig(cfg *config.PostgreSQLConfig) (*PostgreSQLClient, error) {
+	client, err := NewPostgreSQLClient(DSN(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxConnections > 0 {
+		err := ConfigureConnectionPool(client.DB,
+			int(cfg.MinConnections),
+			int(cfg.MaxConnections),
+			time.Duration(cfg.MaxConnLifetime)*time.Second,
+			time.Duration(cfg.IdleTimeout)*time.Second,
+		)
+		if err != nil {
+			_ = client.Close(context.Background())
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// DSN builds the connection string of the PostgreSQL configuration
+func DSN(cfg *config.PostgreSQLConfig) string {
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host,
+		cfg.Port,
+		cfg.User,
+		cfg.Password,
+		cfg.Database,
+		cfg.SSLMode,
+	)
+	if cfg.TimeZone != "" {
+		dsn += " TimeZone=" + cfg.TimeZone
+	}
+	if cfg.Options != "" {
+		dsn += " " + cfg.Options
+	}
+	return dsn
+}
+
 // GetDB returns the GORM database instance with context
 func (c *PostgreSQLClient) GetDB(ctx context.Context) *gorm.DB {
 	return c.DB.WithContext(ctx)
 }
 
+// GetReadDB returns the GORM database instance for reads with context, a replica when replicas are configured
+func (c *PostgreSQLClient) GetReadDB(ctx context.Context) *gorm.DB {
+	if c.Replicas == nil {
+		return c.GetDB(ctx)
+	}
+	return c.Replicas.Reader(ctx)
+}
+
 // SetDB sets the GORM database instance
 func (c *PostgreSQLClient) SetDB(db *gorm.DB) {
 	c.DB = db
@@ -48,6 +100,12 @@ func (c *PostgreSQLClient) SetDB(db *gorm.DB) {
 
 // Close closes the PostgreSQL database connection
 func (c *PostgreSQLClient) Close(ctx context.Context) error {
+	if c.Replicas != nil {
+		if err := c.Replicas.Close(); err != nil {
+			return err
+		}
+	}
+
 	sqlDB, err := c.GetDB(ctx).DB()
 	if err != nil {
 		return fmt.Errorf("failed to get PostgreSQL DB: %w", err)
@@ -0,0 +1,27 @@
+package postgre
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-hexagonal/config"
+)
+
+func TestDSN(t *testing.T) {
+	cfg := &config.PostgreSQLConfig{
+		User:     "user",
+		Password: "secret",
+		Host:     "db.internal",
+		Port:     5433,
+		Database: "app",
+		SSLMode:  "require",
+	}
+	assert.Equal(t, "host=db.internal port=5433 user=user password=secret dbname=app sslmode=require", DSN(cfg))
+
+	cfg.TimeZone = "UTC"
+	cfg.Options = "application_name=go-hexagonal"
+	assert.Equal(t,
+		"host=db.internal port=5433 user=user password=secret dbname=app sslmode=require TimeZone=UTC application_name=go-hexagonal",
+		DSN(cfg))
+}
@@ -0,0 +1,98 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/domain/event"
+)
+
+// maxDeadLetterErrorLength bounds the stored handler error to the column size
+const maxDeadLetterErrorLength = 1024
+
+// DeadLetterStore implements the dead letter store for PostgreSQL
+type DeadLetterStore struct {
+	client   *PostgreSQLClient
+	registry *event.TypeRegistry
+}
+
+// NewDeadLetterStore creates a new PostgreSQL dead letter store decoding events with the default type registry
+func NewDeadLetterStore(client *PostgreSQLClient) event.DeadLetterStore {
+	return &DeadLetterStore{
+		client:   client,
+		registry: event.DefaultTypeRegistry,
+	}
+}
+
+// Add records a dead letter, replacing the stored one with the same ID
+func (s *DeadLetterStore) Add(ctx context.Context, letter *event.DeadLetter) error {
+	record, err := repository.NewDeadLetterRecord(letter)
+	if err != nil {
+		return err
+	}
+	if len(record.Error) > maxDeadLetterErrorLength {
+		record.Error = strings.ToValidUTF8(record.Error[:maxDeadLetterErrorLength], "")
+	}
+
+	return s.client.GetDB(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"error", "attempts", "failed_at"}),
+		}).
+		Create(record).Error
+}
+
+// List returns up to limit dead letters, oldest first
+func (s *DeadLetterStore) List(ctx context.Context, limit int) ([]*event.DeadLetter, error) {
+	var records []*repository.DeadLetterRecord
+
+	db := s.client.GetDB(ctx).Order("failed_at ASC, id ASC")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	if err := db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	letters := make([]*event.DeadLetter, 0, len(records))
+	for _, record := range records {
+		letter, err := record.DeadLetter(s.registry)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, letter)
+	}
+
+	return letters, nil
+}
+
+// Get returns a dead letter by ID
+func (s *DeadLetterStore) Get(ctx context.Context, id string) (*event.DeadLetter, error) {
+	var record repository.DeadLetterRecord
+	if err := s.client.GetDB(ctx).Where("id = ?", id).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, event.ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+
+	return record.DeadLetter(s.registry)
+}
+
+// Remove deletes a dead letter
+func (s *DeadLetterStore) Remove(ctx context.Context, id string) error {
+	result := s.client.GetDB(ctx).Where("id = ?", id).Delete(&repository.DeadLetterRecord{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return event.ErrDeadLetterNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package postgre
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"go-hexagonal/domain/model"
+)
+
+// uniqueViolation is the PostgreSQL SQLSTATE for a unique constraint violation
+const uniqueViolation = "23505"
+
+// translateError maps PostgreSQL driver errors to domain errors.
+// name, when known, identifies the example in the conflict message.
+func translateError(err error, name string) error {
+	if isDuplicateKey(err) {
+		// The only unique index besides the primary key is the live name key
+		if name == "" {
+			return model.ErrExampleNameTaken
+		}
+		return model.NewExampleNameTakenError(name)
+	}
+	return err
+}
+
+// isDuplicateKey reports whether err is a unique constraint violation
+func isDuplicateKey(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
@@ -0,0 +1,28 @@
+package postgre
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"go-hexagonal/domain/model"
+)
+
+func TestTranslateError(t *testing.T) {
+	duplicate := &pgconn.PgError{Code: uniqueViolation, ConstraintName: "uk_example_live_name_key"}
+
+	err := translateError(fmt.Errorf("insert: %w", duplicate), "Test")
+	assert.True(t, model.IsExampleNameTakenError(err))
+	assert.Contains(t, err.Error(), "Test")
+
+	assert.ErrorIs(t, translateError(duplicate, ""), model.ErrExampleNameTaken)
+
+	other := &pgconn.PgError{Code: "40P01", Message: "deadlock detected"}
+	assert.Same(t, other, translateError(other, "Test"))
+
+	plain := errors.New("connection refused")
+	assert.Equal(t, plain, translateError(plain, "Test"))
+}
@@ -0,0 +1,129 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/domain/repo"
+)
+
+// EventStreamRepo implements the event stream repository for PostgreSQL
+type EventStreamRepo struct {
+	client *PostgreSQLClient
+}
+
+// NewEventStreamRepo creates a new PostgreSQL event stream repository
+func NewEventStreamRepo(client *PostgreSQLClient) repo.IEventStreamRepo {
+	return &EventStreamRepo{
+		client: client,
+	}
+}
+
+// Append adds events to the end of a stream if it is still at the expected version.
+// The unique (stream_id, version) key rejects concurrent appends that passed the check.
+func (r *EventStreamRepo) Append(ctx context.Context, tr repo.Transaction, streamID string, expectedVersion int, events ...*repo.StreamEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	db := r.getDB(ctx, tr)
+
+	var current int
+	if err := db.Model(&repo.StreamEvent{}).
+		Select("COALESCE(MAX(version), 0)").
+		Where("stream_id = ?", streamID).
+		Scan(&current).Error; err != nil {
+		return err
+	}
+	if current != expectedVersion {
+		return repo.ErrStreamVersionConflict
+	}
+
+	now := time.Now()
+	for i, evt := range events {
+		evt.StreamID = streamID
+		evt.Version = expectedVersion + i + 1
+		evt.CreatedAt = now
+	}
+
+	if err := db.Create(events).Error; err != nil {
+		if isDuplicateKey(err) {
+			return repo.ErrStreamVersionConflict
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Load returns the events of a stream after the given version in order
+func (r *EventStreamRepo) Load(ctx context.Context, tr repo.Transaction, streamID string, afterVersion int) ([]*repo.StreamEvent, error) {
+	events := make([]*repo.StreamEvent, 0)
+	if err := r.getDB(ctx, tr).
+		Where("stream_id = ? AND version > ?", streamID, afterVersion).
+		Order("version ASC").
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// StreamIDs returns the IDs of all streams starting with prefix
+func (r *EventStreamRepo) StreamIDs(ctx context.Context, tr repo.Transaction, prefix string) ([]string, error) {
+	streamIDs := make([]string, 0)
+	if err := r.getDB(ctx, tr).Model(&repo.StreamEvent{}).
+		Distinct("stream_id").
+		Where("stream_id LIKE ?", repository.PrefixPattern(prefix)).
+		Order("stream_id ASC").
+		Pluck("stream_id", &streamIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return streamIDs, nil
+}
+
+// SaveSnapshot replaces the snapshot of a stream
+func (r *EventStreamRepo) SaveSnapshot(ctx context.Context, tr repo.Transaction, snapshot *repo.StreamSnapshot) error {
+	snapshot.CreatedAt = time.Now()
+
+	return r.getDB(ctx, tr).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "stream_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"version", "state", "created_at"}),
+		}).
+		Create(snapshot).Error
+}
+
+// LoadSnapshot returns the snapshot of a stream
+func (r *EventStreamRepo) LoadSnapshot(ctx context.Context, tr repo.Transaction, streamID string) (*repo.StreamSnapshot, error) {
+	var snapshot repo.StreamSnapshot
+	if err := r.getDB(ctx, tr).Where("stream_id = ?", streamID).First(&snapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repo.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// getDB returns the appropriate database connection based on transaction
+func (r *EventStreamRepo) getDB(ctx context.Context, tr repo.Transaction) *gorm.DB {
+	// Fall back to the unit of work carried by the context, e.g. when called with a no-op transaction
+	if _, ok := tr.(*repository.Transaction); !ok {
+		if ctxTr, found := repo.TransactionFromContext(ctx); found {
+			tr = ctxTr
+		}
+	}
+
+	if sqlTr, ok := tr.(*repository.Transaction); ok && sqlTr.Session != nil {
+		return sqlTr.Session.WithContext(tr.Context())
+	}
+	return r.client.GetDB(ctx)
+}
@@ -0,0 +1,26 @@
+package postgre
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/domain/repo/repotest"
+)
+
+func TestExampleRepo_Contract(t *testing.T) {
+	// Skip this test in CI environments or when running quick tests
+	if testing.Short() {
+		t.Skip("Skipping PostgreSQL container test in short mode")
+	}
+
+	// One container serves every subtest, each starts from an empty table
+	client := GetTestDB(t, SetupPostgreSQLContainer(t))
+	factory := NewTransactionFactory(client)
+
+	repotest.RunExampleRepoTests(t, repo.PostgresStore, func(t *testing.T) (repo.IExampleRepo, repo.TransactionFactory) {
+		require.NoError(t, client.DB.Exec("TRUNCATE TABLE example RESTART IDENTITY").Error)
+		return NewExampleRepo(client), factory
+	})
+}
@@ -38,20 +38,112 @@ func SetupPostgreSQLContainer(t *testing.T) *config.PostgreSQLConfig {
 	initSQL := "CREATE TABLE IF NOT EXISTS example (\n" +
 		"    id SERIAL PRIMARY KEY,\n" +
 		"    name VARCHAR(255) NOT NULL,\n" +
+		"    name_key VARCHAR(255) NOT NULL,\n" +
 		"    alias VARCHAR(255),\n" +
 		"    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
 		"    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
-		"    deleted_at TIMESTAMP\n" +
+		"    deleted_at TIMESTAMP,\n" +
+		"    version INTEGER NOT NULL DEFAULT 1\n" +
 		");\n\n" +
 		"CREATE INDEX idx_example_name ON example(name);\n" +
+		"CREATE UNIQUE INDEX uk_example_live_name_key ON example(name_key) WHERE deleted_at IS NULL;\n" +
 		"CREATE INDEX idx_example_deleted_at ON example(deleted_at);\n" +
+		"CREATE INDEX idx_example_created_at_id ON example(created_at, id);\n" +
 		"COMMENT ON TABLE example IS 'Example table for Hexagonal Architecture';\n" +
 		"COMMENT ON COLUMN example.id IS 'Primary key ID';\n" +
 		"COMMENT ON COLUMN example.name IS 'Name';\n" +
+		"COMMENT ON COLUMN example.name_key IS 'Case-folded, NFKC-normalized name';\n" +
 		"COMMENT ON COLUMN example.alias IS 'Alias';\n" +
 		"COMMENT ON COLUMN example.created_at IS 'Creation time';\n" +
 		"COMMENT ON COLUMN example.updated_at IS 'Update time';\n" +
-		"COMMENT ON COLUMN example.deleted_at IS 'Deletion time';"
+		"COMMENT ON COLUMN example.deleted_at IS 'Deletion time';\n" +
+		"COMMENT ON COLUMN example.version IS 'Optimistic locking version';\n\n" +
+		"CREATE TABLE IF NOT EXISTS outbox (\n" +
+		"    id BIGSERIAL PRIMARY KEY,\n" +
+		"    event_id VARCHAR(64) NOT NULL,\n" +
+		"    event_name VARCHAR(255) NOT NULL,\n" +
+		"    aggregate_id VARCHAR(64) NOT NULL,\n" +
+		"    schema_version INT NOT NULL DEFAULT 1,\n" +
+		"    correlation_id VARCHAR(128) NOT NULL DEFAULT '',\n" +
+		"    causation_id VARCHAR(128) NOT NULL DEFAULT '',\n" +
+		"    payload JSONB NOT NULL,\n" +
+		"    occurred_at TIMESTAMP NOT NULL,\n" +
+		"    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+		"    published_at TIMESTAMP,\n" +
+		"    parked_at TIMESTAMP,\n" +
+		"    attempts INTEGER NOT NULL DEFAULT 0,\n" +
+		"    last_error VARCHAR(1024)\n" +
+		");\n\n" +
+		"CREATE UNIQUE INDEX uk_outbox_event_id ON outbox(event_id);\n" +
+		"CREATE INDEX idx_outbox_pending ON outbox(id) WHERE published_at IS NULL AND parked_at IS NULL;\n" +
+		"COMMENT ON TABLE outbox IS 'Domain events awaiting delivery';\n" +
+		"COMMENT ON COLUMN outbox.event_id IS 'Event ID, used by consumers for deduplication';\n" +
+		"COMMENT ON COLUMN outbox.published_at IS 'Delivery time, NULL while pending';\n" +
+		"COMMENT ON COLUMN outbox.parked_at IS 'Time delivery was given up after too many failed attempts';\n\n" +
+		"CREATE TABLE IF NOT EXISTS event_store (\n" +
+		"    id BIGSERIAL PRIMARY KEY,\n" +
+		"    event_id VARCHAR(64) NOT NULL,\n" +
+		"    event_name VARCHAR(255) NOT NULL,\n" +
+		"    aggregate_id VARCHAR(64) NOT NULL,\n" +
+		"    schema_version INT NOT NULL DEFAULT 1,\n" +
+		"    correlation_id VARCHAR(128) NOT NULL DEFAULT '',\n" +
+		"    causation_id VARCHAR(128) NOT NULL DEFAULT '',\n" +
+		"    payload JSONB NOT NULL,\n" +
+		"    occurred_at TIMESTAMP NOT NULL,\n" +
+		"    processed_at TIMESTAMP,\n" +
+		"    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP\n" +
+		");\n\n" +
+		"CREATE UNIQUE INDEX uk_event_store_event_id ON event_store(event_id);\n" +
+		"CREATE INDEX idx_event_store_occurred_at ON event_store(occurred_at);\n" +
+		"CREATE INDEX idx_event_store_event_name_occurred_at ON event_store(event_name, occurred_at);\n" +
+		"COMMENT ON TABLE event_store IS 'Published domain events kept for replay';\n" +
+		"COMMENT ON COLUMN event_store.processed_at IS 'Time the handlers processed the event';\n\n" +
+		"CREATE TABLE IF NOT EXISTS dead_letter (\n" +
+		"    id VARCHAR(64) PRIMARY KEY,\n" +
+		"    event_id VARCHAR(64) NOT NULL,\n" +
+		"    event_name VARCHAR(255) NOT NULL,\n" +
+		"    aggregate_id VARCHAR(64) NOT NULL,\n" +
+		"    schema_version INT NOT NULL DEFAULT 1,\n" +
+		"    payload JSONB NOT NULL,\n" +
+		"    occurred_at TIMESTAMP NOT NULL,\n" +
+		"    handler VARCHAR(255) NOT NULL,\n" +
+		"    error VARCHAR(1024) NOT NULL,\n" +
+		"    attempts INTEGER NOT NULL DEFAULT 0,\n" +
+		"    failed_at TIMESTAMP NOT NULL\n" +
+		");\n\n" +
+		"CREATE INDEX idx_dead_letter_failed_at ON dead_letter(failed_at);\n" +
+		"COMMENT ON TABLE dead_letter IS 'Events handlers gave up on after all retries';\n\n" +
+		"CREATE TABLE IF NOT EXISTS event_stream (\n" +
+		"    id BIGSERIAL PRIMARY KEY,\n" +
+		"    stream_id VARCHAR(128) NOT NULL,\n" +
+		"    version INTEGER NOT NULL,\n" +
+		"    event_id VARCHAR(64) NOT NULL,\n" +
+		"    event_name VARCHAR(255) NOT NULL,\n" +
+		"    schema_version INT NOT NULL DEFAULT 1,\n" +
+		"    payload JSONB NOT NULL,\n" +
+		"    occurred_at TIMESTAMP NOT NULL,\n" +
+		"    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP\n" +
+		");\n\n" +
+		"CREATE UNIQUE INDEX uk_event_stream_stream_id_version ON event_stream(stream_id, version);\n" +
+		"CREATE UNIQUE INDEX uk_event_stream_event_id ON event_stream(event_id);\n" +
+		"COMMENT ON TABLE event_stream IS 'Append-only event streams of event-sourced aggregates';\n\n" +
+		"CREATE TABLE IF NOT EXISTS stream_snapshot (\n" +
+		"    stream_id VARCHAR(128) PRIMARY KEY,\n" +
+		"    version INTEGER NOT NULL,\n" +
+		"    state JSONB NOT NULL,\n" +
+		"    created_at TIMESTAMP NOT NULL\n" +
+		");\n\n" +
+		"COMMENT ON TABLE stream_snapshot IS 'Latest snapshot of each event stream';\n\n" +
+		"CREATE TABLE IF NOT EXISTS inbox (\n" +
+		"    id BIGSERIAL PRIMARY KEY,\n" +
+		"    consumer VARCHAR(255) NOT NULL,\n" +
+		"    event_id VARCHAR(64) NOT NULL,\n" +
+		"    event_name VARCHAR(255) NOT NULL,\n" +
+		"    processed_at TIMESTAMP NOT NULL\n" +
+		");\n\n" +
+		"CREATE UNIQUE INDEX uk_inbox_consumer_event_id ON inbox(consumer, event_id);\n" +
+		"CREATE INDEX idx_inbox_processed_at ON inbox(processed_at);\n" +
+		"COMMENT ON TABLE inbox IS 'Events processed by each consumer, for idempotent handling';"
 
 	if _, err := tempFile.WriteString(initSQL); err != nil {
 		t.Fatalf("Failed to write to temp file: %v", err)
@@ -174,18 +266,7 @@ func SetupPostgreSQLContainer(t *testing.T) *config.PostgreSQLConfig {
 func GetTestDB(t *testing.T, config *config.PostgreSQLConfig) *PostgreSQLClient {
 	t.Helper()
 
-	// Create DSN
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s TimeZone=%s",
-		config.Host,
-		config.Port,
-		config.User,
-		config.Password,
-		config.Database,
-		config.SSLMode,
-		config.TimeZone,
-	)
-
-	client, err := NewPostgreSQLClient(dsn)
+	client, err := NewPostgreSQLClient(DSN(config))
 	if err != nil {
 		t.Fatalf("Failed to create PostgreSQL client: %v", err)
 	}
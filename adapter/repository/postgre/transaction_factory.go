@@ -0,0 +1,39 @@
+package postgre
+
+import (
+	"context"
+	"fmt"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/domain/repo"
+)
+
+// TransactionFactory opens transactions on the PostgreSQL database. It accepts only the PostgreSQL
+// store type, requests for any other store are rejected.
+type TransactionFactory struct {
+	client *PostgreSQLClient
+}
+
+// NewTransactionFactory creates a transaction factory over the PostgreSQL client
+func NewTransactionFactory(client *PostgreSQLClient) repo.TransactionFactory {
+	return &TransactionFactory{
+		client: client,
+	}
+}
+
+// NewTransaction creates a new transaction on PostgreSQL
+func (f *TransactionFactory) NewTransaction(ctx context.Context, store repo.StoreType, opts any) (repo.Transaction, error) {
+	if store != repo.PostgresStore {
+		return nil, fmt.Errorf("no client found for store type: %s", store)
+	}
+
+	// Convert options to SQL options if applicable
+	sqlOpts := repository.SQLTxOptions(opts)
+
+	tx, err := repository.NewTransaction(ctx, repository.PostgreSQLStore, f.client, sqlOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	return tx, nil
+}
@@ -125,6 +125,14 @@ func NewClient(opts *ClientOptions) (*RedisClient, error) {
 	return redisClient, nil
 }
 
+// WrapClient wraps an already connected Redis client, e.g. the shared one in the client container
+func WrapClient(client *redis.Client) *RedisClient {
+	return &RedisClient{
+		Client: client,
+		opts:   DefaultClientOptions(),
+	}
+}
+
 // HealthCheck performs a ping to verify the Redis connection is working
 func (c *RedisClient) HealthCheck(ctx context.Context) error {
 	timeoutCtx, cancel := context.WithTimeout(ctx, c.opts.DialTimeout)
@@ -26,6 +26,21 @@ const (
 // ErrCacheMiss is returned when a requested item is not found in cache
 var ErrCacheMiss = errors.New("cache miss")
 
+// setIfNewerScript stores the example only if the cached copy is not of a newer version,
+// so a slow writer cannot replace a fresher entry with the one it read earlier.
+// KEYS[1] is the example key, ARGV[1] the payload, ARGV[2] its version, ARGV[3] the TTL in milliseconds.
+var setIfNewerScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current then
+	local ok, cached = pcall(cjson.decode, current)
+	if ok and type(cached) == 'table' and tonumber(cached.version) and tonumber(cached.version) > tonumber(ARGV[2]) then
+		return 0
+	end
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[3])
+return 1
+`)
+
 // ExampleCacheRepo implements the example cache repository
 type ExampleCacheRepo struct {
 	client *RedisClient
@@ -104,11 +119,16 @@ func (c *ExampleCacheRepo) Set(ctx context.Context, example *model.Example) erro
 		return fmt.Errorf("failed to marshal example: %w", err)
 	}
 
-	// Set the example data
+	// Set the example data unless a newer version is already cached
 	exampleKey := fmt.Sprintf("%s%d", exampleKeyPrefix, example.Id)
-	if err := c.client.Client.Set(ctx, exampleKey, data, defaultCacheDuration).Err(); err != nil {
+	stored, err := setIfNewerScript.Run(ctx, c.client.Client, []string{exampleKey},
+		data, example.Version, defaultCacheDuration.Milliseconds()).Int()
+	if err != nil {
 		return fmt.Errorf("failed to cache example: %w", err)
 	}
+	if stored == 0 {
+		return nil
+	}
 
 	// Set the name to ID mapping
 	nameKey := fmt.Sprintf("%s%s", exampleNamePrefix, example.Name)
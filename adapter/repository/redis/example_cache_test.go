@@ -68,3 +68,31 @@ func TestExampleCache_HealthCheck(t *testing.T) {
 	err = mock.ExpectationsWereMet()
 	assert.NoError(t, err)
 }
+
+func TestExampleCacheRepo_SetKeepsNewerVersion(t *testing.T) {
+	// Create Redis mock
+	config := SetupRedisContainer(t)
+	cache := NewExampleCacheRepo(GetRedisClient(t, config))
+
+	// Cache the newer version first
+	err := cache.Set(testCtx, &model.Example{Id: 1, Name: "newer", Version: 3})
+	assert.NoError(t, err)
+
+	// A stale writer must not replace it
+	err = cache.Set(testCtx, &model.Example{Id: 1, Name: "older", Version: 2})
+	assert.NoError(t, err)
+
+	cached, err := cache.GetByID(testCtx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "newer", cached.Name)
+	assert.Equal(t, 3, cached.Version)
+
+	// A newer version replaces the entry
+	err = cache.Set(testCtx, &model.Example{Id: 1, Name: "newest", Version: 4})
+	assert.NoError(t, err)
+
+	cached, err = cache.GetByID(testCtx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "newest", cached.Name)
+	assert.Equal(t, 4, cached.Version)
+}
@@ -0,0 +1,112 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-hexagonal/domain/repo"
+)
+
+const (
+	// inboxKeyPrefix prefixes the inbox keys, followed by the consumer and the event ID
+	inboxKeyPrefix = "inbox:"
+
+	// DefaultInboxRetention is how long processed events are remembered
+	DefaultInboxRetention = 7 * 24 * time.Hour
+
+	// inboxScanCount is the number of keys inspected per SCAN call when cleaning up
+	inboxScanCount = 100
+)
+
+// InboxRepo implements the inbox repository for Redis. Records expire after the retention period.
+// Redis does not take part in SQL transactions, so a record is not atomic with the handler's changes.
+type InboxRepo struct {
+	client    *RedisClient
+	retention time.Duration
+}
+
+// NewInboxRepo creates a new Redis inbox repository, a non-positive retention uses DefaultInboxRetention
+func NewInboxRepo(client *RedisClient, retention time.Duration) repo.IInboxRepo {
+	if retention <= 0 {
+		retention = DefaultInboxRetention
+	}
+
+	return &InboxRepo{
+		client:    client,
+		retention: retention,
+	}
+}
+
+// Processed reports whether the consumer already processed the event
+func (r *InboxRepo) Processed(ctx context.Context, _ repo.Transaction, consumer, eventID string) (bool, error) {
+	count, err := r.client.Client.Exists(ctx, inboxKey(consumer, eventID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check inbox: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// Save records a processed event unless the consumer already recorded it
+func (r *InboxRepo) Save(ctx context.Context, _ repo.Transaction, message *repo.InboxMessage) error {
+	if message.ProcessedAt.IsZero() {
+		message.ProcessedAt = time.Now()
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal inbox message: %w", err)
+	}
+
+	stored, err := r.client.Client.SetNX(ctx, inboxKey(message.Consumer, message.EventID), data, r.retention).Result()
+	if err != nil {
+		return fmt.Errorf("failed to save inbox message: %w", err)
+	}
+	if !stored {
+		return repo.ErrInboxDuplicate
+	}
+
+	return nil
+}
+
+// DeleteBefore removes the records of events processed before the given time. Records also
+// expire on their own after the retention period.
+func (r *InboxRepo) DeleteBefore(ctx context.Context, _ repo.Transaction, before time.Time) (int64, error) {
+	var deleted int64
+
+	iter := r.client.Client.Scan(ctx, 0, inboxKeyPrefix+"*", inboxScanCount).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		data, err := r.client.Client.Get(ctx, key).Bytes()
+		if err != nil {
+			// Expired since the scan
+			continue
+		}
+
+		var message repo.InboxMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			return deleted, fmt.Errorf("failed to unmarshal inbox message %s: %w", key, err)
+		}
+		if !message.ProcessedAt.Before(before) {
+			continue
+		}
+
+		count, err := r.client.Client.Del(ctx, key).Result()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete inbox message %s: %w", key, err)
+		}
+		deleted += count
+	}
+	if err := iter.Err(); err != nil {
+		return deleted, fmt.Errorf("failed to scan inbox: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// inboxKey returns the key recording that a consumer processed an event
+func inboxKey(consumer, eventID string) string {
+	return inboxKeyPrefix + consumer + ":" + eventID
+}
@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/repo"
+)
+
+func TestInboxRepo(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	inbox := NewInboxRepo(client, 0)
+
+	processed, err := inbox.Processed(testCtx, nil, "consumer", "event-1")
+	require.NoError(t, err)
+	assert.False(t, processed)
+
+	message := &repo.InboxMessage{Consumer: "consumer", EventID: "event-1", EventName: "example.created"}
+	require.NoError(t, inbox.Save(testCtx, nil, message))
+	assert.False(t, message.ProcessedAt.IsZero())
+
+	processed, err = inbox.Processed(testCtx, nil, "consumer", "event-1")
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	// Each consumer keeps its own record of the event
+	processed, err = inbox.Processed(testCtx, nil, "other", "event-1")
+	require.NoError(t, err)
+	assert.False(t, processed)
+
+	assert.ErrorIs(t, inbox.Save(testCtx, nil, &repo.InboxMessage{Consumer: "consumer", EventID: "event-1"}), repo.ErrInboxDuplicate)
+
+	ttl, err := client.Client.TTL(testCtx, inboxKey("consumer", "event-1")).Result()
+	require.NoError(t, err)
+	assert.InDelta(t, DefaultInboxRetention.Seconds(), ttl.Seconds(), 1)
+}
+
+func TestInboxRepo_DeleteBefore(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	inbox := NewInboxRepo(client, time.Hour)
+
+	now := time.Now()
+	require.NoError(t, inbox.Save(testCtx, nil, &repo.InboxMessage{Consumer: "consumer", EventID: "old", ProcessedAt: now.Add(-time.Minute)}))
+	require.NoError(t, inbox.Save(testCtx, nil, &repo.InboxMessage{Consumer: "consumer", EventID: "new", ProcessedAt: now}))
+
+	deleted, err := inbox.DeleteBefore(testCtx, nil, now.Add(-time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	processed, err := inbox.Processed(testCtx, nil, "consumer", "old")
+	require.NoError(t, err)
+	assert.False(t, processed)
+	processed, err = inbox.Processed(testCtx, nil, "consumer", "new")
+	require.NoError(t, err)
+	assert.True(t, processed)
+}
@@ -0,0 +1,263 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+const (
+	// Projection key prefixes for Redis
+	projectionCheckpointPrefix = "projection:checkpoint:"
+	exampleDailyCountKey       = "projection:example:daily_counts"
+	exampleDailyCountEventsKey = "projection:example:daily_count_events"
+	exampleNameIndexNamesKey   = "projection:example:names"
+	exampleNameIndexSearchKey  = "projection:example:name_search"
+
+	// nameIndexSeparator separates the normalized name from the ID in search index members
+	nameIndexSeparator = "\x00"
+)
+
+// ProjectionCheckpointRepo stores projector checkpoints as JSON values
+type ProjectionCheckpointRepo struct {
+	client *RedisClient
+}
+
+// NewProjectionCheckpointRepo creates a new Redis projection checkpoint repository
+func NewProjectionCheckpointRepo(client *RedisClient) repo.IProjectionCheckpointRepo {
+	return &ProjectionCheckpointRepo{
+		client: client,
+	}
+}
+
+// LoadCheckpoint returns the checkpoint of a projector
+func (r *ProjectionCheckpointRepo) LoadCheckpoint(ctx context.Context, projector string) (*repo.ProjectionCheckpoint, error) {
+	data, err := r.client.Client.Get(ctx, projectionCheckpointPrefix+projector).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, repo.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get projection checkpoint: %w", err)
+	}
+
+	var checkpoint repo.ProjectionCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal projection checkpoint: %w", err)
+	}
+
+	return &checkpoint, nil
+}
+
+// SaveCheckpoint replaces the checkpoint of a projector
+func (r *ProjectionCheckpointRepo) SaveCheckpoint(ctx context.Context, checkpoint *repo.ProjectionCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal projection checkpoint: %w", err)
+	}
+
+	if err := r.client.Client.Set(ctx, projectionCheckpointPrefix+checkpoint.Projector, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to set projection checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCheckpoint removes the checkpoint of a projector
+func (r *ProjectionCheckpointRepo) DeleteCheckpoint(ctx context.Context, projector string) error {
+	if err := r.client.Client.Del(ctx, projectionCheckpointPrefix+projector).Err(); err != nil {
+		return fmt.Errorf("failed to delete projection checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// countEventScript increments the count of a day unless the event is already in the set of counted events
+var countEventScript = redis.NewScript(`
+if redis.call("SADD", KEYS[2], ARGV[2]) == 1 then
+	redis.call("HINCRBY", KEYS[1], ARGV[1], 1)
+end
+return 0
+`)
+
+// ExampleDailyCountRepo keeps the daily example counts in a hash of day to count, and the IDs of
+// the counted events in a set
+type ExampleDailyCountRepo struct {
+	client *RedisClient
+}
+
+// NewExampleDailyCountRepo creates a new Redis daily example count repository
+func NewExampleDailyCountRepo(client *RedisClient) repo.IExampleDailyCountRepo {
+	return &ExampleDailyCountRepo{
+		client: client,
+	}
+}
+
+// CountEvent counts an event on the day, an event that was already counted is not counted again
+func (r *ExampleDailyCountRepo) CountEvent(ctx context.Context, day string, eventID string) error {
+	keys := []string{exampleDailyCountKey, exampleDailyCountEventsKey}
+	if err := countEventScript.Run(ctx, r.client.Client, keys, day, eventID).Err(); err != nil {
+		return fmt.Errorf("failed to increment daily example count: %w", err)
+	}
+
+	return nil
+}
+
+// Counts returns the counts of all days in date order
+func (r *ExampleDailyCountRepo) Counts(ctx context.Context) ([]repo.ExampleDailyCount, error) {
+	values, err := r.client.Client.HGetAll(ctx, exampleDailyCountKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily example counts: %w", err)
+	}
+
+	counts := make([]repo.ExampleDailyCount, 0, len(values))
+	for day, value := range values {
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid daily example count for %s: %w", day, err)
+		}
+		counts = append(counts, repo.ExampleDailyCount{Day: day, Count: count})
+	}
+
+	// Days are in YYYY-MM-DD form, so string order is date order
+	slices.SortFunc(counts, func(a, b repo.ExampleDailyCount) int {
+		return strings.Compare(a.Day, b.Day)
+	})
+
+	return counts, nil
+}
+
+// Reset removes all counts
+func (r *ExampleDailyCountRepo) Reset(ctx context.Context) error {
+	if err := r.client.Client.Del(ctx, exampleDailyCountKey, exampleDailyCountEventsKey).Err(); err != nil {
+		return fmt.Errorf("failed to reset daily example counts: %w", err)
+	}
+
+	return nil
+}
+
+// ExampleNameIndexRepo keeps the name of every example in a hash of ID to name, and the live ones
+// in a sorted set of "normalized name\x00ID" members searched by lexicographic range
+type ExampleNameIndexRepo struct {
+	client *RedisClient
+}
+
+// NewExampleNameIndexRepo creates a new Redis example name index repository
+func NewExampleNameIndexRepo(client *RedisClient) repo.IExampleNameIndexRepo {
+	return &ExampleNameIndexRepo{
+		client: client,
+	}
+}
+
+// Put indexes an example under its current name, replacing its previous name
+func (r *ExampleNameIndexRepo) Put(ctx context.Context, id int, name string) error {
+	field := strconv.Itoa(id)
+
+	previous, err := r.client.Client.HGet(ctx, exampleNameIndexNamesKey, field).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to get indexed example name: %w", err)
+	}
+
+	_, err = r.client.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if previous != "" {
+			pipe.ZRem(ctx, exampleNameIndexSearchKey, nameIndexMember(id, previous))
+		}
+		pipe.HSet(ctx, exampleNameIndexNamesKey, field, name)
+		pipe.ZAdd(ctx, exampleNameIndexSearchKey, &redis.Z{Member: nameIndexMember(id, name)})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to index example name: %w", err)
+	}
+
+	return nil
+}
+
+// Remove takes an example out of search results
+func (r *ExampleNameIndexRepo) Remove(ctx context.Context, id int) error {
+	name, err := r.client.Client.HGet(ctx, exampleNameIndexNamesKey, strconv.Itoa(id)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get indexed example name: %w", err)
+	}
+
+	if err := r.client.Client.ZRem(ctx, exampleNameIndexSearchKey, nameIndexMember(id, name)).Err(); err != nil {
+		return fmt.Errorf("failed to remove example from name index: %w", err)
+	}
+
+	return nil
+}
+
+// Restore returns a removed example to search results under its last name
+func (r *ExampleNameIndexRepo) Restore(ctx context.Context, id int) error {
+	name, err := r.client.Client.HGet(ctx, exampleNameIndexNamesKey, strconv.Itoa(id)).Result()
+	if errors.Is(err, redis.Nil) {
+		return repo.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get indexed example name: %w", err)
+	}
+
+	if err := r.client.Client.ZAdd(ctx, exampleNameIndexSearchKey, &redis.Z{Member: nameIndexMember(id, name)}).Err(); err != nil {
+		return fmt.Errorf("failed to restore example to name index: %w", err)
+	}
+
+	return nil
+}
+
+// Search returns the IDs of live examples whose normalized name starts with the normalized prefix
+func (r *ExampleNameIndexRepo) Search(ctx context.Context, prefix string, limit int) ([]int, error) {
+	key := model.NormalizeExampleName(prefix)
+	by := &redis.ZRangeBy{
+		// Members sharing the prefix sort between the prefix itself and the prefix followed by 0xff
+		Min: "[" + key,
+		Max: "[" + key + "\xff",
+	}
+	if limit > 0 {
+		by.Count = int64(limit)
+	}
+
+	members, err := r.client.Client.ZRangeByLex(ctx, exampleNameIndexSearchKey, by).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to search example name index: %w", err)
+	}
+
+	ids := make([]int, 0, len(members))
+	for _, member := range members {
+		_, field, found := strings.Cut(member, nameIndexSeparator)
+		if !found {
+			return nil, fmt.Errorf("invalid example name index member %q", member)
+		}
+		id, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid example name index member %q: %w", member, err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Reset removes all entries
+func (r *ExampleNameIndexRepo) Reset(ctx context.Context) error {
+	if err := r.client.Client.Del(ctx, exampleNameIndexNamesKey, exampleNameIndexSearchKey).Err(); err != nil {
+		return fmt.Errorf("failed to reset example name index: %w", err)
+	}
+
+	return nil
+}
+
+// nameIndexMember returns the search index member of an example
+func nameIndexMember(id int, name string) string {
+	return model.NormalizeExampleName(name) + nameIndexSeparator + strconv.Itoa(id)
+}
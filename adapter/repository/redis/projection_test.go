@@ -0,0 +1,111 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/repo"
+)
+
+func TestProjectionCheckpointRepo(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	checkpoints := NewProjectionCheckpointRepo(client)
+
+	_, err := checkpoints.LoadCheckpoint(testCtx, "counts")
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+
+	occurredAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, checkpoints.SaveCheckpoint(testCtx, &repo.ProjectionCheckpoint{
+		Projector:  "counts",
+		Sequence:   42,
+		EventID:    "event-1",
+		OccurredAt: occurredAt,
+		Processed:  3,
+	}))
+
+	checkpoint, err := checkpoints.LoadCheckpoint(testCtx, "counts")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), checkpoint.Sequence)
+	assert.Equal(t, "event-1", checkpoint.EventID)
+	assert.True(t, occurredAt.Equal(checkpoint.OccurredAt))
+	assert.Equal(t, int64(3), checkpoint.Processed)
+
+	require.NoError(t, checkpoints.DeleteCheckpoint(testCtx, "counts"))
+	_, err = checkpoints.LoadCheckpoint(testCtx, "counts")
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+}
+
+func TestExampleDailyCountRepo(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	counts := NewExampleDailyCountRepo(client)
+
+	require.NoError(t, counts.CountEvent(testCtx, "2024-05-02", "event-1"))
+	require.NoError(t, counts.CountEvent(testCtx, "2024-05-01", "event-2"))
+	require.NoError(t, counts.CountEvent(testCtx, "2024-05-02", "event-3"))
+	require.NoError(t, counts.CountEvent(testCtx, "2024-05-02", "event-4"))
+
+	// Counting an event again changes nothing
+	require.NoError(t, counts.CountEvent(testCtx, "2024-05-02", "event-1"))
+
+	got, err := counts.Counts(testCtx)
+	require.NoError(t, err)
+	assert.Equal(t, []repo.ExampleDailyCount{
+		{Day: "2024-05-01", Count: 1},
+		{Day: "2024-05-02", Count: 3},
+	}, got)
+
+	// A reset forgets the counted events, so they count again
+	require.NoError(t, counts.Reset(testCtx))
+	got, err = counts.Counts(testCtx)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+	require.NoError(t, counts.CountEvent(testCtx, "2024-05-02", "event-1"))
+	got, err = counts.Counts(testCtx)
+	require.NoError(t, err)
+	assert.Equal(t, []repo.ExampleDailyCount{{Day: "2024-05-02", Count: 1}}, got)
+}
+
+func TestExampleNameIndexRepo(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	index := NewExampleNameIndexRepo(client)
+
+	require.NoError(t, index.Put(testCtx, 1, "Alpha"))
+	require.NoError(t, index.Put(testCtx, 2, "alphabet"))
+	require.NoError(t, index.Put(testCtx, 3, "beta"))
+
+	ids, err := index.Search(testCtx, "ALPHA", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ids)
+
+	ids, err = index.Search(testCtx, "alpha", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, ids)
+
+	// Renaming replaces the previous entry
+	require.NoError(t, index.Put(testCtx, 1, "gamma"))
+	ids, err = index.Search(testCtx, "alpha", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, ids)
+
+	// Removed examples come back under their last name
+	require.NoError(t, index.Remove(testCtx, 1))
+	ids, err = index.Search(testCtx, "g", 0)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	require.NoError(t, index.Restore(testCtx, 1))
+	ids, err = index.Search(testCtx, "g", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, ids)
+
+	assert.ErrorIs(t, index.Restore(testCtx, 9), repo.ErrNotFound)
+	require.NoError(t, index.Remove(testCtx, 9))
+
+	require.NoError(t, index.Reset(testCtx))
+	ids, err = index.Search(testCtx, "", 0)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
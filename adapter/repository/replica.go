@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"go-hexagonal/config"
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/util/log"
+)
+
+// Replica routing defaults
+const (
+	// DefaultReplicaHealthCheckInterval is how often replicas are pinged when the configuration sets no interval
+	DefaultReplicaHealthCheckInterval = 5 * time.Second
+	// DefaultReplicaStickyWindow is how long reads stay on the primary after a write when the configuration sets no window
+	DefaultReplicaStickyWindow = 2 * time.Second
+
+	// replicaPingTimeout bounds a single health check of a replica
+	replicaPingTimeout = time.Second
+	// replicaCallbackName names the GORM callbacks the router registers
+	replicaCallbackName = "replica_router"
+)
+
+// ReplicaRouterOptions tunes how a ReplicaRouter routes reads
+type ReplicaRouterOptions struct {
+	// HealthCheckInterval is how often replicas are pinged, zero disables the background checks
+	HealthCheckInterval time.Duration
+	// StickyWindow is how long the reads of a read session go to the primary after it wrote
+	StickyWindow time.Duration
+}
+
+// ReplicaRouter sends writes to the primary database and spreads reads over its healthy replicas.
+// Reads fall back to the primary when no replica is healthy, or when the read session carried by
+// the context wrote within the sticky window, so a request reads its own writes.
+type ReplicaRouter struct {
+	primary  *gorm.DB
+	replicas []*replica
+	options  ReplicaRouterOptions
+	next     atomic.Uint64
+	now      func() time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// replica is a read replica and its last known health
+type replica struct {
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+// NewReplicaRouter creates a router over the primary and its replicas. Replicas start out healthy,
+// writes through the primary are recorded on the read session of their context.
+func NewReplicaRouter(primary *gorm.DB, replicas []*gorm.DB, options ReplicaRouterOptions) (*ReplicaRouter, error) {
+	r := &ReplicaRouter{
+		primary: primary,
+		options: options,
+		now:     time.Now,
+		stop:    make(chan struct{}),
+	}
+
+	// Record writes, including those made inside transactions on the primary
+	callbacks := primary.Callback()
+	for _, err := range []error{
+		callbacks.Create().After("gorm:create").Register(replicaCallbackName, r.markWrite),
+		callbacks.Update().After("gorm:update").Register(replicaCallbackName, r.markWrite),
+		callbacks.Delete().After("gorm:delete").Register(replicaCallbackName, r.markWrite),
+		callbacks.Raw().After("gorm:raw").Register(replicaCallbackName, r.markWrite),
+	} {
+		if err != nil {
+			return nil, fmt.Errorf("failed to register write callback: %w", err)
+		}
+	}
+
+	// Take a replica out of rotation as soon as a read finds it unreachable
+	for _, db := range replicas {
+		rep := &replica{db: db}
+		rep.healthy.Store(true)
+		callbacks := db.Callback()
+		if err := callbacks.Query().After("gorm:query").Register(replicaCallbackName, r.watchReplica(rep)); err != nil {
+			return nil, fmt.Errorf("failed to register replica callback: %w", err)
+		}
+		if err := callbacks.Row().After("gorm:row").Register(replicaCallbackName, r.watchReplica(rep)); err != nil {
+			return nil, fmt.Errorf("failed to register replica callback: %w", err)
+		}
+		r.replicas = append(r.replicas, rep)
+	}
+
+	return r, nil
+}
+
+// OpenReplicaRouter opens the replicas listed in cfg with open and routes the reads of primary over
+// them. It returns nil when cfg lists no replicas, the router checks the replicas' health until closed.
+func OpenReplicaRouter(primary *gorm.DB, cfg *config.ReadReplicasConfig, open func(config.ReplicaConfig) (*gorm.DB, error)) (*ReplicaRouter, error) {
+	if cfg == nil || len(cfg.Replicas) == 0 {
+		return nil, nil
+	}
+
+	options := ReplicaRouterOptions{
+		HealthCheckInterval: DefaultReplicaHealthCheckInterval,
+		StickyWindow:        DefaultReplicaStickyWindow,
+	}
+	if cfg.HealthCheckInterval != "" {
+		options.HealthCheckInterval = config.GetDuration(cfg.HealthCheckInterval)
+	}
+	if cfg.StickyWindow != "" {
+		options.StickyWindow = config.GetDuration(cfg.StickyWindow)
+	}
+
+	replicas := make([]*gorm.DB, 0, len(cfg.Replicas))
+	for i, replicaCfg := range cfg.Replicas {
+		db, err := open(replicaCfg)
+		if err != nil {
+			closeGormDBs(replicas)
+			return nil, fmt.Errorf("failed to open replica %d: %w", i+1, err)
+		}
+		replicas = append(replicas, db)
+	}
+
+	router, err := NewReplicaRouter(primary, replicas, options)
+	if err != nil {
+		closeGormDBs(replicas)
+		return nil, err
+	}
+	router.Start()
+
+	return router, nil
+}
+
+// Primary returns the primary database with context
+func (r *ReplicaRouter) Primary(ctx context.Context) *gorm.DB {
+	return r.primary.WithContext(ctx)
+}
+
+// Reader returns the database to read from with context, the next healthy replica in turn or the
+// primary if none is healthy, ctx asks for primary reads or its read session wrote within the sticky window
+func (r *ReplicaRouter) Reader(ctx context.Context) *gorm.DB {
+	if repo.RequiresPrimaryRead(ctx) {
+		return r.Primary(ctx)
+	}
+	if session, ok := repo.ReadSessionFromContext(ctx); ok && session.WroteWithin(r.options.StickyWindow, r.now()) {
+		return r.Primary(ctx)
+	}
+
+	count := uint64(len(r.replicas))
+	start := r.next.Add(1)
+	for i := range count {
+		if rep := r.replicas[(start+i)%count]; rep.healthy.Load() {
+			return rep.db.WithContext(ctx)
+		}
+	}
+
+	return r.Primary(ctx)
+}
+
+// HealthyReplicas returns the number of replicas currently receiving reads
+func (r *ReplicaRouter) HealthyReplicas() int {
+	healthy := 0
+	for _, rep := range r.replicas {
+		if rep.healthy.Load() {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// CheckHealth pings every replica, returning the reachable ones to rotation and taking the others out
+func (r *ReplicaRouter) CheckHealth(ctx context.Context) {
+	for i, rep := range r.replicas {
+		err := pingGormDB(ctx, rep.db)
+		if healthy := err == nil; rep.healthy.Swap(healthy) != healthy {
+			if healthy {
+				log.Logger.Info("read replica is healthy again", zap.Int("replica", i+1))
+			} else {
+				log.Logger.Warn("read replica is unhealthy, reads fail over", zap.Int("replica", i+1), zap.Error(err))
+			}
+		}
+	}
+}
+
+// Start checks the health of the replicas in the background until the router is closed
+func (r *ReplicaRouter) Start() {
+	if r.options.HealthCheckInterval <= 0 {
+		return
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.options.HealthCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.CheckHealth(context.Background())
+			}
+		}
+	}()
+}
+
+// Close stops the health checks and closes the replicas, the primary is left to its owner
+func (r *ReplicaRouter) Close() error {
+	var err error
+	r.stopOnce.Do(func() {
+		close(r.stop)
+		r.wg.Wait()
+
+		for _, rep := range r.replicas {
+			sqlDB, dbErr := rep.db.DB()
+			if dbErr == nil {
+				dbErr = sqlDB.Close()
+			}
+			if dbErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to close replica: %w", dbErr))
+			}
+		}
+	})
+	return err
+}
+
+// markWrite records a successful write on the read session of the statement's context
+func (r *ReplicaRouter) markWrite(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Context == nil {
+		return
+	}
+	if session, ok := repo.ReadSessionFromContext(db.Statement.Context); ok {
+		session.MarkWrite(r.now())
+	}
+}
+
+// watchReplica returns a callback taking the replica out of rotation when a read cannot reach it,
+// the health checks bring it back
+func (r *ReplicaRouter) watchReplica(rep *replica) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if isConnectionError(db.Error) && rep.healthy.CompareAndSwap(true, false) {
+			log.Logger.Warn("read replica is unreachable, reads fail over", zap.Error(db.Error))
+		}
+	}
+}
+
+// isConnectionError reports whether err means the database could not be reached
+func isConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.As(err, &netErr)
+}
+
+// pingGormDB pings the database behind db within the replica ping timeout
+func pingGormDB(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
+
+// closeGormDBs closes the connection pools of dbs, ignoring errors
+func closeGormDBs(dbs []*gorm.DB) {
+	for _, db := range dbs {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go-hexagonal/config"
+	"go-hexagonal/domain/repo"
+)
+
+// newNodeDB opens an in-memory database whose node table names the database, so reads tell where they ran
+func newNodeDB(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s-%s?mode=memory&cache=shared", t.Name(), name)), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	require.NoError(t, db.Exec("CREATE TABLE node (name TEXT)").Error)
+	require.NoError(t, db.Exec("INSERT INTO node (name) VALUES (?)", name).Error)
+	return db
+}
+
+// nodeName returns the name of the database db reads from
+func nodeName(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+	var name string
+	require.NoError(t, db.Raw("SELECT name FROM node ORDER BY rowid LIMIT 1").Scan(&name).Error)
+	return name
+}
+
+func newTestReplicaRouter(t *testing.T, options ReplicaRouterOptions) *ReplicaRouter {
+	t.Helper()
+	router, err := NewReplicaRouter(newNodeDB(t, "primary"), []*gorm.DB{newNodeDB(t, "replica-1"), newNodeDB(t, "replica-2")}, options)
+	require.NoError(t, err)
+	return router
+}
+
+func TestReplicaRouter_RoundRobin(t *testing.T) {
+	router := newTestReplicaRouter(t, ReplicaRouterOptions{})
+	ctx := context.Background()
+
+	seen := map[string]int{}
+	for range 4 {
+		seen[nodeName(t, router.Reader(ctx))]++
+	}
+	assert.Equal(t, map[string]int{"replica-1": 2, "replica-2": 2}, seen)
+	assert.Equal(t, "primary", nodeName(t, router.Primary(ctx)))
+}
+
+func TestReplicaRouter_ReadYourWrites(t *testing.T) {
+	router := newTestReplicaRouter(t, ReplicaRouterOptions{StickyWindow: time.Second})
+	now := time.Now()
+	router.now = func() time.Time { return now }
+
+	session := repo.NewReadSession()
+	ctx := repo.ContextWithReadSession(context.Background(), session)
+	assert.NotEqual(t, "primary", nodeName(t, router.Reader(ctx)))
+
+	// Lookups a write depends on always go to the primary
+	assert.Equal(t, "primary", nodeName(t, router.Reader(repo.ContextWithPrimaryReads(ctx))))
+
+	// Reads do not pin the session, writes through the primary do until the window passed
+	nodeName(t, router.Primary(ctx))
+	assert.False(t, session.WroteWithin(time.Second, now))
+	require.NoError(t, router.Primary(ctx).Exec("INSERT INTO node (name) VALUES ('written')").Error)
+	assert.Equal(t, "primary", nodeName(t, router.Reader(ctx)))
+
+	// Other requests keep reading from the replicas
+	assert.NotEqual(t, "primary", nodeName(t, router.Reader(context.Background())))
+
+	now = now.Add(time.Second)
+	assert.NotEqual(t, "primary", nodeName(t, router.Reader(ctx)))
+
+	// Writes inside a transaction on the primary count as well
+	tx := router.Primary(ctx).Begin()
+	require.NoError(t, tx.Table("node").Create(map[string]any{"name": "in transaction"}).Error)
+	require.NoError(t, tx.Commit().Error)
+	assert.Equal(t, "primary", nodeName(t, router.Reader(ctx)))
+}
+
+func TestReplicaRouter_Failover(t *testing.T) {
+	router := newTestReplicaRouter(t, ReplicaRouterOptions{})
+	ctx := context.Background()
+
+	// A replica failing its health check gets no reads until it passes one again
+	replicaDB, err := router.replicas[0].db.DB()
+	require.NoError(t, err)
+	require.NoError(t, replicaDB.Close())
+	router.CheckHealth(ctx)
+	assert.Equal(t, 1, router.HealthyReplicas())
+	for range 3 {
+		assert.Equal(t, "replica-2", nodeName(t, router.Reader(ctx)))
+	}
+
+	router.replicas[1].healthy.Store(false)
+	assert.Equal(t, "primary", nodeName(t, router.Reader(ctx)))
+	router.CheckHealth(ctx)
+	assert.Equal(t, 1, router.HealthyReplicas())
+	assert.Equal(t, "replica-2", nodeName(t, router.Reader(ctx)))
+
+	// Closing is idempotent and leaves the primary open
+	require.NoError(t, router.Close())
+	require.NoError(t, router.Close())
+	assert.Equal(t, "primary", nodeName(t, router.Primary(ctx)))
+}
+
+func TestReplicaRouter_ReadOnlyTransactions(t *testing.T) {
+	router := newTestReplicaRouter(t, ReplicaRouterOptions{})
+	primary := router.Primary(context.Background())
+	factory := NewTransactionFactory(map[StoreType]any{MySQLStore: &MySQL{DB: primary, Replicas: router}})
+
+	for _, tc := range []struct {
+		name      string
+		opts      any
+		onReplica bool
+	}{
+		{name: "default", opts: nil},
+		{name: "read-write options", opts: &repo.TransactionOptions{}},
+		{name: "read-only options", opts: &repo.TransactionOptions{ReadOnly: true}, onReplica: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tx, err := factory.NewTransaction(context.Background(), repo.MySQLStore, tc.opts)
+			require.NoError(t, err)
+			require.NoError(t, tx.Begin())
+			defer func() { _ = tx.Rollback() }()
+
+			assert.Equal(t, tc.onReplica, tx.Options().ReadOnly)
+			assert.Equal(t, tc.onReplica, nodeName(t, tx.(*Transaction).Session) != "primary")
+		})
+	}
+}
+
+func TestOpenReplicaRouter(t *testing.T) {
+	primary := newNodeDB(t, "primary")
+
+	router, err := OpenReplicaRouter(primary, &config.ReadReplicasConfig{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, router)
+
+	// A replica failing to open closes the ones opened before it
+	var opened []*gorm.DB
+	_, err = OpenReplicaRouter(primary, &config.ReadReplicasConfig{Replicas: []config.ReplicaConfig{{Host: "a"}, {Host: "b"}}},
+		func(replica config.ReplicaConfig) (*gorm.DB, error) {
+			if replica.Host == "b" {
+				return nil, errors.New("unreachable")
+			}
+			db := newNodeDB(t, replica.Host)
+			opened = append(opened, db)
+			return db, nil
+		})
+	require.ErrorContains(t, err, "failed to open replica 2")
+	require.Len(t, opened, 1)
+	sqlDB, err := opened[0].DB()
+	require.NoError(t, err)
+	assert.Error(t, sqlDB.Ping())
+
+	router, err = OpenReplicaRouter(primary, &config.ReadReplicasConfig{
+		Replicas:            []config.ReplicaConfig{{Host: "replica"}},
+		HealthCheckInterval: "10ms",
+		StickyWindow:        "0s",
+	}, func(replica config.ReplicaConfig) (*gorm.DB, error) {
+		return newNodeDB(t, replica.Host), nil
+	})
+	require.NoError(t, err)
+	defer func() { _ = router.Close() }()
+	assert.Equal(t, ReplicaRouterOptions{HealthCheckInterval: 10 * time.Millisecond}, router.options)
+
+	// The background checks bring a replica back once it answers
+	router.replicas[0].healthy.Store(false)
+	assert.Eventually(t, func() bool { return router.HealthyReplicas() == 1 }, time.Second, 10*time.Millisecond)
+}
@@ -8,6 +8,7 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"go-hexagonal/adapter/repository/memory"
 	"go-hexagonal/util/log"
 
 	"github.com/go-redis/redis/v8"
@@ -21,6 +22,9 @@ type ClientContainer struct {
 	MySQL      *MySQL
 	Redis      *Redis
 	PostgreSQL *PostgreSQL
+	SQLite     *SQLite
+	// Memory keeps examples in process memory, it needs no closing
+	Memory *memory.Store
 }
 
 // Global instance for backward compatibility
@@ -64,6 +68,11 @@ func (c *ClientContainer) Close(ctx context.Context) {
 			log.Logger.Error("failed to close PostgreSQL connection", zap.Error(err))
 		}
 	}
+	if c.SQLite != nil {
+		if err := c.SQLite.Close(ctx); err != nil {
+			log.Logger.Error("failed to close SQLite connection", zap.Error(err))
+		}
+	}
 	if c.Redis != nil {
 		if err := c.Redis.Close(ctx); err != nil {
 			log.Logger.Error("failed to close Redis connection", zap.Error(err))
@@ -84,6 +93,8 @@ func Close(ctx context.Context) {
 // MySQL represents a MySQL database client
 type MySQL struct {
 	DB *gorm.DB
+	// Replicas routes reads to the read replicas, nil when none are configured
+	Replicas *ReplicaRouter
 }
 
 // SetDB sets the GORM database connection
@@ -99,9 +110,19 @@ func (m *MySQL) GetDB(ctx context.Context) *gorm.DB {
 	return m.DB.WithContext(ctx)
 }
 
-// Close closes the MySQL connection
+// GetReadDB returns the GORM database connection for reads, a replica when replicas are configured
+func (m *MySQL) GetReadDB(ctx context.Context) *gorm.DB {
+	if m.Replicas == nil {
+		return m.GetDB(ctx)
+	}
+	return m.Replicas.Reader(ctx)
+}
+
+// Close closes the MySQL read replicas, GORM manages the pool of the primary
 func (m *MySQL) Close(ctx context.Context) error {
-	// No-op for now, as GORM manages connection pooling
+	if m.Replicas != nil {
+		return m.Replicas.Close()
+	}
 	return nil
 }
 
@@ -113,6 +134,8 @@ func NewMySQLClient(db *gorm.DB) *MySQL {
 // PostgreSQL represents a PostgreSQL database client
 type PostgreSQL struct {
 	DB *gorm.DB
+	// Replicas routes reads to the read replicas, nil when none are configured
+	Replicas *ReplicaRouter
 }
 
 // SetDB sets the GORM database connection
@@ -128,9 +151,19 @@ func (p *PostgreSQL) GetDB(ctx context.Context) *gorm.DB {
 	return p.DB.WithContext(ctx)
 }
 
-// Close closes the PostgreSQL connection
+// GetReadDB returns the GORM database connection for reads, a replica when replicas are configured
+func (p *PostgreSQL) GetReadDB(ctx context.Context) *gorm.DB {
+	if p.Replicas == nil {
+		return p.GetDB(ctx)
+	}
+	return p.Replicas.Reader(ctx)
+}
+
+// Close closes the PostgreSQL read replicas, GORM manages the pool of the primary
 func (p *PostgreSQL) Close(ctx context.Context) error {
-	// No-op for now, as GORM manages connection pooling
+	if p.Replicas != nil {
+		return p.Replicas.Close()
+	}
 	return nil
 }
 
@@ -139,6 +172,44 @@ func NewPostgreSQLClient(db *gorm.DB) *PostgreSQL {
 	return &PostgreSQL{DB: db}
 }
 
+// SQLite represents a SQLite database client
+type SQLite struct {
+	DB *gorm.DB
+}
+
+// SetDB sets the GORM database connection
+func (s *SQLite) SetDB(db *gorm.DB) {
+	s.DB = db
+}
+
+// GetDB returns the GORM database connection
+func (s *SQLite) GetDB(ctx context.Context) *gorm.DB {
+	if s.DB == nil {
+		return nil
+	}
+	return s.DB.WithContext(ctx)
+}
+
+// Close closes the SQLite connection, checkpointing the write-ahead log into the database file
+func (s *SQLite) Close(ctx context.Context) error {
+	if s.DB == nil {
+		return nil
+	}
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get SQLite DB: %w", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("failed to close SQLite connection: %w", err)
+	}
+	return nil
+}
+
+// NewSQLiteClient creates a new SQLite client
+func NewSQLiteClient(db *gorm.DB) *SQLite {
+	return &SQLite{DB: db}
+}
+
 // Redis represents a Redis client
 type Redis struct {
 	DB *redis.Client
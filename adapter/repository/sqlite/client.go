@@ -0,0 +1,155 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/config"
+)
+
+// SQLite defaults
+const (
+	// MemoryPath keeps the database in memory, it is lost when the client is closed
+	MemoryPath = ":memory:"
+	// DefaultBusyTimeout is how long, in milliseconds, a statement waits for a lock held by another connection
+	DefaultBusyTimeout = 5000
+	// DefaultJournalMode lets readers proceed while a transaction writes
+	DefaultJournalMode = "WAL"
+	// DefaultMaxOpenConns bounds the connections to a database file
+	DefaultMaxOpenConns = 4
+)
+
+// SQLiteClient represents a SQLite database client using GORM
+type SQLiteClient struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteClient opens the database file of the configuration, creating it when missing
+func NewSQLiteClient(cfg *config.SQLiteConfig) (*SQLiteClient, error) {
+	if cfg == nil || cfg.Path == "" {
+		return nil, repository.ErrMissingSQLiteConfig
+	}
+
+	// SQLite creates the database file but not the directory it is in
+	if cfg.Path != MemoryPath {
+		if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create SQLite database directory: %w", err)
+		}
+	}
+
+	db, err := openSQLiteDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLiteClient{DB: db}, nil
+}
+
+// GetDB returns the GORM database instance with context
+func (c *SQLiteClient) GetDB(ctx context.Context) *gorm.DB {
+	return c.DB.WithContext(ctx)
+}
+
+// SetDB sets the GORM database instance
+func (c *SQLiteClient) SetDB(db *gorm.DB) {
+	c.DB = db
+}
+
+// Close closes the SQLite database connection
+func (c *SQLiteClient) Close(ctx context.Context) error {
+	sqlDB, err := c.GetDB(ctx).DB()
+	if err != nil {
+		return fmt.Errorf("failed to get SQLite DB: %w", err)
+	}
+
+	if sqlDB != nil {
+		if err := sqlDB.Close(); err != nil {
+			return fmt.Errorf("failed to close SQLite connection: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DSN builds the data source name of the configuration. Write transactions take the write lock when
+// they begin, so concurrent writers wait for each other instead of failing to upgrade a read lock.
+func DSN(cfg *config.SQLiteConfig) string {
+	busyTimeout := cfg.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = DefaultBusyTimeout
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout("+strconv.Itoa(busyTimeout)+")")
+	params.Add("_pragma", "foreign_keys(1)")
+	if cfg.Path != MemoryPath {
+		journalMode := cfg.JournalMode
+		if journalMode == "" {
+			journalMode = DefaultJournalMode
+		}
+		params.Add("_pragma", "journal_mode("+journalMode+")")
+	}
+	params.Set("_txlock", "immediate")
+
+	return cfg.Path + "?" + params.Encode()
+}
+
+// openSQLiteDB creates and opens a new GORM database connection
+func openSQLiteDB(cfg *config.SQLiteConfig) (*gorm.DB, error) {
+	// Configure GORM logger
+	gormLogger := logger.New(
+		log.New(os.Stdout, "\r\n", log.LstdFlags),
+		logger.Config{
+			SlowThreshold:             time.Second, // Slow SQL threshold
+			LogLevel:                  logger.Warn, // Log level
+			IgnoreRecordNotFoundError: true,        // Not found is an expected outcome of lookups
+			Colorful:                  true,        // Enable colorful output
+		},
+	)
+
+	// GORM configuration
+	gormConfig := &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{SingularTable: true},
+		Logger:         gormLogger,
+	}
+
+	// Open database connection
+	db, err := gorm.Open(sqlite.Open(DSN(cfg)), gormConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database %s: %w", cfg.Path, err)
+	}
+
+	// Configure connection pool
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SQL DB: %w", err)
+	}
+
+	maxOpenConns := cfg.MaxOpenConns
+	if maxOpenConns <= 0 {
+		maxOpenConns = DefaultMaxOpenConns
+	}
+	// Every connection to an in-memory database opens a database of its own
+	if cfg.Path == MemoryPath {
+		maxOpenConns = 1
+	}
+	sqlDB.SetMaxOpenConns(maxOpenConns)
+	sqlDB.SetMaxIdleConns(maxOpenConns)
+	// Keep the connections, closing the last one drops an in-memory database
+	sqlDB.SetConnMaxLifetime(0)
+	sqlDB.SetConnMaxIdleTime(0)
+
+	return db, nil
+}
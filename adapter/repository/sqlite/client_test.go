@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/config"
+	"go-hexagonal/domain/model"
+)
+
+func TestNewSQLiteClient_MissingConfig(t *testing.T) {
+	_, err := NewSQLiteClient(nil)
+	assert.ErrorIs(t, err, repository.ErrMissingSQLiteConfig)
+
+	_, err = NewSQLiteClient(&config.SQLiteConfig{Enabled: true})
+	assert.ErrorIs(t, err, repository.ErrMissingSQLiteConfig)
+}
+
+func TestSQLiteClient_MigrateIsIdempotent(t *testing.T) {
+	cfg := SetupSQLiteDatabase(t)
+	client := GetTestDB(t, cfg)
+	ctx := context.Background()
+
+	require.NoError(t, client.Migrate(ctx))
+
+	var versions []int
+	require.NoError(t, client.GetDB(ctx).Table(migrationTable).Order("version").Pluck("version", &versions).Error)
+	loaded, err := loadMigrations()
+	require.NoError(t, err)
+	require.Len(t, versions, len(loaded))
+	assert.Equal(t, 1, versions[0])
+
+	// The database file outlives the client
+	_, err = NewExampleRepo(client).Create(ctx, nil, &model.Example{Name: "kept"})
+	require.NoError(t, err)
+	reopened := GetTestDB(t, cfg)
+	found, err := NewExampleRepo(reopened).FindByName(ctx, nil, "kept")
+	require.NoError(t, err)
+	assert.Equal(t, "kept", found.Name)
+}
+
+func TestSQLiteClient_InMemory(t *testing.T) {
+	client := GetTestDB(t, &config.SQLiteConfig{Enabled: true, Path: MemoryPath, MaxOpenConns: 8})
+	ctx := context.Background()
+
+	sqlDB, err := client.DB.DB()
+	require.NoError(t, err)
+	assert.Equal(t, 1, sqlDB.Stats().MaxOpenConnections)
+
+	r := NewExampleRepo(client)
+	created, err := r.Create(ctx, nil, &model.Example{Name: "in memory"})
+	require.NoError(t, err)
+	_, err = r.GetByID(ctx, nil, created.Id)
+	assert.NoError(t, err)
+}
+
+func TestDSN(t *testing.T) {
+	dsn := DSN(&config.SQLiteConfig{Path: "app.db", BusyTimeout: 100, JournalMode: "DELETE"})
+	assert.Contains(t, dsn, "app.db?")
+	assert.Contains(t, dsn, "busy_timeout%28100%29")
+	assert.Contains(t, dsn, "journal_mode%28DELETE%29")
+	assert.Contains(t, dsn, "_txlock=immediate")
+
+	assert.NotContains(t, DSN(&config.SQLiteConfig{Path: MemoryPath}), "journal_mode")
+}
@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"errors"
+
+	driver "github.com/glebarez/go-sqlite"
+
+	"go-hexagonal/domain/model"
+)
+
+// Extended SQLite result codes of unique index violations
+const (
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+// translateError maps SQLite driver errors to domain errors.
+// name, when known, identifies the example in the conflict message.
+func translateError(err error, name string) error {
+	if isDuplicateKey(err) {
+		// The only unique index besides the primary key is the live name key
+		if name == "" {
+			return model.ErrExampleNameTaken
+		}
+		return model.NewExampleNameTakenError(name)
+	}
+	return err
+}
+
+// isDuplicateKey reports whether err is a unique index violation
+func isDuplicateKey(err error) bool {
+	var sqliteErr *driver.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code() == sqliteConstraintUnique || sqliteErr.Code() == sqliteConstraintPrimaryKey
+}
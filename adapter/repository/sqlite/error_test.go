@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/model"
+)
+
+func TestTranslateError(t *testing.T) {
+	client := GetTestDB(t, SetupSQLiteDatabase(t))
+	db := client.GetDB(context.Background())
+
+	require.NoError(t, db.Exec("INSERT INTO example (name, name_key) VALUES ('Test', 'test')").Error)
+	duplicate := db.Exec("INSERT INTO example (name, name_key) VALUES ('TEST', 'test')").Error
+	require.Error(t, duplicate)
+
+	err := translateError(duplicate, "TEST")
+	assert.True(t, model.IsExampleNameTakenError(err))
+	assert.Contains(t, err.Error(), "TEST")
+
+	assert.ErrorIs(t, translateError(duplicate, ""), model.ErrExampleNameTaken)
+
+	notNull := db.Exec("INSERT INTO example (name_key) VALUES ('other')").Error
+	require.Error(t, notNull)
+	assert.Same(t, notNull, translateError(notNull, "Test"))
+
+	plain := errors.New("disk I/O error")
+	assert.Equal(t, plain, translateError(plain, "Test"))
+}
@@ -0,0 +1,286 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+// ExampleRepo implements the example repository for SQLite
+type ExampleRepo struct {
+	client *SQLiteClient
+}
+
+// NewExampleRepo creates a new SQLite example repository
+func NewExampleRepo(client *SQLiteClient) repo.IExampleRepo {
+	return &ExampleRepo{
+		client: client,
+	}
+}
+
+// Create creates a new example in the database
+func (r *ExampleRepo) Create(ctx context.Context, tr repo.Transaction, example *model.Example) (*model.Example, error) {
+	// Set timestamps
+	now := time.Now()
+	example.CreatedAt = now
+	example.UpdatedAt = now
+
+	// New records start at the first version
+	if example.Version == 0 {
+		example.Version = 1
+	}
+
+	// Derive the uniqueness key from the name
+	example.NameKey = model.NormalizeExampleName(example.Name)
+
+	// Get DB connection (from transaction or direct client)
+	db := r.getDB(ctx, tr)
+
+	// Create record
+	if err := db.Create(example).Error; err != nil {
+		return nil, translateError(err, example.Name)
+	}
+
+	return example, nil
+}
+
+// Update updates an existing example if its version still matches the stored one.
+// On success the example's version is advanced to the persisted value.
+func (r *ExampleRepo) Update(ctx context.Context, tr repo.Transaction, example *model.Example) error {
+	// Set update timestamp
+	example.UpdatedAt = time.Now()
+
+	// Get DB connection (from transaction or direct client)
+	db := r.getDB(ctx, tr)
+
+	// Conditional update, only applied if nobody changed the row since it was read
+	result := db.Model(&model.Example{}).
+		Where("id = ? AND version = ? AND deleted_at IS NULL", example.Id, example.Version).
+		Updates(map[string]any{
+			"name":       example.Name,
+			"name_key":   model.NormalizeExampleName(example.Name),
+			"alias":      example.Alias,
+			"updated_at": example.UpdatedAt,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return translateError(result.Error, example.Name)
+	}
+
+	// Distinguish a missing record from a stale version
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := db.Model(&model.Example{}).Where("id = ? AND deleted_at IS NULL", example.Id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return repo.ErrNotFound
+		}
+		return model.ErrExampleModified
+	}
+
+	example.Version++
+	return nil
+}
+
+// Delete soft deletes an example by ID
+func (r *ExampleRepo) Delete(ctx context.Context, tr repo.Transaction, id int) error {
+	// Get DB connection (from transaction or direct client)
+	db := r.getDB(ctx, tr)
+
+	// Mark record as deleted
+	result := db.Model(&model.Example{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(map[string]any{
+			"deleted_at": time.Now(),
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// Check if record exists
+	if result.RowsAffected == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+// Restore clears the deletion mark of a soft deleted example
+func (r *ExampleRepo) Restore(ctx context.Context, tr repo.Transaction, id int) error {
+	// Get DB connection (from transaction or direct client)
+	db := r.getDB(ctx, tr)
+
+	// Clear deletion mark
+	result := db.Model(&model.Example{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]any{
+			"deleted_at": nil,
+			"updated_at": time.Now(),
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		// A live example may have taken the name while this one was deleted
+		return translateError(result.Error, "")
+	}
+
+	// Check if a deleted record exists
+	if result.RowsAffected == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+// Purge permanently removes an example by ID
+func (r *ExampleRepo) Purge(ctx context.Context, tr repo.Transaction, id int) error {
+	// Get DB connection (from transaction or direct client)
+	db := r.getDB(ctx, tr)
+
+	// Delete record
+	result := db.Delete(&model.Example{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// Check if record exists
+	if result.RowsAffected == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetByID retrieves an example by ID
+func (r *ExampleRepo) GetByID(ctx context.Context, tr repo.Transaction, id int) (*model.Example, error) {
+	// Get DB connection (from transaction or direct client)
+	db := r.getDB(ctx, tr)
+
+	// Find record
+	var example model.Example
+	if err := db.Where("id = ? AND deleted_at IS NULL", id).First(&example).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repo.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &example, nil
+}
+
+// GetByIDWithDeleted retrieves an example by ID even if it is soft deleted
+func (r *ExampleRepo) GetByIDWithDeleted(ctx context.Context, tr repo.Transaction, id int) (*model.Example, error) {
+	// Get DB connection (from transaction or direct client)
+	db := r.getDB(ctx, tr)
+
+	// Find record
+	var example model.Example
+	if err := db.Where("id = ?", id).First(&example).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repo.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &example, nil
+}
+
+// FindByName retrieves a live example by name, ignoring case and Unicode representation
+func (r *ExampleRepo) FindByName(ctx context.Context, tr repo.Transaction, name string) (*model.Example, error) {
+	// Get DB connection (from transaction or direct client)
+	db := r.getDB(ctx, tr)
+
+	// Find record
+	var example model.Example
+	if err := db.Where("name_key = ? AND deleted_at IS NULL", model.NormalizeExampleName(name)).First(&example).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repo.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &example, nil
+}
+
+// List retrieves examples matching the query
+func (r *ExampleRepo) List(ctx context.Context, tr repo.Transaction, query repo.ExampleListQuery) ([]*model.Example, error) {
+	// Get DB connection (from transaction or direct client)
+	db := r.getDB(ctx, tr)
+
+	// Build query
+	db = repository.ApplyExampleFilterWithEscape(db.Model(&model.Example{}), query.Filter, "LIKE")
+	db = repository.ApplyExampleOrder(db, query)
+
+	// Find records
+	examples := make([]*model.Example, 0)
+	if err := db.Find(&examples).Error; err != nil {
+		return nil, err
+	}
+
+	return examples, nil
+}
+
+// Count returns the number of examples matching the filter
+func (r *ExampleRepo) Count(ctx context.Context, tr repo.Transaction, filter repo.ExampleFilter) (int64, error) {
+	// Get DB connection (from transaction or direct client)
+	db := r.getDB(ctx, tr)
+
+	// Count records
+	var total int64
+	if err := repository.ApplyExampleFilterWithEscape(db.Model(&model.Example{}), filter, "LIKE").Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// ListByCursor retrieves a page of examples using keyset pagination
+func (r *ExampleRepo) ListByCursor(ctx context.Context, tr repo.Transaction, query repo.ExampleKeysetQuery) ([]*model.Example, error) {
+	// Get DB connection (from transaction or direct client)
+	db := r.getDB(ctx, tr)
+
+	// Build query
+	db = repository.ApplyExampleFilterWithEscape(db.Model(&model.Example{}), query.Filter, "LIKE")
+	db = repository.ApplyExampleKeyset(db, query)
+
+	// Find records
+	examples := make([]*model.Example, 0)
+	if err := db.Find(&examples).Error; err != nil {
+		return nil, err
+	}
+
+	// Backward reads are scanned in reverse, restore display order
+	if query.Backward {
+		slices.Reverse(examples)
+	}
+
+	return examples, nil
+}
+
+// getDB returns the appropriate database connection based on transaction
+func (r *ExampleRepo) getDB(ctx context.Context, tr repo.Transaction) *gorm.DB {
+	// Fall back to the unit of work carried by the context, e.g. when called with a no-op transaction
+	if _, ok := tr.(*repository.Transaction); !ok {
+		if ctxTr, found := repo.TransactionFromContext(ctx); found {
+			tr = ctxTr
+		}
+	}
+
+	if tr != nil {
+		// Use transaction context
+		txCtx := tr.Context()
+		// Check if we can get session from transaction implementation
+		if repo, ok := tr.(*repository.Transaction); ok && repo.Session != nil {
+			return repo.Session.WithContext(txCtx)
+		}
+	}
+	return r.client.GetDB(ctx)
+}
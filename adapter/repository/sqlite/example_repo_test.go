@@ -0,0 +1,289 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/domain/repo/repotest"
+)
+
+// newTestRepo creates an example repository and a transaction factory over a fresh database file
+func newTestRepo(t *testing.T) (repo.IExampleRepo, repo.TransactionFactory) {
+	t.Helper()
+	client := GetTestDB(t, SetupSQLiteDatabase(t))
+	return NewExampleRepo(client), NewTransactionFactory(client)
+}
+
+// createExample stores an example outside of any transaction
+func createExample(t *testing.T, r repo.IExampleRepo, name, alias string) *model.Example {
+	t.Helper()
+	created, err := r.Create(context.Background(), nil, &model.Example{Name: name, Alias: alias})
+	require.NoError(t, err)
+	return created
+}
+
+// inTransaction runs fn in a transaction, committed when fn succeeds
+func inTransaction(ctx context.Context, factory repo.TransactionFactory, fn func(tx repo.Transaction) error) error {
+	tx, err := factory.NewTransaction(ctx, repo.SQLiteStore, nil)
+	if err != nil {
+		return err
+	}
+	if err := tx.Begin(); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func TestExampleRepo_CreateAndGet(t *testing.T) {
+	r, _ := newTestRepo(t)
+	ctx := context.Background()
+
+	created := createExample(t, r, "Straße", "alias")
+	assert.NotZero(t, created.Id)
+	assert.Equal(t, 1, created.Version)
+
+	found, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "Straße", found.Name)
+	assert.Equal(t, "alias", found.Alias)
+	assert.WithinDuration(t, created.CreatedAt, found.CreatedAt, time.Millisecond)
+
+	byName, err := r.FindByName(ctx, nil, "STRASSE")
+	require.NoError(t, err)
+	assert.Equal(t, created.Id, byName.Id)
+
+	_, err = r.GetByID(ctx, nil, created.Id+1)
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+	_, err = r.FindByName(ctx, nil, "missing")
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+}
+
+func TestExampleRepo_LiveNameIsUnique(t *testing.T) {
+	r, _ := newTestRepo(t)
+	ctx := context.Background()
+
+	first := createExample(t, r, "Name", "")
+	_, err := r.Create(ctx, nil, &model.Example{Name: "NAME"})
+	assert.True(t, model.IsExampleNameTakenError(err))
+
+	// The name is free again once its example is deleted, which then cannot be restored
+	require.NoError(t, r.Delete(ctx, nil, first.Id))
+	createExample(t, r, "name", "")
+	assert.ErrorIs(t, r.Restore(ctx, nil, first.Id), model.ErrExampleNameTaken)
+}
+
+func TestExampleRepo_UpdateChecksVersion(t *testing.T) {
+	r, _ := newTestRepo(t)
+	ctx := context.Background()
+
+	created := createExample(t, r, "name", "alias")
+	stale := *created
+
+	created.Alias = "changed"
+	require.NoError(t, r.Update(ctx, nil, created))
+	assert.Equal(t, 2, created.Version)
+
+	stale.Alias = "lost update"
+	assert.ErrorIs(t, r.Update(ctx, nil, &stale), model.ErrExampleModified)
+
+	missing := &model.Example{Id: created.Id + 1, Name: "missing", Version: 1}
+	assert.ErrorIs(t, r.Update(ctx, nil, missing), repo.ErrNotFound)
+
+	found, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "changed", found.Alias)
+	assert.Equal(t, 2, found.Version)
+}
+
+func TestExampleRepo_DeleteRestorePurge(t *testing.T) {
+	r, _ := newTestRepo(t)
+	ctx := context.Background()
+
+	created := createExample(t, r, "name", "")
+
+	require.NoError(t, r.Delete(ctx, nil, created.Id))
+	assert.ErrorIs(t, r.Delete(ctx, nil, created.Id), repo.ErrNotFound)
+	_, err := r.GetByID(ctx, nil, created.Id)
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+
+	deleted, err := r.GetByIDWithDeleted(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.NotNil(t, deleted.DeletedAt)
+
+	require.NoError(t, r.Restore(ctx, nil, created.Id))
+	assert.ErrorIs(t, r.Restore(ctx, nil, created.Id), repo.ErrNotFound)
+	restored, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+	assert.Equal(t, 3, restored.Version)
+
+	require.NoError(t, r.Purge(ctx, nil, created.Id))
+	assert.ErrorIs(t, r.Purge(ctx, nil, created.Id), repo.ErrNotFound)
+	_, err = r.GetByIDWithDeleted(ctx, nil, created.Id)
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+}
+
+func TestExampleRepo_ListAndCount(t *testing.T) {
+	r, _ := newTestRepo(t)
+	ctx := context.Background()
+
+	createExample(t, r, "100% cotton", "shirt")
+	createExample(t, r, "1000 cotton", "sheet")
+	deleted := createExample(t, r, "100% wool", "sweater")
+	require.NoError(t, r.Delete(ctx, nil, deleted.Id))
+
+	// Wildcards in the filter are matched literally
+	filter := repo.ExampleFilter{Name: "0%"}
+	examples, err := r.List(ctx, nil, repo.ExampleListQuery{Filter: filter})
+	require.NoError(t, err)
+	require.Len(t, examples, 1)
+	assert.Equal(t, "100% cotton", examples[0].Name)
+
+	total, err := r.Count(ctx, nil, repo.ExampleFilter{Name: "COTTON"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	total, err = r.Count(ctx, nil, repo.ExampleFilter{Name: "100", IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+
+	examples, err = r.List(ctx, nil, repo.ExampleListQuery{SortBy: repo.ExampleSortByName, SortDesc: true, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, examples, 1)
+	assert.Equal(t, "1000 cotton", examples[0].Name)
+}
+
+func TestExampleRepo_ListByCursor(t *testing.T) {
+	r, _ := newTestRepo(t)
+	ctx := context.Background()
+
+	var created []*model.Example
+	for i := 1; i <= 5; i++ {
+		created = append(created, createExample(t, r, fmt.Sprintf("example %d", i), ""))
+	}
+
+	page, err := r.ListByCursor(ctx, nil, repo.ExampleKeysetQuery{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, created[0].Id, page[0].Id)
+	assert.Equal(t, created[1].Id, page[1].Id)
+
+	page, err = r.ListByCursor(ctx, nil, repo.ExampleKeysetQuery{Cursor: repo.NewExampleCursor(page[1]), Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, created[2].Id, page[0].Id)
+	assert.Equal(t, created[3].Id, page[1].Id)
+
+	page, err = r.ListByCursor(ctx, nil, repo.ExampleKeysetQuery{Cursor: repo.NewExampleCursor(page[0]), Backward: true, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, created[0].Id, page[0].Id)
+	assert.Equal(t, created[1].Id, page[1].Id)
+}
+
+func TestExampleRepo_TransactionRollback(t *testing.T) {
+	r, factory := newTestRepo(t)
+	ctx := context.Background()
+
+	// Transactions are only opened for the SQLite store
+	_, err := factory.NewTransaction(ctx, repo.MySQLStore, nil)
+	require.Error(t, err)
+	tx, err := factory.NewTransaction(ctx, repo.SQLiteStore, nil)
+	require.NoError(t, err)
+	assert.Equal(t, repo.SQLiteStore, tx.StoreType())
+	require.NoError(t, tx.Begin())
+
+	created, err := r.Create(ctx, tx, &model.Example{Name: "rolled back"})
+	require.NoError(t, err)
+	_, err = r.GetByID(ctx, tx, created.Id)
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	_, err = r.GetByID(ctx, nil, created.Id)
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+
+	_, err = factory.NewTransaction(ctx, repo.RedisStore, nil)
+	assert.Error(t, err)
+}
+
+func TestExampleRepo_ConcurrentWriters(t *testing.T) {
+	r, factory := newTestRepo(t)
+	ctx := context.Background()
+
+	created := createExample(t, r, "counter", "")
+
+	// Writers serialize on the write lock instead of failing with SQLITE_BUSY
+	const writers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- inTransaction(ctx, factory, func(tx repo.Transaction) error {
+				example, err := r.GetByID(ctx, tx, created.Id)
+				if err != nil {
+					return err
+				}
+				example.Alias = fmt.Sprintf("writer %d", i)
+				return r.Update(ctx, tx, example)
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	found, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 1+writers, found.Version)
+
+	// Read-only transactions do not take the write lock
+	readOnly, err := factory.NewTransaction(ctx, repo.SQLiteStore, &sql.TxOptions{ReadOnly: true})
+	require.NoError(t, err)
+	assert.True(t, readOnly.Options().ReadOnly)
+	require.NoError(t, readOnly.Begin())
+	_, err = r.GetByID(ctx, readOnly, created.Id)
+	require.NoError(t, err)
+	require.NoError(t, readOnly.Commit())
+}
+
+func TestExampleRepo_ContextTransaction(t *testing.T) {
+	r, factory := newTestRepo(t)
+	ctx := context.Background()
+
+	tx, err := factory.NewTransaction(ctx, repo.SQLiteStore, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Begin())
+
+	// Called with a no-op transaction, the repository joins the one carried by the context
+	txCtx := repo.ContextWithTransaction(ctx, tx)
+	created, err := r.Create(txCtx, repo.NewNoopTransaction(nil), &model.Example{Name: "joined"})
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	_, err = r.GetByID(ctx, nil, created.Id)
+	assert.True(t, errors.Is(err, repo.ErrNotFound))
+}
+
+func TestExampleRepo_Contract(t *testing.T) {
+	repotest.RunExampleRepoTests(t, repo.SQLiteStore, func(t *testing.T) (repo.IExampleRepo, repo.TransactionFactory) {
+		return newTestRepo(t)
+	})
+}
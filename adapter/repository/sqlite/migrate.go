@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrations holds the schema changes, applied in the order of the version prefixing their file names
+//
+//go:embed migrations/*.up.sql
+var migrations embed.FS
+
+// migrationTable records the applied migration versions
+const migrationTable = "schema_migrations"
+
+// migration is a schema change read from the embedded files
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// Migrate applies the migrations not applied to the database yet, each in a transaction of its own
+func (c *SQLiteClient) Migrate(ctx context.Context) error {
+	db := c.GetDB(ctx)
+
+	if err := db.Exec("CREATE TABLE IF NOT EXISTS " + migrationTable +
+		" (version INTEGER PRIMARY KEY, applied_at DATETIME NOT NULL)").Error; err != nil {
+		return fmt.Errorf("failed to create %s table: %w", migrationTable, err)
+	}
+
+	pending, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pending {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var applied int64
+			if err := tx.Table(migrationTable).Where("version = ?", m.version).Count(&applied).Error; err != nil {
+				return err
+			}
+			if applied > 0 {
+				return nil
+			}
+
+			if err := tx.Exec(m.sql).Error; err != nil {
+				return err
+			}
+			return tx.Exec("INSERT INTO "+migrationTable+" (version, applied_at) VALUES (?, ?)", m.version, time.Now()).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
+
+// loadMigrations reads the embedded migrations sorted by version
+func loadMigrations() ([]migration, error) {
+	files, err := fs.Glob(migrations, "migrations/*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make([]migration, 0, len(files))
+	for _, file := range files {
+		name := strings.TrimPrefix(file, "migrations/")
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has no version prefix: %w", name, err)
+		}
+
+		content, err := migrations.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, migration{version: version, name: name, sql: string(content)})
+	}
+
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].version < loaded[j].version })
+	return loaded, nil
+}
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS example (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    name_key TEXT NOT NULL,
+    alias TEXT DEFAULT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL DEFAULT NULL,
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+-- Names are unique among the examples that are not soft deleted
+CREATE UNIQUE INDEX IF NOT EXISTS uk_live_name_key ON example (name_key) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_name ON example (name);
+CREATE INDEX IF NOT EXISTS idx_deleted_at ON example (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_created_at_id ON example (created_at, id);
@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"go-hexagonal/config"
+)
+
+// SetupSQLiteDatabase returns the configuration of a database file in a temporary directory of the test
+func SetupSQLiteDatabase(t *testing.T) *config.SQLiteConfig {
+	t.Helper()
+
+	return &config.SQLiteConfig{
+		Enabled: true,
+		Path:    filepath.Join(t.TempDir(), "test.db"),
+	}
+}
+
+// GetTestDB opens the database of the configuration with the migrations applied, it is closed when the test ends
+func GetTestDB(t *testing.T, cfg *config.SQLiteConfig) *SQLiteClient {
+	t.Helper()
+
+	client, err := NewSQLiteClient(cfg)
+	if err != nil {
+		t.Fatalf("Failed to open SQLite database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := client.Close(context.Background()); err != nil {
+			t.Errorf("Failed to close SQLite database: %v", err)
+		}
+	})
+
+	if err := client.Migrate(context.Background()); err != nil {
+		t.Fatalf("Failed to migrate SQLite database: %v", err)
+	}
+
+	return client
+}
@@ -0,0 +1,39 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/domain/repo"
+)
+
+// TransactionFactory opens transactions on the SQLite database. It accepts only the SQLite store
+// type, requests for any other store are rejected.
+type TransactionFactory struct {
+	client *SQLiteClient
+}
+
+// NewTransactionFactory creates a transaction factory over the SQLite client
+func NewTransactionFactory(client *SQLiteClient) repo.TransactionFactory {
+	return &TransactionFactory{
+		client: client,
+	}
+}
+
+// NewTransaction creates a new transaction, read-only options start it without taking the write lock
+func (f *TransactionFactory) NewTransaction(ctx context.Context, store repo.StoreType, opts any) (repo.Transaction, error) {
+	if store != repo.SQLiteStore {
+		return nil, fmt.Errorf("no client found for store type: %s", store)
+	}
+
+	// Convert options to SQL options if applicable
+	sqlOpts := repository.SQLTxOptions(opts)
+
+	tx, err := repository.NewTransaction(ctx, repository.SQLiteStore, f.client, sqlOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	return tx, nil
+}
@@ -98,7 +98,7 @@ func (tr *Transaction) Options() *repo.TransactionOptions {
 // NewTransaction creates a new transaction with the specified store type and options
 func NewTransaction(ctx context.Context, store StoreType, client any, sqlOpt *sql.TxOptions) (*Transaction, error) {
 	// Convert store type to domain store type
-	domainStore := repo.StoreType(store)
+	domainStore := toDomainStoreType(store)
 
 	// Convert SQL options to transaction options
 	var options *repo.TransactionOptions
@@ -118,15 +118,23 @@ func NewTransaction(ctx context.Context, store StoreType, client any, sqlOpt *sq
 	}
 
 	switch store {
-	case MySQLStore, PostgreSQLStore:
+	case MySQLStore, PostgreSQLStore, SQLiteStore:
 		// Handle SQL-based databases with GORM
 		var db *gorm.DB
 
+		// Read-only transactions run on a replica when the client routes reads
+		methodNames := []string{"GetDB"}
+		if sqlOpt != nil && sqlOpt.ReadOnly {
+			methodNames = []string{"GetReadDB", "GetDB"}
+		}
+
 		// Use reflection to check type and call appropriate method
 		clientValue := reflect.ValueOf(client)
-		if clientValue.Kind() == reflect.Ptr && !clientValue.IsNil() {
-			// Try to call GetDB method
-			method := clientValue.MethodByName("GetDB")
+		for _, methodName := range methodNames {
+			if db != nil || clientValue.Kind() != reflect.Ptr || clientValue.IsNil() {
+				break
+			}
+			method := clientValue.MethodByName(methodName)
 			if method.IsValid() {
 				result := method.Call([]reflect.Value{reflect.ValueOf(ctx)})
 				if len(result) > 0 && !result[0].IsNil() {
@@ -152,6 +160,20 @@ func NewTransaction(ctx context.Context, store StoreType, client any, sqlOpt *sq
 	return tr, nil
 }
 
+// SQLTxOptions converts the options given to a transaction factory, *sql.TxOptions or
+// *repo.TransactionOptions, to SQL transaction options
+func SQLTxOptions(opts any) *sql.TxOptions {
+	switch opt := opts.(type) {
+	case *sql.TxOptions:
+		return opt
+	case *repo.TransactionOptions:
+		if opt != nil && opt.ReadOnly {
+			return &sql.TxOptions{ReadOnly: true}
+		}
+	}
+	return nil
+}
+
 // StoreType defines the type of storage
 type StoreType string
 
@@ -160,4 +182,37 @@ const (
 	MySQLStore      StoreType = "MySQL"
 	RedisStore      StoreType = "Redis"
 	PostgreSQLStore StoreType = "PostgreSQL"
+	SQLiteStore     StoreType = "SQLite"
 )
+
+// toAdapterStoreType maps a domain store type to the adapter store type
+func toAdapterStoreType(store repo.StoreType) StoreType {
+	switch store {
+	case repo.MySQLStore:
+		return MySQLStore
+	case repo.PostgresStore:
+		return PostgreSQLStore
+	case repo.SQLiteStore:
+		return SQLiteStore
+	case repo.RedisStore:
+		return RedisStore
+	default:
+		return StoreType(store)
+	}
+}
+
+// toDomainStoreType maps an adapter store type to the domain store type
+func toDomainStoreType(store StoreType) repo.StoreType {
+	switch store {
+	case MySQLStore:
+		return repo.MySQLStore
+	case PostgreSQLStore:
+		return repo.PostgresStore
+	case SQLiteStore:
+		return repo.SQLiteStore
+	case RedisStore:
+		return repo.RedisStore
+	default:
+		return repo.StoreType(store)
+	}
+}
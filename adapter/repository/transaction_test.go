@@ -0,0 +1,33 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/domain/repo"
+)
+
+func TestTransactionFactory_ResolvesDomainStoreTypes(t *testing.T) {
+	db := newDryRunDB(t)
+	factory := repository.NewTransactionFactory(map[repository.StoreType]any{
+		repository.MySQLStore:      repository.NewMySQLClient(db),
+		repository.PostgreSQLStore: repository.NewPostgreSQLClient(db),
+	})
+
+	for _, store := range []repo.StoreType{repo.MySQLStore, repo.PostgresStore} {
+		tx, err := factory.NewTransaction(context.Background(), store, nil)
+		require.NoError(t, err, store)
+		assert.Equal(t, store, tx.StoreType())
+
+		sqlTx, ok := tx.(*repository.Transaction)
+		require.True(t, ok)
+		assert.NotNil(t, sqlTx.Session)
+	}
+
+	_, err := factory.NewTransaction(context.Background(), repo.MongoStore, nil)
+	assert.Error(t, err)
+}
@@ -5,3 +5,9 @@ type Pager struct {
 	PageSize  int `json:"page_size"`
 	TotalRows int `json:"total_rows"`
 }
+
+type CursorPager struct {
+	PageSize   int    `json:"page_size"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
@@ -21,14 +21,25 @@ type DeleteExampleReq struct {
 	Id int `uri:"id" binding:"required"`
 }
 
+type RestoreExampleReq struct {
+	Id int `uri:"id" binding:"required"`
+}
+
+type PurgeExampleReq struct {
+	Id int `uri:"id" binding:"required"`
+}
+
 type UpdateExampleReq struct {
 	Id    uint   `uri:"id"`
 	Name  string `json:"name"`
 	Alias string `json:"alias"`
+	// Version is the version the client read, an If-Match header takes precedence
+	Version int `json:"version" binding:"min=0"`
 }
 
 type GetExampleReq struct {
-	Id int `uri:"id" binding:"required"`
+	Id             int  `uri:"id" binding:"required"`
+	IncludeDeleted bool `form:"include_deleted"`
 }
 
 type GetExampleResponse struct {
@@ -38,3 +49,15 @@ type GetExampleResponse struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+type ListExampleReq struct {
+	Name           string    `form:"name"`
+	Alias          string    `form:"alias"`
+	CreatedAfter   time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore  time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	SortBy         string    `form:"sort_by" binding:"omitempty,oneof=id name created_at updated_at"`
+	SortOrder      string    `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	Pagination     string    `form:"pagination" binding:"omitempty,oneof=offset cursor"`
+	Cursor         string    `form:"cursor"`
+	IncludeDeleted bool      `form:"include_deleted"`
+}
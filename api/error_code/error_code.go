@@ -24,6 +24,8 @@ const (
 	InvalidParamsCode   = 10001
 	NotFoundCode        = 10002
 	TooManyRequestsCode = 10003
+	ConflictCode        = 10004
+	UnavailableCode     = 10005
 
 	UnauthorizedAuthNotExistErrorCode  = 20001
 	UnauthorizedTokenErrorCode         = 20002
@@ -33,8 +35,9 @@ const (
 	CopyErrorErrorCode = 30001
 	JSONErrorErrorCode = 30002
 
-	AccountExistErrorCode  = 40001
-	UserNameExistErrorCode = 40002
+	AccountExistErrorCode     = 40001
+	UserNameExistErrorCode    = 40002
+	ExampleNameExistErrorCode = 40003
 )
 
 // API error code
@@ -44,6 +47,8 @@ var (
 	InvalidParams   = NewError(InvalidParamsCode, "invalid params")
 	NotFound        = NewError(NotFoundCode, "record not found")
 	TooManyRequests = NewError(TooManyRequestsCode, "too many requests")
+	Conflict        = NewError(ConflictCode, "resource conflict")
+	Unavailable     = NewError(UnavailableCode, "service unavailable")
 )
 
 // Auth error code
@@ -62,8 +67,9 @@ var (
 
 // Business error code
 var (
-	AccountExist  = NewError(AccountExistErrorCode, "account already exists")
-	UserNameExist = NewError(UserNameExistErrorCode, "username already exists")
+	AccountExist     = NewError(AccountExistErrorCode, "account already exists")
+	UserNameExist    = NewError(UserNameExistErrorCode, "username already exists")
+	ExampleNameExist = NewError(ExampleNameExistErrorCode, "example name already exists")
 )
 
 // NewError creates a new Error instance with the specified code and message
@@ -162,6 +168,10 @@ func determineHTTPStatusCode(code int) int {
 		return http.StatusUnauthorized
 	case TooManyRequestsCode:
 		return http.StatusTooManyRequests
+	case UnavailableCode:
+		return http.StatusServiceUnavailable
+	case ConflictCode, AccountExistErrorCode, UserNameExistErrorCode, ExampleNameExistErrorCode:
+		return http.StatusConflict
 	default:
 		return http.StatusInternalServerError
 	}
@@ -1,7 +1,11 @@
 package http
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cast"
@@ -9,9 +13,13 @@ import (
 	"go-hexagonal/api/dto"
 	"go-hexagonal/api/error_code"
 	"go-hexagonal/api/http/handle"
+	"go-hexagonal/api/http/paginate"
 	"go-hexagonal/api/http/validator"
 	"go-hexagonal/application"
+	"go-hexagonal/application/example"
 	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/util/error_handler"
 	"go-hexagonal/util/log"
 )
 
@@ -36,11 +44,11 @@ func CreateExample(ctx *gin.Context) {
 	}
 
 	// If converter exists, convert DTO to domain model first
-	var example *model.Example
+	var entity *model.Example
 	var err error
 
 	if converter != nil {
-		example, err = converter.FromCreateRequest(&body)
+		entity, err = converter.FromCreateRequest(&body)
 		if err != nil {
 			log.SugaredLogger.Errorf("CreateExample.FromCreateRequest errs: %v", err)
 			response.ToErrorResponse(error_code.ServerError)
@@ -50,12 +58,12 @@ func CreateExample(ctx *gin.Context) {
 
 	// Execute use case
 	var result any
-	if example != nil && services != nil && services.ExampleService != nil {
+	if entity != nil && services != nil && services.ExampleService != nil {
 		// Use converted model to execute creation
-		created, err := services.ExampleService.Create(ctx, example.Name, example.Alias)
+		created, err := services.ExampleService.Create(ctx, entity.Name, entity.Alias)
 		if err != nil {
 			log.SugaredLogger.Errorf("CreateExample failed: %v", err.Error())
-			response.ToErrorResponse(error_code.ServerError)
+			response.ToErrorResponse(exampleAPIError(ctx, err, "create example"))
 			return
 		}
 
@@ -68,10 +76,13 @@ func CreateExample(ctx *gin.Context) {
 		}
 	} else {
 		// Fall back to original implementation
-		result, err = appFactory.CreateExampleUseCase().Execute(ctx, body)
+		result, err = appFactory.CreateExampleUseCase().Execute(ctx, &example.CreateInput{
+			Name:  body.Name,
+			Alias: body.Alias,
+		})
 		if err != nil {
 			log.SugaredLogger.Errorf("CreateExample failed: %v", err.Error())
-			response.ToErrorResponse(error_code.ServerError)
+			response.ToErrorResponse(exampleAPIError(ctx, err, "create example"))
 			return
 		}
 	}
@@ -91,25 +102,60 @@ func DeleteExample(ctx *gin.Context) {
 		return
 	}
 
-	// Use direct service call or application factory
-	var err error
-	if services != nil && services.ExampleService != nil {
-		// Direct service call for deletion
-		err = services.ExampleService.Delete(ctx, param.Id)
-		if err != nil {
-			log.SugaredLogger.Errorf("DeleteExample failed: %v", err.Error())
-			response.ToErrorResponse(error_code.ServerError)
-			return
-		}
-	} else {
-		// Fall back to original implementation
-		_, err = appFactory.DeleteExampleUseCase().Execute(ctx, param.Id)
-		if err != nil {
-			log.SugaredLogger.Errorf("DeleteExample failed.%v", err.Error())
-			response.ToErrorResponse(error_code.ServerError)
-			return
-		}
+	// Execute use case, the soft delete and its outbox record share one transaction
+	_, err := appFactory.DeleteExampleUseCase().Execute(ctx, &example.DeleteInput{ID: param.Id})
+	if err != nil {
+		log.SugaredLogger.Errorf("DeleteExample failed: %v", err.Error())
+		response.ToErrorResponse(exampleAPIError(ctx, err, "delete example"))
+		return
 	}
+
+	response.ToResponse(gin.H{})
+}
+
+func RestoreExample(ctx *gin.Context) {
+	response := handle.NewResponse(ctx)
+	param := dto.RestoreExampleReq{}
+
+	valid, errs := validator.BindAndValid(ctx, &param, ctx.ShouldBindUri)
+	if !valid {
+		log.SugaredLogger.Errorf("RestoreExample.BindAndValid errs: %v", errs)
+		errResp := error_code.InvalidParams.WithDetails(errs.Errors()...)
+		response.ToErrorResponse(errResp)
+		return
+	}
+
+	// Execute use case
+	result, err := appFactory.RestoreExampleUseCase().Execute(ctx, &example.RestoreInput{ID: param.Id})
+	if err != nil {
+		log.SugaredLogger.Errorf("RestoreExample failed: %v", err.Error())
+		response.ToErrorResponse(exampleAPIError(ctx, err, "restore example"))
+		return
+	}
+
+	response.ToResponse(result)
+}
+
+func PurgeExample(ctx *gin.Context) {
+	response := handle.NewResponse(ctx)
+	param := dto.PurgeExampleReq{}
+
+	valid, errs := validator.BindAndValid(ctx, &param, ctx.ShouldBindUri)
+	if !valid {
+		log.SugaredLogger.Errorf("PurgeExample.BindAndValid errs: %v", errs)
+		errResp := error_code.InvalidParams.WithDetails(errs.Errors()...)
+		response.ToErrorResponse(errResp)
+		return
+	}
+
+	// Execute use case
+	_, err := appFactory.PurgeExampleUseCase().Execute(ctx, &example.PurgeInput{ID: param.Id})
+	if err != nil {
+		log.SugaredLogger.Errorf("PurgeExample failed: %v", err.Error())
+		response.ToErrorResponse(exampleAPIError(ctx, err, "purge example"))
+		return
+	}
+
 	response.ToResponse(gin.H{})
 }
 
@@ -125,6 +171,15 @@ func UpdateExample(ctx *gin.Context) {
 		return
 	}
 
+	// The version the client read may also come as an If-Match precondition
+	if version, ok, err := ifMatchVersion(ctx); err != nil {
+		log.SugaredLogger.Errorf("UpdateExample.ifMatchVersion errs: %v", err)
+		response.ToErrorResponse(error_code.InvalidParams.WithDetails(err.Error()))
+		return
+	} else if ok {
+		body.Version = version
+	}
+
 	// If converter exists, convert DTO to domain model first
 	var err error
 
@@ -137,18 +192,24 @@ func UpdateExample(ctx *gin.Context) {
 		}
 
 		// Use converted model to execute update
-		err = services.ExampleService.Update(ctx, example.Id, example.Name, example.Alias)
+		err = services.ExampleService.Update(ctx, example.Id, example.Name, example.Alias, example.Version)
 		if err != nil {
 			log.SugaredLogger.Errorf("UpdateExample failed: %v", err.Error())
-			response.ToErrorResponse(error_code.ServerError)
+			response.ToErrorResponse(exampleAPIError(ctx, err, "update example"))
 			return
 		}
 	} else {
 		// Fall back to original implementation
-		_, err = appFactory.UpdateExampleUseCase().Execute(ctx, body)
+		input := &example.UpdateInput{
+			ID:      int(body.Id),
+			Name:    body.Name,
+			Alias:   body.Alias,
+			Version: body.Version,
+		}
+		_, err = appFactory.UpdateExampleUseCase().Execute(ctx, input)
 		if err != nil {
 			log.SugaredLogger.Errorf("UpdateExample failed.%v", err.Error())
-			response.ToErrorResponse(error_code.ServerError)
+			response.ToErrorResponse(exampleAPIError(ctx, err, "update example"))
 			return
 		}
 	}
@@ -167,17 +228,18 @@ func GetExample(ctx *gin.Context) {
 		response.ToErrorResponse(errResp)
 		return
 	}
+	param.IncludeDeleted = cast.ToBool(ctx.Query("include_deleted"))
 
 	// Execute use case
 	var result any
 	var err error
 
-	if converter != nil && services != nil && services.ExampleService != nil {
+	if converter != nil && services != nil && services.ExampleService != nil && !param.IncludeDeleted {
 		// Direct service call
 		example, err := services.ExampleService.Get(ctx, param.Id)
 		if err != nil {
 			log.SugaredLogger.Errorf("GetExample failed: %v", err.Error())
-			response.ToErrorResponse(error_code.ServerError)
+			response.ToErrorResponse(exampleAPIError(ctx, err, "get example"))
 			return
 		}
 
@@ -190,10 +252,13 @@ func GetExample(ctx *gin.Context) {
 		}
 	} else {
 		// Fall back to original implementation
-		result, err = appFactory.GetExampleUseCase().Execute(ctx, param.Id)
+		result, err = appFactory.GetExampleUseCase().Execute(ctx, &example.GetInput{
+			ID:             param.Id,
+			IncludeDeleted: param.IncludeDeleted,
+		})
 		if err != nil {
 			log.SugaredLogger.Errorf("GetExample failed.%v", err.Error())
-			response.ToErrorResponse(error_code.ServerError)
+			response.ToErrorResponse(exampleAPIError(ctx, err, "get example"))
 			return
 		}
 	}
@@ -253,3 +318,161 @@ func FindExampleByName(ctx *gin.Context) {
 
 	response.ToResponse(result)
 }
+
+func ListExamples(ctx *gin.Context) {
+	response := handle.NewResponse(ctx)
+	param := dto.ListExampleReq{}
+
+	valid, errs := validator.BindAndValid(ctx, &param, ctx.ShouldBindQuery)
+	if !valid {
+		log.SugaredLogger.Errorf("ListExamples.BindAndValid errs: %v", errs)
+		errResp := error_code.InvalidParams.WithDetails(errs.Errors()...)
+		response.ToErrorResponse(errResp)
+		return
+	}
+
+	// A cursor implies keyset pagination; offset pagination remains the default
+	if param.Cursor != "" || param.Pagination == "cursor" {
+		if !paginate.CursorPaginationEnabled() {
+			response.ToErrorResponse(error_code.InvalidParams.WithDetails("cursor pagination is disabled"))
+			return
+		}
+		// Cursors follow the creation order, only its direction can be chosen
+		if param.SortBy != "" {
+			response.ToErrorResponse(error_code.InvalidParams.WithDetails("sort_by is not supported with cursor pagination, use sort_order"))
+			return
+		}
+		listExamplesByCursor(ctx, response, param)
+		return
+	}
+
+	input := &example.ListInput{
+		Name:           param.Name,
+		Alias:          param.Alias,
+		SortBy:         param.SortBy,
+		SortOrder:      param.SortOrder,
+		Page:           paginate.GetPage(ctx),
+		PageSize:       paginate.GetPageSize(ctx),
+		IncludeDeleted: param.IncludeDeleted,
+	}
+	if !param.CreatedAfter.IsZero() {
+		input.CreatedAfter = &param.CreatedAfter
+	}
+	if !param.CreatedBefore.IsZero() {
+		input.CreatedBefore = &param.CreatedBefore
+	}
+
+	// Execute use case
+	result, err := appFactory.ListExamplesUseCase().Execute(ctx, input)
+	if err != nil {
+		log.SugaredLogger.Errorf("ListExamples failed: %v", err.Error())
+		response.ToErrorResponse(error_handler.HandleAPIError(ctx, err, "list examples"))
+		return
+	}
+
+	output := result.(*example.ListOutput)
+	response.ToResponseList(output.Items, int(output.Total))
+}
+
+// listExamplesByCursor serves ListExamples with keyset pagination using opaque cursors
+func listExamplesByCursor(ctx *gin.Context, response *handle.Response, param dto.ListExampleReq) {
+	input := &example.ListByCursorInput{
+		Name:           param.Name,
+		Alias:          param.Alias,
+		SortOrder:      param.SortOrder,
+		PageSize:       paginate.GetPageSize(ctx),
+		IncludeDeleted: param.IncludeDeleted,
+	}
+	if !param.CreatedAfter.IsZero() {
+		input.CreatedAfter = &param.CreatedAfter
+	}
+	if !param.CreatedBefore.IsZero() {
+		input.CreatedBefore = &param.CreatedBefore
+	}
+
+	if param.Cursor != "" {
+		cursor, err := paginate.DecodeCursor(param.Cursor)
+		if err != nil {
+			log.SugaredLogger.Errorf("ListExamples.DecodeCursor err: %v", err)
+			response.ToErrorResponse(error_code.InvalidParams.WithDetails("cursor is invalid"))
+			return
+		}
+		// The cursor is bound to the order it was issued for
+		input.Cursor = &repo.ExampleCursor{ID: cursor.ID, CreatedAt: cursor.CreatedAt}
+		input.Backward = cursor.Backward
+		input.SortOrder = example.SortOrderAsc
+		if cursor.Desc {
+			input.SortOrder = example.SortOrderDesc
+		}
+	}
+
+	// Execute use case
+	result, err := appFactory.ListExamplesByCursorUseCase().Execute(ctx, input)
+	if err != nil {
+		log.SugaredLogger.Errorf("ListExamples failed: %v", err.Error())
+		response.ToErrorResponse(error_handler.HandleAPIError(ctx, err, "list examples"))
+		return
+	}
+
+	output := result.(*example.CursorListOutput)
+	desc := input.SortOrder == example.SortOrderDesc
+
+	nextCursor, err := encodeExampleCursor(output.NextCursor, false, desc)
+	if err != nil {
+		log.SugaredLogger.Errorf("ListExamples.EncodeCursor err: %v", err)
+		response.ToErrorResponse(error_code.ServerError)
+		return
+	}
+	prevCursor, err := encodeExampleCursor(output.PrevCursor, true, desc)
+	if err != nil {
+		log.SugaredLogger.Errorf("ListExamples.EncodeCursor err: %v", err)
+		response.ToErrorResponse(error_code.ServerError)
+		return
+	}
+
+	response.ToResponseCursorList(output.Items, nextCursor, prevCursor)
+}
+
+// encodeExampleCursor turns a repository position into an opaque token, or "" when there is none
+func encodeExampleCursor(position *repo.ExampleCursor, backward, desc bool) (string, error) {
+	if position == nil {
+		return "", nil
+	}
+	return paginate.EncodeCursor(paginate.Cursor{
+		ID:        position.ID,
+		CreatedAt: position.CreatedAt,
+		Backward:  backward,
+		Desc:      desc,
+	})
+}
+
+// exampleAPIError maps a use case error to an API error, reporting missing examples as not found
+func exampleAPIError(ctx *gin.Context, err error, operation string) *error_code.Error {
+	if errors.Is(err, repo.ErrNotFound) {
+		return error_code.NotFound
+	}
+	if model.IsExampleNameTakenError(err) {
+		return error_code.ExampleNameExist
+	}
+	if model.IsExampleModifiedError(err) {
+		return error_code.Conflict.WithMessage("Conflict: %s", err.Error())
+	}
+	return error_handler.HandleAPIError(ctx, err, operation)
+}
+
+// ifMatchVersion returns the example version of the request's If-Match header, such as "3" or W/"3".
+// ok is false without the header.
+func ifMatchVersion(ctx *gin.Context) (version int, ok bool, err error) {
+	header := ctx.GetHeader("If-Match")
+	if header == "" {
+		return 0, false, nil
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(strings.TrimSpace(header), "W/"), `"`)
+	version, err = strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, false, fmt.Errorf("invalid If-Match header %s, want a positive example version", header)
+	}
+
+	return version, true, nil
+}
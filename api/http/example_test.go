@@ -8,12 +8,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"go-hexagonal/api/dto"
+	"go-hexagonal/api/error_code"
 	"go-hexagonal/application"
+	"go-hexagonal/domain/event"
 	"go-hexagonal/domain/model"
 	"go-hexagonal/domain/repo"
 	"go-hexagonal/domain/service"
@@ -63,6 +67,51 @@ func (m *MockExampleRepo) FindByName(ctx context.Context, tr repo.Transaction, n
 	return args.Get(0).(*model.Example), args.Error(1)
 }
 
+// List mocks the List method
+func (m *MockExampleRepo) List(ctx context.Context, tr repo.Transaction, query repo.ExampleListQuery) ([]*model.Example, error) {
+	args := m.Called(ctx, tr, query)
+	if e, ok := args.Get(0).([]*model.Example); ok {
+		return e, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// Count mocks the Count method
+func (m *MockExampleRepo) Count(ctx context.Context, tr repo.Transaction, filter repo.ExampleFilter) (int64, error) {
+	args := m.Called(ctx, tr, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Restore mocks the Restore method
+func (m *MockExampleRepo) Restore(ctx context.Context, tr repo.Transaction, id int) error {
+	args := m.Called(ctx, tr, id)
+	return args.Error(0)
+}
+
+// Purge mocks the Purge method
+func (m *MockExampleRepo) Purge(ctx context.Context, tr repo.Transaction, id int) error {
+	args := m.Called(ctx, tr, id)
+	return args.Error(0)
+}
+
+// GetByIDWithDeleted mocks the GetByIDWithDeleted method
+func (m *MockExampleRepo) GetByIDWithDeleted(ctx context.Context, tr repo.Transaction, id int) (*model.Example, error) {
+	args := m.Called(ctx, tr, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Example), args.Error(1)
+}
+
+// ListByCursor mocks the ListByCursor method
+func (m *MockExampleRepo) ListByCursor(ctx context.Context, tr repo.Transaction, query repo.ExampleKeysetQuery) ([]*model.Example, error) {
+	args := m.Called(ctx, tr, query)
+	if e, ok := args.Get(0).([]*model.Example); ok {
+		return e, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 // MockConverter mocks the Converter interface
 type MockConverter struct {
 	mock.Mock
@@ -114,6 +163,22 @@ func (m *MockTransaction) Conn(ctx context.Context) any {
 	return args.Get(0)
 }
 
+func (m *MockTransaction) WithContext(ctx context.Context) repo.Transaction {
+	return m
+}
+
+func (m *MockTransaction) Context() context.Context {
+	return context.Background()
+}
+
+func (m *MockTransaction) StoreType() repo.StoreType {
+	return repo.MySQLStore
+}
+
+func (m *MockTransaction) Options() *repo.TransactionOptions {
+	return nil
+}
+
 // MockTransactionFactory mocks the TransactionFactory interface
 type MockTransactionFactory struct {
 	mock.Mock
@@ -164,7 +229,7 @@ func setupTest(t *testing.T) (*gin.Engine, *MockExampleRepo, *service.ExampleSer
 	RegisterServices(testServices)
 
 	// Initialize application factory - no longer needs converter
-	testAppFactory := application.NewFactory(testService, mockTxFactory)
+	testAppFactory := application.NewFactory(testService, mockTxFactory, repo.MySQLStore)
 	SetAppFactory(testAppFactory)
 
 	// Set up Gin
@@ -198,6 +263,7 @@ func TestCreateExample(t *testing.T) {
 	}
 
 	// Set up mock behavior for direct service call
+	mockRepo.On("FindByName", mock.Anything, mock.Anything, "Test Example").Return(nil, repo.ErrNotFound)
 	mockRepo.On("Create", mock.Anything, mock.Anything, mock.AnythingOfType("*model.Example")).Return(expectedExample, nil)
 	// Set up converter expectations
 	mockConverter.On("FromCreateRequest", mock.AnythingOfType("*dto.CreateExampleReq")).Return(expectedExample, nil)
@@ -222,6 +288,38 @@ func TestCreateExample(t *testing.T) {
 	mockConverter.AssertExpectations(t)
 }
 
+func TestCreateExample_NameTaken(t *testing.T) {
+	router, mockRepo, _, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	// Go through the application use case as the server does
+	originalConverter := converter
+	RegisterConverter(nil)
+	defer RegisterConverter(originalConverter)
+
+	router.POST("/api/examples", CreateExample)
+
+	// A live example already uses the name with different casing
+	mockRepo.On("FindByName", mock.Anything, mock.Anything, "Test Example").Return(&model.Example{Id: 2, Name: "TEST EXAMPLE"}, nil)
+
+	// Create request
+	jsonData, _ := json.Marshal(map[string]any{"name": "Test Example", "alias": "test"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/examples", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute request
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	// Check results
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, float64(error_code.ExampleNameExistErrorCode), body["code"])
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestGetExample(t *testing.T) {
 	router, mockRepo, _, mockConverter, cleanup := setupTest(t)
 	defer cleanup()
@@ -297,6 +395,82 @@ func TestUpdateExample(t *testing.T) {
 	mockConverter.AssertExpectations(t)
 }
 
+func TestUpdateExample_Conflict(t *testing.T) {
+	router, mockRepo, _, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	// Go through the application use case as the server does
+	originalConverter := converter
+	RegisterConverter(nil)
+	defer RegisterConverter(originalConverter)
+
+	router.PUT("/api/examples/:id", UpdateExample)
+
+	// Another writer bumped the version between read and write
+	mockRepo.On("GetByID", mock.Anything, mock.Anything, 1).Return(&model.Example{Id: 1, Name: "Example", Version: 2}, nil)
+	mockRepo.On("FindByName", mock.Anything, mock.Anything, "Updated Example").Return(nil, repo.ErrNotFound)
+	mockRepo.On("Update", mock.Anything, mock.Anything, mock.AnythingOfType("*model.Example")).Return(model.ErrExampleModified)
+
+	// Create request
+	jsonData, _ := json.Marshal(map[string]any{"name": "Updated Example", "alias": "updated"})
+	req, _ := http.NewRequest(http.MethodPut, "/api/examples/1", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute request
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	// Check results, a concurrent modification has its own code apart from other conflicts
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, float64(error_code.ConflictCode), body["code"])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateExample_ExpectedVersion(t *testing.T) {
+	router, mockRepo, _, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	originalConverter := converter
+	RegisterConverter(nil)
+	defer RegisterConverter(originalConverter)
+
+	router.PUT("/api/examples/:id", UpdateExample)
+
+	// The client read version 1, the stored example is at version 2
+	mockRepo.On("GetByID", mock.Anything, mock.Anything, 1).Return(&model.Example{Id: 1, Name: "Example", Version: 2}, nil)
+
+	for _, tc := range []struct {
+		name    string
+		body    map[string]any
+		ifMatch string
+		code    int
+	}{
+		{name: "If-Match header", body: map[string]any{"name": "Updated Example"}, ifMatch: `"1"`, code: http.StatusConflict},
+		{name: "weak If-Match header", body: map[string]any{"name": "Updated Example"}, ifMatch: `W/"1"`, code: http.StatusConflict},
+		{name: "body version", body: map[string]any{"name": "Updated Example", "version": 1}, code: http.StatusConflict},
+		{name: "invalid If-Match header", body: map[string]any{"name": "Updated Example"}, ifMatch: "*", code: http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			jsonData, _ := json.Marshal(tc.body)
+			req, _ := http.NewRequest(http.MethodPut, "/api/examples/1", bytes.NewBuffer(jsonData))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tc.code, recorder.Code)
+		})
+	}
+
+	// A stale version is rejected before the repository is written
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestDeleteExample(t *testing.T) {
 	router, mockRepo, _, _, cleanup := setupTest(t)
 	defer cleanup()
@@ -326,6 +500,166 @@ func TestDeleteExample(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+// stagingTransaction keeps writes pending until Commit, like a database transaction
+type stagingTransaction struct {
+	MockTransaction
+	pending   []func()
+	commitErr error
+}
+
+func (tx *stagingTransaction) Begin() error {
+	return nil
+}
+
+func (tx *stagingTransaction) Commit() error {
+	if tx.commitErr != nil {
+		return tx.commitErr
+	}
+	for _, apply := range tx.pending {
+		apply()
+	}
+	tx.pending = nil
+	return nil
+}
+
+func (tx *stagingTransaction) Rollback() error {
+	tx.pending = nil
+	return nil
+}
+
+// stage applies a write when its transaction commits, writes outside a staging transaction autocommit
+func stage(tr repo.Transaction, apply func()) {
+	if tx, ok := tr.(*stagingTransaction); ok {
+		tx.pending = append(tx.pending, apply)
+		return
+	}
+	apply()
+}
+
+// stagingOutbox records saved messages once their transaction commits
+type stagingOutbox struct {
+	repo.IOutboxRepo
+	saved []*repo.OutboxMessage
+}
+
+func (o *stagingOutbox) Save(ctx context.Context, tr repo.Transaction, messages ...*repo.OutboxMessage) error {
+	stage(tr, func() { o.saved = append(o.saved, messages...) })
+	return nil
+}
+
+func TestDeleteExample_OutboxSharesTransaction(t *testing.T) {
+	router, mockRepo, testService, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	outbox := &stagingOutbox{}
+	testService.Outbox = outbox
+
+	failing := &stagingTransaction{commitErr: fmt.Errorf("connection lost")}
+	txFactory := new(MockTransactionFactory)
+	txFactory.On("NewTransaction", mock.Anything, mock.Anything, mock.Anything).Return(failing, nil).Once()
+	txFactory.On("NewTransaction", mock.Anything, mock.Anything, mock.Anything).Return(&stagingTransaction{}, nil).Once()
+	SetAppFactory(application.NewFactory(testService, txFactory, repo.MySQLStore))
+
+	router.DELETE("/api/examples/:id", DeleteExample)
+
+	// The soft delete is staged in the same transaction as its outbox record
+	deleted := false
+	mockRepo.On("GetByID", mock.Anything, mock.Anything, 1).Return(&model.Example{Id: 1, Name: "Test Example"}, nil)
+	mockRepo.On("Delete", mock.Anything, mock.Anything, 1).
+		Run(func(args mock.Arguments) {
+			stage(args.Get(1).(repo.Transaction), func() { deleted = true })
+		}).
+		Return(nil)
+
+	// A failed commit leaves neither the delete nor the outbox record behind
+	req, _ := http.NewRequest(http.MethodDelete, "/api/examples/1", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.False(t, deleted)
+	assert.Empty(t, outbox.saved)
+
+	// A successful commit applies both
+	req, _ = http.NewRequest(http.MethodDelete, "/api/examples/1", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, deleted)
+	if assert.Len(t, outbox.saved, 1) {
+		assert.Equal(t, event.ExampleDeletedEventName, outbox.saved[0].EventName)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRestoreExample(t *testing.T) {
+	router, mockRepo, _, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	router.POST("/api/examples/:id/restore", RestoreExample)
+
+	// Prepare test data
+	deletedAt := time.Now()
+	deleted := &model.Example{Id: 1, Name: "Test Example", Alias: "test", DeletedAt: &deletedAt}
+	restored := &model.Example{Id: 1, Name: "Test Example", Alias: "test", Version: 2}
+
+	// Set up mock behavior
+	mockRepo.On("GetByIDWithDeleted", mock.Anything, mock.Anything, 1).Return(deleted, nil)
+	mockRepo.On("FindByName", mock.Anything, mock.Anything, "Test Example").Return(nil, repo.ErrNotFound)
+	mockRepo.On("Restore", mock.Anything, mock.Anything, 1).Return(nil)
+	mockRepo.On("GetByID", mock.Anything, mock.Anything, 1).Return(restored, nil)
+
+	// Create request
+	req, _ := http.NewRequest(http.MethodPost, "/api/examples/1/restore", nil)
+
+	// Execute request
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	// Verify results
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"version":2`)
+
+	// Restoring a live example conflicts
+	mockRepo.On("GetByIDWithDeleted", mock.Anything, mock.Anything, 2).Return(&model.Example{Id: 2, Name: "Live"}, nil)
+
+	req, _ = http.NewRequest(http.MethodPost, "/api/examples/2/restore", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, float64(error_code.ConflictCode), body["code"])
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPurgeExample(t *testing.T) {
+	router, mockRepo, _, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	router.DELETE("/api/examples/:id/purge", PurgeExample)
+
+	// Set up mock behavior
+	mockRepo.On("GetByIDWithDeleted", mock.Anything, mock.Anything, 1).Return(&model.Example{Id: 1, Name: "Test Example"}, nil)
+	mockRepo.On("Purge", mock.Anything, mock.Anything, 1).Return(nil)
+	mockRepo.On("GetByIDWithDeleted", mock.Anything, mock.Anything, 2).Return(nil, repo.ErrNotFound)
+
+	// Purge an existing example
+	req, _ := http.NewRequest(http.MethodDelete, "/api/examples/1/purge", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	// Purge a missing example
+	req, _ = http.NewRequest(http.MethodDelete, "/api/examples/2/purge", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	mockRepo.AssertExpectations(t)
+}
+
 func TestFindExampleByName(t *testing.T) {
 	router, mockRepo, _, mockConverter, cleanup := setupTest(t)
 	defer cleanup()
@@ -374,3 +708,97 @@ func TestFindExampleByName(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 	mockConverter.AssertExpectations(t)
 }
+
+func TestListExamples(t *testing.T) {
+	router, mockRepo, _, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	// Register handler
+	router.GET("/api/examples", ListExamples)
+
+	// Prepare test data
+	examples := []*model.Example{
+		{Id: 1, Name: "Test Example", Alias: "test"},
+	}
+
+	// Set up mock behavior
+	mockRepo.On("Count", mock.Anything, mock.Anything, repo.ExampleFilter{Name: "Test"}).Return(int64(1), nil)
+	mockRepo.On("List", mock.Anything, mock.Anything, mock.AnythingOfType("repo.ExampleListQuery")).Return(examples, nil)
+
+	// Create request
+	req, _ := http.NewRequest(http.MethodGet, "/api/examples?name=Test&sort_by=name&sort_order=desc&page=1&page_size=5", nil)
+
+	// Execute request
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	// Check results
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"total_rows":1`)
+
+	// Invalid sort field is rejected
+	req, _ = http.NewRequest(http.MethodGet, "/api/examples?sort_by=password", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListExamples_Cursor(t *testing.T) {
+	router, mockRepo, _, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	// Register handler
+	router.GET("/api/examples", ListExamples)
+
+	// Prepare test data
+	now := time.Now()
+	examples := []*model.Example{
+		{Id: 1, Name: "Test Example", Alias: "test", CreatedAt: now},
+		{Id: 2, Name: "Test Example", Alias: "test", CreatedAt: now},
+		{Id: 3, Name: "Test Example", Alias: "test", CreatedAt: now},
+	}
+
+	// First page: one extra row signals a following page
+	mockRepo.On("ListByCursor", mock.Anything, mock.Anything, repo.ExampleKeysetQuery{SortDesc: true, Limit: 3}).Return(examples, nil).Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/examples?pagination=cursor&sort_order=desc&page_size=2", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var body struct {
+		Data struct {
+			Pager dto.CursorPager `json:"pager"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.NotEmpty(t, body.Data.Pager.NextCursor)
+	assert.Empty(t, body.Data.Pager.PrevCursor)
+
+	// Following the next cursor keeps the order it was issued for
+	mockRepo.On("ListByCursor", mock.Anything, mock.Anything, mock.MatchedBy(func(q repo.ExampleKeysetQuery) bool {
+		return q.Cursor != nil && q.Cursor.ID == 2 && q.SortDesc && !q.Backward
+	})).Return(examples[2:], nil).Once()
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/examples?page_size=2&cursor="+body.Data.Pager.NextCursor, nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	// A tampered cursor is rejected
+	req, _ = http.NewRequest(http.MethodGet, "/api/examples?cursor=bogus.token", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// Cursors follow the creation order, choosing another sort field is rejected
+	req, _ = http.NewRequest(http.MethodGet, "/api/examples?pagination=cursor&sort_by=name", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "sort_by is not supported with cursor pagination")
+
+	mockRepo.AssertExpectations(t)
+}
@@ -55,6 +55,21 @@ func (r *Response) ToResponseList(list interface{}, totalRows int) {
 	})
 }
 
+func (r *Response) ToResponseCursorList(list interface{}, nextCursor, prevCursor string) {
+	r.Ctx.JSON(http.StatusOK, StandardResponse{
+		Code:    0,
+		Message: "success",
+		Data: gin.H{
+			"list": list,
+			"pager": dto.CursorPager{
+				PageSize:   paginate.GetPageSize(r.Ctx),
+				NextCursor: nextCursor,
+				PrevCursor: prevCursor,
+			},
+		},
+	})
+}
+
 func (r *Response) ToErrorResponse(err *error_code.Error) {
 	response := StandardResponse{
 		Code:    err.Code,
@@ -118,7 +133,7 @@ func Error(c *gin.Context, err error) {
 			errorCode = error_code.UnauthorizedTokenErrorCode
 		case "conflict", "already_exists":
 			statusCode = http.StatusConflict
-			errorCode = error_code.AccountExistErrorCode
+			errorCode = error_code.ConflictCode
 		}
 
 		response := StandardResponse{
@@ -33,6 +33,17 @@ func TestMain(m *testing.M) {
 		return
 	}
 
+	// The memory store needs no infrastructure, e.g. APP_PERSISTENCE_DRIVER=memory go test ./api/http/...
+	if config.GlobalConfig.UsesMemoryStore() {
+		clients, err := dependency.InitializeRepositories(dependency.WithMemory())
+		if err != nil {
+			log.SugaredLogger.Fatalf("Failed to initialize repositories: %v", err)
+		}
+		repository.Clients = clients
+		os.Exit(runWithServices(m))
+		return
+	}
+
 	// Use test containers
 	t := &testing.T{}
 	mysqlConfig := mysql.SetupMySQLContainer(t)
@@ -53,6 +64,11 @@ func TestMain(m *testing.M) {
 	repository.Clients = clients
 	_ = repository.Clients.MySQL.GetDB(ctx).AutoMigrate(&entity.Example{})
 
+	os.Exit(runWithServices(m))
+}
+
+// runWithServices registers the services over the initialized repositories and runs the tests
+func runWithServices(m *testing.M) int {
 	// Initialize services using dependency injection
 	svcs, err := dependency.InitializeServices(ctx, dependency.WithExampleService())
 	if err != nil {
@@ -63,5 +79,5 @@ func TestMain(m *testing.M) {
 	RegisterServices(svcs)
 
 	// Run tests
-	os.Exit(m.Run())
+	return m.Run()
 }
@@ -121,6 +121,8 @@ func handleEnhancedError(c *gin.Context, err error) {
 		handle.Error(c, error_code.InvalidParams.WithMessage("Validation error: %s", err.Error()))
 	case errors.IsNotFoundError(err):
 		handle.Error(c, error_code.NotFound.WithMessage("Resource not found: %s", err.Error()))
+	case errors.IsConflictError(err):
+		handle.Error(c, error_code.Conflict.WithMessage("Conflict: %s", err.Error()))
 	case errors.IsPersistenceError(err):
 		handle.Error(c, error_code.ServerError.WithMessage("Database operation failed"))
 	case errors.IsSystemError(err):
@@ -147,7 +149,7 @@ func handleAppError(c *gin.Context, appErr *errors.AppError) {
 	case errors.ErrorTypeForbidden:
 		apiErr = error_code.UnauthorizedTokenError.WithMessage("Access forbidden")
 	case errors.ErrorTypeConflict:
-		apiErr = error_code.AccountExist.WithMessage("Conflict: %s", appErr.Message)
+		apiErr = error_code.Conflict.WithMessage("Conflict: %s", appErr.Message)
 	case errors.ErrorTypePersistence, errors.ErrorTypeSystem:
 		apiErr = error_code.ServerError.WithMessage("Server error: %s", appErr.Message)
 	case errors.ErrorTypeBusiness:
@@ -180,7 +182,7 @@ func handleHTTPStatusError(c *gin.Context) {
 	case http.StatusTooManyRequests:
 		handle.Error(c, error_code.TooManyRequests)
 	case http.StatusConflict:
-		handle.Error(c, error_code.AccountExist.WithMessage("Resource conflict"))
+		handle.Error(c, error_code.Conflict)
 	default:
 		handle.Error(c, error_code.ServerError)
 	}
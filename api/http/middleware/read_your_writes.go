@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"go-hexagonal/domain/repo"
+)
+
+// ReadYourWrites is a middleware that starts a read session for each request. Stores routing
+// reads to replicas use it to serve the reads following a write of the request from the primary.
+func ReadYourWrites() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(repo.ContextWithReadSession(c.Request.Context(), repo.NewReadSession()))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/repo"
+)
+
+func TestReadYourWrites(t *testing.T) {
+	var sessions []*repo.ReadSession
+	engine := gin.New()
+	engine.ContextWithFallback = true
+	engine.Use(ReadYourWrites())
+	engine.GET("/test", func(c *gin.Context) {
+		session, ok := repo.ReadSessionFromContext(c)
+		require.True(t, ok)
+		assert.False(t, session.WroteWithin(time.Minute, time.Now()))
+		session.MarkWrite(time.Now())
+		sessions = append(sessions, session)
+	})
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		engine.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	// Every request gets its own session, a write of one does not pin the reads of the next
+	require.Len(t, sessions, 2)
+	assert.NotSame(t, sessions[0], sessions[1])
+}
@@ -3,25 +3,47 @@ package middleware
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+
+	"go-hexagonal/domain/event"
 )
 
 const (
 	// RequestIDHeader is the header key for request ID
 	RequestIDHeader = "X-Request-ID"
+	// CorrelationIDHeader is the header key for the correlation ID shared by every request of a flow
+	CorrelationIDHeader = "X-Correlation-ID"
+
+	// maxTraceIDLength bounds caller supplied IDs to what the event tables store
+	maxTraceIDLength = 128
 )
 
-// RequestID is a middleware that injects a request ID into the context of each request
+// RequestID is a middleware that injects a request ID into the context of each request.
+// The request ID and correlation ID are also added to the request context, so events
+// raised while serving the request can be traced back to it.
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get request ID from header or generate a new one
 		requestID := c.GetHeader(RequestIDHeader)
-		if requestID == "" {
+		if requestID == "" || len(requestID) > maxTraceIDLength {
 			requestID = uuid.New().String()
 		}
 
+		// Continue the caller's flow if it sent one, otherwise the request starts a new one
+		correlationID := c.GetHeader(CorrelationIDHeader)
+		if correlationID == "" || len(correlationID) > maxTraceIDLength {
+			correlationID = requestID
+		}
+
 		// Set request ID to header
 		c.Writer.Header().Set(RequestIDHeader, requestID)
+		c.Writer.Header().Set(CorrelationIDHeader, correlationID)
 		c.Set(RequestIDHeader, requestID)
+		c.Set(CorrelationIDHeader, correlationID)
+
+		c.Request = c.Request.WithContext(event.ContextWithMetadata(c.Request.Context(), event.Metadata{
+			CorrelationID: correlationID,
+			CausationID:   requestID,
+		}))
 
 		c.Next()
 	}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"go-hexagonal/domain/event"
+)
+
+func TestRequestID(t *testing.T) {
+	newEngine := func(seen *event.Metadata) *gin.Engine {
+		engine := gin.New()
+		engine.ContextWithFallback = true
+		engine.Use(RequestID())
+		engine.GET("/test", func(c *gin.Context) {
+			*seen = event.MetadataFromContext(c)
+		})
+		return engine
+	}
+
+	t.Run("New correlation", func(t *testing.T) {
+		var seen event.Metadata
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		newEngine(&seen).ServeHTTP(w, req)
+
+		requestID := w.Header().Get(RequestIDHeader)
+		assert.NotEmpty(t, requestID)
+		assert.Equal(t, requestID, w.Header().Get(CorrelationIDHeader))
+		assert.Equal(t, event.Metadata{CorrelationID: requestID, CausationID: requestID}, seen)
+	})
+
+	t.Run("Caller correlation", func(t *testing.T) {
+		var seen event.Metadata
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(RequestIDHeader, "request-1")
+		req.Header.Set(CorrelationIDHeader, "flow-1")
+		newEngine(&seen).ServeHTTP(w, req)
+
+		assert.Equal(t, "request-1", w.Header().Get(RequestIDHeader))
+		assert.Equal(t, "flow-1", w.Header().Get(CorrelationIDHeader))
+		assert.Equal(t, event.Metadata{CorrelationID: "flow-1", CausationID: "request-1"}, seen)
+	})
+}
@@ -0,0 +1,121 @@
+package paginate
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-hexagonal/config"
+)
+
+var (
+	// ErrInvalidCursor is returned when a cursor is malformed or its signature does not match
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrCursorPaginationDisabled is returned when cursors are used while cursor pagination is disabled
+	ErrCursorPaginationDisabled = errors.New("cursor pagination is disabled")
+	// ErrCursorSecretRequired is returned when cursor pagination is enabled without a signing key
+	ErrCursorSecretRequired = errors.New("http_server.cursor_secret is required when cursor pagination is enabled")
+)
+
+// Cursor is the position carried by an opaque pagination token
+type Cursor struct {
+	ID        int       `json:"i"`
+	CreatedAt time.Time `json:"t"`
+	// Backward marks a cursor that reads the page preceding the position
+	Backward bool `json:"b,omitempty"`
+	// Desc records the sort order the cursor was issued for
+	Desc bool `json:"d,omitempty"`
+}
+
+// GetCursor returns the raw cursor token from the query string
+func GetCursor(c *gin.Context) string {
+	return c.Query("cursor")
+}
+
+// CheckConfig fails when cursor pagination is enabled without a signing key. Every instance must
+// sign with the same configured key, so cursors survive restarts and work across instances.
+func CheckConfig(cfg *config.HttpServerConfig) error {
+	if cfg != nil && cfg.CursorPagination && cfg.CursorSecret == "" {
+		return ErrCursorSecretRequired
+	}
+	return nil
+}
+
+// CursorPaginationEnabled reports whether cursor pagination is enabled with a signing key
+func CursorPaginationEnabled() bool {
+	_, err := cursorSecret()
+	return err == nil
+}
+
+// EncodeCursor serializes and signs a cursor into an opaque URL-safe token
+func EncodeCursor(cursor Cursor) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := sign(encoded)
+	if err != nil {
+		return "", err
+	}
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// DecodeCursor verifies the signature of a token and returns the cursor it carries
+func DecodeCursor(token string) (Cursor, error) {
+	var cursor Cursor
+
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return cursor, ErrInvalidCursor
+	}
+
+	expected, err := sign(encoded)
+	if err != nil {
+		return cursor, err
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, expected) {
+		return cursor, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return cursor, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// sign computes the HMAC of the encoded payload
+func sign(encoded string) ([]byte, error) {
+	secret, err := cursorSecret()
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil), nil
+}
+
+// cursorSecret returns the configured signing key, failing when cursor pagination is disabled
+func cursorSecret() ([]byte, error) {
+	if config.GlobalConfig == nil || config.GlobalConfig.HTTPServer == nil {
+		return nil, ErrCursorPaginationDisabled
+	}
+	cfg := config.GlobalConfig.HTTPServer
+	if !cfg.CursorPagination || cfg.CursorSecret == "" {
+		return nil, ErrCursorPaginationDisabled
+	}
+	return []byte(cfg.CursorSecret), nil
+}
@@ -0,0 +1,86 @@
+package paginate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/config"
+)
+
+// setHTTPServerConfig installs an HTTP server configuration for the duration of the test
+func setHTTPServerConfig(t *testing.T, cfg *config.HttpServerConfig) {
+	t.Helper()
+	previous := config.GlobalConfig
+	config.GlobalConfig = &config.Config{HTTPServer: cfg}
+	t.Cleanup(func() { config.GlobalConfig = previous })
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	setHTTPServerConfig(t, &config.HttpServerConfig{CursorPagination: true, CursorSecret: "secret"})
+	cursor := Cursor{ID: 7, CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), Backward: true, Desc: true}
+
+	token, err := EncodeCursor(cursor)
+	require.NoError(t, err)
+
+	decoded, err := DecodeCursor(token)
+	require.NoError(t, err)
+	assert.Equal(t, cursor.ID, decoded.ID)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.True(t, decoded.Backward)
+	assert.True(t, decoded.Desc)
+}
+
+func TestDecodeCursor_Rejected(t *testing.T) {
+	setHTTPServerConfig(t, &config.HttpServerConfig{CursorPagination: true, CursorSecret: "secret"})
+	token, err := EncodeCursor(Cursor{ID: 7, CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	forged, err := EncodeCursor(Cursor{ID: 8, CreatedAt: time.Now()})
+	require.NoError(t, err)
+	payload, _, _ := strings.Cut(forged, ".")
+	_, signature, _ := strings.Cut(token, ".")
+
+	for _, tampered := range []string{"", "garbage", token + "x", payload + "." + signature} {
+		_, err := DecodeCursor(tampered)
+		assert.ErrorIs(t, err, ErrInvalidCursor, tampered)
+	}
+}
+
+func TestDecodeCursor_OtherSecret(t *testing.T) {
+	setHTTPServerConfig(t, &config.HttpServerConfig{CursorPagination: true, CursorSecret: "secret"})
+	token, err := EncodeCursor(Cursor{ID: 7, CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	// Instances sharing the secret accept each other's cursors, others reject them
+	setHTTPServerConfig(t, &config.HttpServerConfig{CursorPagination: true, CursorSecret: "secret"})
+	_, err = DecodeCursor(token)
+	assert.NoError(t, err)
+	setHTTPServerConfig(t, &config.HttpServerConfig{CursorPagination: true, CursorSecret: "other"})
+	_, err = DecodeCursor(token)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestCursorPaginationDisabled(t *testing.T) {
+	for _, cfg := range []*config.HttpServerConfig{
+		{CursorSecret: "secret"},
+		{CursorPagination: true},
+	} {
+		setHTTPServerConfig(t, cfg)
+		assert.False(t, CursorPaginationEnabled())
+		_, err := EncodeCursor(Cursor{ID: 7, CreatedAt: time.Now()})
+		assert.ErrorIs(t, err, ErrCursorPaginationDisabled)
+		_, err = DecodeCursor("payload.signature")
+		assert.ErrorIs(t, err, ErrCursorPaginationDisabled)
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	assert.NoError(t, CheckConfig(nil))
+	assert.NoError(t, CheckConfig(&config.HttpServerConfig{}))
+	assert.NoError(t, CheckConfig(&config.HttpServerConfig{CursorPagination: true, CursorSecret: "secret"}))
+	assert.ErrorIs(t, CheckConfig(&config.HttpServerConfig{CursorPagination: true}), ErrCursorSecretRequired)
+}
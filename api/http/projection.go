@@ -0,0 +1,76 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"go-hexagonal/api/error_code"
+	"go-hexagonal/api/http/handle"
+	"go-hexagonal/application/projection"
+	"go-hexagonal/util/log"
+)
+
+// projections is the projection manager, nil when projections are disabled
+var projections *projection.Manager
+
+// RegisterProjections registers the projection manager for the projection endpoints
+func RegisterProjections(m *projection.Manager) {
+	projections = m
+}
+
+// GetProjectionStatus reports the checkpoint and lag of every projector
+func GetProjectionStatus(ctx *gin.Context) {
+	response := handle.NewResponse(ctx)
+	if projections == nil {
+		response.ToErrorResponse(error_code.Unavailable.WithDetails("projections are disabled"))
+		return
+	}
+
+	statuses, err := projections.Status(ctx)
+	if err != nil {
+		log.SugaredLogger.Errorf("GetProjectionStatus failed: %v", err)
+		response.ToErrorResponse(error_code.ServerError)
+		return
+	}
+
+	response.ToResponse(gin.H{"projections": statuses})
+}
+
+// RebuildProjection rebuilds the read model of a projector from the event store and reports the
+// resulting status of every projector
+func RebuildProjection(ctx *gin.Context) {
+	response := handle.NewResponse(ctx)
+	if projections == nil {
+		response.ToErrorResponse(error_code.Unavailable.WithDetails("projections are disabled"))
+		return
+	}
+
+	name := ctx.Param("name")
+	if err := projections.Rebuild(ctx, name); err != nil {
+		log.SugaredLogger.Errorf("RebuildProjection failed: %v", err)
+		if errors.Is(err, projection.ErrUnknownProjector) {
+			response.ToErrorResponse(error_code.NotFound.WithDetails(err.Error()))
+			return
+		}
+		if errors.Is(err, projection.ErrNoEventStore) {
+			response.ToErrorResponse(error_code.Unavailable.WithDetails(err.Error()))
+			return
+		}
+		if errors.Is(err, projection.ErrNoStoredEvents) {
+			response.ToErrorResponse(error_code.Conflict.WithDetails(err.Error()))
+			return
+		}
+		response.ToErrorResponse(error_code.ServerError)
+		return
+	}
+
+	statuses, err := projections.Status(ctx)
+	if err != nil {
+		log.SugaredLogger.Errorf("RebuildProjection.Status failed: %v", err)
+		response.ToErrorResponse(error_code.ServerError)
+		return
+	}
+
+	response.ToResponse(gin.H{"projections": statuses})
+}
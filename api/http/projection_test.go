@@ -0,0 +1,134 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/api/error_code"
+	"go-hexagonal/application/projection"
+	"go-hexagonal/domain/event"
+	"go-hexagonal/domain/repo"
+)
+
+// memoryCheckpoints keeps projection checkpoints in a map
+type memoryCheckpoints map[string]repo.ProjectionCheckpoint
+
+func (c memoryCheckpoints) LoadCheckpoint(_ context.Context, projector string) (*repo.ProjectionCheckpoint, error) {
+	checkpoint, ok := c[projector]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	return &checkpoint, nil
+}
+
+func (c memoryCheckpoints) SaveCheckpoint(_ context.Context, checkpoint *repo.ProjectionCheckpoint) error {
+	c[checkpoint.Projector] = *checkpoint
+	return nil
+}
+
+func (c memoryCheckpoints) DeleteCheckpoint(_ context.Context, projector string) error {
+	delete(c, projector)
+	return nil
+}
+
+// noopDailyCounts discards the daily example counts
+type noopDailyCounts struct{}
+
+func (noopDailyCounts) CountEvent(context.Context, string, string) error { return nil }
+func (noopDailyCounts) Counts(context.Context) ([]repo.ExampleDailyCount, error) {
+	return nil, nil
+}
+func (noopDailyCounts) Reset(context.Context) error { return nil }
+
+// emptyEventStore is an event store that holds no events
+type emptyEventStore struct {
+	event.NoopEventStore
+}
+
+func setupProjectionTest(t *testing.T, manager *projection.Manager) *gin.Engine {
+	t.Helper()
+
+	original := projections
+	RegisterProjections(manager)
+	t.Cleanup(func() { projections = original })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/projections", GetProjectionStatus)
+	router.POST("/api/projections/:name/rebuild", RebuildProjection)
+	return router
+}
+
+func TestGetProjectionStatus(t *testing.T) {
+	manager := projection.NewManager(&event.NoopEventStore{}, memoryCheckpoints{},
+		projection.NewExampleDailyCountProjector(noopDailyCounts{}))
+	router := setupProjectionTest(t, manager)
+
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/projections", nil)
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var body struct {
+		Data struct {
+			Projections []projection.Status `json:"projections"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Len(t, body.Data.Projections, 1)
+	assert.Equal(t, projection.ExampleDailyCountProjectorName, body.Data.Projections[0].Name)
+	assert.Equal(t, 0, body.Data.Projections[0].Lag)
+}
+
+func TestGetProjectionStatus_Disabled(t *testing.T) {
+	router := setupProjectionTest(t, nil)
+
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/projections", nil)
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, float64(error_code.UnavailableCode), body["code"])
+}
+
+func TestRebuildProjection(t *testing.T) {
+	manager := projection.NewManager(&event.NoopEventStore{}, memoryCheckpoints{},
+		projection.NewExampleDailyCountProjector(noopDailyCounts{}))
+	router := setupProjectionTest(t, manager)
+
+	// Without an event store there is nothing to rebuild from
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/api/projections/"+projection.ExampleDailyCountProjectorName+"/rebuild", nil)
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/api/projections/missing/rebuild", nil)
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestRebuildProjection_NoStoredEvents(t *testing.T) {
+	manager := projection.NewManager(&emptyEventStore{}, memoryCheckpoints{},
+		projection.NewExampleDailyCountProjector(noopDailyCounts{}))
+	router := setupProjectionTest(t, manager)
+
+	// Rebuilding from an empty event store would only clear the read model
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/api/projections/"+projection.ExampleDailyCountProjectorName+"/rebuild", nil)
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, float64(error_code.ConflictCode), body["code"])
+}
@@ -35,15 +35,18 @@ func RegisterConverter(c service.Converter) {
 	converter = c
 }
 
-// InitAppFactory initializes the application factory and sets it for API handlers
-func InitAppFactory(s *service.Services) {
-	// Create transaction factory instance
-	txFactory := repo.NewNoOpTransactionFactory()
+// InitAppFactory initializes the application factory and sets it for API handlers, its use cases
+// open their transactions on storeType. A nil transaction factory falls back to no-op transactions.
+func InitAppFactory(s *service.Services, txFactory repo.TransactionFactory, storeType repo.StoreType) {
+	if txFactory == nil {
+		txFactory = repo.NewNoOpTransactionFactory()
+	}
 
 	// Create application factory with necessary parameters
 	factory := application.NewFactory(
 		s.ExampleService,
 		txFactory,
+		storeType,
 	)
 
 	// Use the external SetAppFactory function defined in example.go
@@ -57,6 +60,9 @@ func NewServerRoute() *gin.Engine {
 	}
 
 	router := gin.New()
+	// Let use cases receiving the gin context see values stored in the request context,
+	// such as the correlation IDs attached by the request ID middleware
+	router.ContextWithFallback = true
 
 	// Register custom validators
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
@@ -65,7 +71,8 @@ func NewServerRoute() *gin.Engine {
 
 	// Apply middleware
 	router.Use(gin.Recovery())
-	router.Use(httpMiddleware.RequestID()) // Add request ID middleware
+	router.Use(httpMiddleware.RequestID())      // Add request ID middleware
+	router.Use(httpMiddleware.ReadYourWrites()) // Keep reads after a write of the request on the primary
 	router.Use(httpMiddleware.Cors())
 	router.Use(httpMiddleware.RequestLogger()) // Add request logging middleware
 	router.Use(httpMiddleware.Translations())
@@ -120,11 +127,21 @@ func NewServerRoute() *gin.Engine {
 		examples := api.Group("/examples")
 		{
 			examples.POST("", CreateExample)
+			examples.GET("", ListExamples)
 			examples.GET("/:id", GetExample)
 			examples.PUT("/:id", UpdateExample)
 			examples.DELETE("/:id", DeleteExample)
+			examples.POST("/:id/restore", RestoreExample)
+			examples.DELETE("/:id/purge", PurgeExample)
 			examples.GET("/name/:name", FindExampleByName)
 		}
+
+		// Read model projections
+		projectionGroup := api.Group("/projections")
+		{
+			projectionGroup.GET("", GetProjectionStatus)
+			projectionGroup.POST("/:name/rebuild", RebuildProjection)
+		}
 	}
 
 	return router
@@ -18,41 +18,64 @@ type UseCase interface {
 // UseCaseHandler provides a base implementation for use cases
 type UseCaseHandler struct {
 	TxFactory repo.TransactionFactory
+	// StoreType is the store the use cases open their transactions on, the configured primary store
+	StoreType repo.StoreType
 }
 
-// NewUseCaseHandler creates a new use case handler
-func NewUseCaseHandler(txFactory repo.TransactionFactory) *UseCaseHandler {
+// NewUseCaseHandler creates a new use case handler opening its transactions on storeType
+func NewUseCaseHandler(txFactory repo.TransactionFactory, storeType repo.StoreType) *UseCaseHandler {
 	return &UseCaseHandler{
 		TxFactory: txFactory,
+		StoreType: storeType,
 	}
 }
 
-// ExecuteInTransaction executes the given function within a transaction
+// ExecuteInTransaction executes the given function within a transaction.
+// The transaction is carried by the context passed to fn, so repositories reached through it
+// share one unit of work. When ctx already carries a transaction, fn joins it and the
+// outermost caller decides whether to commit.
 func (h *UseCaseHandler) ExecuteInTransaction(
 	ctx context.Context,
 	storeType repo.StoreType,
 	fn func(context.Context, repo.Transaction) (any, error),
 ) (any, error) {
+	// Join the enclosing unit of work
+	if tx, ok := repo.TransactionFromContext(ctx); ok {
+		return fn(ctx, tx)
+	}
+
 	// Create transaction
 	tx, err := h.TxFactory.NewTransaction(ctx, storeType, nil)
 	if err != nil {
 		log.SugaredLogger.Errorf("Failed to create transaction: %v", err)
 		return nil, errors.Wrapf(err, errors.ErrorTypeSystem, "failed to create transaction")
 	}
-	defer func() { _ = tx.Rollback() }()
+	if err = tx.Begin(); err != nil {
+		log.SugaredLogger.Errorf("Failed to begin transaction: %v", err)
+		return nil, errors.Wrapf(err, errors.ErrorTypeSystem, "failed to begin transaction")
+	}
+
+	// Roll back unless the commit below succeeded, this also covers panics in fn
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
 
 	// Execute function within transaction
-	result, err := fn(ctx, tx)
+	result, err := fn(repo.ContextWithTransaction(ctx, tx), tx)
 	if err != nil {
 		log.SugaredLogger.Errorf("Transaction execution failed: %v", err)
 		return nil, err
 	}
 
-	// Commit transaction
+	// Commit transaction, a failed commit is rolled back as well
 	if err = tx.Commit(); err != nil {
 		log.SugaredLogger.Errorf("Failed to commit transaction: %v", err)
 		return nil, errors.Wrapf(err, errors.ErrorTypeSystem, "failed to commit transaction")
 	}
+	committed = true
 
 	return result, nil
 }
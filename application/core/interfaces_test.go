@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/repo"
+)
+
+// recordingTransaction records the lifecycle calls made on it
+type recordingTransaction struct {
+	*repo.BaseTransaction
+	calls     []string
+	beginErr  error
+	commitErr error
+}
+
+func (tx *recordingTransaction) Begin() error {
+	tx.calls = append(tx.calls, "begin")
+	return tx.beginErr
+}
+
+func (tx *recordingTransaction) Commit() error {
+	tx.calls = append(tx.calls, "commit")
+	return tx.commitErr
+}
+
+func (tx *recordingTransaction) Rollback() error {
+	tx.calls = append(tx.calls, "rollback")
+	return nil
+}
+
+// recordingTransactionFactory hands out a single recording transaction
+type recordingTransactionFactory struct {
+	tx      *recordingTransaction
+	created int
+}
+
+func (f *recordingTransactionFactory) NewTransaction(ctx context.Context, store repo.StoreType, opts any) (repo.Transaction, error) {
+	f.created++
+	return f.tx, nil
+}
+
+func newRecordingHandler() (*UseCaseHandler, *recordingTransactionFactory) {
+	factory := &recordingTransactionFactory{
+		tx: &recordingTransaction{BaseTransaction: repo.NewBaseTransaction(context.Background(), repo.MySQLStore, nil)},
+	}
+	return NewUseCaseHandler(factory, repo.MySQLStore), factory
+}
+
+func TestExecuteInTransaction_Commit(t *testing.T) {
+	handler, factory := newRecordingHandler()
+
+	result, err := handler.ExecuteInTransaction(context.Background(), repo.MySQLStore, func(ctx context.Context, tx repo.Transaction) (any, error) {
+		// The transaction is carried by the context for the services below
+		ctxTx, ok := repo.TransactionFromContext(ctx)
+		require.True(t, ok)
+		assert.Same(t, tx, ctxTx)
+		return "done", nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "done", result)
+	assert.Equal(t, []string{"begin", "commit"}, factory.tx.calls)
+}
+
+func TestExecuteInTransaction_RollbackOnError(t *testing.T) {
+	handler, factory := newRecordingHandler()
+	failure := errors.New("step failed")
+
+	_, err := handler.ExecuteInTransaction(context.Background(), repo.MySQLStore, func(ctx context.Context, tx repo.Transaction) (any, error) {
+		return nil, failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, []string{"begin", "rollback"}, factory.tx.calls)
+}
+
+func TestExecuteInTransaction_RollbackOnPanic(t *testing.T) {
+	handler, factory := newRecordingHandler()
+
+	assert.Panics(t, func() {
+		_, _ = handler.ExecuteInTransaction(context.Background(), repo.MySQLStore, func(ctx context.Context, tx repo.Transaction) (any, error) {
+			panic("boom")
+		})
+	})
+	assert.Equal(t, []string{"begin", "rollback"}, factory.tx.calls)
+}
+
+func TestExecuteInTransaction_RollbackOnCommitError(t *testing.T) {
+	handler, factory := newRecordingHandler()
+	factory.tx.commitErr = errors.New("connection lost")
+
+	_, err := handler.ExecuteInTransaction(context.Background(), repo.MySQLStore, func(ctx context.Context, tx repo.Transaction) (any, error) {
+		return "done", nil
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, []string{"begin", "commit", "rollback"}, factory.tx.calls)
+}
+
+func TestExecuteInTransaction_BeginError(t *testing.T) {
+	handler, factory := newRecordingHandler()
+	factory.tx.beginErr = errors.New("connection lost")
+	called := false
+
+	_, err := handler.ExecuteInTransaction(context.Background(), repo.MySQLStore, func(ctx context.Context, tx repo.Transaction) (any, error) {
+		called = true
+		return nil, nil
+	})
+
+	assert.Error(t, err)
+	assert.False(t, called)
+	assert.Equal(t, []string{"begin"}, factory.tx.calls)
+}
+
+func TestExecuteInTransaction_JoinsEnclosingTransaction(t *testing.T) {
+	handler, factory := newRecordingHandler()
+	outer := &recordingTransaction{BaseTransaction: repo.NewBaseTransaction(context.Background(), repo.MySQLStore, nil)}
+	ctx := repo.ContextWithTransaction(context.Background(), outer)
+
+	_, err := handler.ExecuteInTransaction(ctx, repo.MySQLStore, func(ctx context.Context, tx repo.Transaction) (any, error) {
+		assert.Same(t, outer, tx)
+		return nil, nil
+	})
+
+	require.NoError(t, err)
+	assert.Zero(t, factory.created)
+	// The outermost caller owns commit and rollback
+	assert.Empty(t, outer.calls)
+}
@@ -19,9 +19,10 @@ type CreateUseCase struct {
 func NewCreateUseCase(
 	exampleService service.IExampleService,
 	txFactory repo.TransactionFactory,
+	storeType repo.StoreType,
 ) *CreateUseCase {
 	return &CreateUseCase{
-		UseCaseHandler: core.NewUseCaseHandler(txFactory),
+		UseCaseHandler: core.NewUseCaseHandler(txFactory, storeType),
 		exampleService: exampleService,
 	}
 }
@@ -39,7 +40,7 @@ func (uc *CreateUseCase) Execute(ctx context.Context, input any) (any, error) {
 	}
 
 	// Execute in transaction
-	result, err := uc.ExecuteInTransaction(ctx, repo.MySQLStore, func(ctx context.Context, tx repo.Transaction) (any, error) {
+	result, err := uc.ExecuteInTransaction(ctx, uc.StoreType, func(ctx context.Context, tx repo.Transaction) (any, error) {
 		// Call domain service
 		example, err := uc.exampleService.Create(ctx, createInput.Name, createInput.Alias)
 		if err != nil {
@@ -53,11 +53,46 @@ func (m *MockExampleService) Delete(ctx context.Context, id int) error {
 }
 
 // Update implements the Update method
-func (m *MockExampleService) Update(ctx context.Context, id int, name, alias string) error {
-	args := m.Called(ctx, id, name, alias)
+func (m *MockExampleService) Update(ctx context.Context, id int, name, alias string, expectedVersion int) error {
+	args := m.Called(ctx, id, name, alias, expectedVersion)
 	return args.Error(0)
 }
 
+// List implements the List method
+func (m *MockExampleService) List(ctx context.Context, query repo.ExampleListQuery) ([]*model.Example, int64, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.Example), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockExampleService) ListByCursor(ctx context.Context, query repo.ExampleKeysetQuery) (*repo.ExampleCursorPage, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repo.ExampleCursorPage), args.Error(1)
+}
+
+func (m *MockExampleService) Restore(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockExampleService) Purge(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockExampleService) GetWithDeleted(ctx context.Context, id int) (*model.Example, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Example), args.Error(1)
+}
+
 // TestablCreateUseCase modifies CreateUseCase for testing purposes
 type TestablCreateUseCase struct {
 	CreateUseCase
@@ -20,9 +20,10 @@ type DeleteUseCase struct {
 func NewDeleteUseCase(
 	exampleService service.IExampleService,
 	txFactory repo.TransactionFactory,
+	storeType repo.StoreType,
 ) *DeleteUseCase {
 	return &DeleteUseCase{
-		UseCaseHandler: core.NewUseCaseHandler(txFactory),
+		UseCaseHandler: core.NewUseCaseHandler(txFactory, storeType),
 		exampleService: exampleService,
 	}
 }
@@ -40,7 +41,7 @@ func (uc *DeleteUseCase) Execute(ctx context.Context, input any) (any, error) {
 	}
 
 	// Execute in transaction
-	_, err := uc.ExecuteInTransaction(ctx, repo.MySQLStore, func(ctx context.Context, tx repo.Transaction) (any, error) {
+	_, err := uc.ExecuteInTransaction(ctx, uc.StoreType, func(ctx context.Context, tx repo.Transaction) (any, error) {
 		// Call domain service to delete the example
 		err := uc.exampleService.Delete(ctx, deleteInput.ID)
 		if err != nil {
@@ -5,6 +5,7 @@ import (
 
 	"go-hexagonal/application/core"
 	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
 )
 
 // Input DTOs
@@ -32,6 +33,8 @@ type UpdateInput struct {
 	ID    int    `json:"id" validate:"required"`
 	Name  string `json:"name" validate:"required"`
 	Alias string `json:"alias"`
+	// Version is the version the client read, 0 updates whatever version is stored
+	Version int `json:"version"`
 }
 
 // Validate validates the update input
@@ -46,13 +49,19 @@ func (i *UpdateInput) Validate() error {
 			"name": "required",
 		})
 	}
+	if i.Version < 0 {
+		return core.ValidationError("invalid version", map[string]any{
+			"version": "must not be negative",
+		})
+	}
 	return nil
 }
 
 // GetInput represents input for retrieving an example by ID
 type GetInput struct {
 	core.BaseInput
-	ID int `json:"id" validate:"required"`
+	ID             int  `json:"id" validate:"required"`
+	IncludeDeleted bool `json:"include_deleted"`
 }
 
 // Validate validates the get input
@@ -81,6 +90,38 @@ func (i *DeleteInput) Validate() error {
 	return nil
 }
 
+// RestoreInput represents input for restoring a soft deleted example
+type RestoreInput struct {
+	core.BaseInput
+	ID int `json:"id" validate:"required"`
+}
+
+// Validate validates the restore input
+func (i *RestoreInput) Validate() error {
+	if i.ID <= 0 {
+		return core.ValidationError("invalid ID", map[string]any{
+			"id": "must be positive",
+		})
+	}
+	return nil
+}
+
+// PurgeInput represents input for permanently removing an example
+type PurgeInput struct {
+	core.BaseInput
+	ID int `json:"id" validate:"required"`
+}
+
+// Validate validates the purge input
+func (i *PurgeInput) Validate() error {
+	if i.ID <= 0 {
+		return core.ValidationError("invalid ID", map[string]any{
+			"id": "must be positive",
+		})
+	}
+	return nil
+}
+
 // FindByNameInput represents input for finding an example by name
 type FindByNameInput struct {
 	core.BaseInput
@@ -97,16 +138,146 @@ func (i *FindByNameInput) Validate() error {
 	return nil
 }
 
+// Sort orders accepted by ListInput
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// ListInput represents input for listing examples with filtering, sorting and pagination
+type ListInput struct {
+	core.BaseInput
+	Name           string     `json:"name"`
+	Alias          string     `json:"alias"`
+	CreatedAfter   *time.Time `json:"created_after"`
+	CreatedBefore  *time.Time `json:"created_before"`
+	SortBy         string     `json:"sort_by"`
+	SortOrder      string     `json:"sort_order"`
+	Page           int        `json:"page"`
+	PageSize       int        `json:"page_size"`
+	IncludeDeleted bool       `json:"include_deleted"`
+}
+
+// Validate validates the list input
+func (i *ListInput) Validate() error {
+	if i.Page <= 0 {
+		return core.ValidationError("invalid page", map[string]any{
+			"page": "must be positive",
+		})
+	}
+	if i.PageSize <= 0 {
+		return core.ValidationError("invalid page size", map[string]any{
+			"page_size": "must be positive",
+		})
+	}
+	if i.SortBy != "" && !repo.ExampleSortField(i.SortBy).IsValid() {
+		return core.ValidationError("invalid sort field", map[string]any{
+			"sort_by": "must be one of id, name, created_at, updated_at",
+		})
+	}
+	if i.SortOrder != "" && i.SortOrder != SortOrderAsc && i.SortOrder != SortOrderDesc {
+		return core.ValidationError("invalid sort order", map[string]any{
+			"sort_order": "must be asc or desc",
+		})
+	}
+	if i.CreatedAfter != nil && i.CreatedBefore != nil && !i.CreatedAfter.Before(*i.CreatedBefore) {
+		return core.ValidationError("invalid creation time range", map[string]any{
+			"created_after": "must be before created_before",
+		})
+	}
+	return nil
+}
+
+// ToQuery converts the input into a repository list query
+func (i *ListInput) ToQuery() repo.ExampleListQuery {
+	sortBy := repo.ExampleSortField(i.SortBy)
+	if sortBy == "" {
+		sortBy = repo.ExampleSortByID
+	}
+
+	return repo.ExampleListQuery{
+		Filter: repo.ExampleFilter{
+			Name:           i.Name,
+			Alias:          i.Alias,
+			CreatedAfter:   i.CreatedAfter,
+			CreatedBefore:  i.CreatedBefore,
+			IncludeDeleted: i.IncludeDeleted,
+		},
+		SortBy:   sortBy,
+		SortDesc: i.SortOrder == SortOrderDesc,
+		Offset:   (i.Page - 1) * i.PageSize,
+		Limit:    i.PageSize,
+	}
+}
+
+// ListByCursorInput represents input for listing examples with keyset pagination
+type ListByCursorInput struct {
+	core.BaseInput
+	Name           string              `json:"name"`
+	Alias          string              `json:"alias"`
+	CreatedAfter   *time.Time          `json:"created_after"`
+	CreatedBefore  *time.Time          `json:"created_before"`
+	SortOrder      string              `json:"sort_order"`
+	Cursor         *repo.ExampleCursor `json:"cursor"`
+	Backward       bool                `json:"backward"`
+	PageSize       int                 `json:"page_size"`
+	IncludeDeleted bool                `json:"include_deleted"`
+}
+
+// Validate validates the keyset list input
+func (i *ListByCursorInput) Validate() error {
+	if i.PageSize <= 0 {
+		return core.ValidationError("invalid page size", map[string]any{
+			"page_size": "must be positive",
+		})
+	}
+	if i.SortOrder != "" && i.SortOrder != SortOrderAsc && i.SortOrder != SortOrderDesc {
+		return core.ValidationError("invalid sort order", map[string]any{
+			"sort_order": "must be asc or desc",
+		})
+	}
+	if i.Backward && i.Cursor == nil {
+		return core.ValidationError("cursor is required", map[string]any{
+			"cursor": "required when reading backward",
+		})
+	}
+	if i.CreatedAfter != nil && i.CreatedBefore != nil && !i.CreatedAfter.Before(*i.CreatedBefore) {
+		return core.ValidationError("invalid creation time range", map[string]any{
+			"created_after": "must be before created_before",
+		})
+	}
+	return nil
+}
+
+// ToQuery converts the input into a repository keyset query
+func (i *ListByCursorInput) ToQuery() repo.ExampleKeysetQuery {
+	return repo.ExampleKeysetQuery{
+		Filter: repo.ExampleFilter{
+			Name:           i.Name,
+			Alias:          i.Alias,
+			CreatedAfter:   i.CreatedAfter,
+			CreatedBefore:  i.CreatedBefore,
+			IncludeDeleted: i.IncludeDeleted,
+		},
+		Cursor:   i.Cursor,
+		Backward: i.Backward,
+		SortDesc: i.SortOrder == SortOrderDesc,
+		Limit:    i.PageSize,
+	}
+}
+
 // Output DTOs
 
 // ExampleOutput represents the output format for example entities
 type ExampleOutput struct {
 	core.BaseOutput
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Alias     string    `json:"alias"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Alias     string     `json:"alias"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Version   int        `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 // FromModel converts a domain model to an output DTO
@@ -116,6 +287,8 @@ func (o *ExampleOutput) FromModel(example *model.Example) {
 	o.Alias = example.Alias
 	o.CreatedAt = example.CreatedAt
 	o.UpdatedAt = example.UpdatedAt
+	o.Version = example.Version
+	o.DeletedAt = example.DeletedAt
 	o.Status = "success"
 }
 
@@ -125,3 +298,53 @@ func NewExampleOutput(example *model.Example) *ExampleOutput {
 	output.FromModel(example)
 	return output
 }
+
+// ListOutput represents a page of examples
+type ListOutput struct {
+	core.BaseOutput
+	Items    []*ExampleOutput `json:"items"`
+	Total    int64            `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+}
+
+// NewListOutput creates a new list output from models
+func NewListOutput(examples []*model.Example, total int64, page, pageSize int) *ListOutput {
+	items := make([]*ExampleOutput, 0, len(examples))
+	for _, example := range examples {
+		items = append(items, NewExampleOutput(example))
+	}
+
+	return &ListOutput{
+		BaseOutput: core.BaseOutput{Status: "success"},
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+	}
+}
+
+// CursorListOutput represents a keyset-paginated page of examples
+type CursorListOutput struct {
+	core.BaseOutput
+	Items      []*ExampleOutput    `json:"items"`
+	NextCursor *repo.ExampleCursor `json:"-"`
+	PrevCursor *repo.ExampleCursor `json:"-"`
+	PageSize   int                 `json:"page_size"`
+}
+
+// NewCursorListOutput creates a new keyset list output from a repository page
+func NewCursorListOutput(page *repo.ExampleCursorPage, pageSize int) *CursorListOutput {
+	items := make([]*ExampleOutput, 0, len(page.Items))
+	for _, example := range page.Items {
+		items = append(items, NewExampleOutput(example))
+	}
+
+	return &CursorListOutput{
+		BaseOutput: core.BaseOutput{Status: "success"},
+		Items:      items,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		PageSize:   pageSize,
+	}
+}
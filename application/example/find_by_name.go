@@ -20,9 +20,10 @@ type FindByNameUseCase struct {
 func NewFindByNameUseCase(
 	exampleService service.IExampleService,
 	txFactory repo.TransactionFactory,
+	storeType repo.StoreType,
 ) *FindByNameUseCase {
 	return &FindByNameUseCase{
-		UseCaseHandler: core.NewUseCaseHandler(txFactory),
+		UseCaseHandler: core.NewUseCaseHandler(txFactory, storeType),
 		exampleService: exampleService,
 	}
 }
@@ -20,9 +20,10 @@ type GetUseCase struct {
 func NewGetUseCase(
 	exampleService service.IExampleService,
 	txFactory repo.TransactionFactory,
+	storeType repo.StoreType,
 ) *GetUseCase {
 	return &GetUseCase{
-		UseCaseHandler: core.NewUseCaseHandler(txFactory),
+		UseCaseHandler: core.NewUseCaseHandler(txFactory, storeType),
 		exampleService: exampleService,
 	}
 }
@@ -40,7 +41,11 @@ func (uc *GetUseCase) Execute(ctx context.Context, input any) (any, error) {
 	}
 
 	// Retrieve example directly (no transaction needed)
-	example, err := uc.exampleService.Get(ctx, getInput.ID)
+	get := uc.exampleService.Get
+	if getInput.IncludeDeleted {
+		get = uc.exampleService.GetWithDeleted
+	}
+	example, err := get(ctx, getInput.ID)
 	if err != nil {
 		log.SugaredLogger.Errorf("Failed to get example: %v", err)
 		return nil, fmt.Errorf("failed to get example: %w", err)
@@ -0,0 +1,52 @@
+package example
+
+import (
+	"context"
+	"fmt"
+
+	"go-hexagonal/application/core"
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/domain/service"
+	"go-hexagonal/util/log"
+)
+
+// ListUseCase handles the list examples use case
+type ListUseCase struct {
+	*core.UseCaseHandler
+	exampleService service.IExampleService
+}
+
+// NewListUseCase creates a new ListUseCase instance
+func NewListUseCase(
+	exampleService service.IExampleService,
+	txFactory repo.TransactionFactory,
+	storeType repo.StoreType,
+) *ListUseCase {
+	return &ListUseCase{
+		UseCaseHandler: core.NewUseCaseHandler(txFactory, storeType),
+		exampleService: exampleService,
+	}
+}
+
+// Execute processes the list examples request
+func (uc *ListUseCase) Execute(ctx context.Context, input any) (any, error) {
+	// Convert and validate input
+	listInput, ok := input.(*ListInput)
+	if !ok {
+		return nil, core.ValidationError("invalid input type", nil)
+	}
+
+	if err := listInput.Validate(); err != nil {
+		return nil, err
+	}
+
+	// List examples directly (no transaction needed for read-only operation)
+	examples, total, err := uc.exampleService.List(ctx, listInput.ToQuery())
+	if err != nil {
+		log.SugaredLogger.Errorf("Failed to list examples: %v", err)
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	// Create output DTO
+	return NewListOutput(examples, total, listInput.Page, listInput.PageSize), nil
+}
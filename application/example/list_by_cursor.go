@@ -0,0 +1,52 @@
+package example
+
+import (
+	"context"
+	"fmt"
+
+	"go-hexagonal/application/core"
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/domain/service"
+	"go-hexagonal/util/log"
+)
+
+// ListByCursorUseCase handles the keyset-paginated list examples use case
+type ListByCursorUseCase struct {
+	*core.UseCaseHandler
+	exampleService service.IExampleService
+}
+
+// NewListByCursorUseCase creates a new ListByCursorUseCase instance
+func NewListByCursorUseCase(
+	exampleService service.IExampleService,
+	txFactory repo.TransactionFactory,
+	storeType repo.StoreType,
+) *ListByCursorUseCase {
+	return &ListByCursorUseCase{
+		UseCaseHandler: core.NewUseCaseHandler(txFactory, storeType),
+		exampleService: exampleService,
+	}
+}
+
+// Execute processes the keyset-paginated list examples request
+func (uc *ListByCursorUseCase) Execute(ctx context.Context, input any) (any, error) {
+	// Convert and validate input
+	listInput, ok := input.(*ListByCursorInput)
+	if !ok {
+		return nil, core.ValidationError("invalid input type", nil)
+	}
+
+	if err := listInput.Validate(); err != nil {
+		return nil, err
+	}
+
+	// List examples directly (no transaction needed for read-only operation)
+	page, err := uc.exampleService.ListByCursor(ctx, listInput.ToQuery())
+	if err != nil {
+		log.SugaredLogger.Errorf("Failed to list examples by cursor: %v", err)
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	// Create output DTO
+	return NewCursorListOutput(page, listInput.PageSize), nil
+}
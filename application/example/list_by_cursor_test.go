@@ -0,0 +1,105 @@
+package example
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+// TestListByCursorUseCase_Success tests listing a keyset page of examples
+func TestListByCursorUseCase_Success(t *testing.T) {
+	// Create mock service
+	mockService := new(MockExampleService)
+
+	// Test data
+	now := time.Now()
+	cursor := &repo.ExampleCursor{ID: 2, CreatedAt: now}
+	page := &repo.ExampleCursorPage{
+		Items: []*model.Example{
+			{Id: 3, Name: "Example 3", Alias: "ex3", CreatedAt: now, UpdatedAt: now},
+		},
+		NextCursor: &repo.ExampleCursor{ID: 3, CreatedAt: now},
+		PrevCursor: &repo.ExampleCursor{ID: 3, CreatedAt: now},
+	}
+	expectedQuery := repo.ExampleKeysetQuery{
+		Filter:   repo.ExampleFilter{Alias: "ex"},
+		Cursor:   cursor,
+		SortDesc: true,
+		Limit:    1,
+	}
+
+	// Set mock behavior
+	mockService.On("ListByCursor", mock.Anything, expectedQuery).Return(page, nil)
+
+	// Execute use case
+	useCase := NewListByCursorUseCase(mockService, nil, repo.MySQLStore)
+	result, err := useCase.Execute(context.Background(), &ListByCursorInput{
+		Alias:     "ex",
+		SortOrder: SortOrderDesc,
+		Cursor:    cursor,
+		PageSize:  1,
+	})
+
+	// Assert results
+	assert.NoError(t, err)
+	output, ok := result.(*CursorListOutput)
+	assert.True(t, ok)
+	assert.Len(t, output.Items, 1)
+	assert.Equal(t, 3, output.Items[0].ID)
+	assert.Equal(t, page.NextCursor, output.NextCursor)
+	assert.Equal(t, page.PrevCursor, output.PrevCursor)
+	assert.Equal(t, 1, output.PageSize)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestListByCursorUseCase_Error tests error propagation from the service
+func TestListByCursorUseCase_Error(t *testing.T) {
+	// Create mock service
+	mockService := new(MockExampleService)
+
+	// Set mock behavior - simulate error
+	mockService.On("ListByCursor", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+	// Execute use case
+	useCase := NewListByCursorUseCase(mockService, nil, repo.MySQLStore)
+	result, err := useCase.Execute(context.Background(), &ListByCursorInput{PageSize: 10})
+
+	// Assert results
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "failed to list examples")
+
+	mockService.AssertExpectations(t)
+}
+
+// TestListByCursorInput_Validate tests validation of keyset list input
+func TestListByCursorInput_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   ListByCursorInput
+		wantErr bool
+	}{
+		{name: "valid input", input: ListByCursorInput{PageSize: 10, SortOrder: SortOrderDesc}},
+		{name: "invalid page size", input: ListByCursorInput{PageSize: 0}, wantErr: true},
+		{name: "invalid sort order", input: ListByCursorInput{PageSize: 10, SortOrder: "up"}, wantErr: true},
+		{name: "backward without cursor", input: ListByCursorInput{PageSize: 10, Backward: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
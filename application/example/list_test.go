@@ -0,0 +1,108 @@
+package example
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+// TestListUseCase_Success tests listing a page of examples
+func TestListUseCase_Success(t *testing.T) {
+	// Create mock service
+	mockService := new(MockExampleService)
+
+	// Test data
+	now := time.Now()
+	examples := []*model.Example{
+		{Id: 3, Name: "Example 3", Alias: "ex3", CreatedAt: now, UpdatedAt: now},
+		{Id: 4, Name: "Example 4", Alias: "ex4", CreatedAt: now, UpdatedAt: now},
+	}
+	expectedQuery := repo.ExampleListQuery{
+		Filter:   repo.ExampleFilter{Name: "Example"},
+		SortBy:   repo.ExampleSortByCreatedAt,
+		SortDesc: true,
+		Offset:   2,
+		Limit:    2,
+	}
+
+	// Set mock behavior
+	mockService.On("List", mock.Anything, expectedQuery).Return(examples, int64(6), nil)
+
+	// Execute use case
+	useCase := NewListUseCase(mockService, nil, repo.MySQLStore)
+	result, err := useCase.Execute(context.Background(), &ListInput{
+		Name:      "Example",
+		SortBy:    "created_at",
+		SortOrder: SortOrderDesc,
+		Page:      2,
+		PageSize:  2,
+	})
+
+	// Assert results
+	assert.NoError(t, err)
+	output, ok := result.(*ListOutput)
+	assert.True(t, ok)
+	assert.Equal(t, int64(6), output.Total)
+	assert.Equal(t, 2, output.Page)
+	assert.Equal(t, 2, output.PageSize)
+	assert.Len(t, output.Items, 2)
+	assert.Equal(t, 3, output.Items[0].ID)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestListUseCase_Error tests error propagation from the service
+func TestListUseCase_Error(t *testing.T) {
+	// Create mock service
+	mockService := new(MockExampleService)
+
+	// Set mock behavior - simulate error
+	mockService.On("List", mock.Anything, mock.Anything).Return(nil, int64(0), assert.AnError)
+
+	// Execute use case
+	useCase := NewListUseCase(mockService, nil, repo.MySQLStore)
+	result, err := useCase.Execute(context.Background(), &ListInput{Page: 1, PageSize: 10})
+
+	// Assert results
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "failed to list examples")
+
+	mockService.AssertExpectations(t)
+}
+
+// TestListInput_Validate tests validation of list input
+func TestListInput_Validate(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		input   ListInput
+		wantErr bool
+	}{
+		{name: "valid input", input: ListInput{Page: 1, PageSize: 10, SortBy: "name", SortOrder: SortOrderAsc}},
+		{name: "invalid page", input: ListInput{Page: 0, PageSize: 10}, wantErr: true},
+		{name: "invalid page size", input: ListInput{Page: 1, PageSize: 0}, wantErr: true},
+		{name: "invalid sort field", input: ListInput{Page: 1, PageSize: 10, SortBy: "password"}, wantErr: true},
+		{name: "invalid sort order", input: ListInput{Page: 1, PageSize: 10, SortOrder: "up"}, wantErr: true},
+		{name: "inverted time range", input: ListInput{Page: 1, PageSize: 10, CreatedAfter: &now, CreatedBefore: &earlier}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
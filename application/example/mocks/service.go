@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
 )
 
 // ExampleService is a mock implementation of service.ExampleService
@@ -52,3 +53,42 @@ func (m *ExampleService) FindByName(ctx context.Context, name string) (*model.Ex
 	}
 	return args.Get(0).(*model.Example), args.Error(1)
 }
+
+// List mocks the List method
+func (m *ExampleService) List(ctx context.Context, query repo.ExampleListQuery) ([]*model.Example, int64, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.Example), args.Get(1).(int64), args.Error(2)
+}
+
+// ListByCursor mocks the ListByCursor method
+func (m *ExampleService) ListByCursor(ctx context.Context, query repo.ExampleKeysetQuery) (*repo.ExampleCursorPage, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repo.ExampleCursorPage), args.Error(1)
+}
+
+// Restore mocks the Restore method
+func (m *ExampleService) Restore(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Purge mocks the Purge method
+func (m *ExampleService) Purge(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// GetWithDeleted mocks the GetWithDeleted method
+func (m *ExampleService) GetWithDeleted(ctx context.Context, id int) (*model.Example, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Example), args.Error(1)
+}
@@ -0,0 +1,60 @@
+package example
+
+import (
+	"context"
+	"fmt"
+
+	"go-hexagonal/application/core"
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/domain/service"
+	"go-hexagonal/util/log"
+)
+
+// PurgeUseCase handles the purge example use case
+type PurgeUseCase struct {
+	*core.UseCaseHandler
+	exampleService service.IExampleService
+}
+
+// NewPurgeUseCase creates a new PurgeUseCase instance
+func NewPurgeUseCase(
+	exampleService service.IExampleService,
+	txFactory repo.TransactionFactory,
+	storeType repo.StoreType,
+) *PurgeUseCase {
+	return &PurgeUseCase{
+		UseCaseHandler: core.NewUseCaseHandler(txFactory, storeType),
+		exampleService: exampleService,
+	}
+}
+
+// Execute processes the purge example request
+func (uc *PurgeUseCase) Execute(ctx context.Context, input any) (any, error) {
+	// Convert and validate input
+	purgeInput, ok := input.(*PurgeInput)
+	if !ok {
+		return nil, core.ValidationError("invalid input type", nil)
+	}
+
+	if err := purgeInput.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Execute in transaction
+	_, err := uc.ExecuteInTransaction(ctx, uc.StoreType, func(ctx context.Context, tx repo.Transaction) (any, error) {
+		// Call domain service to purge the example
+		err := uc.exampleService.Purge(ctx, purgeInput.ID)
+		if err != nil {
+			log.SugaredLogger.Errorf("Failed to purge example: %v", err)
+			return nil, fmt.Errorf("failed to purge example: %w", err)
+		}
+
+		return core.NewSuccessOutput(), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return core.NewSuccessOutput(), nil
+}
@@ -0,0 +1,49 @@
+package example
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go-hexagonal/domain/repo"
+)
+
+// TestPurgeUseCase_Success tests permanently removing an example
+func TestPurgeUseCase_Success(t *testing.T) {
+	// Create mock service
+	mockService := new(MockExampleService)
+
+	// Set mock behavior
+	mockService.On("Purge", mock.Anything, 1).Return(nil)
+
+	// Execute use case
+	useCase := NewPurgeUseCase(mockService, repo.NewNoOpTransactionFactory(), repo.MySQLStore)
+	result, err := useCase.Execute(context.Background(), &PurgeInput{ID: 1})
+
+	// Assert results
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestPurgeUseCase_Error tests error propagation from the service
+func TestPurgeUseCase_Error(t *testing.T) {
+	// Create mock service
+	mockService := new(MockExampleService)
+
+	// Set mock behavior - simulate missing example
+	mockService.On("Purge", mock.Anything, 2).Return(repo.ErrNotFound)
+
+	// Execute use case
+	useCase := NewPurgeUseCase(mockService, repo.NewNoOpTransactionFactory(), repo.MySQLStore)
+	result, err := useCase.Execute(context.Background(), &PurgeInput{ID: 2})
+
+	// Assert results
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+	assert.Nil(t, result)
+
+	mockService.AssertExpectations(t)
+}
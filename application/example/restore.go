@@ -0,0 +1,68 @@
+package example
+
+import (
+	"context"
+	"fmt"
+
+	"go-hexagonal/application/core"
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/domain/service"
+	"go-hexagonal/util/log"
+)
+
+// RestoreUseCase handles the restore example use case
+type RestoreUseCase struct {
+	*core.UseCaseHandler
+	exampleService service.IExampleService
+}
+
+// NewRestoreUseCase creates a new RestoreUseCase instance
+func NewRestoreUseCase(
+	exampleService service.IExampleService,
+	txFactory repo.TransactionFactory,
+	storeType repo.StoreType,
+) *RestoreUseCase {
+	return &RestoreUseCase{
+		UseCaseHandler: core.NewUseCaseHandler(txFactory, storeType),
+		exampleService: exampleService,
+	}
+}
+
+// Execute processes the restore example request
+func (uc *RestoreUseCase) Execute(ctx context.Context, input any) (any, error) {
+	// Convert and validate input
+	restoreInput, ok := input.(*RestoreInput)
+	if !ok {
+		return nil, core.ValidationError("invalid input type", nil)
+	}
+
+	if err := restoreInput.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Execute in transaction
+	result, err := uc.ExecuteInTransaction(ctx, uc.StoreType, func(ctx context.Context, tx repo.Transaction) (any, error) {
+		// Call domain service to restore the example
+		err := uc.exampleService.Restore(ctx, restoreInput.ID)
+		if err != nil {
+			log.SugaredLogger.Errorf("Failed to restore example: %v", err)
+			return nil, fmt.Errorf("failed to restore example: %w", err)
+		}
+
+		// Get the restored example
+		restoredExample, err := uc.exampleService.Get(ctx, restoreInput.ID)
+		if err != nil {
+			log.SugaredLogger.Errorf("Failed to get restored example: %v", err)
+			return nil, fmt.Errorf("failed to get restored example: %w", err)
+		}
+
+		// Create output DTO
+		return NewExampleOutput(restoredExample), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package example
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+// TestRestoreUseCase_Success tests restoring a soft deleted example
+func TestRestoreUseCase_Success(t *testing.T) {
+	// Create mock service
+	mockService := new(MockExampleService)
+
+	// Test data
+	now := time.Now()
+	restored := &model.Example{Id: 1, Name: "Example 1", Alias: "ex1", CreatedAt: now, UpdatedAt: now, Version: 3}
+
+	// Set mock behavior
+	mockService.On("Restore", mock.Anything, 1).Return(nil)
+	mockService.On("Get", mock.Anything, 1).Return(restored, nil)
+
+	// Execute use case
+	useCase := NewRestoreUseCase(mockService, repo.NewNoOpTransactionFactory(), repo.MySQLStore)
+	result, err := useCase.Execute(context.Background(), &RestoreInput{ID: 1})
+
+	// Assert results
+	assert.NoError(t, err)
+	output, ok := result.(*ExampleOutput)
+	assert.True(t, ok)
+	assert.Equal(t, 1, output.ID)
+	assert.Equal(t, 3, output.Version)
+	assert.Nil(t, output.DeletedAt)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestRestoreUseCase_Error tests error propagation from the service
+func TestRestoreUseCase_Error(t *testing.T) {
+	// Create mock service
+	mockService := new(MockExampleService)
+
+	// Set mock behavior - example is not deleted
+	mockService.On("Restore", mock.Anything, 1).Return(model.ErrExampleNotDeleted)
+
+	// Execute use case
+	useCase := NewRestoreUseCase(mockService, repo.NewNoOpTransactionFactory(), repo.MySQLStore)
+	result, err := useCase.Execute(context.Background(), &RestoreInput{ID: 1})
+
+	// Assert results
+	assert.ErrorIs(t, err, model.ErrExampleNotDeleted)
+	assert.Nil(t, result)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestRestoreUseCase_InvalidInput tests input validation
+func TestRestoreUseCase_InvalidInput(t *testing.T) {
+	useCase := NewRestoreUseCase(new(MockExampleService), repo.NewNoOpTransactionFactory(), repo.MySQLStore)
+
+	_, err := useCase.Execute(context.Background(), &RestoreInput{ID: 0})
+	assert.Error(t, err)
+
+	_, err = useCase.Execute(context.Background(), 1)
+	assert.Error(t, err)
+}
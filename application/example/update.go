@@ -20,9 +20,10 @@ type UpdateUseCase struct {
 func NewUpdateUseCase(
 	exampleService service.IExampleService,
 	txFactory repo.TransactionFactory,
+	storeType repo.StoreType,
 ) *UpdateUseCase {
 	return &UpdateUseCase{
-		UseCaseHandler: core.NewUseCaseHandler(txFactory),
+		UseCaseHandler: core.NewUseCaseHandler(txFactory, storeType),
 		exampleService: exampleService,
 	}
 }
@@ -40,9 +41,9 @@ func (uc *UpdateUseCase) Execute(ctx context.Context, input any) (any, error) {
 	}
 
 	// Execute in transaction
-	result, err := uc.ExecuteInTransaction(ctx, repo.MySQLStore, func(ctx context.Context, tx repo.Transaction) (any, error) {
+	result, err := uc.ExecuteInTransaction(ctx, uc.StoreType, func(ctx context.Context, tx repo.Transaction) (any, error) {
 		// Call domain service to update the example
-		err := uc.exampleService.Update(ctx, updateInput.ID, updateInput.Name, updateInput.Alias)
+		err := uc.exampleService.Update(ctx, updateInput.ID, updateInput.Name, updateInput.Alias, updateInput.Version)
 		if err != nil {
 			log.SugaredLogger.Errorf("Failed to update example: %v", err)
 			return nil, fmt.Errorf("failed to update example: %w", err)
@@ -42,13 +42,14 @@ func (uc *testableUpdateUseCase) Execute(ctx context.Context, input dto.UpdateEx
 
 	// Convert DTO to domain model
 	example := &model.Example{
-		Id:    int(input.Id),
-		Name:  input.Name,
-		Alias: input.Alias,
+		Id:      int(input.Id),
+		Name:    input.Name,
+		Alias:   input.Alias,
+		Version: input.Version,
 	}
 
 	// Call domain service
-	if err := uc.exampleService.Update(ctx, example.Id, example.Name, example.Alias); err != nil {
+	if err := uc.exampleService.Update(ctx, example.Id, example.Name, example.Alias, example.Version); err != nil {
 		return fmt.Errorf("failed to update example: %w", err)
 	}
 
@@ -73,7 +74,7 @@ func TestUpdateUseCase_Execute_Success(t *testing.T) {
 	}
 
 	// Setup mock behavior
-	mockService.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockService.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	// Create testable use case
 	useCase := newTestableUpdateUseCase(mockService)
@@ -101,7 +102,7 @@ func TestUpdateUseCase_Execute_Error(t *testing.T) {
 
 	// Setup mock behavior - simulate error
 	expectedError := assert.AnError
-	mockService.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(expectedError)
+	mockService.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(expectedError)
 
 	// Create testable use case
 	useCase := newTestableUpdateUseCase(mockService)
@@ -10,42 +10,65 @@ import (
 type Factory struct {
 	exampleService service.IExampleService
 	txFactory      repo.TransactionFactory
+	storeType      repo.StoreType
 }
 
-// NewFactory creates a new application factory
+// NewFactory creates a new application factory whose use cases open their transactions on storeType
 func NewFactory(
 	exampleService service.IExampleService,
 	txFactory repo.TransactionFactory,
+	storeType repo.StoreType,
 ) *Factory {
 	return &Factory{
 		exampleService: exampleService,
 		txFactory:      txFactory,
+		storeType:      storeType,
 	}
 }
 
 // CreateExampleUseCase returns a new create example use case
 func (f *Factory) CreateExampleUseCase() *example.CreateUseCase {
-	return example.NewCreateUseCase(f.exampleService, f.txFactory)
+	return example.NewCreateUseCase(f.exampleService, f.txFactory, f.storeType)
 }
 
 // DeleteExampleUseCase returns a new delete example use case
 func (f *Factory) DeleteExampleUseCase() *example.DeleteUseCase {
-	return example.NewDeleteUseCase(f.exampleService, f.txFactory)
+	return example.NewDeleteUseCase(f.exampleService, f.txFactory, f.storeType)
+}
+
+// RestoreExampleUseCase returns a new restore example use case
+func (f *Factory) RestoreExampleUseCase() *example.RestoreUseCase {
+	return example.NewRestoreUseCase(f.exampleService, f.txFactory, f.storeType)
+}
+
+// PurgeExampleUseCase returns a new purge example use case
+func (f *Factory) PurgeExampleUseCase() *example.PurgeUseCase {
+	return example.NewPurgeUseCase(f.exampleService, f.txFactory, f.storeType)
 }
 
 // UpdateExampleUseCase returns a new update example use case
 func (f *Factory) UpdateExampleUseCase() *example.UpdateUseCase {
-	return example.NewUpdateUseCase(f.exampleService, f.txFactory)
+	return example.NewUpdateUseCase(f.exampleService, f.txFactory, f.storeType)
 }
 
 // GetExampleUseCase returns a new get example use case
 func (f *Factory) GetExampleUseCase() *example.GetUseCase {
-	return example.NewGetUseCase(f.exampleService, f.txFactory)
+	return example.NewGetUseCase(f.exampleService, f.txFactory, f.storeType)
 }
 
 // FindExampleByNameUseCase returns a new find example by name use case
 func (f *Factory) FindExampleByNameUseCase() *example.FindByNameUseCase {
-	return example.NewFindByNameUseCase(f.exampleService, f.txFactory)
+	return example.NewFindByNameUseCase(f.exampleService, f.txFactory, f.storeType)
+}
+
+// ListExamplesUseCase returns a new list examples use case
+func (f *Factory) ListExamplesUseCase() *example.ListUseCase {
+	return example.NewListUseCase(f.exampleService, f.txFactory, f.storeType)
+}
+
+// ListExamplesByCursorUseCase returns a new keyset-paginated list examples use case
+func (f *Factory) ListExamplesByCursorUseCase() *example.ListByCursorUseCase {
+	return example.NewListByCursorUseCase(f.exampleService, f.txFactory, f.storeType)
 }
 
 // CreateExampleInput creates a new create example input
@@ -0,0 +1,109 @@
+package projection
+
+import (
+	"context"
+	"fmt"
+
+	"go-hexagonal/domain/event"
+	"go-hexagonal/domain/repo"
+)
+
+// Names of the example projectors
+const (
+	ExampleDailyCountProjectorName = "example_daily_counts"
+	ExampleNameIndexProjectorName  = "example_name_index"
+)
+
+// dayLayout formats the UTC day an example was created on
+const dayLayout = "2006-01-02"
+
+// ExampleDailyCountProjector counts the examples created per day
+type ExampleDailyCountProjector struct {
+	counts repo.IExampleDailyCountRepo
+}
+
+// NewExampleDailyCountProjector creates a projector maintaining the daily example counts
+func NewExampleDailyCountProjector(counts repo.IExampleDailyCountRepo) *ExampleDailyCountProjector {
+	return &ExampleDailyCountProjector{counts: counts}
+}
+
+// Name identifies the projector
+func (p *ExampleDailyCountProjector) Name() string {
+	return ExampleDailyCountProjectorName
+}
+
+// EventNames returns the example creation event
+func (p *ExampleDailyCountProjector) EventNames() []string {
+	return []string{event.ExampleCreatedEventName}
+}
+
+// Project counts a created example on the UTC day it was created, once however often the event is applied
+func (p *ExampleDailyCountProjector) Project(ctx context.Context, evt event.Event) error {
+	day := evt.OccurredAt().UTC().Format(dayLayout)
+	return p.counts.CountEvent(ctx, day, evt.EventID())
+}
+
+// Reset removes all counts
+func (p *ExampleDailyCountProjector) Reset(ctx context.Context) error {
+	return p.counts.Reset(ctx)
+}
+
+// ExampleNameIndexProjector keeps the index used to search live examples by name
+type ExampleNameIndexProjector struct {
+	index repo.IExampleNameIndexRepo
+}
+
+// NewExampleNameIndexProjector creates a projector maintaining the example name index
+func NewExampleNameIndexProjector(index repo.IExampleNameIndexRepo) *ExampleNameIndexProjector {
+	return &ExampleNameIndexProjector{index: index}
+}
+
+// Name identifies the projector
+func (p *ExampleNameIndexProjector) Name() string {
+	return ExampleNameIndexProjectorName
+}
+
+// EventNames returns the events changing the name or visibility of an example
+func (p *ExampleNameIndexProjector) EventNames() []string {
+	return []string{
+		event.ExampleCreatedEventName, event.ExampleUpdatedEventName,
+		event.ExampleDeletedEventName, event.ExampleRestoredEventName,
+	}
+}
+
+// Project indexes created and renamed examples, and hides deleted ones until they are restored
+func (p *ExampleNameIndexProjector) Project(ctx context.Context, evt event.Event) error {
+	switch e := evt.(type) {
+	case event.ExampleCreatedEvent:
+		created, ok := e.Payload.(event.ExampleCreatedPayload)
+		if !ok {
+			return fmt.Errorf("unexpected payload %T", e.Payload)
+		}
+		return p.index.Put(ctx, created.ID, created.Name)
+	case event.ExampleUpdatedEvent:
+		updated, ok := e.Payload.(event.ExampleUpdatedPayload)
+		if !ok {
+			return fmt.Errorf("unexpected payload %T", e.Payload)
+		}
+		return p.index.Put(ctx, updated.ID, updated.Name)
+	case event.ExampleDeletedEvent:
+		deleted, ok := e.Payload.(event.ExampleDeletedPayload)
+		if !ok {
+			return fmt.Errorf("unexpected payload %T", e.Payload)
+		}
+		return p.index.Remove(ctx, deleted.ID)
+	case event.ExampleRestoredEvent:
+		restored, ok := e.Payload.(event.ExampleRestoredPayload)
+		if !ok {
+			return fmt.Errorf("unexpected payload %T", e.Payload)
+		}
+		return p.index.Restore(ctx, restored.ID)
+	default:
+		return fmt.Errorf("unexpected event %s", evt.EventName())
+	}
+}
+
+// Reset removes all entries
+func (p *ExampleNameIndexProjector) Reset(ctx context.Context) error {
+	return p.index.Reset(ctx)
+}
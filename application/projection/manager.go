@@ -0,0 +1,424 @@
+// Package projection maintains denormalized read models built from domain events
+package projection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go-hexagonal/domain/event"
+	"go-hexagonal/domain/repo"
+	"go-hexagonal/util/log"
+)
+
+// Event store reading defaults
+const (
+	// DefaultPollInterval is how often the projectors read the event store for events they were not
+	// handed by the bus, such as those published by other processes
+	DefaultPollInterval = 5 * time.Second
+	// DefaultGapTimeout is how long reading waits for a missing sequence to be committed before
+	// going past it
+	DefaultGapTimeout = 5 * time.Second
+
+	// readBatchSize is the number of events read from the store at a time
+	readBatchSize = 500
+)
+
+var (
+	// ErrUnknownProjector is returned when addressing a projector that is not registered
+	ErrUnknownProjector = errors.New("unknown projector")
+	// ErrNoEventStore is returned when rebuilding without an event store to replay
+	ErrNoEventStore = errors.New("no event store to replay")
+	// ErrNoStoredEvents is returned when rebuilding from an event store that holds no events, which
+	// would only clear the read model
+	ErrNoStoredEvents = errors.New("event store holds no events")
+)
+
+// Projector maintains a read model from the events it is interested in
+type Projector interface {
+	// Name identifies the projector and its checkpoint
+	Name() string
+	// EventNames returns the names of the events the projector applies
+	EventNames() []string
+	// Project applies an event to the read model. It must tolerate an event applied twice, which
+	// happens when the process stops between projecting the event and saving the checkpoint.
+	Project(ctx context.Context, evt event.Event) error
+	// Reset clears the read model before a rebuild
+	Reset(ctx context.Context) error
+}
+
+// Status describes how far a projector has come through the event history
+type Status struct {
+	Name        string `json:"name"`
+	LastEventID string `json:"last_event_id,omitempty"`
+	// Position is the event store sequence the projector has come to
+	Position  int64 `json:"position"`
+	Processed int64 `json:"processed"`
+	// Lag is the number of stored events of interest the projector has not applied yet
+	Lag int `json:"lag"`
+	// LagSeconds is the time between the last applied event and the latest stored event of interest
+	LagSeconds float64   `json:"lag_seconds"`
+	Rebuilding bool      `json:"rebuilding"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
+
+// Manager runs projectors: it feeds them the events of the event store, tracks their checkpoints
+// and rebuilds them by replaying the store.
+//
+// Positions are event store sequences, so events are applied in the order they were stored
+// whatever their occurrence times, and each one once. An event from the bus makes its projectors
+// read the store past their checkpoints instead of being applied directly, and the store is
+// polled for the events published by other processes. Checkpoints are saved after the read model
+// is updated, so an event may be applied twice if the process stops in between.
+//
+// A sequence becomes visible when its event is committed, so a concurrent publisher can leave a
+// gap for a short while. Reading stops at a gap until it is filled, or until the event after it
+// was stored longer than the gap timeout ago and the sequence is taken as never committed.
+type Manager struct {
+	log          event.EventLog
+	checkpoints  repo.IProjectionCheckpointRepo
+	projections  []*projection
+	byName       map[string]*projection
+	pollInterval time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewManager creates a manager for the given projectors. A nil or no-op store disables catch-up,
+// rebuilds and lag reporting, events from the bus are then applied as they come.
+func NewManager(store event.EventLog, checkpoints repo.IProjectionCheckpointRepo, projectors ...Projector) *Manager {
+	if _, noop := store.(*event.NoopEventStore); noop {
+		store = nil
+	}
+
+	m := &Manager{
+		log:          store,
+		checkpoints:  checkpoints,
+		projections:  make([]*projection, 0, len(projectors)),
+		byName:       make(map[string]*projection, len(projectors)),
+		pollInterval: DefaultPollInterval,
+		stop:         make(chan struct{}),
+	}
+	for _, projector := range projectors {
+		p := &projection{
+			projector:   projector,
+			checkpoints: checkpoints,
+			log:         store,
+			gapTimeout:  DefaultGapTimeout,
+			now:         time.Now,
+		}
+		m.projections = append(m.projections, p)
+		m.byName[projector.Name()] = p
+	}
+
+	return m
+}
+
+// Start subscribes the projectors to the bus, catches them up with the events stored since their
+// checkpoints and polls the store until ctx is done or the manager is stopped. Events published
+// while catching up wait for it and are then applied in turn.
+func (m *Manager) Start(ctx context.Context, bus event.EventBus) error {
+	for _, p := range m.projections {
+		bus.Subscribe(p)
+	}
+
+	for _, p := range m.projections {
+		if err := p.catchUp(ctx); err != nil {
+			return fmt.Errorf("failed to catch up projector %s: %w", p.projector.Name(), err)
+		}
+	}
+
+	if m.log != nil && m.pollInterval > 0 {
+		m.wg.Add(1)
+		go m.poll(ctx)
+	}
+
+	return nil
+}
+
+// Stop unsubscribes the projectors from the bus and stops polling the store
+func (m *Manager) Stop(bus event.EventBus) {
+	for _, p := range m.projections {
+		bus.Unsubscribe(p)
+	}
+
+	m.stopOnce.Do(func() { close(m.stop) })
+	m.wg.Wait()
+}
+
+// poll catches the projectors up with the store at every poll interval
+func (m *Manager) poll(ctx context.Context) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			for _, p := range m.projections {
+				if err := p.catchUp(ctx); err != nil {
+					log.Logger.Warn("Failed to catch up projection",
+						zap.String("projector", p.projector.Name()), zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+// Rebuild clears the read model of a projector and replays the whole event store through it.
+// Live events wait until the rebuild is done. Without an event store, or with one holding no
+// events, the read model is left untouched.
+func (m *Manager) Rebuild(ctx context.Context, name string) error {
+	p, ok := m.byName[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownProjector, name)
+	}
+	if m.log == nil {
+		return ErrNoEventStore
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rebuilding.Store(true)
+	defer p.rebuilding.Store(false)
+
+	first, err := m.log.ReadEvents(ctx, 0, 1)
+	if err != nil {
+		return fmt.Errorf("failed to load events: %w", err)
+	}
+	if len(first) == 0 {
+		return ErrNoStoredEvents
+	}
+
+	if err := p.projector.Reset(ctx); err != nil {
+		return fmt.Errorf("failed to reset projector %s: %w", name, err)
+	}
+	if err := m.checkpoints.DeleteCheckpoint(ctx, name); err != nil {
+		return fmt.Errorf("failed to delete checkpoint of projector %s: %w", name, err)
+	}
+	p.checkpoint = &repo.ProjectionCheckpoint{Projector: name}
+
+	log.Logger.Info("Rebuilding projection", zap.String("projector", name))
+	return p.readStore(ctx)
+}
+
+// Status returns the status of every projector, in registration order
+func (m *Manager) Status(ctx context.Context) ([]Status, error) {
+	statuses := make([]Status, 0, len(m.projections))
+	for _, p := range m.projections {
+		status, err := m.status(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// status compares the checkpoint of a projector with the event store
+func (m *Manager) status(ctx context.Context, p *projection) (Status, error) {
+	checkpoint, err := p.currentCheckpoint(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{
+		Name:        p.projector.Name(),
+		LastEventID: checkpoint.EventID,
+		Position:    checkpoint.Sequence,
+		Processed:   checkpoint.Processed,
+		Rebuilding:  p.rebuilding.Load(),
+		UpdatedAt:   checkpoint.UpdatedAt,
+	}
+	if m.log == nil {
+		return status, nil
+	}
+
+	lag, latest, err := m.log.CountEvents(ctx, checkpoint.Sequence, p.projector.EventNames())
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to count events: %w", err)
+	}
+	status.Lag = int(lag)
+	if !checkpoint.OccurredAt.IsZero() && latest.After(checkpoint.OccurredAt) {
+		status.LagSeconds = latest.Sub(checkpoint.OccurredAt).Seconds()
+	}
+
+	return status, nil
+}
+
+// projection subscribes a projector to the bus and keeps its checkpoint
+type projection struct {
+	projector   Projector
+	checkpoints repo.IProjectionCheckpointRepo
+	log         event.EventLog
+	gapTimeout  time.Duration
+	now         func() time.Time
+
+	// mu serializes live events, catch-up and rebuilds
+	mu         sync.Mutex
+	checkpoint *repo.ProjectionCheckpoint
+	rebuilding atomic.Bool
+}
+
+// HandleEvent reads the events stored past the checkpoint, which include the published one. Without
+// an event store the event is applied directly, unless it is the last one applied.
+func (p *projection) HandleEvent(ctx context.Context, evt event.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	checkpoint, err := p.loadCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	if p.log != nil {
+		return p.readStore(ctx)
+	}
+
+	if evt.EventID() == checkpoint.EventID {
+		return nil
+	}
+	next, err := p.apply(ctx, *checkpoint, evt)
+	if err != nil {
+		return err
+	}
+	return p.saveCheckpoint(ctx, next)
+}
+
+// InterestedIn reports whether the projector applies events with the given name
+func (p *projection) InterestedIn(eventName string) bool {
+	return slices.Contains(p.projector.EventNames(), eventName)
+}
+
+// catchUp applies the stored events the projector has not seen yet
+func (p *projection) catchUp(ctx context.Context) error {
+	if p.log == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.loadCheckpoint(ctx); err != nil {
+		return err
+	}
+	return p.readStore(ctx)
+}
+
+// readStore applies the events stored past the checkpoint, batch by batch, until it reaches the
+// end of the store or a gap that may still be filled, p.mu must be held and the checkpoint loaded
+func (p *projection) readStore(ctx context.Context) error {
+	for {
+		events, err := p.log.ReadEvents(ctx, p.checkpoint.Sequence, readBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to load events: %w", err)
+		}
+
+		next := *p.checkpoint
+		for _, stored := range events {
+			if stored.Sequence != next.Sequence+1 && p.now().Sub(stored.StoredAt) < p.gapTimeout {
+				// An event before this one may not be committed yet
+				return p.saveCheckpoint(ctx, next)
+			}
+
+			if p.InterestedIn(stored.Event.EventName()) {
+				if next, err = p.apply(ctx, next, stored.Event); err != nil {
+					return errors.Join(err, p.saveCheckpoint(ctx, next))
+				}
+				next.Sequence = stored.Sequence
+				if err := p.saveCheckpoint(ctx, next); err != nil {
+					return err
+				}
+				continue
+			}
+			next.Sequence = stored.Sequence
+		}
+		if err := p.saveCheckpoint(ctx, next); err != nil {
+			return err
+		}
+
+		if len(events) < readBatchSize {
+			return nil
+		}
+	}
+}
+
+// apply projects an event and returns the checkpoint advanced past it
+func (p *projection) apply(ctx context.Context, checkpoint repo.ProjectionCheckpoint, evt event.Event) (repo.ProjectionCheckpoint, error) {
+	if err := p.projector.Project(ctx, evt); err != nil {
+		return checkpoint, fmt.Errorf("projector %s failed on event %s: %w", p.projector.Name(), evt.EventID(), err)
+	}
+
+	checkpoint.EventID = evt.EventID()
+	checkpoint.OccurredAt = evt.OccurredAt()
+	checkpoint.Processed++
+	return checkpoint, nil
+}
+
+// saveCheckpoint stores the checkpoint if it moved, p.mu must be held
+func (p *projection) saveCheckpoint(ctx context.Context, checkpoint repo.ProjectionCheckpoint) error {
+	if checkpoint == *p.checkpoint {
+		return nil
+	}
+
+	checkpoint.UpdatedAt = time.Now()
+	if err := p.checkpoints.SaveCheckpoint(ctx, &checkpoint); err != nil {
+		return fmt.Errorf("failed to save checkpoint of projector %s: %w", p.projector.Name(), err)
+	}
+	p.checkpoint = &checkpoint
+
+	return nil
+}
+
+// loadCheckpoint reads the checkpoint once and keeps it in memory, p.mu must be held
+func (p *projection) loadCheckpoint(ctx context.Context) (*repo.ProjectionCheckpoint, error) {
+	if p.checkpoint != nil {
+		return p.checkpoint, nil
+	}
+
+	checkpoint, err := p.checkpoints.LoadCheckpoint(ctx, p.projector.Name())
+	if errors.Is(err, repo.ErrNotFound) {
+		checkpoint = &repo.ProjectionCheckpoint{Projector: p.projector.Name()}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint of projector %s: %w", p.projector.Name(), err)
+	}
+
+	p.checkpoint = checkpoint
+	return checkpoint, nil
+}
+
+// currentCheckpoint returns a copy of the checkpoint without waiting for a running rebuild
+func (p *projection) currentCheckpoint(ctx context.Context) (*repo.ProjectionCheckpoint, error) {
+	if p.mu.TryLock() {
+		defer p.mu.Unlock()
+		checkpoint, err := p.loadCheckpoint(ctx)
+		if err != nil {
+			return nil, err
+		}
+		current := *checkpoint
+		return &current, nil
+	}
+
+	// Busy applying events, the stored checkpoint trails the in-memory one by at most one event
+	checkpoint, err := p.checkpoints.LoadCheckpoint(ctx, p.projector.Name())
+	if errors.Is(err, repo.ErrNotFound) {
+		return &repo.ProjectionCheckpoint{Projector: p.projector.Name()}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint of projector %s: %w", p.projector.Name(), err)
+	}
+	return checkpoint, nil
+}
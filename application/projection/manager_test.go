@@ -0,0 +1,468 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/event"
+	"go-hexagonal/domain/repo"
+)
+
+// memoryEventStore keeps saved events in the order they were saved, numbering them from 1
+type memoryEventStore struct {
+	mu     sync.Mutex
+	events []event.StoredEvent
+}
+
+func (s *memoryEventStore) SaveEvent(_ context.Context, evt event.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var sequence int64 = 1
+	if len(s.events) > 0 {
+		sequence = s.events[len(s.events)-1].Sequence + 1
+	}
+	s.events = append(s.events, event.StoredEvent{Sequence: sequence, StoredAt: time.Now(), Event: evt})
+	return nil
+}
+
+// saveAt stores an event under the given sequence, in sequence order
+func (s *memoryEventStore) saveAt(sequence int64, storedAt time.Time, evt event.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event.StoredEvent{Sequence: sequence, StoredAt: storedAt, Event: evt})
+	slices.SortFunc(s.events, func(a, b event.StoredEvent) int { return int(a.Sequence - b.Sequence) })
+}
+
+func (s *memoryEventStore) GetEvents(_ context.Context, eventType string, since time.Time) ([]event.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []event.Event
+	for _, stored := range s.events {
+		evt := stored.Event
+		if (eventType == "" || evt.EventName() == eventType) && !evt.OccurredAt().Before(since) {
+			events = append(events, evt)
+		}
+	}
+	return events, nil
+}
+
+func (s *memoryEventStore) GetEventsBetween(ctx context.Context, eventType string, from, to time.Time) ([]event.Event, error) {
+	events, err := s.GetEvents(ctx, eventType, from)
+	return slices.DeleteFunc(events, func(evt event.Event) bool { return !evt.OccurredAt().Before(to) }), err
+}
+
+func (s *memoryEventStore) ReadEvents(_ context.Context, after int64, limit int) ([]event.StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []event.StoredEvent
+	for _, stored := range s.events {
+		if stored.Sequence > after && len(events) < limit {
+			events = append(events, stored)
+		}
+	}
+	return events, nil
+}
+
+func (s *memoryEventStore) CountEvents(_ context.Context, after int64, eventNames []string) (int64, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var count int64
+	var latest time.Time
+	for _, stored := range s.events {
+		if stored.Sequence > after && slices.Contains(eventNames, stored.Event.EventName()) {
+			count++
+			if stored.Event.OccurredAt().After(latest) {
+				latest = stored.Event.OccurredAt()
+			}
+		}
+	}
+	return count, latest, nil
+}
+
+func (s *memoryEventStore) MarkProcessed(context.Context, string) error {
+	return nil
+}
+
+type memoryCheckpoints struct {
+	mu          sync.Mutex
+	checkpoints map[string]repo.ProjectionCheckpoint
+}
+
+func newMemoryCheckpoints() *memoryCheckpoints {
+	return &memoryCheckpoints{checkpoints: make(map[string]repo.ProjectionCheckpoint)}
+}
+
+func (c *memoryCheckpoints) LoadCheckpoint(_ context.Context, projector string) (*repo.ProjectionCheckpoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	checkpoint, ok := c.checkpoints[projector]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	return &checkpoint, nil
+}
+
+func (c *memoryCheckpoints) SaveCheckpoint(_ context.Context, checkpoint *repo.ProjectionCheckpoint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checkpoints[checkpoint.Projector] = *checkpoint
+	return nil
+}
+
+func (c *memoryCheckpoints) DeleteCheckpoint(_ context.Context, projector string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.checkpoints, projector)
+	return nil
+}
+
+type memoryDailyCounts struct {
+	counts  map[string]int64
+	counted map[string]bool
+}
+
+func (c *memoryDailyCounts) CountEvent(_ context.Context, day string, eventID string) error {
+	if c.counts == nil {
+		c.counts = make(map[string]int64)
+		c.counted = make(map[string]bool)
+	}
+	if !c.counted[eventID] {
+		c.counted[eventID] = true
+		c.counts[day]++
+	}
+	return nil
+}
+
+func (c *memoryDailyCounts) Counts(context.Context) ([]repo.ExampleDailyCount, error) {
+	counts := make([]repo.ExampleDailyCount, 0, len(c.counts))
+	for day, count := range c.counts {
+		counts = append(counts, repo.ExampleDailyCount{Day: day, Count: count})
+	}
+	return counts, nil
+}
+
+func (c *memoryDailyCounts) Reset(context.Context) error {
+	c.counts = nil
+	c.counted = nil
+	return nil
+}
+
+// memoryNameIndex maps example IDs to names, hidden ones are removed from search results
+type memoryNameIndex struct {
+	names  map[int]string
+	hidden map[int]bool
+}
+
+func newMemoryNameIndex() *memoryNameIndex {
+	return &memoryNameIndex{names: make(map[int]string), hidden: make(map[int]bool)}
+}
+
+func (i *memoryNameIndex) Put(_ context.Context, id int, name string) error {
+	i.names[id] = name
+	return nil
+}
+
+func (i *memoryNameIndex) Remove(_ context.Context, id int) error {
+	i.hidden[id] = true
+	return nil
+}
+
+func (i *memoryNameIndex) Restore(_ context.Context, id int) error {
+	if _, ok := i.names[id]; !ok {
+		return repo.ErrNotFound
+	}
+	delete(i.hidden, id)
+	return nil
+}
+
+func (i *memoryNameIndex) Search(_ context.Context, prefix string, _ int) ([]int, error) {
+	var ids []int
+	for id, name := range i.names {
+		if !i.hidden[id] && len(name) >= len(prefix) && name[:len(prefix)] == prefix {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func (i *memoryNameIndex) Reset(context.Context) error {
+	i.names = make(map[int]string)
+	i.hidden = make(map[int]bool)
+	return nil
+}
+
+// failingProjector fails on the events it is told to
+type failingProjector struct {
+	failOn string
+}
+
+func (p *failingProjector) Name() string                { return "failing" }
+func (p *failingProjector) EventNames() []string        { return []string{event.ExampleCreatedEventName} }
+func (p *failingProjector) Reset(context.Context) error { return nil }
+func (p *failingProjector) Project(_ context.Context, evt event.Event) error {
+	if evt.EventID() == p.failOn {
+		return errors.New("projection failed")
+	}
+	return nil
+}
+
+// storeAndPublish saves events in the store and publishes them like the async bus does
+func storeAndPublish(t *testing.T, store *memoryEventStore, bus event.EventBus, events ...event.Event) {
+	t.Helper()
+	for _, evt := range events {
+		require.NoError(t, store.SaveEvent(context.Background(), evt))
+		if bus != nil {
+			require.NoError(t, bus.Publish(context.Background(), evt))
+		}
+	}
+}
+
+func TestManager_LiveEvents(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryEventStore{}
+	checkpoints := newMemoryCheckpoints()
+	counts := &memoryDailyCounts{}
+	index := newMemoryNameIndex()
+	manager := NewManager(store, checkpoints, NewExampleDailyCountProjector(counts), NewExampleNameIndexProjector(index))
+
+	bus := event.NewInMemoryEventBus()
+	require.NoError(t, manager.Start(ctx, bus))
+
+	created := event.NewExampleCreatedEvent(1, "alpha", "")
+	storeAndPublish(t, store, bus,
+		created,
+		event.NewExampleCreatedEvent(2, "beta", ""),
+		event.NewExampleUpdatedEvent(1, "gamma", ""),
+		event.NewExampleDeletedEvent(2),
+	)
+
+	day := created.OccurredAt().UTC().Format(dayLayout)
+	assert.Equal(t, map[string]int64{day: 2}, counts.counts)
+
+	ids, err := index.Search(ctx, "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, ids)
+	assert.Equal(t, "gamma", index.names[1])
+
+	// Redelivering the last event does not count it twice
+	require.NoError(t, bus.Publish(ctx, store.events[1].Event))
+	assert.Equal(t, int64(2), counts.counts[day])
+
+	statuses, err := manager.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, ExampleDailyCountProjectorName, statuses[0].Name)
+	assert.Equal(t, int64(2), statuses[0].Processed)
+	assert.Equal(t, 0, statuses[0].Lag)
+	assert.Equal(t, ExampleNameIndexProjectorName, statuses[1].Name)
+	assert.Equal(t, int64(4), statuses[1].Processed)
+	assert.Equal(t, store.events[3].Event.EventID(), statuses[1].LastEventID)
+	assert.Equal(t, int64(4), statuses[1].Position)
+
+	manager.Stop(bus)
+	storeAndPublish(t, store, bus, event.NewExampleCreatedEvent(3, "delta", ""))
+	assert.Equal(t, int64(2), counts.counts[day])
+
+	// Events stored while the projectors were not listening show up as lag
+	statuses, err = manager.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, statuses[0].Lag)
+	assert.Equal(t, 1, statuses[1].Lag)
+}
+
+func TestManager_CatchUp(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryEventStore{}
+	checkpoints := newMemoryCheckpoints()
+	counts := &memoryDailyCounts{}
+
+	storeAndPublish(t, store, nil,
+		event.NewExampleCreatedEvent(1, "alpha", ""),
+		event.NewExampleCreatedEvent(2, "beta", ""),
+	)
+
+	first := NewManager(store, checkpoints, NewExampleDailyCountProjector(counts))
+	require.NoError(t, first.Start(ctx, event.NewInMemoryEventBus()))
+	assert.Equal(t, int64(2), sum(counts))
+
+	// A restarted manager only applies what was stored after its checkpoint
+	storeAndPublish(t, store, nil, event.NewExampleCreatedEvent(3, "gamma", ""))
+	restarted := NewManager(store, checkpoints, NewExampleDailyCountProjector(counts))
+	require.NoError(t, restarted.Start(ctx, event.NewInMemoryEventBus()))
+	assert.Equal(t, int64(3), sum(counts))
+
+	statuses, err := restarted.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), statuses[0].Processed)
+	assert.Equal(t, 0, statuses[0].Lag)
+}
+
+func TestManager_Rebuild(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryEventStore{}
+	checkpoints := newMemoryCheckpoints()
+	index := newMemoryNameIndex()
+	manager := NewManager(store, checkpoints, NewExampleNameIndexProjector(index))
+
+	storeAndPublish(t, store, nil,
+		event.NewExampleCreatedEvent(1, "alpha", ""),
+		event.NewExampleDeletedEvent(1),
+		event.NewExampleRestoredEvent(1),
+	)
+
+	// Entries that do not follow from the events are dropped
+	index.names[7] = "stale"
+
+	require.NoError(t, manager.Rebuild(ctx, ExampleNameIndexProjectorName))
+	assert.Equal(t, map[int]string{1: "alpha"}, index.names)
+	assert.Empty(t, index.hidden)
+
+	statuses, err := manager.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), statuses[0].Processed)
+	assert.False(t, statuses[0].Rebuilding)
+
+	assert.ErrorIs(t, manager.Rebuild(ctx, "missing"), ErrUnknownProjector)
+}
+
+func TestManager_AppliesInStoreOrder(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryEventStore{}
+	counts := &memoryDailyCounts{}
+	index := newMemoryNameIndex()
+	manager := NewManager(store, newMemoryCheckpoints(), NewExampleDailyCountProjector(counts), NewExampleNameIndexProjector(index))
+
+	bus := event.NewInMemoryEventBus()
+	require.NoError(t, manager.Start(ctx, bus))
+	defer manager.Stop(bus)
+
+	// Events sharing an occurrence time, or stored after a later one, are all applied in store order
+	at := time.Now().Truncate(time.Second)
+	first := event.NewExampleCreatedEvent(1, "alpha", "")
+	first.OccurredOn = at
+	second := event.NewExampleCreatedEvent(2, "beta", "")
+	second.OccurredOn = at
+	renamed := event.NewExampleUpdatedEvent(1, "gamma", "")
+	renamed.OccurredOn = at.Add(time.Minute)
+	late := event.NewExampleUpdatedEvent(1, "delta", "")
+	late.OccurredOn = at.Add(-time.Minute)
+	storeAndPublish(t, store, bus, first, second, renamed, late)
+
+	assert.Equal(t, int64(2), sum(counts))
+	assert.Equal(t, "delta", index.names[1])
+
+	statuses, err := manager.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), statuses[1].Position)
+	assert.Equal(t, int64(4), statuses[1].Processed)
+	assert.Equal(t, 0, statuses[1].Lag)
+}
+
+func TestManager_WaitsForGaps(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryEventStore{}
+	counts := &memoryDailyCounts{}
+	manager := NewManager(store, newMemoryCheckpoints(), NewExampleDailyCountProjector(counts))
+	p := manager.byName[ExampleDailyCountProjectorName]
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	// Sequence 2 may still be committed, so the event after it waits
+	store.saveAt(1, now, event.NewExampleCreatedEvent(1, "alpha", ""))
+	store.saveAt(3, now, event.NewExampleCreatedEvent(3, "gamma", ""))
+	require.NoError(t, p.catchUp(ctx))
+	assert.Equal(t, int64(1), sum(counts))
+
+	store.saveAt(2, now, event.NewExampleCreatedEvent(2, "beta", ""))
+	require.NoError(t, p.catchUp(ctx))
+	assert.Equal(t, int64(3), sum(counts))
+
+	// A gap older than the gap timeout is a sequence that was never committed
+	store.saveAt(5, now, event.NewExampleCreatedEvent(5, "epsilon", ""))
+	require.NoError(t, p.catchUp(ctx))
+	assert.Equal(t, int64(3), sum(counts))
+
+	now = now.Add(DefaultGapTimeout)
+	require.NoError(t, p.catchUp(ctx))
+	assert.Equal(t, int64(4), sum(counts))
+	assert.Equal(t, int64(5), p.checkpoint.Sequence)
+}
+
+func TestManager_PollsStore(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryEventStore{}
+	counts := &memoryDailyCounts{}
+	manager := NewManager(store, newMemoryCheckpoints(), NewExampleDailyCountProjector(counts))
+	manager.pollInterval = 10 * time.Millisecond
+
+	bus := event.NewInMemoryEventBus()
+	require.NoError(t, manager.Start(ctx, bus))
+	defer manager.Stop(bus)
+
+	// Events stored by other processes never reach the bus
+	storeAndPublish(t, store, nil, event.NewExampleCreatedEvent(1, "alpha", ""))
+	assert.Eventually(t, func() bool {
+		statuses, err := manager.Status(ctx)
+		return err == nil && statuses[0].Processed == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestManager_RebuildRequiresEvents(t *testing.T) {
+	ctx := context.Background()
+	index := newMemoryNameIndex()
+	index.names[1] = "alpha"
+
+	// Neither a missing store nor an empty one clears the read model
+	for _, store := range []event.EventLog{nil, &event.NoopEventStore{}} {
+		manager := NewManager(store, newMemoryCheckpoints(), NewExampleNameIndexProjector(index))
+		assert.ErrorIs(t, manager.Rebuild(ctx, ExampleNameIndexProjectorName), ErrNoEventStore)
+	}
+	manager := NewManager(&memoryEventStore{}, newMemoryCheckpoints(), NewExampleNameIndexProjector(index))
+	assert.ErrorIs(t, manager.Rebuild(ctx, ExampleNameIndexProjectorName), ErrNoStoredEvents)
+
+	assert.Equal(t, map[int]string{1: "alpha"}, index.names)
+}
+
+func TestManager_FailedEventIsRetried(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryEventStore{}
+	checkpoints := newMemoryCheckpoints()
+	failing := &failingProjector{}
+	manager := NewManager(store, checkpoints, failing)
+
+	bus := event.NewInMemoryEventBus()
+	require.NoError(t, manager.Start(ctx, bus))
+
+	evt := event.NewExampleCreatedEvent(1, "alpha", "")
+	failing.failOn = evt.EventID()
+	require.NoError(t, store.SaveEvent(ctx, evt))
+	assert.Error(t, bus.Publish(ctx, evt))
+
+	// The checkpoint did not move, so the event is still pending
+	statuses, err := manager.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, statuses[0].Lag)
+
+	failing.failOn = ""
+	require.NoError(t, bus.Publish(ctx, evt))
+	statuses, err = manager.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, statuses[0].Lag)
+	assert.Equal(t, int64(1), statuses[0].Processed)
+}
+
+func sum(counts *memoryDailyCounts) int64 {
+	var total int64
+	for _, count := range counts.counts {
+		total += count
+	}
+	return total
+}
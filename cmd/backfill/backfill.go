@@ -0,0 +1,91 @@
+package backfill
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/adapter/repository/mysql"
+	"go-hexagonal/adapter/repository/postgre"
+	"go-hexagonal/config"
+	"go-hexagonal/util/log"
+)
+
+// CommandName is the subcommand that backfills the name keys of existing examples
+const CommandName = "backfill-name-keys"
+
+// Supported databases
+const (
+	StoreMySQL    = "mysql"
+	StorePostgres = "postgres"
+)
+
+// Run sets the name keys of the existing examples with the application's normalization, between the
+// migration adding the name_key column and the one making it unique, e.g.
+//
+//	migrate -path migrations/mysql -database "mysql://...?multiStatements=true" goto 2
+//	go-hexagonal backfill-name-keys --store mysql
+//	migrate -path migrations/mysql -database "mysql://...?multiStatements=true" up
+func Run(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet(CommandName, flag.ContinueOnError)
+	store := flags.String("store", StoreMySQL, "database holding the examples, mysql or postgres")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	db, closeDB, err := openDB(*store)
+	if err != nil {
+		return err
+	}
+	defer closeDB(ctx)
+
+	updated, err := repository.BackfillExampleNameKeys(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	log.Logger.Info("Example name keys backfilled",
+		zap.String("store", *store),
+		zap.Int("updated", updated))
+	return nil
+}
+
+// openDB connects to the configured database
+func openDB(store string) (*gorm.DB, func(context.Context), error) {
+	switch store {
+	case StoreMySQL:
+		db, err := repository.OpenGormDB()
+		if err != nil {
+			return nil, nil, err
+		}
+		client := &mysql.MySQLClient{DB: db}
+		return client.DB, closeClient(client.Close), nil
+
+	case StorePostgres:
+		pgConfig := config.GlobalConfig.Postgre
+		if pgConfig == nil {
+			return nil, nil, repository.ErrMissingPostgreSQLConfig
+		}
+		client, err := postgre.NewPostgreSQLClient(postgre.DSN(pgConfig))
+		if err != nil {
+			return nil, nil, err
+		}
+		return client.DB, closeClient(client.Close), nil
+
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", repository.ErrUnsupportedStoreType, store)
+	}
+}
+
+// closeClient adapts a client's Close to log instead of returning the error
+func closeClient(closeFn func(context.Context) error) func(context.Context) {
+	return func(ctx context.Context) {
+		if err := closeFn(ctx); err != nil {
+			log.Logger.Error("Failed to close database connection", zap.Error(err))
+		}
+	}
+}
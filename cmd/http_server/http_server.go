@@ -8,17 +8,18 @@ import (
 
 	http2 "go-hexagonal/api/http"
 	"go-hexagonal/config"
+	"go-hexagonal/domain/repo"
 	"go-hexagonal/domain/service"
 	"go-hexagonal/util/log"
 )
 
 // Start initializes and starts the HTTP server
-func Start(ctx context.Context, errChan chan error, httpCloseCh chan struct{}, services *service.Services) {
+func Start(ctx context.Context, errChan chan error, httpCloseCh chan struct{}, services *service.Services, txFactory repo.TransactionFactory, storeType repo.StoreType) {
 	// Register services for API handlers to use
 	http2.RegisterServices(services)
 
 	// Initialize application factory
-	http2.InitAppFactory(services)
+	http2.InitAppFactory(services, txFactory, storeType)
 
 	// Initialize server
 	srv := &http.Server{
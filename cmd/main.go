@@ -10,8 +10,12 @@ import (
 
 	"go-hexagonal/adapter/dependency"
 	"go-hexagonal/adapter/repository"
+	http2 "go-hexagonal/api/http"
+	"go-hexagonal/api/http/paginate"
 	"go-hexagonal/api/middleware"
+	"go-hexagonal/cmd/backfill"
 	"go-hexagonal/cmd/http_server"
+	"go-hexagonal/cmd/replay"
 	"go-hexagonal/config"
 	"go-hexagonal/util/log"
 
@@ -41,6 +45,25 @@ func main() {
 		zap.String("service", ServiceName),
 		zap.String("env", string(config.GlobalConfig.Env)))
 
+	// Subcommands share the configuration and logging, then exit
+	if len(os.Args) > 1 && os.Args[1] == replay.CommandName {
+		if err := replay.Run(context.Background(), os.Args[2:]); err != nil {
+			log.Logger.Fatal("Replay failed", zap.Error(err))
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == backfill.CommandName {
+		if err := backfill.Run(context.Background(), os.Args[2:]); err != nil {
+			log.Logger.Fatal("Backfill failed", zap.Error(err))
+		}
+		return
+	}
+
+	// Cursors must be signed with the same configured key by every instance
+	if err := paginate.CheckConfig(config.GlobalConfig.HTTPServer); err != nil {
+		log.Logger.Fatal("Invalid HTTP server configuration", zap.Error(err))
+	}
+
 	// Initialize metrics collection system
 	middleware.InitializeMetrics()
 	log.Logger.Info("Metrics collection system initialized")
@@ -67,16 +90,19 @@ func main() {
 
 	// Initialize repositories using wire dependency injection with options
 	log.Logger.Info("Initializing repositories")
-	clients, err := dependency.InitializeRepositories(
-		dependency.WithMySQL(),
-		dependency.WithRedis(),
-	)
+	// persistence.driver selects the primary store, the memory store runs without any database or cache
+	repositoryOptions := []dependency.RepositoryOption{dependency.WithPersistence()}
+	if !config.GlobalConfig.UsesMemoryStore() {
+		repositoryOptions = append(repositoryOptions, dependency.WithRedis())
+	}
+	clients, err := dependency.InitializeRepositories(repositoryOptions...)
 	if err != nil {
 		log.Logger.Fatal("Failed to initialize repositories",
 			zap.Error(err))
 	}
 	repository.Clients = clients
-	log.Logger.Info("Repositories initialized successfully")
+	log.Logger.Info("Repositories initialized successfully",
+		zap.String("persistence_driver", config.GlobalConfig.PersistenceDriver()))
 
 	// Initialize services using dependency injection
 	log.Logger.Info("Initializing services")
@@ -87,6 +113,33 @@ func main() {
 	}
 	log.Logger.Info("Services initialized successfully")
 
+	// Maintain the read models from the published events when projections are enabled
+	if config.GlobalConfig.Projections != nil && config.GlobalConfig.Projections.Enabled {
+		projections, err := dependency.ProvideProjectionManager(clients)
+		if err != nil {
+			log.Logger.Fatal("Failed to initialize projections",
+				zap.Error(err))
+		}
+		if err := projections.Start(ctx, services.EventBus); err != nil {
+			log.Logger.Fatal("Failed to start projections",
+				zap.Error(err))
+		}
+		http2.RegisterProjections(projections)
+		log.Logger.Info("Projections started")
+	}
+
+	// Use case transactions run against the initialized SQL clients, on the primary store persistence.driver selects
+	txFactory := dependency.ProvideTransactionFactory(clients)
+	storeType := dependency.ProvideStoreType()
+
+	// Run the background jobs, such as the outbox relay and the inbox cleanup, until shutdown
+	scheduler, err := dependency.ProvideScheduler(clients, services.EventBus, txFactory)
+	if err != nil {
+		log.Logger.Fatal("Failed to initialize jobs",
+			zap.Error(err))
+	}
+	scheduler.Start()
+
 	// Create error channel and HTTP close channel
 	errChan := make(chan error, 1)
 	httpCloseCh := make(chan struct{}, 1)
@@ -94,7 +147,7 @@ func main() {
 	// Start HTTP server
 	log.Logger.Info("Starting HTTP server",
 		zap.String("address", config.GlobalConfig.HTTPServer.Addr))
-	go http_server.Start(ctx, errChan, httpCloseCh, services)
+	go http_server.Start(ctx, errChan, httpCloseCh, services, txFactory, storeType)
 	log.Logger.Info("HTTP server started")
 
 	// Listen for signals
@@ -127,5 +180,7 @@ func main() {
 			zap.Duration("timeout", DefaultShutdownTimeout))
 	}
 
+	scheduler.Stop()
+
 	log.Logger.Info("Server gracefully stopped")
 }
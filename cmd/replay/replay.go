@@ -0,0 +1,152 @@
+package replay
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go-hexagonal/adapter/repository"
+	"go-hexagonal/adapter/repository/mysql"
+	"go-hexagonal/adapter/repository/postgre"
+	"go-hexagonal/config"
+	"go-hexagonal/domain/event"
+	"go-hexagonal/util/log"
+)
+
+// CommandName is the subcommand that replays stored events
+const CommandName = "replay"
+
+// DefaultDrainTimeout bounds the wait for handlers to finish the replayed events
+const DefaultDrainTimeout = 30 * time.Second
+
+// Supported event store backends
+const (
+	StoreMySQL    = "mysql"
+	StorePostgres = "postgres"
+)
+
+// Run replays the stored events of a time window through the application's event handlers, e.g.
+//
+//	go-hexagonal replay --since 2025-01-01T00:00:00Z --until 2025-01-02T00:00:00Z --type example.created
+func Run(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet(CommandName, flag.ContinueOnError)
+	eventType := flags.String("type", "", "event name to replay, all events when empty")
+	since := flags.String("since", "", "start of the window (inclusive), RFC 3339")
+	until := flags.String("until", "", "end of the window (exclusive), RFC 3339, defaults to now")
+	store := flags.String("store", StoreMySQL, "event store backend, mysql or postgres")
+	drainTimeout := flags.Duration("drain-timeout", DefaultDrainTimeout, "time to wait for handlers to finish")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	from, to, err := parseWindow(*since, *until, time.Now())
+	if err != nil {
+		return err
+	}
+
+	eventStore, closeStore, err := openEventStore(*store)
+	if err != nil {
+		return err
+	}
+	defer closeStore(ctx)
+
+	busConfig := event.DefaultAsyncEventBusConfig()
+	busConfig.EventStore = eventStore
+	bus := event.NewAsyncEventBus(busConfig)
+	bus.Subscribe(event.NewLoggingEventHandler())
+	bus.Subscribe(event.NewExampleEventHandler())
+
+	log.Logger.Info("Replaying events",
+		zap.String("store", *store),
+		zap.String("event_type", *eventType),
+		zap.Time("from", from),
+		zap.Time("to", to))
+
+	replayErr := bus.ReplayEventsBetween(ctx, *eventType, from, to)
+
+	// Let the handlers finish what was queued, also when the replay stopped early
+	if err := bus.Close(*drainTimeout); err != nil {
+		if replayErr != nil {
+			return replayErr
+		}
+		return err
+	}
+	if replayErr != nil {
+		return replayErr
+	}
+
+	log.Logger.Info("Replay completed")
+	return nil
+}
+
+// parseWindow parses the replay window, until defaults to now
+func parseWindow(since, until string, now time.Time) (time.Time, time.Time, error) {
+	if since == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("--since is required")
+	}
+
+	from, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --since: %w", err)
+	}
+
+	to := now
+	if until != "" {
+		if to, err = time.Parse(time.RFC3339, until); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--since must be before --until")
+	}
+
+	return from, to, nil
+}
+
+// openEventStore connects to the configured event store backend
+func openEventStore(store string) (event.EventStore, func(context.Context), error) {
+	switch store {
+	case StoreMySQL:
+		db, err := repository.OpenGormDB()
+		if err != nil {
+			return nil, nil, err
+		}
+		client := &mysql.MySQLClient{DB: db}
+		return mysql.NewEventStore(client), closeClient(client.Close), nil
+
+	case StorePostgres:
+		pgConfig := config.GlobalConfig.Postgre
+		if pgConfig == nil {
+			return nil, nil, repository.ErrMissingPostgreSQLConfig
+		}
+		dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+			pgConfig.Host,
+			pgConfig.Port,
+			pgConfig.User,
+			pgConfig.Password,
+			pgConfig.Database,
+			pgConfig.SSLMode,
+		)
+		client, err := postgre.NewPostgreSQLClient(dsn)
+		if err != nil {
+			return nil, nil, err
+		}
+		return postgre.NewEventStore(client), closeClient(client.Close), nil
+
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", repository.ErrUnsupportedStoreType, store)
+	}
+}
+
+// closeClient adapts a client's Close to log instead of returning the error
+func closeClient(closeFn func(context.Context) error) func(context.Context) {
+	return func(ctx context.Context) {
+		if err := closeFn(ctx); err != nil {
+			log.Logger.Error("Failed to close event store connection", zap.Error(err))
+		}
+	}
+}
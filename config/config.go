@@ -2,6 +2,7 @@ package config
 
 import (
 	"flag"
+	"net"
 	"os"
 	"strconv"
 	"strings"
@@ -16,6 +17,16 @@ import (
 // Constants
 const (
 	TrueStr = "true" // String representation of boolean true value
+
+	StoreMemory = "memory" // Store value keeping examples in process memory
+)
+
+// Persistence drivers selecting the primary store of the examples
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
 )
 
 type Env string
@@ -24,6 +35,26 @@ func (e Env) IsProd() bool {
 	return e == "prod"
 }
 
+// PersistenceDriver returns the primary store of the examples. Without persistence.driver, the legacy
+// settings still apply: store: memory selects the memory store and sqlite.enabled selects SQLite.
+func (c *Config) PersistenceDriver() string {
+	if c.Persistence != nil && c.Persistence.Driver != "" {
+		return strings.ToLower(c.Persistence.Driver)
+	}
+	if c.Store == StoreMemory {
+		return DriverMemory
+	}
+	if c.SQLite != nil && c.SQLite.Enabled {
+		return DriverSQLite
+	}
+	return DriverMySQL
+}
+
+// UsesMemoryStore reports whether examples are kept in process memory
+func (c *Config) UsesMemoryStore() bool {
+	return c.PersistenceDriver() == DriverMemory
+}
+
 var GlobalConfig *Config
 var configMutex sync.RWMutex
 var lastConfigChangeTime time.Time
@@ -44,8 +75,21 @@ type Config struct {
 	MySQL         *MySQLConfig      `yaml:"mysql" mapstructure:"mysql"`
 	Redis         *RedisConfig      `yaml:"redis" mapstructure:"redis"`
 	Postgre       *PostgreSQLConfig `yaml:"postgres" mapstructure:"postgres"`
+	SQLite        *SQLiteConfig     `yaml:"sqlite" mapstructure:"sqlite"`
 	MongoDB       *MongoDBConfig    `yaml:"mongodb" mapstructure:"mongodb"`
 	MigrationDir  string            `yaml:"migration_dir" mapstructure:"migration_dir"`
+	// Persistence selects the primary store of the examples
+	Persistence *PersistenceConfig `yaml:"persistence" mapstructure:"persistence"`
+	// Store: memory is the legacy way to select the memory store, prefer persistence.driver
+	Store string `yaml:"store" mapstructure:"store"`
+	// EventSourcing stores examples as event streams instead of rows when enabled
+	EventSourcing *EventSourcingConfig `yaml:"event_sourcing" mapstructure:"event_sourcing"`
+	// Projections maintains the read models built from domain events when enabled
+	Projections *ProjectionsConfig `yaml:"projections" mapstructure:"projections"`
+	// Outbox tunes the relay delivering the events recorded in the MySQL or PostgreSQL outbox
+	Outbox *OutboxConfig `yaml:"outbox" mapstructure:"outbox"`
+	// Inbox tunes how long event handlers remember the events they processed
+	Inbox *InboxConfig `yaml:"inbox" mapstructure:"inbox"`
 }
 
 type AppConfig struct {
@@ -59,8 +103,11 @@ type HttpServerConfig struct {
 	Pprof           bool   `yaml:"pprof" mapstructure:"pprof"`
 	DefaultPageSize int    `yaml:"default_page_size" mapstructure:"default_page_size"`
 	MaxPageSize     int    `yaml:"max_page_size" mapstructure:"max_page_size"`
-	ReadTimeout     string `yaml:"read_timeout" mapstructure:"read_timeout"`
-	WriteTimeout    string `yaml:"write_timeout" mapstructure:"write_timeout"`
+	// CursorPagination enables keyset pagination with signed cursors, it requires CursorSecret
+	CursorPagination bool   `yaml:"cursor_pagination" mapstructure:"cursor_pagination"`
+	CursorSecret     string `yaml:"cursor_secret" mapstructure:"cursor_secret"`
+	ReadTimeout      string `yaml:"read_timeout" mapstructure:"read_timeout"`
+	WriteTimeout     string `yaml:"write_timeout" mapstructure:"write_timeout"`
 }
 
 type MetricsConfig struct {
@@ -96,6 +143,8 @@ type MySQLConfig struct {
 	CharSet      string `yaml:"char_set" mapstructure:"char_set"`
 	ParseTime    bool   `yaml:"parse_time" mapstructure:"parse_time"`
 	TimeZone     string `yaml:"time_zone" mapstructure:"time_zone"`
+	// ReadReplicas optionally routes read-only operations to replicas of the database
+	ReadReplicas *ReadReplicasConfig `yaml:"read_replicas" mapstructure:"read_replicas"`
 }
 
 type PostgreSQLConfig struct {
@@ -112,6 +161,67 @@ type PostgreSQLConfig struct {
 	IdleTimeout     int    `yaml:"idle_timeout" mapstructure:"idle_timeout"`
 	ConnectTimeout  int    `yaml:"connect_timeout" mapstructure:"connect_timeout"`
 	TimeZone        string `yaml:"time_zone" mapstructure:"time_zone"`
+	// ReadReplicas optionally routes read-only operations to replicas of the database
+	ReadReplicas *ReadReplicasConfig `yaml:"read_replicas" mapstructure:"read_replicas"`
+}
+
+type ReadReplicasConfig struct {
+	Replicas []ReplicaConfig `yaml:"replicas" mapstructure:"replicas"`
+	// HealthCheckInterval is how often replicas are pinged, unhealthy ones get no reads until they answer again
+	HealthCheckInterval string `yaml:"health_check_interval" mapstructure:"health_check_interval"`
+	// StickyWindow is how long the reads of a request go to the primary after the request wrote
+	StickyWindow string `yaml:"sticky_window" mapstructure:"sticky_window"`
+}
+
+// ReplicaConfig describes a read replica, empty fields are taken from the primary
+type ReplicaConfig struct {
+	Host     string `yaml:"host" mapstructure:"host"`
+	Port     int    `yaml:"port" mapstructure:"port"`
+	User     string `yaml:"user" mapstructure:"user"`
+	Password string `yaml:"password" mapstructure:"password"`
+}
+
+// Replica returns the configuration of a replica of the MySQL database
+func (c *MySQLConfig) Replica(replica ReplicaConfig) *MySQLConfig {
+	cfg := *c
+	cfg.Host, cfg.Port, cfg.User, cfg.Password = replica.merge(c.Host, c.Port, c.User, c.Password)
+	cfg.ReadReplicas = nil
+	return &cfg
+}
+
+// Replica returns the configuration of a replica of the PostgreSQL database
+func (c *PostgreSQLConfig) Replica(replica ReplicaConfig) *PostgreSQLConfig {
+	cfg := *c
+	cfg.Host, cfg.Port, cfg.User, cfg.Password = replica.merge(c.Host, c.Port, c.User, c.Password)
+	cfg.ReadReplicas = nil
+	return &cfg
+}
+
+// merge fills the empty fields of the replica with those of its primary
+func (r ReplicaConfig) merge(host string, port int, user, password string) (string, int, string, string) {
+	if r.Host != "" {
+		host = r.Host
+	}
+	if r.Port != 0 {
+		port = r.Port
+	}
+	if r.User != "" {
+		user = r.User
+	}
+	if r.Password != "" {
+		password = r.Password
+	}
+	return host, port, user, password
+}
+
+type SQLiteConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+	// Path is the database file, ":memory:" keeps the database in memory
+	Path string `yaml:"path" mapstructure:"path"`
+	// BusyTimeout is how long, in milliseconds, a statement waits for a lock held by another connection
+	BusyTimeout  int    `yaml:"busy_timeout" mapstructure:"busy_timeout"`
+	JournalMode  string `yaml:"journal_mode" mapstructure:"journal_mode"`
+	MaxOpenConns int    `yaml:"max_open_conns" mapstructure:"max_open_conns"`
 }
 
 type RedisConfig struct {
@@ -124,6 +234,37 @@ type RedisConfig struct {
 	MinIdleConns int    `yaml:"minIdleConns" mapstructure:"minIdleConns"`
 }
 
+type PersistenceConfig struct {
+	// Driver is one of mysql, postgres, sqlite or memory, its config section must be present
+	Driver string `yaml:"driver" mapstructure:"driver"`
+}
+
+type EventSourcingConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+	// SnapshotInterval is the number of events between snapshots of a stream, negative disables snapshots
+	SnapshotInterval int `yaml:"snapshot_interval" mapstructure:"snapshot_interval"`
+}
+
+type ProjectionsConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+}
+
+type OutboxConfig struct {
+	// RelaySchedule is the cron spec, with seconds, of the relay runs
+	RelaySchedule string `yaml:"relay_schedule" mapstructure:"relay_schedule"`
+	// BatchSize is the number of messages delivered per transaction
+	BatchSize int `yaml:"batch_size" mapstructure:"batch_size"`
+	// MaxAttempts is the number of failed deliveries after which a message is parked
+	MaxAttempts int `yaml:"max_attempts" mapstructure:"max_attempts"`
+}
+
+type InboxConfig struct {
+	// Retention is how long processed events are remembered, e.g. "168h"
+	Retention string `yaml:"retention" mapstructure:"retention"`
+	// CleanupSchedule is the cron spec, with seconds, of the runs removing expired records
+	CleanupSchedule string `yaml:"cleanup_schedule" mapstructure:"cleanup_schedule"`
+}
+
 type MongoDBConfig struct {
 	Host        string `yaml:"host" mapstructure:"host"`
 	Port        int    `yaml:"port" mapstructure:"port"`
@@ -187,16 +328,27 @@ func applyEnvOverrides(conf *Config) {
 	applyAppEnvOverrides(conf)
 	applyHTTPServerEnvOverrides(conf)
 	applyMetricsServerEnvOverrides(conf)
+	applyPersistenceEnvOverrides(conf)
 	applyMySQLEnvOverrides(conf)
 	applyPostgresEnvOverrides(conf)
+	applySQLiteEnvOverrides(conf)
 	applyRedisEnvOverrides(conf)
 	applyMongoDBEnvOverrides(conf)
 	applyLogEnvOverrides(conf)
+	applyEventSourcingEnvOverrides(conf)
+	applyProjectionsEnvOverrides(conf)
+	applyOutboxEnvOverrides(conf)
+	applyInboxEnvOverrides(conf)
 
 	// Migration directory
 	if migrationDir := os.Getenv("APP_MIGRATION_DIR"); migrationDir != "" {
 		conf.MigrationDir = migrationDir
 	}
+
+	// Example store
+	if store := os.Getenv("APP_STORE"); store != "" {
+		conf.Store = store
+	}
 }
 
 // applyAppEnvOverrides applies App related environment variables
@@ -236,6 +388,12 @@ func applyHTTPServerEnvOverrides(conf *Config) {
 			conf.HTTPServer.MaxPageSize = val
 		}
 	}
+	if cursorPagination := os.Getenv("APP_HTTP_SERVER_CURSOR_PAGINATION"); cursorPagination != "" {
+		conf.HTTPServer.CursorPagination = cursorPagination == TrueStr
+	}
+	if cursorSecret := os.Getenv("APP_HTTP_SERVER_CURSOR_SECRET"); cursorSecret != "" {
+		conf.HTTPServer.CursorSecret = cursorSecret
+	}
 	if readTimeout := os.Getenv("APP_HTTP_SERVER_READ_TIMEOUT"); readTimeout != "" {
 		conf.HTTPServer.ReadTimeout = readTimeout
 	}
@@ -266,8 +424,27 @@ func applyMetricsServerEnvOverrides(conf *Config) {
 	}
 }
 
+// applyPersistenceEnvOverrides applies persistence related environment variables
+func applyPersistenceEnvOverrides(conf *Config) {
+	if conf.Persistence == nil {
+		conf.Persistence = &PersistenceConfig{}
+	}
+
+	if driver := os.Getenv("APP_PERSISTENCE_DRIVER"); driver != "" {
+		conf.Persistence.Driver = driver
+	}
+}
+
 // applyMySQLEnvOverrides applies MySQL related environment variables
 func applyMySQLEnvOverrides(conf *Config) {
+	// A missing section stays missing unless the environment configures it
+	if conf.MySQL == nil {
+		if !hasEnvPrefix("APP_MYSQL_") {
+			return
+		}
+		conf.MySQL = &MySQLConfig{}
+	}
+
 	if host := os.Getenv("APP_MYSQL_HOST"); host != "" {
 		conf.MySQL.Host = host
 	}
@@ -310,10 +487,20 @@ func applyMySQLEnvOverrides(conf *Config) {
 	if timeZone := os.Getenv("APP_MYSQL_TIME_ZONE"); timeZone != "" {
 		conf.MySQL.TimeZone = timeZone
 	}
+	if replicas := os.Getenv("APP_MYSQL_REPLICAS"); replicas != "" {
+		conf.MySQL.ReadReplicas = withReplicaHosts(conf.MySQL.ReadReplicas, replicas)
+	}
 }
 
 // applyPostgresEnvOverrides applies PostgreSQL related environment variables
 func applyPostgresEnvOverrides(conf *Config) {
+	if conf.Postgre == nil {
+		if !hasEnvPrefix("APP_POSTGRES_") {
+			return
+		}
+		conf.Postgre = &PostgreSQLConfig{}
+	}
+
 	if host := os.Getenv("APP_POSTGRES_HOST"); host != "" {
 		conf.Postgre.Host = host
 	}
@@ -365,10 +552,47 @@ func applyPostgresEnvOverrides(conf *Config) {
 	if timeZone := os.Getenv("APP_POSTGRES_TIME_ZONE"); timeZone != "" {
 		conf.Postgre.TimeZone = timeZone
 	}
+	if replicas := os.Getenv("APP_POSTGRES_REPLICAS"); replicas != "" {
+		conf.Postgre.ReadReplicas = withReplicaHosts(conf.Postgre.ReadReplicas, replicas)
+	}
+}
+
+// applySQLiteEnvOverrides applies SQLite related environment variables
+func applySQLiteEnvOverrides(conf *Config) {
+	if conf.SQLite == nil {
+		conf.SQLite = &SQLiteConfig{}
+	}
+
+	if enabled := os.Getenv("APP_SQLITE_ENABLED"); enabled != "" {
+		conf.SQLite.Enabled = enabled == TrueStr
+	}
+	if path := os.Getenv("APP_SQLITE_PATH"); path != "" {
+		conf.SQLite.Path = path
+	}
+	if busyTimeout := os.Getenv("APP_SQLITE_BUSY_TIMEOUT"); busyTimeout != "" {
+		if val, err := strconv.Atoi(busyTimeout); err == nil {
+			conf.SQLite.BusyTimeout = val
+		}
+	}
+	if journalMode := os.Getenv("APP_SQLITE_JOURNAL_MODE"); journalMode != "" {
+		conf.SQLite.JournalMode = journalMode
+	}
+	if maxOpenConns := os.Getenv("APP_SQLITE_MAX_OPEN_CONNS"); maxOpenConns != "" {
+		if val, err := strconv.Atoi(maxOpenConns); err == nil {
+			conf.SQLite.MaxOpenConns = val
+		}
+	}
 }
 
 // applyRedisEnvOverrides applies Redis related environment variables
 func applyRedisEnvOverrides(conf *Config) {
+	if conf.Redis == nil {
+		if !hasEnvPrefix("APP_REDIS_") {
+			return
+		}
+		conf.Redis = &RedisConfig{}
+	}
+
 	if host := os.Getenv("APP_REDIS_HOST"); host != "" {
 		conf.Redis.Host = host
 	}
@@ -402,6 +626,63 @@ func applyRedisEnvOverrides(conf *Config) {
 	}
 }
 
+// applyEventSourcingEnvOverrides applies event sourcing related environment variables
+func applyEventSourcingEnvOverrides(conf *Config) {
+	if conf.EventSourcing == nil {
+		conf.EventSourcing = &EventSourcingConfig{}
+	}
+
+	if enabled := os.Getenv("APP_EVENT_SOURCING_ENABLED"); enabled != "" {
+		conf.EventSourcing.Enabled = enabled == TrueStr
+	}
+	if interval := os.Getenv("APP_EVENT_SOURCING_SNAPSHOT_INTERVAL"); interval != "" {
+		if val, err := strconv.Atoi(interval); err == nil {
+			conf.EventSourcing.SnapshotInterval = val
+		}
+	}
+}
+
+// applyProjectionsEnvOverrides applies projection related environment variables
+func applyProjectionsEnvOverrides(conf *Config) {
+	if conf.Projections == nil {
+		conf.Projections = &ProjectionsConfig{}
+	}
+
+	if enabled := os.Getenv("APP_PROJECTIONS_ENABLED"); enabled != "" {
+		conf.Projections.Enabled = enabled == TrueStr
+	}
+}
+
+// applyOutboxEnvOverrides applies outbox relay related environment variables
+func applyOutboxEnvOverrides(conf *Config) {
+	if conf.Outbox == nil {
+		conf.Outbox = &OutboxConfig{}
+	}
+
+	if schedule := os.Getenv("APP_OUTBOX_RELAY_SCHEDULE"); schedule != "" {
+		conf.Outbox.RelaySchedule = schedule
+	}
+	if maxAttempts := os.Getenv("APP_OUTBOX_MAX_ATTEMPTS"); maxAttempts != "" {
+		if val, err := strconv.Atoi(maxAttempts); err == nil {
+			conf.Outbox.MaxAttempts = val
+		}
+	}
+}
+
+// applyInboxEnvOverrides applies inbox related environment variables
+func applyInboxEnvOverrides(conf *Config) {
+	if conf.Inbox == nil {
+		conf.Inbox = &InboxConfig{}
+	}
+
+	if retention := os.Getenv("APP_INBOX_RETENTION"); retention != "" {
+		conf.Inbox.Retention = retention
+	}
+	if schedule := os.Getenv("APP_INBOX_CLEANUP_SCHEDULE"); schedule != "" {
+		conf.Inbox.CleanupSchedule = schedule
+	}
+}
+
 // applyMongoDBEnvOverrides applies MongoDB related environment variables
 func applyMongoDBEnvOverrides(conf *Config) {
 	if conf.MongoDB == nil {
@@ -505,3 +786,36 @@ func Init(path, file string) {
 func GetDuration(durationStr string) time.Duration {
 	return cast.ToDuration(durationStr)
 }
+
+// withReplicaHosts replaces the replicas of cfg by a comma separated list of host or host:port entries,
+// keeping its other settings
+func withReplicaHosts(cfg *ReadReplicasConfig, hosts string) *ReadReplicasConfig {
+	if cfg == nil {
+		cfg = &ReadReplicasConfig{}
+	}
+	cfg.Replicas = nil
+	for _, entry := range strings.Split(hosts, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		replica := ReplicaConfig{Host: entry}
+		if host, port, err := net.SplitHostPort(entry); err == nil {
+			if val, err := strconv.Atoi(port); err == nil {
+				replica = ReplicaConfig{Host: host, Port: val}
+			}
+		}
+		cfg.Replicas = append(cfg.Replicas, replica)
+	}
+	return cfg
+}
+
+// hasEnvPrefix reports whether any environment variable name starts with prefix
+func hasEnvPrefix(prefix string) bool {
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, prefix) {
+			return true
+		}
+	}
+	return false
+}
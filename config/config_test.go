@@ -40,6 +40,18 @@ func TestConfigEnvOverrides(t *testing.T) {
 	_ = os.Setenv("APP_MYSQL_PORT", "3307")
 	_ = os.Setenv("APP_REDIS_HOST", "test-redis-host")
 	_ = os.Setenv("APP_LOG_COMPRESS", "true")
+	_ = os.Setenv("APP_EVENT_SOURCING_ENABLED", "true")
+	_ = os.Setenv("APP_EVENT_SOURCING_SNAPSHOT_INTERVAL", "10")
+	_ = os.Setenv("APP_PROJECTIONS_ENABLED", "true")
+	_ = os.Setenv("APP_OUTBOX_RELAY_SCHEDULE", "@every 5s")
+	_ = os.Setenv("APP_OUTBOX_MAX_ATTEMPTS", "3")
+	_ = os.Setenv("APP_INBOX_RETENTION", "24h")
+	_ = os.Setenv("APP_HTTP_SERVER_CURSOR_PAGINATION", "false")
+	_ = os.Setenv("APP_INBOX_CLEANUP_SCHEDULE", "@every 10m")
+	_ = os.Setenv("APP_SQLITE_ENABLED", "true")
+	_ = os.Setenv("APP_SQLITE_PATH", "/var/lib/app/test.db")
+	_ = os.Setenv("APP_SQLITE_BUSY_TIMEOUT", "1000")
+	_ = os.Setenv("APP_PERSISTENCE_DRIVER", "postgres")
 
 	// Load config
 	conf, err := Load("./", "config.yaml")
@@ -55,6 +67,18 @@ func TestConfigEnvOverrides(t *testing.T) {
 		_ = os.Unsetenv("APP_MYSQL_PORT")
 		_ = os.Unsetenv("APP_REDIS_HOST")
 		_ = os.Unsetenv("APP_LOG_COMPRESS")
+		_ = os.Unsetenv("APP_EVENT_SOURCING_ENABLED")
+		_ = os.Unsetenv("APP_EVENT_SOURCING_SNAPSHOT_INTERVAL")
+		_ = os.Unsetenv("APP_PROJECTIONS_ENABLED")
+		_ = os.Unsetenv("APP_OUTBOX_RELAY_SCHEDULE")
+		_ = os.Unsetenv("APP_OUTBOX_MAX_ATTEMPTS")
+		_ = os.Unsetenv("APP_INBOX_RETENTION")
+		_ = os.Unsetenv("APP_HTTP_SERVER_CURSOR_PAGINATION")
+		_ = os.Unsetenv("APP_INBOX_CLEANUP_SCHEDULE")
+		_ = os.Unsetenv("APP_SQLITE_ENABLED")
+		_ = os.Unsetenv("APP_SQLITE_PATH")
+		_ = os.Unsetenv("APP_SQLITE_BUSY_TIMEOUT")
+		_ = os.Unsetenv("APP_PERSISTENCE_DRIVER")
 	}()
 
 	// Verify environment variables were applied correctly
@@ -62,10 +86,64 @@ func TestConfigEnvOverrides(t *testing.T) {
 	assert.Equal(t, "test-app", conf.App.Name)
 	assert.False(t, conf.App.Debug)
 	assert.Equal(t, ":4000", conf.HTTPServer.Addr)
+	assert.False(t, conf.HTTPServer.CursorPagination)
 	assert.Equal(t, "test-mysql-host", conf.MySQL.Host)
 	assert.Equal(t, 3307, conf.MySQL.Port)
 	assert.Equal(t, "test-redis-host", conf.Redis.Host)
 	assert.True(t, conf.Log.Compress)
+	assert.True(t, conf.EventSourcing.Enabled)
+	assert.Equal(t, 10, conf.EventSourcing.SnapshotInterval)
+	assert.True(t, conf.Projections.Enabled)
+	assert.Equal(t, "@every 5s", conf.Outbox.RelaySchedule)
+	assert.Equal(t, 3, conf.Outbox.MaxAttempts)
+	assert.Equal(t, "24h", conf.Inbox.Retention)
+	assert.Equal(t, "@every 10m", conf.Inbox.CleanupSchedule)
+	assert.True(t, conf.SQLite.Enabled)
+	assert.Equal(t, "/var/lib/app/test.db", conf.SQLite.Path)
+	assert.Equal(t, 1000, conf.SQLite.BusyTimeout)
+	assert.Equal(t, "WAL", conf.SQLite.JournalMode)
+	assert.Equal(t, DriverPostgres, conf.PersistenceDriver())
+}
+
+// TestPersistenceDriver tests the selection of the primary store, including the legacy settings
+func TestPersistenceDriver(t *testing.T) {
+	tests := []struct {
+		name string
+		conf Config
+		want string
+	}{
+		{name: "default", conf: Config{}, want: DriverMySQL},
+		{name: "driver", conf: Config{Persistence: &PersistenceConfig{Driver: "Postgres"}}, want: DriverPostgres},
+		{name: "legacy store", conf: Config{Store: StoreMemory}, want: DriverMemory},
+		{name: "legacy sqlite", conf: Config{SQLite: &SQLiteConfig{Enabled: true}}, want: DriverSQLite},
+		{
+			name: "driver wins over legacy",
+			conf: Config{Persistence: &PersistenceConfig{Driver: DriverMySQL}, Store: StoreMemory},
+			want: DriverMySQL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.conf.PersistenceDriver())
+		})
+	}
+}
+
+// TestMissingSectionsStayMissing tests that environment overrides do not invent absent sections
+func TestMissingSectionsStayMissing(t *testing.T) {
+	conf := &Config{}
+	applyMySQLEnvOverrides(conf)
+	applyPostgresEnvOverrides(conf)
+	applyRedisEnvOverrides(conf)
+	assert.Nil(t, conf.MySQL)
+	assert.Nil(t, conf.Postgre)
+	assert.Nil(t, conf.Redis)
+
+	t.Setenv("APP_POSTGRES_HOST", "db.internal")
+	applyPostgresEnvOverrides(conf)
+	require.NotNil(t, conf.Postgre)
+	assert.Equal(t, "db.internal", conf.Postgre.Host)
 }
 
 // TestConfigWatchChanges tests the config file change monitoring feature
@@ -191,3 +269,32 @@ func TestEnvIsProd(t *testing.T) {
 		})
 	}
 }
+
+func TestReadReplicas(t *testing.T) {
+	t.Setenv("APP_MYSQL_REPLICAS", "replica-1:3307, replica-2,")
+	conf := &Config{MySQL: &MySQLConfig{
+		Host: "primary", Port: 3306, User: "app", Password: "secret", Database: "db",
+		ReadReplicas: &ReadReplicasConfig{StickyWindow: "5s"},
+	}}
+	applyMySQLEnvOverrides(conf)
+
+	replicas := conf.MySQL.ReadReplicas
+	require.NotNil(t, replicas)
+	assert.Equal(t, "5s", replicas.StickyWindow)
+	assert.Equal(t, []ReplicaConfig{{Host: "replica-1", Port: 3307}, {Host: "replica-2"}}, replicas.Replicas)
+
+	// Replicas inherit what they leave empty from the primary
+	replica := conf.MySQL.Replica(replicas.Replicas[1])
+	assert.Equal(t, "replica-2", replica.Host)
+	assert.Equal(t, 3306, replica.Port)
+	assert.Equal(t, "app", replica.User)
+	assert.Equal(t, "db", replica.Database)
+	assert.Nil(t, replica.ReadReplicas)
+	assert.Equal(t, "primary", conf.MySQL.Host)
+
+	pg := (&PostgreSQLConfig{Host: "primary", Port: 5432, User: "app"}).Replica(ReplicaConfig{Host: "standby", User: "reader", Password: "ro"})
+	assert.Equal(t, "standby", pg.Host)
+	assert.Equal(t, 5432, pg.Port)
+	assert.Equal(t, "reader", pg.User)
+	assert.Equal(t, "ro", pg.Password)
+}
@@ -3,25 +3,51 @@ package event
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
+	"strconv"
 	"sync"
 	"time"
 
 	"go-hexagonal/util/errors"
 	"go-hexagonal/util/log"
+	"go-hexagonal/util/metrics"
 
 	"go.uber.org/zap"
 )
 
 // EventStore defines the interface for persisting events
 type EventStore interface {
-	// SaveEvent persists an event
+	// SaveEvent persists an event, saving an event that is already stored is a no-op
 	SaveEvent(ctx context.Context, event Event) error
-	// GetEvents retrieves events by type
+	// GetEvents retrieves events by type that occurred at or after since, an empty type matches all events
 	GetEvents(ctx context.Context, eventType string, since time.Time) ([]Event, error)
+	// GetEventsBetween retrieves events by type that occurred in [from, to), in the order they occurred
+	GetEventsBetween(ctx context.Context, eventType string, from, to time.Time) ([]Event, error)
 	// MarkProcessed marks an event as processed
 	MarkProcessed(ctx context.Context, eventID string) error
 }
 
+// StoredEvent is an event with the position the event store gave it
+type StoredEvent struct {
+	// Sequence increases with every stored event, in the order the events were stored
+	Sequence int64
+	// StoredAt is when the event was stored
+	StoredAt time.Time
+	Event    Event
+}
+
+// EventLog is an event store that can be read in the order the events were stored
+type EventLog interface {
+	EventStore
+	// ReadEvents returns up to limit events stored after the given sequence, in sequence order.
+	// Sequences may have gaps, and a sequence is visible once the event is committed, so an
+	// event can appear behind one with a higher sequence for a short while.
+	ReadEvents(ctx context.Context, after int64, limit int) ([]StoredEvent, error)
+	// CountEvents returns the number of events with the given names stored after the given sequence,
+	// and the latest time one of them occurred, zero when there is none
+	CountEvents(ctx context.Context, after int64, eventNames []string) (int64, time.Time, error)
+}
+
 // NoopEventStore is a no-operation event store
 type NoopEventStore struct{}
 
@@ -35,28 +61,92 @@ func (s *NoopEventStore) GetEvents(ctx context.Context, eventType string, since
 	return []Event{}, nil
 }
 
+// GetEventsBetween returns an empty slice
+func (s *NoopEventStore) GetEventsBetween(ctx context.Context, eventType string, from, to time.Time) ([]Event, error) {
+	return []Event{}, nil
+}
+
+// ReadEvents returns an empty slice
+func (s *NoopEventStore) ReadEvents(ctx context.Context, after int64, limit int) ([]StoredEvent, error) {
+	return []StoredEvent{}, nil
+}
+
+// CountEvents returns zero
+func (s *NoopEventStore) CountEvents(ctx context.Context, after int64, eventNames []string) (int64, time.Time, error) {
+	return 0, time.Time{}, nil
+}
+
 // MarkProcessed does nothing and returns nil
 func (s *NoopEventStore) MarkProcessed(ctx context.Context, eventID string) error {
 	return nil
 }
 
+// OrderingMode controls the order in which AsyncEventBus hands events to handlers
+type OrderingMode int
+
+const (
+	// OrderingNone processes events concurrently, events may be handled in any order
+	OrderingNone OrderingMode = iota
+	// OrderingPerAggregate shards events by aggregate ID onto the workers, so the events of an
+	// aggregate are handled one after another in publish order while aggregates run in parallel
+	OrderingPerAggregate
+)
+
+// BackpressurePolicy controls what Publish does when the queue is full
+type BackpressurePolicy int
+
+const (
+	// BackpressureError rejects the event with an error
+	BackpressureError BackpressurePolicy = iota
+	// BackpressureBlock waits for queue space until the context is done
+	BackpressureBlock
+	// BackpressureDrop discards the event and reports success
+	BackpressureDrop
+)
+
+// asyncEventBusMetricName labels the metrics of AsyncEventBus
+const asyncEventBusMetricName = "async_event_bus"
+
 // AsyncEventBus implements an asynchronous event bus
 type AsyncEventBus struct {
-	handlers   []EventHandler
-	store      EventStore
-	mu         sync.RWMutex
-	eventQueue chan Event
-	workerPool chan struct{} // Semaphore for limiting concurrent workers
-	quit       chan struct{}
-	wg         sync.WaitGroup
+	handlers      []EventHandler
+	store         EventStore
+	retry         RetryPolicy
+	deadLetter    DeadLetterSink
+	backpressure  BackpressurePolicy
+	errorCallback func(event Event, err error)
+	mu            sync.RWMutex
+	eventQueue    chan Event
+	shards        []chan Event  // Per-worker queues in OrderingPerAggregate mode
+	workerPool    chan struct{} // Semaphore for limiting concurrent workers
+	quit          chan struct{}
+	wg            sync.WaitGroup
+	// closing is closed when Close starts and releases publishers waiting for queue space.
+	// Publishers enqueue under the read lock of closeMu, Close stops the workers under its write
+	// lock, so every event a publisher enqueued is processed before the workers stop.
+	closing   chan struct{}
+	closeMu   sync.RWMutex
+	closeOnce sync.Once
+	// ctx lives as long as the bus, handlers run with it and Close cancels it
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // AsyncEventBusConfig holds configuration for AsyncEventBus
 type AsyncEventBusConfig struct {
-	QueueSize     int
-	WorkerCount   int
-	EventStore    EventStore
-	ErrorCallback func(event Event, err error)
+	// QueueSize bounds the number of queued events, in OrderingPerAggregate mode it is split across the workers
+	QueueSize   int
+	WorkerCount int
+	EventStore  EventStore
+	// Ordering selects concurrent or per-aggregate ordered processing
+	Ordering OrderingMode
+	// Backpressure selects how Publish behaves when the queue is full
+	Backpressure BackpressurePolicy
+	// RetryPolicy applies to each handler separately
+	RetryPolicy RetryPolicy
+	// DeadLetterSink receives events a handler still fails on after all retries, they are dropped when nil
+	DeadLetterSink DeadLetterSink
+	ErrorCallback  func(event Event, err error)
 }
 
 // DefaultAsyncEventBusConfig returns the default configuration
@@ -65,6 +155,7 @@ func DefaultAsyncEventBusConfig() *AsyncEventBusConfig {
 		QueueSize:   100,
 		WorkerCount: 5,
 		EventStore:  &NoopEventStore{},
+		RetryPolicy: DefaultRetryPolicy(),
 		ErrorCallback: func(event Event, err error) {
 			logCtx := log.NewLogContext().
 				WithComponent("AsyncEventBus").
@@ -92,71 +183,304 @@ func NewAsyncEventBus(config *AsyncEventBusConfig) *AsyncEventBus {
 		config = DefaultAsyncEventBusConfig()
 	}
 
+	workerCount := max(config.WorkerCount, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+
 	bus := &AsyncEventBus{
-		handlers:   make([]EventHandler, 0),
-		store:      config.EventStore,
-		eventQueue: make(chan Event, config.QueueSize),
-		workerPool: make(chan struct{}, config.WorkerCount),
-		quit:       make(chan struct{}),
+		handlers:      make([]EventHandler, 0),
+		store:         config.EventStore,
+		retry:         config.RetryPolicy,
+		deadLetter:    config.DeadLetterSink,
+		backpressure:  config.Backpressure,
+		errorCallback: config.ErrorCallback,
+		workerPool:    make(chan struct{}, workerCount),
+		quit:          make(chan struct{}),
+		closing:       make(chan struct{}),
+		ctx:           ctx,
+		cancel:        cancel,
 	}
 
 	// Start workers
-	bus.startWorkers(config.ErrorCallback)
+	if config.Ordering == OrderingPerAggregate {
+		shardSize := (max(config.QueueSize, 0) + workerCount - 1) / workerCount
+		bus.shards = make([]chan Event, workerCount)
+		for i := range bus.shards {
+			bus.shards[i] = make(chan Event, shardSize)
+			bus.startShardWorker(i)
+		}
+	} else {
+		bus.eventQueue = make(chan Event, config.QueueSize)
+		bus.startWorkers()
+	}
 
 	return bus
 }
 
-// startWorkers starts the worker goroutines
-func (b *AsyncEventBus) startWorkers(errorCallback func(Event, error)) {
+// startWorkers starts the dispatcher handing queued events to concurrent workers
+func (b *AsyncEventBus) startWorkers() {
 	b.wg.Add(1)
 	go func() {
 		defer b.wg.Done()
 		for {
 			select {
 			case event := <-b.eventQueue:
-				// Acquire semaphore slot
-				b.workerPool <- struct{}{}
-
-				// Process event in a new goroutine
-				b.wg.Add(1)
-				go func(evt Event) {
-					defer b.wg.Done()
-					defer func() { <-b.workerPool }() // Release semaphore slot
-
-					// Process the event
-					ctx := context.Background()
-					b.mu.RLock()
-					handlers := make([]EventHandler, len(b.handlers))
-					copy(handlers, b.handlers) // Create a copy to avoid holding the lock
-					b.mu.RUnlock()
-
-					for _, handler := range handlers {
-						if handler.InterestedIn(evt.EventName()) {
-							if err := handler.HandleEvent(ctx, evt); err != nil {
-								if errorCallback != nil {
-									errorCallback(evt, err)
-								}
-							}
-						}
-					}
+				b.recordQueueDepth(0, b.eventQueue)
+				b.dispatch(event)
 
-					// Mark event as processed in the store
-					if b.store != nil {
-						if err := b.store.MarkProcessed(ctx, evt.EventID()); err != nil {
-							if errorCallback != nil {
-								errorCallback(evt, errors.Wrapf(err, errors.ErrorTypePersistence, "failed to mark event as processed: %s", evt.EventID()))
-							}
-						}
+			case <-b.quit:
+				// Hand the events still queued to the workers before stopping
+				for {
+					select {
+					case event := <-b.eventQueue:
+						b.dispatch(event)
+					default:
+						return
 					}
-				}(event)
+				}
+			}
+		}
+	}()
+}
+
+// startShardWorker starts the worker processing the events of one shard in order
+func (b *AsyncEventBus) startShardWorker(shard int) {
+	queue := b.shards[shard]
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for {
+			select {
+			case event := <-queue:
+				b.recordQueueDepth(shard, queue)
+				b.process(event)
 
 			case <-b.quit:
-				return
+				// Process the events still queued before stopping
+				for {
+					select {
+					case event := <-queue:
+						b.process(event)
+					default:
+						return
+					}
+				}
 			}
 		}
 	}()
 }
 
+// dispatch processes an event on a worker goroutine
+func (b *AsyncEventBus) dispatch(event Event) {
+	// Acquire semaphore slot
+	b.workerPool <- struct{}{}
+
+	// Process event in a new goroutine
+	b.wg.Add(1)
+	go func(evt Event) {
+		defer b.wg.Done()
+		defer func() { <-b.workerPool }() // Release semaphore slot
+
+		b.process(evt)
+	}(event)
+}
+
+// process passes an event to the interested handlers and marks it as processed
+func (b *AsyncEventBus) process(evt Event) {
+	// Events raised by the handlers are traced back to this one
+	ctx := ContextForEvent(b.ctx, evt)
+	b.mu.RLock()
+	handlers := make([]EventHandler, len(b.handlers))
+	copy(handlers, b.handlers) // Create a copy to avoid holding the lock
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if !handler.InterestedIn(evt.EventName()) {
+			continue
+		}
+		attempts, err := b.handleWithRetry(ctx, handler, evt)
+		if err == nil {
+			continue
+		}
+		b.reportError(evt, err)
+		// Record the failure also when Close canceled the handler, so the event is not lost
+		if err := b.sendToDeadLetter(context.WithoutCancel(ctx), handler, evt, attempts, err); err != nil {
+			b.reportError(evt, err)
+		}
+	}
+
+	// Mark event as processed in the store
+	if b.store != nil {
+		if err := b.store.MarkProcessed(context.WithoutCancel(ctx), evt.EventID()); err != nil {
+			b.reportError(evt, errors.Wrapf(err, errors.ErrorTypePersistence, "failed to mark event as processed: %s", evt.EventID()))
+		}
+	}
+}
+
+// reportError passes an error to the configured callback
+func (b *AsyncEventBus) reportError(event Event, err error) {
+	if b.errorCallback != nil {
+		b.errorCallback(event, err)
+	}
+}
+
+// queueFor returns the queue of an event and its index, events of an aggregate always share a shard
+func (b *AsyncEventBus) queueFor(event Event) (int, chan Event) {
+	if len(b.shards) == 0 {
+		return 0, b.eventQueue
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(event.AggregateID()))
+	shard := int(hash.Sum32() % uint32(len(b.shards)))
+	return shard, b.shards[shard]
+}
+
+// enqueue queues an event, applying the backpressure policy when the queue is full
+func (b *AsyncEventBus) enqueue(ctx context.Context, event Event, policy BackpressurePolicy) error {
+	b.closeMu.RLock()
+	defer b.closeMu.RUnlock()
+
+	index, queue := b.queueFor(event)
+	defer b.recordQueueDepth(index, queue)
+
+	select {
+	case <-b.closing:
+		return errors.New(errors.ErrorTypeSystem, "event bus is closed")
+	default:
+	}
+
+	select {
+	case queue <- event:
+		return nil
+	default:
+	}
+
+	switch policy {
+	case BackpressureBlock:
+		select {
+		case queue <- event:
+			return nil
+		case <-b.closing:
+			return errors.New(errors.ErrorTypeSystem, "event bus is closed")
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), errors.ErrorTypeSystem, "gave up waiting for queue space: %s", event.EventID())
+		}
+	case BackpressureDrop:
+		metrics.RecordError("event_dropped", asyncEventBusMetricName)
+		return nil
+	default:
+		return errors.New(errors.ErrorTypeSystem, "event queue is full")
+	}
+}
+
+// recordQueueDepth reports the number of events waiting in a queue
+func (b *AsyncEventBus) recordQueueDepth(index int, queue chan Event) {
+	metrics.RecordEventQueueDepth(asyncEventBusMetricName, strconv.Itoa(index), len(queue))
+}
+
+// handleWithRetry calls a handler until it succeeds or the retry policy is exhausted,
+// returning the number of attempts made and the last error
+func (b *AsyncEventBus) handleWithRetry(ctx context.Context, handler EventHandler, event Event) (int, error) {
+	for attempt := 1; ; attempt++ {
+		err := handler.HandleEvent(ctx, event)
+		if err == nil {
+			return attempt, nil
+		}
+		metrics.RecordError("event_handler", HandlerName(handler))
+
+		if attempt >= b.retry.Attempts() {
+			return attempt, err
+		}
+
+		// Retries continue during shutdown until Close gives up waiting and cancels the context
+		select {
+		case <-time.After(b.retry.Backoff(attempt)):
+		case <-ctx.Done():
+			return attempt, err
+		}
+	}
+}
+
+// sendToDeadLetter hands an event a handler gave up on to the dead letter sink
+func (b *AsyncEventBus) sendToDeadLetter(ctx context.Context, handler EventHandler, event Event, attempts int, cause error) error {
+	if b.deadLetter == nil {
+		return nil
+	}
+
+	letter := NewDeadLetter(event, HandlerName(handler), attempts, cause)
+	if err := b.deadLetter.Add(ctx, letter); err != nil {
+		return errors.Wrapf(err, errors.ErrorTypePersistence, "failed to dead-letter event: %s", event.EventID())
+	}
+	metrics.RecordError("event_dead_letter", letter.Handler)
+
+	return nil
+}
+
+// DeadLetters lists up to limit dead-lettered events, oldest first
+func (b *AsyncEventBus) DeadLetters(ctx context.Context, limit int) ([]*DeadLetter, error) {
+	store, err := b.deadLetterStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.List(ctx, limit)
+}
+
+// RedriveDeadLetter passes a dead-lettered event to its handler again, applying the retry policy.
+// The dead letter is removed once handled, and updated with the new failure otherwise.
+func (b *AsyncEventBus) RedriveDeadLetter(ctx context.Context, id string) error {
+	store, err := b.deadLetterStore()
+	if err != nil {
+		return err
+	}
+
+	letter, err := store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	handler := b.findHandler(letter.Handler)
+	if handler == nil {
+		return errors.Newf(errors.ErrorTypeSystem, "handler %s of dead letter %s is not subscribed", letter.Handler, id)
+	}
+
+	attempts, handleErr := b.handleWithRetry(ContextForEvent(ctx, letter.Event), handler, letter.Event)
+	if handleErr == nil {
+		return store.Remove(ctx, id)
+	}
+
+	letter.Attempts += attempts
+	letter.Error = handleErr.Error()
+	letter.FailedAt = time.Now()
+	if err := store.Add(ctx, letter); err != nil {
+		return errors.Wrapf(err, errors.ErrorTypePersistence, "failed to update dead letter: %s", id)
+	}
+
+	return errors.Wrapf(handleErr, errors.ErrorTypeSystem, "failed to re-drive dead letter: %s", id)
+}
+
+// deadLetterStore returns the dead letter sink if it can be inspected
+func (b *AsyncEventBus) deadLetterStore() (DeadLetterStore, error) {
+	store, ok := b.deadLetter.(DeadLetterStore)
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeSystem, "dead letter sink does not support listing")
+	}
+	return store, nil
+}
+
+// findHandler returns the subscribed handler with the given name
+func (b *AsyncEventBus) findHandler(name string) EventHandler {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, handler := range b.handlers {
+		if HandlerName(handler) == name {
+			return handler
+		}
+	}
+	return nil
+}
+
 // Publish publishes an event asynchronously
 func (b *AsyncEventBus) Publish(ctx context.Context, event Event) error {
 	// Persist the event first
@@ -167,12 +491,12 @@ func (b *AsyncEventBus) Publish(ctx context.Context, event Event) error {
 	}
 
 	// Send event to the queue
-	select {
-	case b.eventQueue <- event:
-		return nil
-	default:
-		return errors.New(errors.ErrorTypeSystem, "event queue is full")
+	if err := b.enqueue(ctx, event, b.backpressure); err != nil {
+		return err
 	}
+	metrics.RecordDomainEvent(event.EventName(), asyncEventBusMetricName)
+
+	return nil
 }
 
 // Subscribe registers an event handler
@@ -196,10 +520,18 @@ func (b *AsyncEventBus) Unsubscribe(handler EventHandler) {
 	}
 }
 
-// Close shuts down the event bus gracefully
+// Close shuts down the event bus gracefully: the queued events are processed within the timeout,
+// after which the context of the handlers still running is canceled. Closing again only waits.
 func (b *AsyncEventBus) Close(timeout time.Duration) error {
-	// Signal workers to stop
-	close(b.quit)
+	defer b.cancel()
+
+	b.closeOnce.Do(func() {
+		// Refuse new events, then signal workers to stop once no publisher is enqueueing
+		close(b.closing)
+		b.closeMu.Lock()
+		close(b.quit)
+		b.closeMu.Unlock()
+	})
 
 	// Wait for all workers to finish with timeout
 	c := make(chan struct{})
@@ -216,7 +548,7 @@ func (b *AsyncEventBus) Close(timeout time.Duration) error {
 	}
 }
 
-// ReplayEvents replays events from the store
+// ReplayEvents replays events from the store that occurred at or after since
 func (b *AsyncEventBus) ReplayEvents(ctx context.Context, eventType string, since time.Time) error {
 	if b.store == nil {
 		return errors.New(errors.ErrorTypeSystem, "no event store configured")
@@ -227,9 +559,29 @@ func (b *AsyncEventBus) ReplayEvents(ctx context.Context, eventType string, sinc
 		return errors.Wrapf(err, errors.ErrorTypePersistence, "failed to get events for replay")
 	}
 
+	return b.replay(ctx, events)
+}
+
+// ReplayEventsBetween replays events from the store that occurred in [from, to)
+func (b *AsyncEventBus) ReplayEventsBetween(ctx context.Context, eventType string, from, to time.Time) error {
+	if b.store == nil {
+		return errors.New(errors.ErrorTypeSystem, "no event store configured")
+	}
+
+	events, err := b.store.GetEventsBetween(ctx, eventType, from, to)
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrorTypePersistence, "failed to get events for replay")
+	}
+
+	return b.replay(ctx, events)
+}
+
+// replay hands stored events back to the handlers. The events are already persisted, so unlike
+// Publish they are not saved again, and replay waits for queue space instead of failing when full.
+func (b *AsyncEventBus) replay(ctx context.Context, events []Event) error {
 	for _, event := range events {
-		if err := b.Publish(ctx, event); err != nil {
-			return errors.Wrapf(err, errors.ErrorTypeSystem, "failed to publish event during replay")
+		if err := b.enqueue(ctx, event, BackpressureBlock); err != nil {
+			return errors.Wrapf(err, errors.ErrorTypeSystem, "replay interrupted at event: %s", event.EventID())
 		}
 	}
 
@@ -0,0 +1,421 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryEventStore keeps events in memory for testing
+type memoryEventStore struct {
+	mu        sync.Mutex
+	events    []Event
+	saves     int
+	processed map[string]int
+}
+
+func newMemoryEventStore(events ...Event) *memoryEventStore {
+	return &memoryEventStore{
+		events:    events,
+		processed: make(map[string]int),
+	}
+}
+
+func (s *memoryEventStore) SaveEvent(ctx context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saves++
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *memoryEventStore) GetEvents(ctx context.Context, eventType string, since time.Time) ([]Event, error) {
+	return s.GetEventsBetween(ctx, eventType, since, time.Now().Add(time.Hour))
+}
+
+func (s *memoryEventStore) GetEventsBetween(ctx context.Context, eventType string, from, to time.Time) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []Event
+	for _, event := range s.events {
+		if eventType != "" && event.EventName() != eventType {
+			continue
+		}
+		if event.OccurredAt().Before(from) || !event.OccurredAt().Before(to) {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (s *memoryEventStore) MarkProcessed(ctx context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processed[eventID]++
+	return nil
+}
+
+func TestAsyncEventBus_ReplayEventsBetween(t *testing.T) {
+	now := time.Now()
+	inWindow := MockEvent{name: "test.event", eventID: "in", occurredAt: now.Add(-time.Hour)}
+	beforeWindow := MockEvent{name: "test.event", eventID: "before", occurredAt: now.Add(-3 * time.Hour)}
+	otherType := MockEvent{name: "other.event", eventID: "other", occurredAt: now.Add(-time.Hour)}
+	store := newMemoryEventStore(inWindow, beforeWindow, otherType)
+
+	bus := NewAsyncEventBus(&AsyncEventBusConfig{QueueSize: 1, WorkerCount: 1, EventStore: store})
+	handler := NewMockHandler([]string{"test.event", "other.event"}, nil)
+	bus.Subscribe(handler)
+
+	err := bus.ReplayEventsBetween(context.Background(), "test.event", now.Add(-2*time.Hour), now)
+	require.NoError(t, err)
+
+	// Closing drains the queue before the workers stop
+	require.NoError(t, bus.Close(time.Second))
+
+	assert.Equal(t, []Event{inWindow}, handler.handledEvents)
+	assert.Equal(t, map[string]int{"in": 1}, store.processed)
+	// Replayed events are already stored
+	assert.Zero(t, store.saves)
+}
+
+func TestAsyncEventBus_ReplayEventsCanceled(t *testing.T) {
+	now := time.Now()
+	store := newMemoryEventStore(
+		MockEvent{name: "test.event", eventID: "1", occurredAt: now},
+		MockEvent{name: "test.event", eventID: "2", occurredAt: now},
+		MockEvent{name: "test.event", eventID: "3", occurredAt: now},
+	)
+
+	// Block the only worker, the dispatcher then holds the second event and the third cannot be queued
+	release := make(chan struct{})
+	bus := NewAsyncEventBus(&AsyncEventBusConfig{QueueSize: 0, WorkerCount: 1, EventStore: store})
+	bus.Subscribe(NewMockHandler([]string{"test.event"}, func(ctx context.Context, event Event) error {
+		<-release
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := bus.ReplayEvents(ctx, "test.event", now.Add(-time.Minute))
+	assert.Error(t, err)
+
+	close(release)
+	require.NoError(t, bus.Close(time.Second))
+}
+
+// flakyHandler fails the first failures calls
+type flakyHandler struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (h *flakyHandler) HandleEvent(ctx context.Context, event Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls++
+	if h.calls <= h.failures {
+		return errors.New("handler failed")
+	}
+	return nil
+}
+
+func (h *flakyHandler) InterestedIn(eventName string) bool {
+	return eventName == "test.event"
+}
+
+func (h *flakyHandler) HandlerName() string {
+	return "flaky"
+}
+
+func (h *flakyHandler) setFailures(failures int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures = failures
+	h.calls = 0
+}
+
+func newRetryingBus(deadLetters DeadLetterSink) *AsyncEventBus {
+	return NewAsyncEventBus(&AsyncEventBusConfig{
+		QueueSize:   10,
+		WorkerCount: 1,
+		EventStore:  &NoopEventStore{},
+		RetryPolicy: RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			Multiplier:     2,
+			Jitter:         0.5,
+		},
+		DeadLetterSink: deadLetters,
+	})
+}
+
+func TestAsyncEventBus_RetriesFailingHandler(t *testing.T) {
+	deadLetters := NewInMemoryDeadLetterStore()
+	bus := newRetryingBus(deadLetters)
+	handler := &flakyHandler{failures: 2}
+	other := NewMockHandler([]string{"test.event"}, nil)
+	bus.Subscribe(handler)
+	bus.Subscribe(other)
+
+	require.NoError(t, bus.Publish(context.Background(), MockEvent{name: "test.event", eventID: "1"}))
+	require.NoError(t, bus.Close(time.Second))
+
+	// The third attempt succeeds, nothing is dead-lettered
+	assert.Equal(t, 3, handler.calls)
+	assert.Len(t, other.handledEvents, 1)
+	letters, err := bus.DeadLetters(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+}
+
+func TestAsyncEventBus_DeadLettersAndRedrives(t *testing.T) {
+	deadLetters := NewInMemoryDeadLetterStore()
+	bus := newRetryingBus(deadLetters)
+	handler := &flakyHandler{failures: 10}
+	other := NewMockHandler([]string{"test.event"}, nil)
+	bus.Subscribe(handler)
+	bus.Subscribe(other)
+
+	evt := MockEvent{name: "test.event", eventID: "1"}
+	require.NoError(t, bus.Publish(context.Background(), evt))
+
+	// Only the failing handler's delivery is dead-lettered
+	var letters []*DeadLetter
+	require.Eventually(t, func() bool {
+		var err error
+		letters, err = bus.DeadLetters(context.Background(), 10)
+		return err == nil && len(letters) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, "flaky", letters[0].Handler)
+	assert.Equal(t, evt, letters[0].Event)
+	assert.Equal(t, 3, letters[0].Attempts)
+	assert.Equal(t, "handler failed", letters[0].Error)
+
+	// A re-drive that fails again keeps the letter with the added attempts
+	err := bus.RedriveDeadLetter(context.Background(), letters[0].ID)
+	assert.Error(t, err)
+	letter, err := deadLetters.Get(context.Background(), letters[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 6, letter.Attempts)
+
+	// Once the handler recovers the re-drive removes the letter
+	handler.setFailures(0)
+	require.NoError(t, bus.RedriveDeadLetter(context.Background(), letters[0].ID))
+	_, err = deadLetters.Get(context.Background(), letters[0].ID)
+	assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+
+	require.NoError(t, bus.Close(time.Second))
+	assert.Len(t, other.handledEvents, 1)
+}
+
+// blockingHandler blocks until the context it handles an event with is done
+type blockingHandler struct {
+	started chan struct{}
+}
+
+func (h *blockingHandler) HandleEvent(ctx context.Context, event Event) error {
+	close(h.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (h *blockingHandler) InterestedIn(eventName string) bool {
+	return eventName == "test.event"
+}
+
+func (h *blockingHandler) HandlerName() string {
+	return "blocking"
+}
+
+func TestAsyncEventBus_CloseCancelsHandlers(t *testing.T) {
+	deadLetters := NewInMemoryDeadLetterStore()
+	bus := NewAsyncEventBus(&AsyncEventBusConfig{
+		QueueSize:      10,
+		WorkerCount:    1,
+		RetryPolicy:    RetryPolicy{MaxAttempts: 1},
+		DeadLetterSink: deadLetters,
+	})
+	handler := &blockingHandler{started: make(chan struct{})}
+	bus.Subscribe(handler)
+
+	require.NoError(t, bus.Publish(context.Background(), MockEvent{name: "test.event", eventID: "1"}))
+	<-handler.started
+
+	// Giving up on the handler cancels its context, the failure is still dead-lettered
+	assert.Error(t, bus.Close(50*time.Millisecond))
+	require.Eventually(t, func() bool {
+		letters, err := deadLetters.List(context.Background(), 10)
+		return err == nil && len(letters) == 1 && letters[0].Error == context.Canceled.Error()
+	}, time.Second, time.Millisecond)
+}
+
+func TestAsyncEventBus_CloseWhilePublishing(t *testing.T) {
+	for _, ordering := range []OrderingMode{OrderingNone, OrderingPerAggregate} {
+		bus := NewAsyncEventBus(&AsyncEventBusConfig{
+			QueueSize:    4,
+			WorkerCount:  2,
+			Ordering:     ordering,
+			Backpressure: BackpressureBlock,
+		})
+		var handled atomic.Int64
+		bus.Subscribe(handlerFunc(func(ctx context.Context, event Event) error {
+			handled.Add(1)
+			return nil
+		}))
+
+		// Every event a publisher got accepted is handled, the others are rejected
+		var accepted atomic.Int64
+		var publishers sync.WaitGroup
+		for p := range 8 {
+			publishers.Add(1)
+			go func() {
+				defer publishers.Done()
+				for i := range 50 {
+					evt := MockEvent{name: "test.event", eventID: strconv.Itoa(p*50 + i)}
+					if bus.Publish(context.Background(), evt) == nil {
+						accepted.Add(1)
+					}
+				}
+			}()
+		}
+
+		time.Sleep(time.Millisecond)
+		require.NoError(t, bus.Close(time.Second))
+		publishers.Wait()
+		assert.Equal(t, accepted.Load(), handled.Load())
+
+		// Closing again does not panic
+		assert.NoError(t, bus.Close(time.Second))
+	}
+}
+
+func TestAsyncEventBus_DeadLettersRequireStore(t *testing.T) {
+	bus := newRetryingBus(NewEventBusDeadLetterSink(NewNoopEventBus()))
+	defer bus.Close(time.Second)
+
+	_, err := bus.DeadLetters(context.Background(), 10)
+	assert.Error(t, err)
+	assert.Error(t, bus.RedriveDeadLetter(context.Background(), "missing"))
+}
+
+// handlerFunc handles every event with a function, it is safe for concurrent use if the function is
+type handlerFunc func(ctx context.Context, event Event) error
+
+func (f handlerFunc) HandleEvent(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+func (f handlerFunc) InterestedIn(eventName string) bool {
+	return true
+}
+
+// sequenceEvent is an event carrying its position in the aggregate's history
+type sequenceEvent struct {
+	MockEvent
+	sequence int
+}
+
+func TestAsyncEventBus_OrderingPerAggregate(t *testing.T) {
+	bus := NewAsyncEventBus(&AsyncEventBusConfig{
+		QueueSize:   100,
+		WorkerCount: 4,
+		EventStore:  &NoopEventStore{},
+		Ordering:    OrderingPerAggregate,
+	})
+
+	var mu sync.Mutex
+	handled := make(map[string][]int)
+	bus.Subscribe(handlerFunc(func(ctx context.Context, event Event) error {
+		// Vary the handling time so unordered processing would interleave
+		seq := event.(sequenceEvent).sequence
+		time.Sleep(time.Duration(seq%3) * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		handled[event.AggregateID()] = append(handled[event.AggregateID()], seq)
+		return nil
+	}))
+
+	aggregates := []string{"1", "2", "3", "4", "5", "6"}
+	for seq := range 10 {
+		for _, aggregate := range aggregates {
+			evt := sequenceEvent{
+				MockEvent: MockEvent{name: "test.event", aggregateID: aggregate, eventID: aggregate + "-" + strconv.Itoa(seq)},
+				sequence:  seq,
+			}
+			require.NoError(t, bus.Publish(context.Background(), evt))
+		}
+	}
+	require.NoError(t, bus.Close(5*time.Second))
+
+	expected := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	for _, aggregate := range aggregates {
+		assert.Equal(t, expected, handled[aggregate], "aggregate %s", aggregate)
+	}
+}
+
+func TestAsyncEventBus_Backpressure(t *testing.T) {
+	newBlockedBus := func(t *testing.T, policy BackpressurePolicy) (*AsyncEventBus, *MockHandler) {
+		bus := NewAsyncEventBus(&AsyncEventBusConfig{
+			QueueSize:    1,
+			WorkerCount:  1,
+			EventStore:   &NoopEventStore{},
+			Ordering:     OrderingPerAggregate,
+			Backpressure: policy,
+		})
+
+		// The worker blocks on the first event and the second fills the queue
+		release := make(chan struct{})
+		started := make(chan struct{}, 1)
+		handler := NewMockHandler([]string{"test.event"}, func(ctx context.Context, event Event) error {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+			return nil
+		})
+		bus.Subscribe(handler)
+
+		require.NoError(t, bus.Publish(context.Background(), MockEvent{name: "test.event", eventID: "1"}))
+		<-started
+		require.NoError(t, bus.Publish(context.Background(), MockEvent{name: "test.event", eventID: "2"}))
+
+		t.Cleanup(func() {
+			close(release)
+			require.NoError(t, bus.Close(time.Second))
+		})
+		return bus, handler
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		bus, _ := newBlockedBus(t, BackpressureError)
+		err := bus.Publish(context.Background(), MockEvent{name: "test.event", eventID: "3"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		bus, _ := newBlockedBus(t, BackpressureBlock)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := bus.Publish(ctx, MockEvent{name: "test.event", eventID: "3"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Drop", func(t *testing.T) {
+		bus, _ := newBlockedBus(t, BackpressureDrop)
+		err := bus.Publish(context.Background(), MockEvent{name: "test.event", eventID: "3"})
+		assert.NoError(t, err)
+	})
+}
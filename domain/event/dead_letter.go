@@ -0,0 +1,164 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-hexagonal/util/errors"
+)
+
+// ErrDeadLetterNotFound is returned when a dead letter does not exist
+var ErrDeadLetterNotFound = errors.NewNotFoundError("dead letter not found", nil)
+
+// DeadLetter is an event a handler kept failing on after all retries
+type DeadLetter struct {
+	ID    string
+	Event Event
+	// Handler is the name of the failing handler, see HandlerName
+	Handler  string
+	Error    string
+	Attempts int
+	FailedAt time.Time
+}
+
+// NewDeadLetter records the failure of a handler on an event
+func NewDeadLetter(event Event, handler string, attempts int, err error) *DeadLetter {
+	return &DeadLetter{
+		ID:       uuid.New().String(),
+		Event:    event,
+		Handler:  handler,
+		Error:    err.Error(),
+		Attempts: attempts,
+		FailedAt: time.Now(),
+	}
+}
+
+// DeadLetterSink receives events handlers gave up on
+type DeadLetterSink interface {
+	// Add records a dead letter, adding a letter with a known ID replaces it
+	Add(ctx context.Context, letter *DeadLetter) error
+}
+
+// DeadLetterStore is a dead letter sink that can be inspected, so dead letters can be re-driven
+type DeadLetterStore interface {
+	DeadLetterSink
+	// List returns up to limit dead letters, oldest first
+	List(ctx context.Context, limit int) ([]*DeadLetter, error)
+	// Get returns a dead letter by ID
+	Get(ctx context.Context, id string) (*DeadLetter, error)
+	// Remove deletes a dead letter
+	Remove(ctx context.Context, id string) error
+}
+
+// NamedHandler is implemented by handlers that provide a stable name for dead letters
+type NamedHandler interface {
+	HandlerName() string
+}
+
+// HandlerName returns the name dead letters refer to a handler by, defaulting to its type
+func HandlerName(handler EventHandler) string {
+	if named, ok := handler.(NamedHandler); ok {
+		return named.HandlerName()
+	}
+	return fmt.Sprintf("%T", handler)
+}
+
+// InMemoryDeadLetterStore keeps dead letters in memory
+type InMemoryDeadLetterStore struct {
+	mu      sync.RWMutex
+	letters []*DeadLetter
+}
+
+// NewInMemoryDeadLetterStore creates a new in-memory dead letter store
+func NewInMemoryDeadLetterStore() *InMemoryDeadLetterStore {
+	return &InMemoryDeadLetterStore{
+		letters: make([]*DeadLetter, 0),
+	}
+}
+
+// Add records a dead letter
+func (s *InMemoryDeadLetterStore) Add(ctx context.Context, letter *DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *letter
+	if i := s.indexOf(letter.ID); i >= 0 {
+		s.letters[i] = &stored
+		return nil
+	}
+	s.letters = append(s.letters, &stored)
+	return nil
+}
+
+// List returns up to limit dead letters, oldest first
+func (s *InMemoryDeadLetterStore) List(ctx context.Context, limit int) ([]*DeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	letters := s.letters
+	if limit > 0 && len(letters) > limit {
+		letters = letters[:limit]
+	}
+
+	result := make([]*DeadLetter, 0, len(letters))
+	for _, letter := range letters {
+		copied := *letter
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+// Get returns a dead letter by ID
+func (s *InMemoryDeadLetterStore) Get(ctx context.Context, id string) (*DeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := s.indexOf(id)
+	if i < 0 {
+		return nil, ErrDeadLetterNotFound
+	}
+	copied := *s.letters[i]
+	return &copied, nil
+}
+
+// Remove deletes a dead letter
+func (s *InMemoryDeadLetterStore) Remove(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(id)
+	if i < 0 {
+		return ErrDeadLetterNotFound
+	}
+	s.letters = slices.Delete(s.letters, i, i+1)
+	return nil
+}
+
+// indexOf returns the position of a dead letter, or -1 if it is unknown
+func (s *InMemoryDeadLetterStore) indexOf(id string) int {
+	return slices.IndexFunc(s.letters, func(letter *DeadLetter) bool {
+		return letter.ID == id
+	})
+}
+
+// EventBusDeadLetterSink forwards dead-lettered events to another event bus, e.g. a dead letter topic
+type EventBusDeadLetterSink struct {
+	bus EventBus
+}
+
+// NewEventBusDeadLetterSink creates a sink publishing dead-lettered events on bus
+func NewEventBusDeadLetterSink(bus EventBus) *EventBusDeadLetterSink {
+	return &EventBusDeadLetterSink{
+		bus: bus,
+	}
+}
+
+// Add publishes the dead-lettered event
+func (s *EventBusDeadLetterSink) Add(ctx context.Context, letter *DeadLetter) error {
+	return s.bus.Publish(ctx, letter.Event)
+}
@@ -19,22 +19,41 @@ type Event interface {
 	EventID() string
 }
 
+// Traceable is implemented by events that record what caused them, see Metadata
+type Traceable interface {
+	// CorrelationID returns the ID shared by everything done on behalf of the originating request
+	CorrelationID() string
+	// CausationID returns the ID of the request or event that caused this event
+	CausationID() string
+}
+
+// Versioned is implemented by events that record the schema version of their payload
+type Versioned interface {
+	// SchemaVersion returns the schema version of the payload
+	SchemaVersion() int
+}
+
 // BaseEvent provides a base implementation for events
 type BaseEvent struct {
 	ID         string    `json:"id"`
 	Name       string    `json:"name"`
 	Aggregate  string    `json:"aggregate"`
 	OccurredOn time.Time `json:"occurred_on"`
-	Payload    any       `json:"payload"`
+	// Version is the schema version of the payload, events recorded before versioning have none
+	Version     int    `json:"version,omitempty"`
+	Correlation string `json:"correlation_id,omitempty"`
+	Causation   string `json:"causation_id,omitempty"`
+	Payload     any    `json:"payload"`
 }
 
-// NewBaseEvent creates a new base event
+// NewBaseEvent creates a new base event with the current schema version of its name
 func NewBaseEvent(name, aggregateID string, payload any) BaseEvent {
 	return BaseEvent{
 		ID:         uuid.New().String(),
 		Name:       name,
 		Aggregate:  aggregateID,
 		OccurredOn: time.Now(),
+		Version:    DefaultTypeRegistry.CurrentVersion(name),
 		Payload:    payload,
 	}
 }
@@ -58,3 +77,34 @@ func (e BaseEvent) OccurredAt() time.Time {
 func (e BaseEvent) EventID() string {
 	return e.ID
 }
+
+// SchemaVersionOf returns the schema version of an event's payload, 1 for events that record none
+func SchemaVersionOf(evt Event) int {
+	if versioned, ok := evt.(Versioned); ok {
+		return versioned.SchemaVersion()
+	}
+	return 1
+}
+
+// SchemaVersion returns the schema version of the payload, 1 for events without a version
+func (e BaseEvent) SchemaVersion() int {
+	if e.Version == 0 {
+		return 1
+	}
+	return e.Version
+}
+
+// CorrelationID returns the correlation ID
+func (e BaseEvent) CorrelationID() string {
+	return e.Correlation
+}
+
+// CausationID returns the causation ID
+func (e BaseEvent) CausationID() string {
+	return e.Causation
+}
+
+// envelope returns the base event, events embedding BaseEvent inherit it
+func (e BaseEvent) envelope() BaseEvent {
+	return e
+}
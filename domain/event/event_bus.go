@@ -2,6 +2,8 @@ package event
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 )
 
@@ -45,6 +47,7 @@ func (b *NoopEventBus) Unsubscribe(handler EventHandler) {}
 // InMemoryEventBus implements an in-memory event bus
 type InMemoryEventBus struct {
 	handlers []EventHandler
+	store    EventStore
 	mu       sync.RWMutex
 }
 
@@ -55,19 +58,40 @@ func NewInMemoryEventBus() *InMemoryEventBus {
 	}
 }
 
-// Publish publishes an event to all interested handlers
+// NewStoredInMemoryEventBus creates a new in-memory event bus that saves every event to the store
+// before handing it to the handlers, so the store can replay whatever they have seen
+func NewStoredInMemoryEventBus(store EventStore) *InMemoryEventBus {
+	return &InMemoryEventBus{
+		handlers: make([]EventHandler, 0),
+		store:    store,
+	}
+}
+
+// Publish publishes an event to all interested handlers. A failing handler does not keep the
+// others from running, the errors of all failing handlers are returned together. With a store,
+// an event that cannot be saved is not handed to any handler.
 func (b *InMemoryEventBus) Publish(ctx context.Context, event Event) error {
+	if b.store != nil {
+		if err := b.store.SaveEvent(ctx, event); err != nil {
+			return fmt.Errorf("failed to save event %s: %w", event.EventID(), err)
+		}
+	}
+
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
+	// Events raised by the handlers are traced back to this one
+	handlerCtx := ContextForEvent(ctx, event)
+
+	var errs []error
 	for _, handler := range b.handlers {
 		if handler.InterestedIn(event.EventName()) {
-			if err := handler.HandleEvent(ctx, event); err != nil {
-				return err
+			if err := handler.HandleEvent(handlerCtx, event); err != nil {
+				errs = append(errs, err)
 			}
 		}
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
 // Subscribe registers an event handler
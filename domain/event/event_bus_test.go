@@ -233,7 +233,7 @@ func TestInMemoryEventBus_Publish(t *testing.T) {
 			return expectedErr
 		})
 
-		// Create a handler that would be called after the error handler
+		// Create a handler that is called after the error handler
 		laterHandler := NewMockHandler([]string{"test.event"}, nil)
 
 		// Subscribe handlers
@@ -245,13 +245,13 @@ func TestInMemoryEventBus_Publish(t *testing.T) {
 
 		// Should return the error from the handler
 		assert.Error(t, err)
-		assert.Equal(t, expectedErr, err)
+		assert.ErrorIs(t, err, expectedErr)
 
 		// Error handler should have received the event
 		assert.Len(t, errorHandler.handledEvents, 1)
 
-		// Later handler should not be called due to the error
-		assert.Empty(t, laterHandler.handledEvents)
+		// Later handler still runs despite the error
+		assert.Len(t, laterHandler.handledEvents, 1)
 	})
 
 	t.Run("Multiple Interested Handlers", func(t *testing.T) {
@@ -289,4 +289,41 @@ func TestInMemoryEventBus_Publish(t *testing.T) {
 		// Not interested handler should not have received the event
 		assert.Empty(t, handler3.handledEvents)
 	})
+	t.Run("Stored Events", func(t *testing.T) {
+		store := newMemoryEventStore()
+		bus := NewStoredInMemoryEventBus(store)
+		handler := NewMockHandler([]string{"test.event"}, nil)
+		bus.Subscribe(handler)
+
+		event := MockEvent{
+			name:        "test.event",
+			aggregateID: "123",
+			occurredAt:  time.Now(),
+			eventID:     "event-123",
+		}
+
+		// The event is saved before the handlers see it
+		err := bus.Publish(context.Background(), event)
+		assert.NoError(t, err)
+		assert.Equal(t, []Event{event}, store.events)
+		assert.Len(t, handler.handledEvents, 1)
+
+		// An event that cannot be saved is not handled
+		expectedErr := errors.New("store unavailable")
+		bus = NewStoredInMemoryEventBus(&failingEventStore{err: expectedErr})
+		bus.Subscribe(handler)
+		err = bus.Publish(context.Background(), event)
+		assert.ErrorIs(t, err, expectedErr)
+		assert.Len(t, handler.handledEvents, 1)
+	})
+}
+
+// failingEventStore fails to save any event
+type failingEventStore struct {
+	NoopEventStore
+	err error
+}
+
+func (s *failingEventStore) SaveEvent(ctx context.Context, event Event) error {
+	return s.err
 }
@@ -11,6 +11,11 @@ const (
 	ExampleUpdatedEventName = "example.updated"
 	// ExampleDeletedEventName is the name for example deletion events
 	ExampleDeletedEventName = "example.deleted"
+	// ExampleRestoredEventName is the name for example restore events
+	ExampleRestoredEventName = "example.restored"
+
+	// ExampleEventPattern matches the names of all example events
+	ExampleEventPattern = "example.*"
 )
 
 // ExampleCreatedPayload contains data for example creation events
@@ -80,3 +85,23 @@ func NewExampleDeletedEvent(id int) ExampleDeletedEvent {
 		BaseEvent: NewBaseEvent(ExampleDeletedEventName, strconv.Itoa(id), payload),
 	}
 }
+
+// ExampleRestoredPayload contains data for example restore events
+type ExampleRestoredPayload struct {
+	ID int `json:"id"`
+}
+
+// ExampleRestoredEvent represents an example restore event
+type ExampleRestoredEvent struct {
+	BaseEvent
+}
+
+// NewExampleRestoredEvent creates a new example restore event
+func NewExampleRestoredEvent(id int) ExampleRestoredEvent {
+	payload := ExampleRestoredPayload{
+		ID: id,
+	}
+	return ExampleRestoredEvent{
+		BaseEvent: NewBaseEvent(ExampleRestoredEventName, strconv.Itoa(id), payload),
+	}
+}
@@ -14,7 +14,8 @@ type LoggingEventHandler struct {
 	interestedEvents []string
 }
 
-// NewLoggingEventHandler creates a new logging event handler
+// NewLoggingEventHandler creates a new logging event handler for the events matching the glob patterns,
+// e.g. "example.*", or for all events when none are given
 func NewLoggingEventHandler(events ...string) *LoggingEventHandler {
 	return &LoggingEventHandler{
 		interestedEvents: events,
@@ -32,15 +33,7 @@ func (h *LoggingEventHandler) HandleEvent(ctx context.Context, event Event) erro
 
 // InterestedIn checks if the handler is interested in the event
 func (h *LoggingEventHandler) InterestedIn(eventName string) bool {
-	if len(h.interestedEvents) == 0 {
-		return true
-	}
-	for _, name := range h.interestedEvents {
-		if name == eventName {
-			return true
-		}
-	}
-	return false
+	return matchAny(h.interestedEvents, eventName)
 }
 
 // ExampleEventHandler handles example-related events
@@ -61,6 +54,8 @@ func (h *ExampleEventHandler) HandleEvent(ctx context.Context, event Event) erro
 		return h.handleExampleUpdated(ctx, event)
 	case ExampleDeletedEventName:
 		return h.handleExampleDeleted(ctx, event)
+	case ExampleRestoredEventName:
+		return h.handleExampleRestored(ctx, event)
 	default:
 		return nil
 	}
@@ -68,9 +63,7 @@ func (h *ExampleEventHandler) HandleEvent(ctx context.Context, event Event) erro
 
 // InterestedIn checks if the handler is interested in the event
 func (h *ExampleEventHandler) InterestedIn(eventName string) bool {
-	return eventName == ExampleCreatedEventName ||
-		eventName == ExampleUpdatedEventName ||
-		eventName == ExampleDeletedEventName
+	return MatchEventName(ExampleEventPattern, eventName)
 }
 
 // handleExampleCreated handles example creation events
@@ -96,3 +89,11 @@ func (h *ExampleEventHandler) handleExampleDeleted(ctx context.Context, event Ev
 		zap.String("event_id", event.EventID()))
 	return nil
 }
+
+// handleExampleRestored handles example restore events
+func (h *ExampleEventHandler) handleExampleRestored(ctx context.Context, event Event) error {
+	log.Logger.Info("Example restored",
+		zap.String("id", event.AggregateID()),
+		zap.String("event_id", event.EventID()))
+	return nil
+}
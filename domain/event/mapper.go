@@ -0,0 +1,56 @@
+package event
+
+import (
+	"context"
+	"sync"
+
+	"go-hexagonal/util/errors"
+)
+
+// DomainEvent is an event raised inside an aggregate, it is satisfied by model.DomainEvent
+type DomainEvent interface {
+	EventType() string
+}
+
+// Mapper translates the domain events of aggregates of type A into integration events, stamping
+// them with the correlation and causation IDs of the context they are raised in
+type Mapper[A any] struct {
+	registry *TypeRegistry
+	mu       sync.RWMutex
+	mappings map[string]func(aggregate A, event DomainEvent) Event
+}
+
+// NewMapper creates an empty mapper, registry keeps the concrete types of the mapped events
+func NewMapper[A any](registry *TypeRegistry) *Mapper[A] {
+	return &Mapper[A]{
+		registry: registry,
+		mappings: make(map[string]func(aggregate A, event DomainEvent) Event),
+	}
+}
+
+// RegisterMapping registers the translation of domain events of type T. The aggregate is passed
+// along as it may have changed since the event was raised, e.g. an ID assigned on insert.
+// T must report its event type from its zero value.
+func RegisterMapping[A any, T DomainEvent](m *Mapper[A], fn func(aggregate A, event T) Event) {
+	var zero T
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mappings[zero.EventType()] = func(aggregate A, event DomainEvent) Event {
+		return fn(aggregate, event.(T))
+	}
+}
+
+// Map translates a domain event, domain events without a mapping are an error
+func (m *Mapper[A]) Map(ctx context.Context, aggregate A, event DomainEvent) (Event, error) {
+	m.mu.RLock()
+	mapping, ok := m.mappings[event.EventType()]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, errors.Newf(errors.ErrorTypeSystem, "no integration event mapping for domain event %s", event.EventType())
+	}
+
+	return m.registry.WithMetadata(mapping(aggregate, event), MetadataFromContext(ctx)), nil
+}
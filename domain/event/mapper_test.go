@@ -0,0 +1,65 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/util/errors"
+)
+
+type renamedEvent struct {
+	ID   int
+	Name string
+}
+
+func (renamedEvent) EventType() string { return "test.renamed" }
+
+type unmappedEvent struct{}
+
+func (unmappedEvent) EventType() string { return "test.unmapped" }
+
+func TestMapper_Map(t *testing.T) {
+	mapper := NewMapper[string](DefaultTypeRegistry)
+	RegisterMapping(mapper, func(aggregate string, evt renamedEvent) Event {
+		return NewExampleUpdatedEvent(evt.ID, evt.Name, aggregate)
+	})
+
+	ctx := ContextWithMetadata(context.Background(), Metadata{CorrelationID: "flow-1", CausationID: "request-1"})
+
+	t.Run("Mapped", func(t *testing.T) {
+		mapped, err := mapper.Map(ctx, "alias", renamedEvent{ID: 3, Name: "name"})
+		require.NoError(t, err)
+
+		// The concrete type survives stamping the metadata
+		updated, ok := mapped.(ExampleUpdatedEvent)
+		require.True(t, ok)
+		assert.Equal(t, ExampleUpdatedPayload{ID: 3, Name: "name", Alias: "alias"}, updated.Payload)
+		assert.Equal(t, "flow-1", updated.CorrelationID())
+		assert.Equal(t, "request-1", updated.CausationID())
+	})
+
+	t.Run("Unmapped", func(t *testing.T) {
+		_, err := mapper.Map(ctx, "alias", unmappedEvent{})
+		require.Error(t, err)
+		assert.True(t, errors.IsSystemError(err))
+	})
+}
+
+func TestContextForEvent(t *testing.T) {
+	t.Run("Starts a flow", func(t *testing.T) {
+		evt := NewExampleDeletedEvent(1)
+
+		md := MetadataFromContext(ContextForEvent(context.Background(), evt))
+		assert.Equal(t, Metadata{CorrelationID: evt.EventID(), CausationID: evt.EventID()}, md)
+	})
+
+	t.Run("Continues a flow", func(t *testing.T) {
+		evt := DefaultTypeRegistry.WithMetadata(NewExampleDeletedEvent(1), Metadata{CorrelationID: "flow-1", CausationID: "request-1"})
+
+		md := MetadataFromContext(ContextForEvent(context.Background(), evt))
+		assert.Equal(t, Metadata{CorrelationID: "flow-1", CausationID: evt.EventID()}, md)
+	})
+}
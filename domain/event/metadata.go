@@ -0,0 +1,62 @@
+package event
+
+import (
+	"context"
+)
+
+// Metadata links an event to the request or event that caused it
+type Metadata struct {
+	// CorrelationID is shared by everything done on behalf of one originating request
+	CorrelationID string
+	// CausationID is the ID of the request or event that directly caused the event
+	CausationID string
+}
+
+// metadataKey is the context key for event metadata
+type metadataKey struct{}
+
+// ContextWithMetadata returns a context carrying the metadata for the events raised within it
+func ContextWithMetadata(ctx context.Context, md Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, md)
+}
+
+// MetadataFromContext returns the metadata carried by ctx, empty if there is none
+func MetadataFromContext(ctx context.Context) Metadata {
+	md, _ := ctx.Value(metadataKey{}).(Metadata)
+	return md
+}
+
+// ContextForEvent returns the context to handle an event in, events raised by the handler
+// keep the event's correlation ID and name the event as their cause
+func ContextForEvent(ctx context.Context, event Event) context.Context {
+	md := Metadata{
+		CorrelationID: event.EventID(),
+		CausationID:   event.EventID(),
+	}
+	if traceable, ok := event.(Traceable); ok && traceable.CorrelationID() != "" {
+		md.CorrelationID = traceable.CorrelationID()
+	}
+
+	return ContextWithMetadata(ctx, md)
+}
+
+// WithMetadata returns a copy of an event carrying the metadata. Events embedding BaseEvent keep
+// their concrete type as long as it is registered, other events are returned unchanged.
+func (r *TypeRegistry) WithMetadata(event Event, md Metadata) Event {
+	enveloped, ok := event.(interface{ envelope() BaseEvent })
+	if !ok {
+		return event
+	}
+
+	base := enveloped.envelope()
+	base.Correlation = md.CorrelationID
+	base.Causation = md.CausationID
+
+	if _, isBase := event.(BaseEvent); isBase {
+		return base
+	}
+	if wrapped, ok := r.wrap(base); ok {
+		return wrapped
+	}
+	return event
+}
@@ -0,0 +1,81 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go-hexagonal/util/errors"
+	"go-hexagonal/util/log"
+	"go-hexagonal/util/metrics"
+)
+
+// WithLogging logs the outcome and duration of every handled event
+func WithLogging() Middleware {
+	return func(next EventHandler) EventHandler {
+		return wrapHandler(next, func(ctx context.Context, event Event, next EventHandler) error {
+			start := time.Now()
+			err := next.HandleEvent(ctx, event)
+
+			if log.Logger == nil {
+				return err
+			}
+			fields := []zap.Field{
+				zap.String("handler", HandlerName(next)),
+				zap.String("event_name", event.EventName()),
+				zap.String("event_id", event.EventID()),
+				zap.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				log.Logger.Error("Event handler failed", append(fields, zap.Error(err))...)
+			} else {
+				log.Logger.Debug("Event handled", fields...)
+			}
+
+			return err
+		})
+	}
+}
+
+// WithMetrics counts handled events and handler failures per handler
+func WithMetrics() Middleware {
+	return func(next EventHandler) EventHandler {
+		return wrapHandler(next, func(ctx context.Context, event Event, next EventHandler) error {
+			if err := next.HandleEvent(ctx, event); err != nil {
+				metrics.RecordError("event_handler", HandlerName(next))
+				return err
+			}
+			metrics.RecordDomainEvent(event.EventName(), HandlerName(next))
+			return nil
+		})
+	}
+}
+
+// WithRecovery turns handler panics into errors, so a panicking handler fails like any other
+func WithRecovery() Middleware {
+	return func(next EventHandler) EventHandler {
+		return wrapHandler(next, func(ctx context.Context, event Event, next EventHandler) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = errors.Newf(errors.ErrorTypeSystem, "event handler %s panicked on event %s: %v",
+						HandlerName(next), event.EventID(), r)
+				}
+			}()
+
+			return next.HandleEvent(ctx, event)
+		})
+	}
+}
+
+// WithTimeout bounds the handling of an event through the handler's context
+func WithTimeout(timeout time.Duration) Middleware {
+	return func(next EventHandler) EventHandler {
+		return wrapHandler(next, func(ctx context.Context, event Event, next EventHandler) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			return next.HandleEvent(ctx, event)
+		})
+	}
+}
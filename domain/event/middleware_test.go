@@ -0,0 +1,72 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go-hexagonal/util/log"
+)
+
+func TestChain_Order(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next EventHandler) EventHandler {
+			return wrapHandler(next, func(ctx context.Context, event Event, next EventHandler) error {
+				calls = append(calls, name)
+				return next.HandleEvent(ctx, event)
+			})
+		}
+	}
+
+	handler := Chain(NewPatternHandler("inner", func(ctx context.Context, event Event) error {
+		calls = append(calls, "handler")
+		return nil
+	}, ExampleEventPattern), record("outer"), record("middle"))
+
+	require.NoError(t, handler.HandleEvent(context.Background(), NewExampleDeletedEvent(1)))
+	assert.Equal(t, []string{"outer", "middle", "handler"}, calls)
+
+	// Subscriptions and name come from the wrapped handler
+	assert.True(t, handler.InterestedIn(ExampleDeletedEventName))
+	assert.False(t, handler.InterestedIn("order.created"))
+	assert.Equal(t, "inner", HandlerName(handler))
+}
+
+func TestWithRecovery(t *testing.T) {
+	handler := Chain(NewPatternHandler("panicking", func(ctx context.Context, event Event) error {
+		panic("boom")
+	}), WithRecovery())
+
+	err := handler.HandleEvent(context.Background(), NewExampleDeletedEvent(1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func TestWithTimeout(t *testing.T) {
+	handler := Chain(NewPatternHandler("slow", func(ctx context.Context, event Event) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), WithTimeout(10*time.Millisecond))
+
+	err := handler.HandleEvent(context.Background(), NewExampleDeletedEvent(1))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWithLoggingAndMetrics_PassErrorsThrough(t *testing.T) {
+	log.Logger = zap.NewNop()
+	expected := errors.New("handler failed")
+
+	bus := NewInMemoryEventBus()
+	bus.Subscribe(Chain(NewPatternHandler("failing", func(ctx context.Context, event Event) error {
+		return expected
+	}), WithLogging(), WithMetrics()))
+
+	err := bus.Publish(context.Background(), NewExampleDeletedEvent(1))
+	assert.ErrorIs(t, err, expected)
+}
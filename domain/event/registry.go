@@ -0,0 +1,241 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// payloadDecoder decodes a JSON encoded payload into the payload type of one schema version
+type payloadDecoder func(payload []byte) (any, error)
+
+// upcaster converts a payload of one schema version to the next version
+type upcaster struct {
+	decode payloadDecoder
+	upcast func(payload any) (any, error)
+}
+
+// eventType is the registration of an event name
+type eventType struct {
+	// version is the current schema version, events are published with it
+	version int
+	decode  payloadDecoder
+	// isCurrent reports whether a payload has the type of the current version
+	isCurrent func(payload any) bool
+	wrap      func(BaseEvent) Event
+	// upcasters holds the conversions of older versions by the version they convert from
+	upcasters map[int]upcaster
+}
+
+// TypeRegistry maps event names to their concrete types, so events read back from storage or
+// a broker reach handlers with the same payload types they were published with. Payloads of
+// older schema versions are upcast to the current version before they are handed out.
+type TypeRegistry struct {
+	mu    sync.RWMutex
+	types map[string]*eventType
+}
+
+// NewTypeRegistry creates an empty type registry
+func NewTypeRegistry() *TypeRegistry {
+	return &TypeRegistry{
+		types: make(map[string]*eventType),
+	}
+}
+
+// DefaultTypeRegistry holds the event types of this service
+var DefaultTypeRegistry = NewTypeRegistry()
+
+func init() {
+	RegisterEventType[ExampleCreatedPayload](DefaultTypeRegistry, ExampleCreatedEventName, func(base BaseEvent) Event {
+		return ExampleCreatedEvent{BaseEvent: base}
+	})
+	RegisterEventType[ExampleUpdatedPayload](DefaultTypeRegistry, ExampleUpdatedEventName, func(base BaseEvent) Event {
+		return ExampleUpdatedEvent{BaseEvent: base}
+	})
+	RegisterEventType[ExampleDeletedPayload](DefaultTypeRegistry, ExampleDeletedEventName, func(base BaseEvent) Event {
+		return ExampleDeletedEvent{BaseEvent: base}
+	})
+	RegisterEventType[ExampleRestoredPayload](DefaultTypeRegistry, ExampleRestoredEventName, func(base BaseEvent) Event {
+		return ExampleRestoredEvent{BaseEvent: base}
+	})
+}
+
+// RegisterEventType registers the payload type P for events with the given name, as schema version 1.
+// wrap turns the decoded envelope into the concrete event type.
+func RegisterEventType[P any](r *TypeRegistry, name string, wrap func(BaseEvent) Event) {
+	RegisterEventVersion[P](r, name, 1, wrap)
+}
+
+// RegisterEventVersion registers P as the payload type of the current schema version of an event.
+// Older versions are read through the upcasters registered with RegisterUpcaster.
+func RegisterEventVersion[P any](r *TypeRegistry, name string, version int, wrap func(BaseEvent) Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	registered := r.lookup(name)
+	registered.version = version
+	registered.decode = decodePayload[P](name, version)
+	registered.isCurrent = func(payload any) bool {
+		_, ok := payload.(P)
+		return ok
+	}
+	registered.wrap = wrap
+}
+
+// RegisterUpcaster registers the conversion of payloads of an event from schema version from,
+// with payload type From, to version from+1 with payload type To. Chained upcasters bring
+// payloads of any older version to the current one.
+func RegisterUpcaster[From, To any](r *TypeRegistry, name string, from int, fn func(From) (To, error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lookup(name).upcasters[from] = upcaster{
+		decode: decodePayload[From](name, from),
+		upcast: func(payload any) (any, error) {
+			typed, ok := payload.(From)
+			if !ok {
+				return nil, fmt.Errorf("upcaster of event %s version %d expects %T, got %T", name, from, typed, payload)
+			}
+			return fn(typed)
+		},
+	}
+}
+
+// lookup returns the registration of an event name, creating it when missing. The caller holds the write lock.
+func (r *TypeRegistry) lookup(name string) *eventType {
+	registered, ok := r.types[name]
+	if !ok {
+		registered = &eventType{upcasters: make(map[int]upcaster)}
+		r.types[name] = registered
+	}
+	return registered
+}
+
+// decodePayload returns a decoder for payloads of type P
+func decodePayload[P any](name string, version int) payloadDecoder {
+	return func(payload []byte) (any, error) {
+		var decoded P
+		if err := json.Unmarshal(payload, &decoded); err != nil {
+			return nil, fmt.Errorf("failed to decode payload of event %s version %d: %w", name, version, err)
+		}
+		return decoded, nil
+	}
+}
+
+// IsRegistered reports whether a type is registered for the event name
+func (r *TypeRegistry) IsRegistered(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	registered, ok := r.types[name]
+	return ok && registered.decode != nil
+}
+
+// CurrentVersion returns the schema version events with the given name are published with,
+// 1 for events without a registered type
+func (r *TypeRegistry) CurrentVersion(name string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if registered, ok := r.types[name]; ok && registered.decode != nil {
+		return registered.version
+	}
+	return 1
+}
+
+// registered returns the registration of an event name with a current payload type
+func (r *TypeRegistry) registered(name string) (*eventType, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	registered, ok := r.types[name]
+	if !ok || registered.decode == nil {
+		return nil, false
+	}
+	return registered, true
+}
+
+// wrap turns an envelope into the concrete event type registered for its name
+func (r *TypeRegistry) wrap(base BaseEvent) (Event, bool) {
+	registered, ok := r.registered(base.Name)
+	if !ok {
+		return nil, false
+	}
+	return registered.wrap(base), true
+}
+
+// Decode rebuilds the event described by base from its JSON encoded payload. Payloads of an
+// older schema version are upcast, the event is returned with the current version.
+// Events without a registered type keep the raw payload.
+func (r *TypeRegistry) Decode(base BaseEvent, payload []byte) (Event, error) {
+	registered, ok := r.registered(base.Name)
+	if !ok {
+		base.Payload = json.RawMessage(payload)
+		return base, nil
+	}
+
+	decoded, err := registered.upcast(base.Name, base.SchemaVersion(), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	base.Version = registered.version
+	base.Payload = decoded
+	return registered.wrap(base), nil
+}
+
+// upcast decodes a payload of the given version and converts it to the current version
+func (t *eventType) upcast(name string, version int, payload []byte) (any, error) {
+	if version == t.version {
+		return t.decode(payload)
+	}
+	if version > t.version {
+		return nil, fmt.Errorf("event %s version %d is newer than the supported version %d", name, version, t.version)
+	}
+
+	first, ok := t.upcasters[version]
+	if !ok {
+		return nil, fmt.Errorf("no upcaster for event %s version %d", name, version)
+	}
+	decoded, err := first.decode(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	for ; version < t.version; version++ {
+		step, ok := t.upcasters[version]
+		if !ok {
+			return nil, fmt.Errorf("no upcaster for event %s version %d", name, version)
+		}
+		if decoded, err = step.upcast(decoded); err != nil {
+			return nil, fmt.Errorf("failed to upcast event %s from version %d: %w", name, version, err)
+		}
+	}
+
+	// The chain has to end in the current payload type, handlers rely on it
+	if !t.isCurrent(decoded) {
+		return nil, fmt.Errorf("upcasters of event %s produce %T instead of the version %d payload", name, decoded, t.version)
+	}
+	return decoded, nil
+}
+
+// MarshalPayload returns the JSON encoded payload of an event
+func MarshalPayload(evt Event) ([]byte, error) {
+	encoded, err := json.Marshal(evt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event %s: %w", evt.EventName(), err)
+	}
+
+	// Keep only the payload, the envelope fields are carried separately
+	var envelope struct {
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(encoded, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to extract payload of event %s: %w", evt.EventName(), err)
+	}
+	if len(envelope.Payload) == 0 {
+		return []byte("null"), nil
+	}
+
+	return envelope.Payload, nil
+}
@@ -0,0 +1,164 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeRegistry_DecodeRegisteredType(t *testing.T) {
+	original := NewExampleUpdatedEvent(7, "name", "alias")
+
+	payload, err := MarshalPayload(original)
+	require.NoError(t, err)
+
+	decoded, err := DefaultTypeRegistry.Decode(BaseEvent{
+		ID:         original.EventID(),
+		Name:       original.EventName(),
+		Aggregate:  original.AggregateID(),
+		OccurredOn: original.OccurredAt(),
+	}, payload)
+	require.NoError(t, err)
+
+	// The event comes back with its concrete type and payload type
+	updated, ok := decoded.(ExampleUpdatedEvent)
+	require.True(t, ok)
+	assert.Equal(t, original.EventID(), updated.EventID())
+	assert.Equal(t, ExampleUpdatedPayload{ID: 7, Name: "name", Alias: "alias"}, updated.Payload)
+}
+
+func TestTypeRegistry_DecodeUnknownType(t *testing.T) {
+	registry := NewTypeRegistry()
+
+	decoded, err := registry.Decode(BaseEvent{Name: "unknown.event"}, []byte(`{"id":1}`))
+	require.NoError(t, err)
+
+	// Unknown events keep the raw payload
+	base, ok := decoded.(BaseEvent)
+	require.True(t, ok)
+	assert.JSONEq(t, `{"id":1}`, string(base.Payload.(json.RawMessage)))
+	assert.False(t, registry.IsRegistered("unknown.event"))
+}
+
+func TestTypeRegistry_DecodeInvalidPayload(t *testing.T) {
+	_, err := DefaultTypeRegistry.Decode(BaseEvent{Name: ExampleDeletedEventName}, []byte(`{"id":"not a number"}`))
+	assert.Error(t, err)
+}
+
+// Successive schema versions of a test event: v1 had a single name field, v2 split it,
+// v3 renamed the last name
+type renamedV1 struct {
+	Name string `json:"name"`
+}
+
+type renamedV2 struct {
+	First string `json:"first"`
+	Last  string `json:"last"`
+}
+
+type renamedV3 struct {
+	First  string `json:"first"`
+	Family string `json:"family"`
+}
+
+type renamedTestEvent struct {
+	BaseEvent
+}
+
+func newVersionedRegistry() *TypeRegistry {
+	registry := NewTypeRegistry()
+	RegisterEventVersion[renamedV3](registry, "test.renamed", 3, func(base BaseEvent) Event {
+		return renamedTestEvent{BaseEvent: base}
+	})
+	RegisterUpcaster(registry, "test.renamed", 1, func(old renamedV1) (renamedV2, error) {
+		first, last, _ := strings.Cut(old.Name, " ")
+		return renamedV2{First: first, Last: last}, nil
+	})
+	RegisterUpcaster(registry, "test.renamed", 2, func(old renamedV2) (renamedV3, error) {
+		return renamedV3{First: old.First, Family: old.Last}, nil
+	})
+	return registry
+}
+
+func TestTypeRegistry_Upcast(t *testing.T) {
+	registry := newVersionedRegistry()
+	assert.Equal(t, 3, registry.CurrentVersion("test.renamed"))
+	assert.Equal(t, 1, registry.CurrentVersion("unknown.event"))
+
+	tests := []struct {
+		name    string
+		version int
+		payload string
+	}{
+		{name: "Unversioned", version: 0, payload: `{"name":"Ada Lovelace"}`},
+		{name: "Version 1", version: 1, payload: `{"name":"Ada Lovelace"}`},
+		{name: "Version 2", version: 2, payload: `{"first":"Ada","last":"Lovelace"}`},
+		{name: "Current", version: 3, payload: `{"first":"Ada","family":"Lovelace"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := registry.Decode(BaseEvent{ID: "1", Name: "test.renamed", Version: tt.version}, []byte(tt.payload))
+			require.NoError(t, err)
+
+			// Handlers see the current shape and version whatever was stored
+			typed, ok := decoded.(renamedTestEvent)
+			require.True(t, ok)
+			assert.Equal(t, 3, typed.SchemaVersion())
+			assert.Equal(t, renamedV3{First: "Ada", Family: "Lovelace"}, typed.Payload)
+		})
+	}
+}
+
+func TestTypeRegistry_UpcastErrors(t *testing.T) {
+	t.Run("Newer than supported", func(t *testing.T) {
+		_, err := newVersionedRegistry().Decode(BaseEvent{Name: "test.renamed", Version: 4}, []byte(`{}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing upcaster", func(t *testing.T) {
+		registry := NewTypeRegistry()
+		RegisterEventVersion[renamedV3](registry, "test.renamed", 3, func(base BaseEvent) Event {
+			return renamedTestEvent{BaseEvent: base}
+		})
+		RegisterUpcaster(registry, "test.renamed", 1, func(old renamedV1) (renamedV2, error) {
+			return renamedV2{First: old.Name}, nil
+		})
+
+		_, err := registry.Decode(BaseEvent{Name: "test.renamed", Version: 1}, []byte(`{"name":"Ada"}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("Chain ends in another type", func(t *testing.T) {
+		registry := NewTypeRegistry()
+		RegisterEventVersion[renamedV3](registry, "test.renamed", 2, func(base BaseEvent) Event {
+			return renamedTestEvent{BaseEvent: base}
+		})
+		RegisterUpcaster(registry, "test.renamed", 1, func(old renamedV1) (renamedV2, error) {
+			return renamedV2{First: old.Name}, nil
+		})
+
+		_, err := registry.Decode(BaseEvent{Name: "test.renamed", Version: 1}, []byte(`{"name":"Ada"}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("Upcaster fails", func(t *testing.T) {
+		registry := newVersionedRegistry()
+		RegisterUpcaster(registry, "test.renamed", 2, func(old renamedV2) (renamedV3, error) {
+			return renamedV3{}, errors.New("no last name")
+		})
+
+		_, err := registry.Decode(BaseEvent{Name: "test.renamed", Version: 2}, []byte(`{"first":"Ada"}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestNewBaseEvent_CurrentVersion(t *testing.T) {
+	evt := NewExampleCreatedEvent(1, "name", "alias")
+	assert.Equal(t, 1, evt.SchemaVersion())
+	assert.Equal(t, 1, SchemaVersionOf(evt))
+}
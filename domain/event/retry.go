@@ -0,0 +1,59 @@
+package event
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how often a failing handler is retried and how long to wait in between
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a handler is called, values below 2 disable retries
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries, zero means no cap
+	MaxBackoff time.Duration
+	// Multiplier grows the wait after every retry, values below 1 keep it constant
+	Multiplier float64
+	// Jitter randomizes each wait by up to this fraction in either direction, e.g. 0.2 for ±20%
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns the default retry policy
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// Attempts returns how often a handler is called at most
+func (p RetryPolicy) Attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// Backoff returns the wait after the given failed attempt, counting from 1
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	multiplier := math.Max(p.Multiplier, 1)
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 {
+		backoff = math.Min(backoff, float64(p.MaxBackoff))
+	}
+
+	if p.Jitter > 0 {
+		backoff *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+
+	return time.Duration(backoff)
+}
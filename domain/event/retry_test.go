@@ -0,0 +1,41 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     300 * time.Millisecond,
+		Multiplier:     2,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.Backoff(2))
+	// Capped at the maximum
+	assert.Equal(t, 300*time.Millisecond, policy.Backoff(3))
+}
+
+func TestRetryPolicy_BackoffJitter(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+
+	for range 100 {
+		backoff := policy.Backoff(2)
+		assert.GreaterOrEqual(t, backoff, 160*time.Millisecond)
+		assert.LessOrEqual(t, backoff, 240*time.Millisecond)
+	}
+}
+
+func TestRetryPolicy_Attempts(t *testing.T) {
+	assert.Equal(t, 1, RetryPolicy{}.Attempts())
+	assert.Equal(t, 3, DefaultRetryPolicy().Attempts())
+}
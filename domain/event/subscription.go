@@ -0,0 +1,121 @@
+package event
+
+import (
+	"context"
+	"path"
+)
+
+// MatchEventName reports whether an event name matches a glob pattern, e.g. "example.*" or "*".
+// Patterns use path.Match syntax, malformed patterns match nothing.
+func MatchEventName(pattern, eventName string) bool {
+	matched, err := path.Match(pattern, eventName)
+	return err == nil && matched
+}
+
+// matchAny reports whether an event name matches one of the patterns, no patterns match every event
+func matchAny(patterns []string, eventName string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if MatchEventName(pattern, eventName) {
+			return true
+		}
+	}
+	return false
+}
+
+// HandlerFunc handles an event
+type HandlerFunc func(ctx context.Context, event Event) error
+
+// funcHandler subscribes a function to the events matching its patterns
+type funcHandler struct {
+	name     string
+	patterns []string
+	handle   HandlerFunc
+}
+
+// HandleEvent calls the handler function
+func (h *funcHandler) HandleEvent(ctx context.Context, event Event) error {
+	return h.handle(ctx, event)
+}
+
+// InterestedIn checks the event name against the patterns
+func (h *funcHandler) InterestedIn(eventName string) bool {
+	return matchAny(h.patterns, eventName)
+}
+
+// HandlerName returns the name dead letters refer to the handler by
+func (h *funcHandler) HandlerName() string {
+	return h.name
+}
+
+// NewPatternHandler creates a handler calling fn for events matching any of the glob patterns,
+// or for all events when no pattern is given. name identifies the handler, e.g. in dead letters.
+func NewPatternHandler(name string, fn HandlerFunc, patterns ...string) EventHandler {
+	return &funcHandler{
+		name:     name,
+		patterns: patterns,
+		handle:   fn,
+	}
+}
+
+// NewTypedHandler creates a handler calling fn with the concrete event type E, e.g. ExampleCreatedEvent.
+// It receives the events matching any of the glob patterns, or all events when no pattern is given,
+// and ignores events of other types.
+func NewTypedHandler[E Event](name string, fn func(ctx context.Context, event E) error, patterns ...string) EventHandler {
+	return &funcHandler{
+		name:     name,
+		patterns: patterns,
+		handle: func(ctx context.Context, event Event) error {
+			typed, ok := event.(E)
+			if !ok {
+				return nil
+			}
+			return fn(ctx, typed)
+		},
+	}
+}
+
+// Middleware decorates an event handler, e.g. with logging or timeouts
+type Middleware func(next EventHandler) EventHandler
+
+// Chain wraps a handler with middleware, the first middleware runs outermost.
+// Unsubscribe the returned handler, not the wrapped one.
+func Chain(handler EventHandler, middleware ...Middleware) EventHandler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// wrappedHandler replaces the handling of a handler, keeping its subscriptions and name
+type wrappedHandler struct {
+	next   EventHandler
+	handle HandlerFunc
+}
+
+// HandleEvent calls the wrapping function
+func (h *wrappedHandler) HandleEvent(ctx context.Context, event Event) error {
+	return h.handle(ctx, event)
+}
+
+// InterestedIn delegates to the wrapped handler
+func (h *wrappedHandler) InterestedIn(eventName string) bool {
+	return h.next.InterestedIn(eventName)
+}
+
+// HandlerName returns the name of the wrapped handler
+func (h *wrappedHandler) HandlerName() string {
+	return HandlerName(h.next)
+}
+
+// wrapHandler builds a middleware from a function receiving the wrapped handler
+func wrapHandler(next EventHandler, handle func(ctx context.Context, event Event, next EventHandler) error) EventHandler {
+	return &wrappedHandler{
+		next: next,
+		handle: func(ctx context.Context, event Event) error {
+			return handle(ctx, event, next)
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchEventName(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"example.*", ExampleCreatedEventName, true},
+		{"example.*", "order.created", false},
+		{"*", ExampleDeletedEventName, true},
+		{"*.created", ExampleCreatedEventName, true},
+		{ExampleUpdatedEventName, ExampleUpdatedEventName, true},
+		{ExampleUpdatedEventName, ExampleCreatedEventName, false},
+		{"example.[", ExampleCreatedEventName, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchEventName(tt.pattern, tt.name), "%s against %s", tt.name, tt.pattern)
+	}
+}
+
+func TestNewPatternHandler(t *testing.T) {
+	var handled []string
+	handler := NewPatternHandler("recorder", func(ctx context.Context, event Event) error {
+		handled = append(handled, event.EventName())
+		return nil
+	}, "example.created", "example.deleted")
+
+	bus := NewInMemoryEventBus()
+	bus.Subscribe(handler)
+	require.NoError(t, bus.Publish(context.Background(), NewExampleCreatedEvent(1, "name", "alias")))
+	require.NoError(t, bus.Publish(context.Background(), NewExampleUpdatedEvent(1, "name", "alias")))
+	require.NoError(t, bus.Publish(context.Background(), NewExampleDeletedEvent(1)))
+
+	assert.Equal(t, []string{ExampleCreatedEventName, ExampleDeletedEventName}, handled)
+	assert.Equal(t, "recorder", HandlerName(handler))
+	assert.True(t, NewPatternHandler("all", nil).InterestedIn("anything"))
+}
+
+func TestNewTypedHandler(t *testing.T) {
+	var payloads []ExampleCreatedPayload
+	handler := NewTypedHandler("created", func(ctx context.Context, event ExampleCreatedEvent) error {
+		payloads = append(payloads, event.Payload.(ExampleCreatedPayload))
+		return nil
+	}, ExampleEventPattern)
+
+	bus := NewInMemoryEventBus()
+	bus.Subscribe(handler)
+	require.NoError(t, bus.Publish(context.Background(), NewExampleCreatedEvent(1, "name", "alias")))
+	// Events of other types are ignored
+	require.NoError(t, bus.Publish(context.Background(), NewExampleDeletedEvent(1)))
+
+	assert.Equal(t, []ExampleCreatedPayload{{ID: 1, Name: "name", Alias: "alias"}}, payloads)
+}
+
+func TestTypedHandler_AsyncEventBus(t *testing.T) {
+	handled := make(chan ExampleRestoredEvent, 1)
+	handler := Chain(
+		NewTypedHandler("restored", func(ctx context.Context, event ExampleRestoredEvent) error {
+			handled <- event
+			return nil
+		}),
+		WithRecovery(),
+		WithTimeout(time.Second),
+	)
+
+	bus := NewAsyncEventBus(&AsyncEventBusConfig{QueueSize: 1, WorkerCount: 1, EventStore: &NoopEventStore{}})
+	bus.Subscribe(handler)
+
+	restored := NewExampleRestoredEvent(2)
+	require.NoError(t, bus.Publish(context.Background(), restored))
+	require.NoError(t, bus.Close(time.Second))
+
+	select {
+	case event := <-handled:
+		assert.Equal(t, restored.EventID(), event.EventID())
+	default:
+		t.Fatal("typed handler did not receive the event")
+	}
+}
@@ -25,6 +25,9 @@ var (
 
 	// ErrExampleModified indicates the example was modified concurrently
 	ErrExampleModified = errors.New(errors.ErrorTypeConflict, "example modified by another process")
+
+	// ErrExampleNotDeleted indicates an attempt to restore an example that is not deleted
+	ErrExampleNotDeleted = errors.New(errors.ErrorTypeConflict, "example is not deleted")
 )
 
 // NewExampleNotFoundWithID creates a not found error with the example ID
@@ -39,7 +42,7 @@ func NewExampleNotFoundWithName(name string) *errors.AppError {
 
 // NewExampleNameTakenError creates an error indicating the name is already taken
 func NewExampleNameTakenError(name string) *errors.AppError {
-	return errors.Newf(errors.ErrorTypeConflict, "example with name '%s' already exists", name)
+	return errors.Wrapf(ErrExampleNameTaken, errors.ErrorTypeConflict, "example with name '%s' already exists", name)
 }
 
 // IsExampleNotFoundError checks if the error indicates an example not found condition
@@ -57,10 +60,30 @@ func IsExampleValidationError(err error) bool {
 
 // IsExampleNameTakenError checks if the error indicates a name conflict
 func IsExampleNameTakenError(err error) bool {
-	return stderrors.Is(err, ErrExampleNameTaken)
+	return wraps(err, ErrExampleNameTaken)
 }
 
 // IsExampleModifiedError checks if the error indicates a concurrent modification
 func IsExampleModifiedError(err error) bool {
-	return stderrors.Is(err, ErrExampleModified)
+	return wraps(err, ErrExampleModified)
+}
+
+// wraps reports whether target itself is in the chain of err. errors.Is matches any AppError of the
+// same type, which cannot tell the conflicts of examples apart.
+func wraps(err error, target error) bool {
+	for err != nil {
+		if err == target {
+			return true
+		}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() {
+				if wraps(e, target) {
+					return true
+				}
+			}
+			return false
+		}
+		err = stderrors.Unwrap(err)
+	}
+	return false
 }
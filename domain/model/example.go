@@ -1,16 +1,23 @@
 package model
 
 import (
+	"strings"
 	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
 )
 
 // Example represents a basic example entity
 type Example struct {
 	Id        int           `json:"id"`
 	Name      string        `json:"name"`
+	NameKey   string        `json:"-"` // Normalized name enforcing uniqueness, derived by persistence adapters
 	Alias     string        `json:"alias"`
 	CreatedAt time.Time     `json:"created_at"`
 	UpdatedAt time.Time     `json:"updated_at"`
+	Version   int           `json:"version"`              // Optimistic locking version, bumped on every update
+	DeletedAt *time.Time    `json:"deleted_at,omitempty"` // Set while the example is soft deleted
 	events    []DomainEvent // Track domain events
 }
 
@@ -30,6 +37,7 @@ func NewExample(name, alias string) (*Example, error) {
 		Alias:     alias,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+		Version:   1,
 		events:    make([]DomainEvent, 0),
 	}
 
@@ -39,6 +47,13 @@ func NewExample(name, alias string) (*Example, error) {
 	return example, nil
 }
 
+// NormalizeExampleName returns the canonical form under which example names must be unique.
+// Names that differ only in case or Unicode representation share the same key.
+func NormalizeExampleName(name string) string {
+	folded := cases.Fold().String(norm.NFKC.String(strings.TrimSpace(name)))
+	return norm.NFKC.String(folded)
+}
+
 // Validate ensures the Example entity meets domain rules
 func (e *Example) Validate() error {
 	if e.Name == "" {
@@ -68,9 +83,31 @@ func (e *Example) Update(name, alias string) error {
 
 // MarkDeleted marks the entity as deleted and records a deletion event
 func (e *Example) MarkDeleted() {
+	now := time.Now()
+	e.DeletedAt = &now
 	e.addEvent(NewExampleDeletedEvent(e))
 }
 
+// Restore brings a soft deleted entity back and records a restore event
+func (e *Example) Restore() error {
+	if !e.IsDeleted() {
+		return ErrExampleNotDeleted
+	}
+
+	e.DeletedAt = nil
+	e.UpdatedAt = time.Now()
+
+	// Record restore event
+	e.addEvent(NewExampleRestoredEvent(e))
+
+	return nil
+}
+
+// IsDeleted reports whether the entity is soft deleted
+func (e *Example) IsDeleted() bool {
+	return e.DeletedAt != nil
+}
+
 // Events returns all accumulated domain events and clears the event list
 func (e *Example) Events() []DomainEvent {
 	events := e.events
@@ -155,3 +192,22 @@ func NewExampleDeletedEvent(example *Example) ExampleDeletedEvent {
 		Timestamp: time.Now(),
 	}
 }
+
+// ExampleRestoredEvent represents the restoration of a soft deleted example
+type ExampleRestoredEvent struct {
+	ExampleID int
+	Timestamp time.Time
+}
+
+// EventType returns the event type
+func (e ExampleRestoredEvent) EventType() string {
+	return "example.restored"
+}
+
+// NewExampleRestoredEvent creates a new example restored event
+func NewExampleRestoredEvent(example *Example) ExampleRestoredEvent {
+	return ExampleRestoredEvent{
+		ExampleID: example.Id,
+		Timestamp: time.Now(),
+	}
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	stderrors "errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -209,6 +211,57 @@ func TestExample_MarkDeleted(t *testing.T) {
 	assert.Equal(t, example.Id, deletedEvent.ExampleID)
 }
 
+func TestExample_Restore(t *testing.T) {
+	example := &Example{
+		Id:     1,
+		Name:   "Test Example",
+		Alias:  "test-alias",
+		events: make([]DomainEvent, 0),
+	}
+
+	// A live example cannot be restored
+	assert.ErrorIs(t, example.Restore(), ErrExampleNotDeleted)
+
+	example.MarkDeleted()
+	assert.True(t, example.IsDeleted())
+	example.Events()
+
+	assert.NoError(t, example.Restore())
+	assert.False(t, example.IsDeleted())
+
+	// Verify events
+	events := example.Events()
+	assert.Len(t, events, 1)
+	assert.Equal(t, "example.restored", events[0].EventType())
+
+	restoredEvent, ok := events[0].(ExampleRestoredEvent)
+	assert.True(t, ok)
+	assert.Equal(t, example.Id, restoredEvent.ExampleID)
+}
+
+func TestNormalizeExampleName(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		same bool
+	}{
+		{name: "case differs", a: "Example", b: "EXAMPLE", same: true},
+		{name: "surrounding spaces", a: "  example ", b: "example", same: true},
+		{name: "full case folding", a: "Straße", b: "STRASSE", same: true},
+		{name: "compatibility ligature", a: "ﬁle", b: "file", same: true},
+		{name: "composed and decomposed accents", a: "Caf\u00e9", b: "cafe\u0301", same: true},
+		{name: "fullwidth letters", a: "ＡＢＣ", b: "abc", same: true},
+		{name: "different names", a: "example", b: "examples", same: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.same, NormalizeExampleName(tt.a) == NormalizeExampleName(tt.b))
+		})
+	}
+}
+
 func TestExample_TableName(t *testing.T) {
 	example := Example{}
 	assert.Equal(t, "example", example.TableName())
@@ -320,3 +373,15 @@ func TestDomainEvents(t *testing.T) {
 	assert.Len(t, events, 1)
 	assert.Equal(t, "example.deleted", events[0].EventType())
 }
+
+func TestExampleConflictErrors(t *testing.T) {
+	nameTaken := fmt.Errorf("create example: %w", NewExampleNameTakenError("taken"))
+	modified := fmt.Errorf("update example: %w", ErrExampleModified)
+
+	// Both are conflicts, yet each is only reported as itself
+	assert.True(t, IsExampleNameTakenError(nameTaken))
+	assert.False(t, IsExampleModifiedError(nameTaken))
+	assert.True(t, IsExampleModifiedError(modified))
+	assert.False(t, IsExampleNameTakenError(modified))
+	assert.True(t, IsExampleModifiedError(stderrors.Join(assert.AnError, modified)))
+}
@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go-hexagonal/domain/event"
+)
+
+// ErrStreamVersionConflict is returned when a stream was appended to since it was read
+var ErrStreamVersionConflict = RepoError("event stream version conflict")
+
+// StreamEvent is an event in the append-only stream of an event-sourced aggregate
+type StreamEvent struct {
+	ID       int64
+	StreamID string
+	// Version is the position of the event in its stream, starting at 1
+	Version       int
+	EventID       string
+	EventName     string
+	SchemaVersion int
+	// Payload is the JSON encoded event payload
+	Payload    string
+	OccurredAt time.Time
+	CreatedAt  time.Time
+}
+
+// TableName returns the table name for stream events
+func (StreamEvent) TableName() string {
+	return "event_stream"
+}
+
+// NewStreamEvent records an event for a stream, the version is assigned when it is appended
+func NewStreamEvent(streamID string, evt event.Event) (*StreamEvent, error) {
+	payload, err := event.MarshalPayload(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StreamEvent{
+		StreamID:      streamID,
+		EventID:       evt.EventID(),
+		EventName:     evt.EventName(),
+		SchemaVersion: event.SchemaVersionOf(evt),
+		Payload:       string(payload),
+		OccurredAt:    evt.OccurredAt(),
+	}, nil
+}
+
+// Event rebuilds the recorded event with its registered type, upcast to the current schema version
+func (e *StreamEvent) Event(registry *event.TypeRegistry) (event.Event, error) {
+	return registry.Decode(event.BaseEvent{
+		ID:         e.EventID,
+		Name:       e.EventName,
+		Aggregate:  e.StreamID,
+		OccurredOn: e.OccurredAt,
+		Version:    e.SchemaVersion,
+	}, []byte(e.Payload))
+}
+
+// StreamSnapshot is the state of an aggregate at a version of its stream, loading starts from it
+// instead of the first event
+type StreamSnapshot struct {
+	StreamID string
+	Version  int
+	// State is the JSON encoded aggregate state
+	State     string
+	CreatedAt time.Time
+}
+
+// TableName returns the table name for stream snapshots
+func (StreamSnapshot) TableName() string {
+	return "stream_snapshot"
+}
+
+// IEventStreamRepo persists the event streams of event-sourced aggregates
+type IEventStreamRepo interface {
+	// Append adds events to the end of a stream and assigns their versions. expectedVersion is the
+	// version the caller read, 0 for a new stream; ErrStreamVersionConflict is returned if the
+	// stream moved on since.
+	Append(ctx context.Context, tr Transaction, streamID string, expectedVersion int, events ...*StreamEvent) error
+	// Load returns the events of a stream after the given version in order, none for a missing stream
+	Load(ctx context.Context, tr Transaction, streamID string, afterVersion int) ([]*StreamEvent, error)
+	// StreamIDs returns the IDs of all streams starting with prefix
+	StreamIDs(ctx context.Context, tr Transaction, prefix string) ([]string, error)
+	// SaveSnapshot replaces the snapshot of a stream
+	SaveSnapshot(ctx context.Context, tr Transaction, snapshot *StreamSnapshot) error
+	// LoadSnapshot returns the snapshot of a stream, ErrNotFound if none was taken
+	LoadSnapshot(ctx context.Context, tr Transaction, streamID string) (*StreamSnapshot, error)
+}
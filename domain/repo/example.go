@@ -2,17 +2,117 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"go-hexagonal/domain/model"
 )
 
+// ExampleSortField represents a field examples can be ordered by
+type ExampleSortField string
+
+const (
+	// ExampleSortByID orders examples by primary key
+	ExampleSortByID ExampleSortField = "id"
+	// ExampleSortByName orders examples by name
+	ExampleSortByName ExampleSortField = "name"
+	// ExampleSortByCreatedAt orders examples by creation time
+	ExampleSortByCreatedAt ExampleSortField = "created_at"
+	// ExampleSortByUpdatedAt orders examples by last update time
+	ExampleSortByUpdatedAt ExampleSortField = "updated_at"
+)
+
+// IsValid checks if the sort field is supported
+func (f ExampleSortField) IsValid() bool {
+	switch f {
+	case ExampleSortByID, ExampleSortByName, ExampleSortByCreatedAt, ExampleSortByUpdatedAt:
+		return true
+	}
+	return false
+}
+
+// ExampleFilter holds the criteria used to select examples
+type ExampleFilter struct {
+	// Name matches examples whose name contains the given substring
+	Name string
+	// Alias matches examples whose alias contains the given substring
+	Alias string
+	// CreatedAfter matches examples created at or after the given time
+	CreatedAfter *time.Time
+	// CreatedBefore matches examples created before the given time
+	CreatedBefore *time.Time
+	// IncludeDeleted also matches soft deleted examples
+	IncludeDeleted bool
+}
+
+// ExampleListQuery describes filtering, ordering and offset pagination for listing examples
+type ExampleListQuery struct {
+	Filter   ExampleFilter
+	SortBy   ExampleSortField
+	SortDesc bool
+	Offset   int
+	Limit    int
+}
+
+// ExampleCursor identifies a position in the (created_at, id) ordering of examples
+type ExampleCursor struct {
+	ID        int
+	CreatedAt time.Time
+}
+
+// NewExampleCursor creates a cursor positioned at the given example
+func NewExampleCursor(example *model.Example) *ExampleCursor {
+	return &ExampleCursor{
+		ID:        example.Id,
+		CreatedAt: example.CreatedAt,
+	}
+}
+
+// ExampleKeysetQuery describes keyset (cursor) pagination over examples ordered by (created_at, id)
+type ExampleKeysetQuery struct {
+	Filter ExampleFilter
+	// Cursor is the exclusive position to continue from; nil starts at the beginning
+	Cursor *ExampleCursor
+	// Backward reads the page preceding the cursor instead of the one following it
+	Backward bool
+	// SortDesc orders examples from newest to oldest
+	SortDesc bool
+	Limit    int
+}
+
+// ExampleCursorPage is a page of examples returned by keyset pagination
+type ExampleCursorPage struct {
+	Items []*model.Example
+	// NextCursor points at the last item when a following page exists
+	NextCursor *ExampleCursor
+	// PrevCursor points at the first item when a preceding page exists
+	PrevCursor *ExampleCursor
+}
+
 // IExampleRepo defines the interface for example repository
 type IExampleRepo interface {
 	Create(ctx context.Context, tr Transaction, example *model.Example) (*model.Example, error)
+	// Delete soft deletes an example, it stays restorable until purged
 	Delete(ctx context.Context, tr Transaction, id int) error
+	// Restore clears the deletion mark of a soft deleted example
+	Restore(ctx context.Context, tr Transaction, id int) error
+	// Purge permanently removes an example, whether soft deleted or not
+	Purge(ctx context.Context, tr Transaction, id int) error
 	Update(ctx context.Context, tr Transaction, entity *model.Example) error
 	GetByID(ctx context.Context, tr Transaction, Id int) (*model.Example, error)
+	// GetByIDWithDeleted retrieves an example by ID even if it is soft deleted
+	GetByIDWithDeleted(ctx context.Context, tr Transaction, id int) (*model.Example, error)
 	FindByName(ctx context.Context, tr Transaction, name string) (*model.Example, error)
+	List(ctx context.Context, tr Transaction, query ExampleListQuery) ([]*model.Example, error)
+	Count(ctx context.Context, tr Transaction, filter ExampleFilter) (int64, error)
+	// ListByCursor returns up to query.Limit examples strictly after (or before, when
+	// query.Backward is set) the cursor, always in the requested display order
+	ListByCursor(ctx context.Context, tr Transaction, query ExampleKeysetQuery) ([]*model.Example, error)
+}
+
+// IExamplePageRepo is implemented by example repositories that read a page and the total number of
+// matches at once, the example service then asks for both with one call instead of Count and List
+type IExamplePageRepo interface {
+	ListWithTotal(ctx context.Context, tr Transaction, query ExampleListQuery) ([]*model.Example, int64, error)
 }
 
 // IExampleCacheRepo defines the interface for example cache repository
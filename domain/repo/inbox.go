@@ -0,0 +1,35 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// ErrInboxDuplicate is returned when recording an event the consumer already processed
+var ErrInboxDuplicate = RepoError("event already processed by consumer")
+
+// InboxMessage records that a consumer processed an event, so redeliveries of the event are skipped
+type InboxMessage struct {
+	ID int64
+	// Consumer is the name of the handler that processed the event
+	Consumer    string
+	EventID     string
+	EventName   string
+	ProcessedAt time.Time
+}
+
+// TableName returns the table name for inbox messages
+func (InboxMessage) TableName() string {
+	return "inbox"
+}
+
+// IInboxRepo persists the events each consumer processed
+type IInboxRepo interface {
+	// Processed reports whether the consumer already processed the event
+	Processed(ctx context.Context, tr Transaction, consumer, eventID string) (bool, error)
+	// Save records a processed event, ErrInboxDuplicate if the consumer already recorded it.
+	// Callers pass the transaction of the handler's own changes so both commit together.
+	Save(ctx context.Context, tr Transaction, message *InboxMessage) error
+	// DeleteBefore removes the records of events processed before the given time and returns how many
+	DeleteBefore(ctx context.Context, tr Transaction, before time.Time) (int64, error)
+}
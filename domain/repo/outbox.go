@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go-hexagonal/domain/event"
+)
+
+// OutboxMessage is an integration event recorded in the same transaction as the aggregate change
+// that raised it, and delivered to the event bus after that transaction commits
+type OutboxMessage struct {
+	ID int64
+	// EventID is the ID of the recorded event, consumers use it to drop redeliveries
+	EventID     string
+	EventName   string
+	AggregateID string
+	// SchemaVersion is the schema version of the payload
+	SchemaVersion int
+	// CorrelationID and CausationID trace the event back to what caused it
+	CorrelationID string
+	CausationID   string
+	// Payload is the JSON encoded event payload
+	Payload     string
+	OccurredAt  time.Time
+	CreatedAt   time.Time
+	PublishedAt *time.Time
+	// ParkedAt is set when the relay gave up on the message after too many failed deliveries
+	ParkedAt *time.Time
+	// Attempts counts failed deliveries
+	Attempts  int
+	LastError string
+}
+
+// TableName returns the table name for outbox messages
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
+
+// NewOutboxMessage records an event for delivery through the outbox
+func NewOutboxMessage(evt event.Event) (*OutboxMessage, error) {
+	payload, err := event.MarshalPayload(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &OutboxMessage{
+		EventID:       evt.EventID(),
+		EventName:     evt.EventName(),
+		AggregateID:   evt.AggregateID(),
+		SchemaVersion: event.SchemaVersionOf(evt),
+		Payload:       string(payload),
+		OccurredAt:    evt.OccurredAt(),
+	}
+	if traceable, ok := evt.(event.Traceable); ok {
+		message.CorrelationID = traceable.CorrelationID()
+		message.CausationID = traceable.CausationID()
+	}
+
+	return message, nil
+}
+
+// Event rebuilds the recorded event with its registered type, keeping its ID so deliveries can be deduplicated.
+// Messages recorded with an older schema version are upcast.
+func (m *OutboxMessage) Event() (event.Event, error) {
+	return event.DefaultTypeRegistry.Decode(event.BaseEvent{
+		ID:          m.EventID,
+		Name:        m.EventName,
+		Aggregate:   m.AggregateID,
+		OccurredOn:  m.OccurredAt,
+		Version:     m.SchemaVersion,
+		Correlation: m.CorrelationID,
+		Causation:   m.CausationID,
+	}, []byte(m.Payload))
+}
+
+// IOutboxRepo persists outbox messages
+type IOutboxRepo interface {
+	// Save records messages, callers pass the transaction that changes the aggregate
+	Save(ctx context.Context, tr Transaction, messages ...*OutboxMessage) error
+	// FetchPending returns up to limit unpublished messages that are not parked, in the order they
+	// were recorded. Inside a transaction the rows stay locked, concurrent relays skip them.
+	FetchPending(ctx context.Context, tr Transaction, limit int) ([]*OutboxMessage, error)
+	// MarkPublished flags a message as delivered
+	MarkPublished(ctx context.Context, tr Transaction, id int64) error
+	// MarkFailed records a failed delivery attempt
+	MarkFailed(ctx context.Context, tr Transaction, id int64, reason string) error
+	// Park records the last failed delivery attempt of a message and takes it out of delivery
+	Park(ctx context.Context, tr Transaction, id int64, reason string) error
+}
@@ -0,0 +1,53 @@
+package repo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/event"
+)
+
+func TestOutboxMessage_RoundTrip(t *testing.T) {
+	original := event.NewExampleCreatedEvent(7, "name", "alias")
+
+	message, err := NewOutboxMessage(original)
+	require.NoError(t, err)
+	assert.Equal(t, original.ID, message.EventID)
+	assert.Equal(t, event.ExampleCreatedEventName, message.EventName)
+	assert.Equal(t, "7", message.AggregateID)
+	assert.JSONEq(t, `{"id":7,"name":"name","alias":"alias"}`, message.Payload)
+
+	// The relayed event keeps its type and ID, and encodes exactly like the original
+	relayed, err := message.Event()
+	require.NoError(t, err)
+	assert.IsType(t, event.ExampleCreatedEvent{}, relayed)
+	assert.Equal(t, original.EventID(), relayed.EventID())
+
+	want, err := json.Marshal(original)
+	require.NoError(t, err)
+	got, err := json.Marshal(relayed)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(want), string(got))
+}
+
+func TestOutboxMessage_KeepsMetadata(t *testing.T) {
+	original := event.DefaultTypeRegistry.WithMetadata(event.NewExampleDeletedEvent(7), event.Metadata{
+		CorrelationID: "flow-1",
+		CausationID:   "request-1",
+	})
+
+	message, err := NewOutboxMessage(original)
+	require.NoError(t, err)
+	assert.Equal(t, "flow-1", message.CorrelationID)
+	assert.Equal(t, "request-1", message.CausationID)
+
+	relayed, err := message.Event()
+	require.NoError(t, err)
+	traceable, ok := relayed.(event.Traceable)
+	require.True(t, ok)
+	assert.Equal(t, "flow-1", traceable.CorrelationID())
+	assert.Equal(t, "request-1", traceable.CausationID())
+}
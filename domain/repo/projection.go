@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// ProjectionCheckpoint is the position of a projector in the event history: the last event it applied
+type ProjectionCheckpoint struct {
+	Projector string `json:"projector"`
+	// Sequence is the event store sequence of the last event the projector went past, applied or not
+	Sequence int64 `json:"sequence"`
+	// EventID is the ID of the last applied event
+	EventID string `json:"event_id"`
+	// OccurredAt is the occurrence time of the last applied event
+	OccurredAt time.Time `json:"occurred_at"`
+	// Processed is the number of events applied since the projector was last rebuilt
+	Processed int64     `json:"processed"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IProjectionCheckpointRepo persists the checkpoints of projectors
+type IProjectionCheckpointRepo interface {
+	// LoadCheckpoint returns the checkpoint of a projector, ErrNotFound if it has not applied any event
+	LoadCheckpoint(ctx context.Context, projector string) (*ProjectionCheckpoint, error)
+	// SaveCheckpoint replaces the checkpoint of a projector
+	SaveCheckpoint(ctx context.Context, checkpoint *ProjectionCheckpoint) error
+	// DeleteCheckpoint removes the checkpoint of a projector, deleting a missing checkpoint is a no-op
+	DeleteCheckpoint(ctx context.Context, projector string) error
+}
+
+// ExampleDailyCount is the number of examples created on a day
+type ExampleDailyCount struct {
+	// Day is the UTC date in YYYY-MM-DD form
+	Day   string `json:"day"`
+	Count int64  `json:"count"`
+}
+
+// IExampleDailyCountRepo stores the read model of examples created per day
+type IExampleDailyCountRepo interface {
+	// CountEvent counts an event on the day, an event that was already counted is not counted again
+	CountEvent(ctx context.Context, day string, eventID string) error
+	// Counts returns the counts of all days in date order
+	Counts(ctx context.Context) ([]ExampleDailyCount, error)
+	// Reset removes all counts
+	Reset(ctx context.Context) error
+}
+
+// IExampleNameIndexRepo stores the read model used to search live examples by name prefix
+type IExampleNameIndexRepo interface {
+	// Put indexes an example under its current name, replacing its previous name
+	Put(ctx context.Context, id int, name string) error
+	// Remove takes an example out of search results, its name is kept so it can be restored
+	Remove(ctx context.Context, id int) error
+	// Restore returns a removed example to search results under its last name
+	Restore(ctx context.Context, id int) error
+	// Search returns the IDs of live examples whose normalized name starts with the normalized
+	// prefix, in name order; a limit of 0 returns all of them
+	Search(ctx context.Context, prefix string, limit int) ([]int, error)
+	// Reset removes all entries
+	Reset(ctx context.Context) error
+}
@@ -0,0 +1,71 @@
+package repo
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// ReadSession remembers when a unit of work, such as a request, last wrote, so stores reading
+// from replicas can send its following reads to the primary until the replicas caught up
+type ReadSession struct {
+	lastWrite atomic.Int64 // Unix nanoseconds of the last write, zero if none
+}
+
+// NewReadSession creates a read session without writes
+func NewReadSession() *ReadSession {
+	return &ReadSession{}
+}
+
+// MarkWrite records a write made at the given time
+func (s *ReadSession) MarkWrite(at time.Time) {
+	if s == nil {
+		return
+	}
+	s.lastWrite.Store(at.UnixNano())
+}
+
+// WroteWithin reports whether the session wrote within window before now
+func (s *ReadSession) WroteWithin(window time.Duration, now time.Time) bool {
+	if s == nil || window <= 0 {
+		return false
+	}
+	last := s.lastWrite.Load()
+	return last != 0 && now.Sub(time.Unix(0, last)) < window
+}
+
+// readSessionContextKey is the context key under which the read session is carried
+type readSessionContextKey struct{}
+
+// ContextWithReadSession returns a copy of ctx carrying the read session
+func ContextWithReadSession(ctx context.Context, session *ReadSession) context.Context {
+	return context.WithValue(ctx, readSessionContextKey{}, session)
+}
+
+// ReadSessionFromContext returns the read session carried by ctx, if any
+func ReadSessionFromContext(ctx context.Context) (*ReadSession, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	session, ok := ctx.Value(readSessionContextKey{}).(*ReadSession)
+	return session, ok && session != nil
+}
+
+// primaryReadsContextKey is the context key marking reads that must be served by the primary store
+type primaryReadsContextKey struct{}
+
+// ContextWithPrimaryReads returns a copy of ctx whose reads must be served by the primary store. Writes
+// use it for the lookups they depend on, such as the version they update or a name they claim, which a
+// lagging replica would answer with stale data.
+func ContextWithPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsContextKey{}, true)
+}
+
+// RequiresPrimaryRead reports whether reads made with ctx must be served by the primary store
+func RequiresPrimaryRead(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	required, _ := ctx.Value(primaryReadsContextKey{}).(bool)
+	return required
+}
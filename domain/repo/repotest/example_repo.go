@@ -0,0 +1,524 @@
+// Package repotest provides the contract every example repository implementation must honour,
+// as a test suite adapters run against their own repository and transaction factory.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
+)
+
+// TimestampTolerance is how far stored timestamps may drift from the clock, stores keeping whole seconds round them
+const TimestampTolerance = time.Second
+
+// Setup returns an example repository over an empty store and the transaction factory its use cases
+// run with. A nil factory marks a repository whose writes take effect immediately; the checks of
+// transaction visibility are then skipped.
+type Setup func(t *testing.T) (repo.IExampleRepo, repo.TransactionFactory)
+
+// storeTransactions opens transactions on the store the repository under test keeps its examples in
+type storeTransactions struct {
+	factory repo.TransactionFactory
+	store   repo.StoreType
+}
+
+// RunExampleRepoTests runs the example repository contract, each subtest on a store created by setup.
+// Transactions are opened for store, the store type the use cases ask for over this repository.
+func RunExampleRepoTests(t *testing.T, store repo.StoreType, setup Setup) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, r repo.IExampleRepo, txs storeTransactions)
+	}{
+		{name: "CreateAndGet", run: testCreateAndGet},
+		{name: "NotFound", run: testNotFound},
+		{name: "NameUniqueness", run: testNameUniqueness},
+		{name: "UpdateChecksVersion", run: testUpdateChecksVersion},
+		{name: "Timestamps", run: testTimestamps},
+		{name: "DeleteRestorePurge", run: testDeleteRestorePurge},
+		{name: "ListAndCount", run: testListAndCount},
+		{name: "ListByCursor", run: testListByCursor},
+		{name: "TransactionVisibility", run: testTransactionVisibility},
+		{name: "ContextTransaction", run: testContextTransaction},
+		{name: "ConcurrentUpdates", run: testConcurrentUpdates},
+		{name: "ConcurrentCreates", run: testConcurrentCreates},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, factory := setup(t)
+			tt.run(t, r, storeTransactions{factory: factory, store: store})
+		})
+	}
+}
+
+// create stores an example outside of any transaction
+func create(t *testing.T, r repo.IExampleRepo, name, alias string) *model.Example {
+	t.Helper()
+	created, err := r.Create(context.Background(), nil, &model.Example{Name: name, Alias: alias})
+	require.NoError(t, err)
+	return created
+}
+
+// requireTransactions skips the test when the repository has no transaction factory
+func requireTransactions(t *testing.T, txs storeTransactions) {
+	t.Helper()
+	if txs.factory == nil {
+		t.Skip("repository has no transaction factory")
+	}
+}
+
+// begin starts a transaction on the store under test
+func begin(t *testing.T, txs storeTransactions) repo.Transaction {
+	t.Helper()
+	tx, err := txs.factory.NewTransaction(context.Background(), txs.store, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Begin())
+	return tx
+}
+
+// inTransaction runs fn in a transaction committed when fn succeeds, or directly without a factory
+func inTransaction(ctx context.Context, txs storeTransactions, fn func(tx repo.Transaction) error) error {
+	if txs.factory == nil {
+		return fn(nil)
+	}
+
+	tx, err := txs.factory.NewTransaction(ctx, txs.store, nil)
+	if err != nil {
+		return err
+	}
+	if err := tx.Begin(); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// assertNotFound asserts the repository reported a missing example with repo.ErrNotFound, the model
+// error is the service's translation of it
+func assertNotFound(t *testing.T, err error) {
+	t.Helper()
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+	assert.False(t, model.IsExampleNotFoundError(err), "repositories report repo.ErrNotFound, not the model error")
+}
+
+func testCreateAndGet(t *testing.T, r repo.IExampleRepo, _ storeTransactions) {
+	ctx := context.Background()
+
+	created := create(t, r, "Straße", "alias")
+	assert.NotZero(t, created.Id)
+	assert.Equal(t, 1, created.Version)
+	assert.Equal(t, model.NormalizeExampleName("Straße"), created.NameKey)
+
+	other := create(t, r, "other", "")
+	assert.NotEqual(t, created.Id, other.Id)
+
+	found, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, created.Id, found.Id)
+	assert.Equal(t, "Straße", found.Name)
+	assert.Equal(t, "alias", found.Alias)
+	assert.Equal(t, 1, found.Version)
+	assert.Nil(t, found.DeletedAt)
+
+	// Names are looked up ignoring case and Unicode representation
+	found, err = r.FindByName(ctx, nil, "STRASSE")
+	require.NoError(t, err)
+	assert.Equal(t, created.Id, found.Id)
+
+	found, err = r.GetByIDWithDeleted(ctx, nil, other.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "other", found.Name)
+}
+
+func testNotFound(t *testing.T, r repo.IExampleRepo, _ storeTransactions) {
+	ctx := context.Background()
+	const missing = 4242
+
+	_, err := r.GetByID(ctx, nil, missing)
+	assertNotFound(t, err)
+	_, err = r.GetByIDWithDeleted(ctx, nil, missing)
+	assertNotFound(t, err)
+	_, err = r.FindByName(ctx, nil, "missing")
+	assertNotFound(t, err)
+	assertNotFound(t, r.Update(ctx, nil, &model.Example{Id: missing, Name: "missing", Version: 1}))
+	assertNotFound(t, r.Delete(ctx, nil, missing))
+	assertNotFound(t, r.Restore(ctx, nil, missing))
+	assertNotFound(t, r.Purge(ctx, nil, missing))
+
+	// Soft deleted examples are missing to everything but GetByIDWithDeleted, Restore and Purge
+	deleted := create(t, r, "deleted", "")
+	require.NoError(t, r.Delete(ctx, nil, deleted.Id))
+	deleted.Version++
+
+	_, err = r.GetByID(ctx, nil, deleted.Id)
+	assertNotFound(t, err)
+	_, err = r.FindByName(ctx, nil, "deleted")
+	assertNotFound(t, err)
+	assertNotFound(t, r.Update(ctx, nil, deleted))
+	assertNotFound(t, r.Delete(ctx, nil, deleted.Id))
+
+	// A live example cannot be restored
+	live := create(t, r, "live", "")
+	assertNotFound(t, r.Restore(ctx, nil, live.Id))
+}
+
+func testNameUniqueness(t *testing.T, r repo.IExampleRepo, _ storeTransactions) {
+	ctx := context.Background()
+
+	first := create(t, r, "Straße", "")
+	_, err := r.Create(ctx, nil, &model.Example{Name: "STRASSE"})
+	assert.True(t, model.IsExampleNameTakenError(err), "got %v", err)
+	assert.False(t, errors.Is(err, repo.ErrNotFound))
+
+	other := create(t, r, "other", "")
+	other.Name = "strasse"
+	err = r.Update(ctx, nil, other)
+	assert.True(t, model.IsExampleNameTakenError(err), "got %v", err)
+
+	// Deleting an example frees its name, then it cannot be restored while the name is taken
+	require.NoError(t, r.Delete(ctx, nil, first.Id))
+	second := create(t, r, "STRASSE", "")
+	assert.ErrorIs(t, r.Restore(ctx, nil, first.Id), model.ErrExampleNameTaken)
+
+	require.NoError(t, r.Purge(ctx, nil, second.Id))
+	require.NoError(t, r.Restore(ctx, nil, first.Id))
+}
+
+func testUpdateChecksVersion(t *testing.T, r repo.IExampleRepo, _ storeTransactions) {
+	ctx := context.Background()
+
+	created := create(t, r, "versioned", "before")
+	stale := *created
+
+	created.Alias = "after"
+	require.NoError(t, r.Update(ctx, nil, created))
+	assert.Equal(t, 2, created.Version)
+
+	// An update based on an older version fails without changing the example
+	stale.Alias = "stale"
+	err := r.Update(ctx, nil, &stale)
+	assert.ErrorIs(t, err, model.ErrExampleModified)
+	assert.False(t, errors.Is(err, repo.ErrNotFound))
+
+	found, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "after", found.Alias)
+	assert.Equal(t, 2, found.Version)
+
+	// The caller's copy carries the persisted version, so it can be updated again
+	created.Alias = "again"
+	require.NoError(t, r.Update(ctx, nil, created))
+	assert.Equal(t, 3, created.Version)
+}
+
+func testTimestamps(t *testing.T, r repo.IExampleRepo, _ storeTransactions) {
+	ctx := context.Background()
+
+	before := time.Now()
+	created := create(t, r, "timed", "")
+	assert.WithinDuration(t, before, created.CreatedAt, TimestampTolerance)
+	assert.WithinDuration(t, created.CreatedAt, created.UpdatedAt, TimestampTolerance)
+
+	stored, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.WithinDuration(t, created.CreatedAt, stored.CreatedAt, TimestampTolerance)
+
+	// Updates move the update time and keep the creation time
+	time.Sleep(10 * time.Millisecond)
+	stored.Alias = "updated"
+	require.NoError(t, r.Update(ctx, nil, stored))
+
+	updated, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.True(t, updated.CreatedAt.Equal(stored.CreatedAt), "created_at changed from %v to %v", stored.CreatedAt, updated.CreatedAt)
+	assert.False(t, updated.UpdatedAt.Before(stored.UpdatedAt))
+	assert.WithinDuration(t, time.Now(), updated.UpdatedAt, TimestampTolerance)
+
+	// Deletion records its time, restoration clears it
+	require.NoError(t, r.Delete(ctx, nil, created.Id))
+	deleted, err := r.GetByIDWithDeleted(ctx, nil, created.Id)
+	require.NoError(t, err)
+	require.NotNil(t, deleted.DeletedAt)
+	assert.WithinDuration(t, time.Now(), *deleted.DeletedAt, TimestampTolerance)
+
+	require.NoError(t, r.Restore(ctx, nil, created.Id))
+	restored, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+	assert.True(t, restored.CreatedAt.Equal(stored.CreatedAt))
+}
+
+func testDeleteRestorePurge(t *testing.T, r repo.IExampleRepo, _ storeTransactions) {
+	ctx := context.Background()
+
+	created := create(t, r, "lifecycle", "")
+
+	// Every change advances the version
+	require.NoError(t, r.Delete(ctx, nil, created.Id))
+	deleted, err := r.GetByIDWithDeleted(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted.Version)
+	assert.True(t, deleted.IsDeleted())
+
+	require.NoError(t, r.Restore(ctx, nil, created.Id))
+	restored, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 3, restored.Version)
+
+	// Purging removes live and deleted examples for good
+	require.NoError(t, r.Purge(ctx, nil, created.Id))
+	_, err = r.GetByIDWithDeleted(ctx, nil, created.Id)
+	assertNotFound(t, err)
+
+	other := create(t, r, "purged while deleted", "")
+	require.NoError(t, r.Delete(ctx, nil, other.Id))
+	require.NoError(t, r.Purge(ctx, nil, other.Id))
+	_, err = r.GetByIDWithDeleted(ctx, nil, other.Id)
+	assertNotFound(t, err)
+}
+
+func testListAndCount(t *testing.T, r repo.IExampleRepo, _ storeTransactions) {
+	ctx := context.Background()
+
+	var ids []int
+	for _, name := range []string{"banana", "Apple", "cherry", "apricot"} {
+		ids = append(ids, create(t, r, name, "fruit").Id)
+	}
+	create(t, r, "carrot", "vegetable")
+	require.NoError(t, r.Delete(ctx, nil, ids[2]))
+
+	examples, err := r.List(ctx, nil, repo.ExampleListQuery{SortBy: repo.ExampleSortByName})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Apple", "apricot", "banana", "carrot"}, names(examples))
+
+	examples, err = r.List(ctx, nil, repo.ExampleListQuery{SortBy: repo.ExampleSortByID, SortDesc: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"carrot", "apricot", "Apple", "banana"}, names(examples))
+
+	// Filters match substrings ignoring case, offset and limit page through the sorted result
+	examples, err = r.List(ctx, nil, repo.ExampleListQuery{
+		Filter: repo.ExampleFilter{Name: "AP"},
+		SortBy: repo.ExampleSortByName,
+		Offset: 1,
+		Limit:  1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apricot"}, names(examples))
+
+	total, err := r.Count(ctx, nil, repo.ExampleFilter{Alias: "fruit"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+
+	total, err = r.Count(ctx, nil, repo.ExampleFilter{Alias: "fruit", IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+
+	future := time.Now().Add(time.Hour)
+	total, err = r.Count(ctx, nil, repo.ExampleFilter{CreatedAfter: &future})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	total, err = r.Count(ctx, nil, repo.ExampleFilter{CreatedBefore: &future})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+}
+
+func testListByCursor(t *testing.T, r repo.IExampleRepo, _ storeTransactions) {
+	ctx := context.Background()
+
+	for i := 1; i <= 5; i++ {
+		create(t, r, fmt.Sprintf("example %d", i), "")
+	}
+
+	// Newest first; examples created within the same instant are ordered by ID
+	page, err := r.ListByCursor(ctx, nil, repo.ExampleKeysetQuery{SortDesc: true, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example 5", "example 4"}, names(page))
+
+	page, err = r.ListByCursor(ctx, nil, repo.ExampleKeysetQuery{Cursor: repo.NewExampleCursor(page[1]), SortDesc: true, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example 3", "example 2"}, names(page))
+
+	page, err = r.ListByCursor(ctx, nil, repo.ExampleKeysetQuery{Cursor: repo.NewExampleCursor(page[1]), SortDesc: true, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example 1"}, names(page))
+
+	// Reading backward from a cursor returns the preceding page in display order
+	page, err = r.ListByCursor(ctx, nil, repo.ExampleKeysetQuery{Cursor: repo.NewExampleCursor(page[0]), SortDesc: true, Backward: true, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example 3", "example 2"}, names(page))
+
+	page, err = r.ListByCursor(ctx, nil, repo.ExampleKeysetQuery{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example 1", "example 2"}, names(page))
+}
+
+func testTransactionVisibility(t *testing.T, r repo.IExampleRepo, txs storeTransactions) {
+	requireTransactions(t, txs)
+	ctx := context.Background()
+
+	existing := create(t, r, "existing", "before")
+
+	// A rolled back transaction leaves no trace
+	tx := begin(t, txs)
+	created, err := r.Create(ctx, tx, &model.Example{Name: "rolled back"})
+	require.NoError(t, err)
+	existing.Alias = "inside"
+	require.NoError(t, r.Update(ctx, tx, existing))
+
+	// The transaction sees its own writes, nobody else does before the commit
+	_, err = r.GetByID(ctx, tx, created.Id)
+	require.NoError(t, err)
+	_, err = r.GetByID(ctx, nil, created.Id)
+	assertNotFound(t, err)
+
+	require.NoError(t, tx.Rollback())
+	_, err = r.GetByIDWithDeleted(ctx, nil, created.Id)
+	assertNotFound(t, err)
+	found, err := r.GetByID(ctx, nil, existing.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "before", found.Alias)
+	assert.Equal(t, 1, found.Version)
+
+	// A committed transaction is visible afterwards
+	tx = begin(t, txs)
+	committed, err := r.Create(ctx, tx, &model.Example{Name: "committed"})
+	require.NoError(t, err)
+	require.NoError(t, r.Delete(ctx, tx, found.Id))
+	require.NoError(t, tx.Commit())
+
+	_, err = r.GetByID(ctx, nil, committed.Id)
+	require.NoError(t, err)
+	_, err = r.GetByID(ctx, nil, found.Id)
+	assertNotFound(t, err)
+}
+
+func testContextTransaction(t *testing.T, r repo.IExampleRepo, txs storeTransactions) {
+	requireTransactions(t, txs)
+	ctx := context.Background()
+
+	// Called with a no-op transaction, the repository joins the one carried by the context
+	tx := begin(t, txs)
+	txCtx := repo.ContextWithTransaction(ctx, tx)
+	created, err := r.Create(txCtx, repo.NewNoopTransaction(nil), &model.Example{Name: "joined"})
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	_, err = r.GetByIDWithDeleted(ctx, nil, created.Id)
+	assertNotFound(t, err)
+}
+
+func testConcurrentUpdates(t *testing.T, r repo.IExampleRepo, txs storeTransactions) {
+	ctx := context.Background()
+	created := create(t, r, "counter", "")
+
+	// Writers racing on one example either succeed or lose with ErrExampleModified, no update is lost
+	const writers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- inTransaction(ctx, txs, func(tx repo.Transaction) error {
+				example, err := r.GetByID(ctx, tx, created.Id)
+				if err != nil {
+					return err
+				}
+				example.Alias = fmt.Sprintf("writer %d", i)
+				return r.Update(ctx, tx, example)
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.ErrorIs(t, err, model.ErrExampleModified)
+	}
+	assert.Positive(t, succeeded)
+
+	found, err := r.GetByID(ctx, nil, created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 1+succeeded, found.Version)
+}
+
+func testConcurrentCreates(t *testing.T, r repo.IExampleRepo, _ storeTransactions) {
+	ctx := context.Background()
+
+	// Creators of distinct names all succeed with distinct IDs, creators of one name race for it. All start
+	// together, and the contenders only insert once every one of them saw the name free, so the store
+	// rather than a lookup must turn all but one away.
+	const creators = 8
+	start := make(chan struct{})
+	var lookedUp sync.WaitGroup
+	lookedUp.Add(creators)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	ids := make(map[int]bool)
+	taken := 0
+	for i := 0; i < creators; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			created, err := r.Create(ctx, nil, &model.Example{Name: fmt.Sprintf("distinct %d", i)})
+			if assert.NoError(t, err) {
+				mu.Lock()
+				ids[created.Id] = true
+				mu.Unlock()
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := r.FindByName(ctx, nil, "contended")
+			assertNotFound(t, err)
+			lookedUp.Done()
+			lookedUp.Wait()
+
+			_, err = r.Create(ctx, nil, &model.Example{Name: "contended"})
+			if err != nil {
+				assert.True(t, model.IsExampleNameTakenError(err), "got %v", err)
+				mu.Lock()
+				taken++
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Len(t, ids, creators)
+	assert.Equal(t, creators-1, taken)
+
+	total, err := r.Count(ctx, nil, repo.ExampleFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(creators+1), total)
+}
+
+// names returns the names of the examples in order
+func names(examples []*model.Example) []string {
+	result := make([]string, 0, len(examples))
+	for _, example := range examples {
+		result = append(result, example.Name)
+	}
+	return result
+}
@@ -15,6 +15,10 @@ const (
 	MySQLStore StoreType = "mysql"
 	// PostgresStore represents PostgreSQL data store
 	PostgresStore StoreType = "postgres"
+	// SQLiteStore represents SQLite data store
+	SQLiteStore StoreType = "sqlite"
+	// MemoryStore represents the process memory data store
+	MemoryStore StoreType = "memory"
 	// MongoStore represents MongoDB data store
 	MongoStore StoreType = "mongo"
 	// RedisStore represents Redis data store
@@ -58,6 +62,23 @@ type Transaction interface {
 	Options() *TransactionOptions
 }
 
+// transactionContextKey is the context key under which the active transaction is carried
+type transactionContextKey struct{}
+
+// ContextWithTransaction returns a copy of ctx carrying tx, so repositories called with it join the transaction
+func ContextWithTransaction(ctx context.Context, tx Transaction) context.Context {
+	return context.WithValue(ctx, transactionContextKey{}, tx)
+}
+
+// TransactionFromContext returns the transaction carried by ctx, if any
+func TransactionFromContext(ctx context.Context) (Transaction, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	tx, ok := ctx.Value(transactionContextKey{}).(Transaction)
+	return tx, ok && tx != nil
+}
+
 // TransactionHandler defines a higher-level interface for transaction handling with metrics
 type TransactionHandler interface {
 	// ExecuteInTransaction executes the given function within a transaction
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"go-hexagonal/domain/event"
+	"go-hexagonal/domain/model"
+)
+
+// exampleEventMapper translates the domain events of examples into integration events
+var exampleEventMapper = newExampleEventMapper()
+
+// newExampleEventMapper registers a mapping for every domain event of model.Example
+func newExampleEventMapper() *event.Mapper[*model.Example] {
+	mapper := event.NewMapper[*model.Example](event.DefaultTypeRegistry)
+
+	event.RegisterMapping(mapper, func(example *model.Example, evt model.ExampleCreatedEvent) event.Event {
+		// The ID is assigned on insert, after the creation event was raised
+		return event.NewExampleCreatedEvent(example.Id, evt.Name, evt.Alias)
+	})
+	event.RegisterMapping(mapper, func(example *model.Example, evt model.ExampleUpdatedEvent) event.Event {
+		return event.NewExampleUpdatedEvent(evt.ExampleID, evt.Name, evt.Alias)
+	})
+	event.RegisterMapping(mapper, func(example *model.Example, evt model.ExampleDeletedEvent) event.Event {
+		return event.NewExampleDeletedEvent(evt.ExampleID)
+	})
+	event.RegisterMapping(mapper, func(example *model.Example, evt model.ExampleRestoredEvent) event.Event {
+		return event.NewExampleRestoredEvent(evt.ExampleID)
+	})
+
+	return mapper
+}
+
+// integrationEvents maps the example's pending domain events to integration events
+func integrationEvents(ctx context.Context, example *model.Example) ([]event.Event, error) {
+	domainEvents := example.Events()
+	events := make([]event.Event, 0, len(domainEvents))
+	for _, domainEvt := range domainEvents {
+		evt, err := exampleEventMapper.Map(ctx, example, domainEvt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, evt)
+	}
+	return events, nil
+}
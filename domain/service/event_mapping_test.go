@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/event"
+	"go-hexagonal/domain/model"
+)
+
+func TestIntegrationEvents(t *testing.T) {
+	example, err := model.NewExample("name", "alias")
+	require.NoError(t, err)
+	example.Id = 5
+	require.NoError(t, example.Update("renamed", "alias"))
+	example.MarkDeleted()
+	require.NoError(t, example.Restore())
+
+	ctx := event.ContextWithMetadata(context.Background(), event.Metadata{CorrelationID: "flow-1", CausationID: "request-1"})
+	events, err := integrationEvents(ctx, example)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	assert.IsType(t, event.ExampleCreatedEvent{}, events[0])
+	assert.IsType(t, event.ExampleUpdatedEvent{}, events[1])
+	assert.IsType(t, event.ExampleDeletedEvent{}, events[2])
+	assert.IsType(t, event.ExampleRestoredEvent{}, events[3])
+	for _, evt := range events {
+		assert.Equal(t, "5", evt.AggregateID())
+		traceable, ok := evt.(event.Traceable)
+		require.True(t, ok)
+		assert.Equal(t, "flow-1", traceable.CorrelationID())
+		assert.Equal(t, "request-1", traceable.CausationID())
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"go-hexagonal/domain/event"
 	"go-hexagonal/domain/model"
@@ -18,6 +19,8 @@ type ExampleService struct {
 	Repository repo.IExampleRepo
 	CacheRepo  repo.IExampleCacheRepo
 	EventBus   event.EventBus
+	// Outbox records events in the aggregate's transaction, when set they are not published directly
+	Outbox repo.IOutboxRepo
 }
 
 // NewExampleService creates a new example service instance
@@ -36,8 +39,16 @@ func (s *ExampleService) Create(ctx context.Context, name string, alias string)
 		return nil, error_handler.HandleAndConvertError(ctx, err, "create example entity", "validation")
 	}
 
-	// Create a no-operation transaction
-	tr := repo.NewNoopTransaction(s.Repository)
+	// The name check must see the latest writes, which a lagging replica may not have
+	ctx = repo.ContextWithPrimaryReads(ctx)
+
+	// Join the caller's transaction if there is one
+	tr := s.transaction(ctx)
+
+	// Reject names already used by another live example
+	if err := s.ensureNameAvailable(ctx, tr, example.Name, 0); err != nil {
+		return nil, err
+	}
 
 	// Persist the entity
 	createdExample, err := s.Repository.Create(ctx, tr, example)
@@ -45,39 +56,24 @@ func (s *ExampleService) Create(ctx context.Context, name string, alias string)
 		return nil, error_handler.HandleAndWrapError(ctx, err, "persist example", "failed to create example")
 	}
 
-	// Update cache if available
-	if s.CacheRepo != nil {
-		if err := s.CacheRepo.Set(ctx, createdExample); err != nil {
-			log.SugaredLogger.Warnf("Failed to update cache: %v", err)
-		}
+	// Record integration events, in the same transaction when an outbox is configured
+	if err := s.publishEvents(ctx, tr, createdExample); err != nil {
+		return nil, error_handler.HandleAndWrapError(ctx, err, "record example events", "failed to record example events")
 	}
 
-	// Publish domain events if event bus is available
-	if s.EventBus != nil {
-		domainEvents := createdExample.Events()
-		for _, evt := range domainEvents {
-			if domainEvt, ok := evt.(model.ExampleCreatedEvent); ok {
-				// Map domain event to integration event
-				integrationEvent := event.NewExampleCreatedEvent(
-					domainEvt.ExampleID,
-					domainEvt.Name,
-					domainEvt.Alias,
-				)
-
-				if err := s.EventBus.Publish(ctx, integrationEvent); err != nil {
-					log.SugaredLogger.Warnf("Failed to publish event: %v", err)
-				}
-			}
-		}
-	}
+	// Update cache if available
+	s.cacheExample(ctx, createdExample)
 
 	return createdExample, nil
 }
 
 // Delete deletes an example by ID
 func (s *ExampleService) Delete(ctx context.Context, id int) error {
-	// Create a no-operation transaction
-	tr := repo.NewNoopTransaction(s.Repository)
+	// The write depends on what it reads, which a lagging replica must not answer
+	ctx = repo.ContextWithPrimaryReads(ctx)
+
+	// Join the caller's transaction if there is one
+	tr := s.transaction(ctx)
 
 	// Get the example to be deleted
 	example, err := s.Repository.GetByID(ctx, tr, id)
@@ -88,11 +84,16 @@ func (s *ExampleService) Delete(ctx context.Context, id int) error {
 	// Mark example as deleted (generates domain event)
 	example.MarkDeleted()
 
-	// Delete from repository
+	// Soft delete in repository
 	if err := s.Repository.Delete(ctx, tr, id); err != nil {
 		return error_handler.HandleAndWrapError(ctx, err, "delete example", "failed to delete example")
 	}
 
+	// Record integration events, in the same transaction when an outbox is configured
+	if err := s.publishEvents(ctx, tr, example); err != nil {
+		return error_handler.HandleAndWrapError(ctx, err, "record example events", "failed to record example events")
+	}
+
 	// Invalidate cache if available
 	if s.CacheRepo != nil {
 		if err := s.CacheRepo.Delete(ctx, id); err != nil {
@@ -100,18 +101,86 @@ func (s *ExampleService) Delete(ctx context.Context, id int) error {
 		}
 	}
 
-	// Publish domain events if event bus is available
-	if s.EventBus != nil {
-		domainEvents := example.Events()
-		for _, evt := range domainEvents {
-			if domainEvt, ok := evt.(model.ExampleDeletedEvent); ok {
-				// Map domain event to integration event
-				integrationEvent := event.NewExampleDeletedEvent(domainEvt.ExampleID)
-
-				if err := s.EventBus.Publish(ctx, integrationEvent); err != nil {
-					log.SugaredLogger.Warnf("Failed to publish event: %v", err)
-				}
-			}
+	return nil
+}
+
+// Restore restores a soft deleted example
+func (s *ExampleService) Restore(ctx context.Context, id int) error {
+	// The write depends on what it reads, which a lagging replica must not answer
+	ctx = repo.ContextWithPrimaryReads(ctx)
+
+	// Join the caller's transaction if there is one
+	tr := s.transaction(ctx)
+
+	// Get the example to be restored, including deleted ones
+	example, err := s.Repository.GetByIDWithDeleted(ctx, tr, id)
+	if err != nil {
+		return error_handler.HandleAndWrapError(ctx, err, "get example for restore", "example not found")
+	}
+
+	// Restore the entity (generates domain event)
+	if err := example.Restore(); err != nil {
+		return error_handler.HandleAndWrapError(ctx, err, "restore example entity", "example is not deleted")
+	}
+
+	// The name may have been taken while the example was deleted
+	if err := s.ensureNameAvailable(ctx, tr, example.Name, id); err != nil {
+		return err
+	}
+
+	// Clear the deletion mark in repository
+	if err := s.Repository.Restore(ctx, tr, id); err != nil {
+		return error_handler.HandleAndWrapError(ctx, err, "restore example", "failed to restore example")
+	}
+
+	// Record integration events, in the same transaction when an outbox is configured
+	if err := s.publishEvents(ctx, tr, example); err != nil {
+		return error_handler.HandleAndWrapError(ctx, err, "record example events", "failed to record example events")
+	}
+
+	// Drop any stale cache entry, the next read caches the restored example
+	if s.CacheRepo != nil {
+		if err := s.CacheRepo.Delete(ctx, id); err != nil {
+			log.SugaredLogger.Warnf("Failed to invalidate cache: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// Purge permanently removes an example, whether soft deleted or not
+func (s *ExampleService) Purge(ctx context.Context, id int) error {
+	// The write depends on what it reads, which a lagging replica must not answer
+	ctx = repo.ContextWithPrimaryReads(ctx)
+
+	// Join the caller's transaction if there is one
+	tr := s.transaction(ctx)
+
+	// Get the example to be purged, including deleted ones
+	example, err := s.Repository.GetByIDWithDeleted(ctx, tr, id)
+	if err != nil {
+		return error_handler.HandleAndWrapError(ctx, err, "get example for purge", "example not found")
+	}
+
+	// A live example disappears now, so announce its deletion
+	if !example.IsDeleted() {
+		example.MarkDeleted()
+	}
+
+	// Remove from repository
+	if err := s.Repository.Purge(ctx, tr, id); err != nil {
+		return error_handler.HandleAndWrapError(ctx, err, "purge example", "failed to purge example")
+	}
+
+	// Record integration events, in the same transaction when an outbox is configured
+	if err := s.publishEvents(ctx, tr, example); err != nil {
+		return error_handler.HandleAndWrapError(ctx, err, "record example events", "failed to record example events")
+	}
+
+	// Invalidate cache if available
+	if s.CacheRepo != nil {
+		if err := s.CacheRepo.Delete(ctx, id); err != nil {
+			log.SugaredLogger.Warnf("Failed to invalidate cache: %v", err)
 		}
 	}
 
@@ -119,9 +188,12 @@ func (s *ExampleService) Delete(ctx context.Context, id int) error {
 }
 
 // Update updates an existing example
-func (s *ExampleService) Update(ctx context.Context, id int, name string, alias string) error {
-	// Create a no-operation transaction
-	tr := repo.NewNoopTransaction(s.Repository)
+func (s *ExampleService) Update(ctx context.Context, id int, name string, alias string, expectedVersion int) error {
+	// The write depends on what it reads, which a lagging replica must not answer
+	ctx = repo.ContextWithPrimaryReads(ctx)
+
+	// Join the caller's transaction if there is one
+	tr := s.transaction(ctx)
 
 	// Get the example to be updated
 	example, err := s.Repository.GetByID(ctx, tr, id)
@@ -129,38 +201,75 @@ func (s *ExampleService) Update(ctx context.Context, id int, name string, alias
 		return error_handler.HandleAndWrapError(ctx, err, "get example for update", "example not found")
 	}
 
+	// The client edited an older version than the stored one
+	if expectedVersion > 0 && example.Version != expectedVersion {
+		return error_handler.HandleAndWrapError(ctx, model.ErrExampleModified, "check example version", "failed to update example")
+	}
+
+	previousKey := model.NormalizeExampleName(example.Name)
+
 	// Update the entity (generates domain event)
 	if err := example.Update(name, alias); err != nil {
 		return error_handler.HandleAndConvertError(ctx, err, "update example entity", "invalid update data")
 	}
 
-	// Persist the changes
+	// Only a rename can collide with another example
+	if model.NormalizeExampleName(example.Name) != previousKey {
+		if err := s.ensureNameAvailable(ctx, tr, example.Name, id); err != nil {
+			return err
+		}
+	}
+
+	// Persist the changes, the repository rejects the write if the version changed since the read
 	if err := s.Repository.Update(ctx, tr, example); err != nil {
+		if model.IsExampleModifiedError(err) && s.CacheRepo != nil {
+			// The cached copy is stale, drop it so the next read sees the winning version
+			if err := s.CacheRepo.Delete(ctx, id); err != nil {
+				log.SugaredLogger.Warnf("Failed to evict stale cache entry: %v", err)
+			}
+		}
 		return error_handler.HandleAndWrapError(ctx, err, "persist example update", "failed to update example")
 	}
 
+	// Record integration events, in the same transaction when an outbox is configured
+	if err := s.publishEvents(ctx, tr, example); err != nil {
+		return error_handler.HandleAndWrapError(ctx, err, "record example events", "failed to record example events")
+	}
+
 	// Update cache if available
-	if s.CacheRepo != nil {
-		if err := s.CacheRepo.Set(ctx, example); err != nil {
-			log.SugaredLogger.Warnf("Failed to update cache: %v", err)
+	s.cacheExample(ctx, example)
+
+	return nil
+}
+
+// publishEvents maps the example's domain events to integration events and hands them on.
+// With an outbox they are recorded in the caller's transaction and delivered by the relay once it
+// commits. Without one they are published directly, best effort.
+func (s *ExampleService) publishEvents(ctx context.Context, tr repo.Transaction, example *model.Example) error {
+	events, err := integrationEvents(ctx, example)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	if s.Outbox != nil {
+		messages := make([]*repo.OutboxMessage, 0, len(events))
+		for _, evt := range events {
+			message, err := repo.NewOutboxMessage(evt)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, message)
 		}
+		return s.Outbox.Save(ctx, tr, messages...)
 	}
 
-	// Publish domain events if event bus is available
 	if s.EventBus != nil {
-		domainEvents := example.Events()
-		for _, evt := range domainEvents {
-			if domainEvt, ok := evt.(model.ExampleUpdatedEvent); ok {
-				// Map domain event to integration event
-				integrationEvent := event.NewExampleUpdatedEvent(
-					domainEvt.ExampleID,
-					domainEvt.Name,
-					domainEvt.Alias,
-				)
-
-				if err := s.EventBus.Publish(ctx, integrationEvent); err != nil {
-					log.SugaredLogger.Warnf("Failed to publish event: %v", err)
-				}
+		for _, evt := range events {
+			if err := s.EventBus.Publish(ctx, evt); err != nil {
+				log.SugaredLogger.Warnf("Failed to publish event: %v", err)
 			}
 		}
 	}
@@ -168,6 +277,52 @@ func (s *ExampleService) Update(ctx context.Context, id int, name string, alias
 	return nil
 }
 
+// transaction returns the transaction carried by ctx, or a no-op transaction
+// when the call is not part of a unit of work
+func (s *ExampleService) transaction(ctx context.Context) repo.Transaction {
+	if tx, ok := repo.TransactionFromContext(ctx); ok {
+		return tx
+	}
+	return repo.NewNoopTransaction(s.Repository)
+}
+
+// cacheExample stores the example in the cache if available.
+// Inside a transaction the entry is evicted instead, the cache must only hold committed state.
+func (s *ExampleService) cacheExample(ctx context.Context, example *model.Example) {
+	if s.CacheRepo == nil {
+		return
+	}
+
+	if _, ok := repo.TransactionFromContext(ctx); ok {
+		if err := s.CacheRepo.Delete(ctx, example.Id); err != nil {
+			log.SugaredLogger.Warnf("Failed to invalidate cache: %v", err)
+		}
+		return
+	}
+
+	if err := s.CacheRepo.Set(ctx, example); err != nil {
+		log.SugaredLogger.Warnf("Failed to update cache: %v", err)
+	}
+}
+
+// ensureNameAvailable checks that no live example other than exceptID uses the name.
+// The unique index remains the final guard against concurrent writers.
+func (s *ExampleService) ensureNameAvailable(ctx context.Context, tr repo.Transaction, name string, exceptID int) error {
+	existing, err := s.Repository.FindByName(ctx, tr, name)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil
+		}
+		return error_handler.HandleAndWrapError(ctx, err, "check example name", "failed to check example name")
+	}
+
+	if existing.Id != exceptID {
+		return error_handler.HandleAndWrapError(ctx, model.NewExampleNameTakenError(name), "check example name", "example name already exists")
+	}
+
+	return nil
+}
+
 // Get retrieves an example by ID
 func (s *ExampleService) Get(ctx context.Context, id int) (*model.Example, error) {
 	// Try to get from cache first
@@ -179,8 +334,8 @@ func (s *ExampleService) Get(ctx context.Context, id int) (*model.Example, error
 		log.SugaredLogger.Debugf("Cache miss for example ID %d: %v", id, err)
 	}
 
-	// Create a no-operation transaction
-	tr := repo.NewNoopTransaction(s.Repository)
+	// Join the caller's transaction if there is one
+	tr := s.transaction(ctx)
 
 	// Get from repository
 	example, err := s.Repository.GetByID(ctx, tr, id)
@@ -189,10 +344,21 @@ func (s *ExampleService) Get(ctx context.Context, id int) (*model.Example, error
 	}
 
 	// Update cache if available
-	if s.CacheRepo != nil {
-		if err := s.CacheRepo.Set(ctx, example); err != nil {
-			log.SugaredLogger.Warnf("Failed to update cache: %v", err)
-		}
+	s.cacheExample(ctx, example)
+
+	return example, nil
+}
+
+// GetWithDeleted retrieves an example by ID even if it is soft deleted
+// The cache only holds live examples, so this always reads the repository
+func (s *ExampleService) GetWithDeleted(ctx context.Context, id int) (*model.Example, error) {
+	// Join the caller's transaction if there is one
+	tr := s.transaction(ctx)
+
+	// Get from repository
+	example, err := s.Repository.GetByIDWithDeleted(ctx, tr, id)
+	if err != nil {
+		return nil, error_handler.HandleError(ctx, err, "get example by ID with deleted")
 	}
 
 	return example, nil
@@ -209,8 +375,8 @@ func (s *ExampleService) FindByName(ctx context.Context, name string) (*model.Ex
 		log.SugaredLogger.Debugf("Cache miss for example name %s: %v", name, err)
 	}
 
-	// Create a no-operation transaction
-	tr := repo.NewNoopTransaction(s.Repository)
+	// Join the caller's transaction if there is one
+	tr := s.transaction(ctx)
 
 	// Get from repository
 	example, err := s.Repository.FindByName(ctx, tr, name)
@@ -219,11 +385,89 @@ func (s *ExampleService) FindByName(ctx context.Context, name string) (*model.Ex
 	}
 
 	// Update cache if available
-	if s.CacheRepo != nil {
-		if err := s.CacheRepo.Set(ctx, example); err != nil {
-			log.SugaredLogger.Warnf("Failed to update cache: %v", err)
+	s.cacheExample(ctx, example)
+
+	return example, nil
+}
+
+// List retrieves a page of examples and the total number of matches
+func (s *ExampleService) List(ctx context.Context, query repo.ExampleListQuery) ([]*model.Example, int64, error) {
+	// Join the caller's transaction if there is one
+	tr := s.transaction(ctx)
+
+	// Repositories that read the page and the total at once are asked once
+	if pager, ok := s.Repository.(repo.IExamplePageRepo); ok {
+		examples, total, err := pager.ListWithTotal(ctx, tr, query)
+		if err != nil {
+			return nil, 0, error_handler.HandleAndWrapError(ctx, err, "list examples", "failed to list examples")
 		}
+		return examples, total, nil
 	}
 
-	return example, nil
+	// Count matching examples
+	total, err := s.Repository.Count(ctx, tr, query.Filter)
+	if err != nil {
+		return nil, 0, error_handler.HandleAndWrapError(ctx, err, "count examples", "failed to count examples")
+	}
+
+	// Skip the page query when nothing matches or the offset is past the end
+	if total == 0 || int64(query.Offset) >= total {
+		return []*model.Example{}, total, nil
+	}
+
+	// Get the requested page
+	examples, err := s.Repository.List(ctx, tr, query)
+	if err != nil {
+		return nil, 0, error_handler.HandleAndWrapError(ctx, err, "list examples", "failed to list examples")
+	}
+
+	return examples, total, nil
+}
+
+// ListByCursor retrieves a page of examples using keyset pagination
+func (s *ExampleService) ListByCursor(ctx context.Context, query repo.ExampleKeysetQuery) (*repo.ExampleCursorPage, error) {
+	// Join the caller's transaction if there is one
+	tr := s.transaction(ctx)
+
+	// Fetch one extra row to find out whether another page exists in the reading direction
+	limit := query.Limit
+	query.Limit = limit + 1
+
+	examples, err := s.Repository.ListByCursor(ctx, tr, query)
+	if err != nil {
+		return nil, error_handler.HandleAndWrapError(ctx, err, "list examples by cursor", "failed to list examples")
+	}
+
+	hasMore := len(examples) > limit
+	if hasMore {
+		// The extra row is the one farthest from the cursor
+		if query.Backward {
+			examples = examples[len(examples)-limit:]
+		} else {
+			examples = examples[:limit]
+		}
+	}
+
+	page := &repo.ExampleCursorPage{Items: examples}
+	if len(examples) == 0 {
+		return page, nil
+	}
+
+	first, last := examples[0], examples[len(examples)-1]
+	if query.Backward {
+		// Reading backward always leaves the cursor row as a following page
+		page.NextCursor = repo.NewExampleCursor(last)
+		if hasMore {
+			page.PrevCursor = repo.NewExampleCursor(first)
+		}
+	} else {
+		if hasMore {
+			page.NextCursor = repo.NewExampleCursor(last)
+		}
+		if query.Cursor != nil {
+			page.PrevCursor = repo.NewExampleCursor(first)
+		}
+	}
+
+	return page, nil
 }
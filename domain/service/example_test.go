@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"go-hexagonal/adapter/repository/memory"
 	"go-hexagonal/domain/event"
 	"go-hexagonal/domain/model"
 	"go-hexagonal/domain/repo"
@@ -60,6 +63,45 @@ func (m *MockExampleRepo) FindByName(ctx context.Context, tr repo.Transaction, n
 	return nil, args.Error(1)
 }
 
+func (m *MockExampleRepo) List(ctx context.Context, tr repo.Transaction, query repo.ExampleListQuery) ([]*model.Example, error) {
+	args := m.Called(ctx, tr, query)
+	if e, ok := args.Get(0).([]*model.Example); ok {
+		return e, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockExampleRepo) Count(ctx context.Context, tr repo.Transaction, filter repo.ExampleFilter) (int64, error) {
+	args := m.Called(ctx, tr, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockExampleRepo) Restore(ctx context.Context, tr repo.Transaction, id int) error {
+	args := m.Called(ctx, tr, id)
+	return args.Error(0)
+}
+
+func (m *MockExampleRepo) Purge(ctx context.Context, tr repo.Transaction, id int) error {
+	args := m.Called(ctx, tr, id)
+	return args.Error(0)
+}
+
+func (m *MockExampleRepo) GetByIDWithDeleted(ctx context.Context, tr repo.Transaction, id int) (*model.Example, error) {
+	args := m.Called(ctx, tr, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Example), args.Error(1)
+}
+
+func (m *MockExampleRepo) ListByCursor(ctx context.Context, tr repo.Transaction, query repo.ExampleKeysetQuery) ([]*model.Example, error) {
+	args := m.Called(ctx, tr, query)
+	if e, ok := args.Get(0).([]*model.Example); ok {
+		return e, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 // Create Mock cache repository
 type MockExampleCacheRepo struct {
 	mock.Mock
@@ -122,6 +164,43 @@ func (m *MockEventBus) Unsubscribe(handler event.EventHandler) {
 	m.Called(handler)
 }
 
+type MockOutboxRepo struct {
+	mock.Mock
+}
+
+// Save implements IOutboxRepo interface's Save method
+func (m *MockOutboxRepo) Save(ctx context.Context, tr repo.Transaction, messages ...*repo.OutboxMessage) error {
+	args := m.Called(ctx, tr, messages)
+	return args.Error(0)
+}
+
+// FetchPending implements IOutboxRepo interface's FetchPending method
+func (m *MockOutboxRepo) FetchPending(ctx context.Context, tr repo.Transaction, limit int) ([]*repo.OutboxMessage, error) {
+	args := m.Called(ctx, tr, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repo.OutboxMessage), args.Error(1)
+}
+
+// MarkPublished implements IOutboxRepo interface's MarkPublished method
+func (m *MockOutboxRepo) MarkPublished(ctx context.Context, tr repo.Transaction, id int64) error {
+	args := m.Called(ctx, tr, id)
+	return args.Error(0)
+}
+
+// MarkFailed implements IOutboxRepo interface's MarkFailed method
+func (m *MockOutboxRepo) MarkFailed(ctx context.Context, tr repo.Transaction, id int64, reason string) error {
+	args := m.Called(ctx, tr, id, reason)
+	return args.Error(0)
+}
+
+// Park implements IOutboxRepo interface's Park method
+func (m *MockOutboxRepo) Park(ctx context.Context, tr repo.Transaction, id int64, reason string) error {
+	args := m.Called(ctx, tr, id, reason)
+	return args.Error(0)
+}
+
 // Create Mock transaction object
 type MockTransaction struct {
 	mock.Mock
@@ -185,6 +264,7 @@ func TestExampleService_Create(t *testing.T) {
 	input.Id = 1 // Ensure ID is set
 
 	// Mock dependency behavior
+	mockRepo.On("FindByName", mock.Anything, mock.Anything, "Test").Return(nil, repo.ErrNotFound)
 	mockRepo.On("Create", mock.Anything, mock.Anything, mock.AnythingOfType("*model.Example")).Run(func(args mock.Arguments) {
 		// When Create is called, add events to created object and ensure ID is set
 		example := args.Get(2).(*model.Example)
@@ -209,6 +289,101 @@ func TestExampleService_Create(t *testing.T) {
 	mockEventBus.AssertExpectations(t)
 }
 
+func TestExampleService_Create_NameTaken(t *testing.T) {
+	mockRepo := new(MockExampleRepo)
+	mockCacheRepo := new(MockExampleCacheRepo)
+	service := NewExampleService(mockRepo, mockCacheRepo)
+
+	// Another live example already uses the name
+	mockRepo.On("FindByName", mock.Anything, mock.Anything, "Test").Return(&model.Example{Id: 7, Name: "TEST"}, nil)
+
+	result, err := service.Create(context.Background(), "Test", "test-alias")
+
+	assert.Nil(t, result)
+	assert.True(t, model.IsExampleNameTakenError(err))
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExampleService_Create_InTransaction(t *testing.T) {
+	mockRepo := new(MockExampleRepo)
+	mockCacheRepo := new(MockExampleCacheRepo)
+	service := NewExampleService(mockRepo, mockCacheRepo)
+
+	// The use case carries its transaction in the context
+	tx := repo.NewBaseTransaction(context.Background(), repo.MySQLStore, nil)
+	ctx := repo.ContextWithTransaction(context.Background(), tx)
+
+	mockRepo.On("FindByName", mock.Anything, tx, "Test").Return(nil, repo.ErrNotFound)
+	mockRepo.On("Create", mock.Anything, tx, mock.AnythingOfType("*model.Example")).Run(func(args mock.Arguments) {
+		args.Get(2).(*model.Example).Id = 1
+	}).Return(&model.Example{Id: 1, Name: "Test", Alias: "test-alias"}, nil)
+	// Uncommitted state must not reach the cache
+	mockCacheRepo.On("Delete", mock.Anything, 1).Return(nil)
+
+	result, err := service.Create(ctx, "Test", "test-alias")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Id)
+	mockCacheRepo.AssertNotCalled(t, "Set", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+	mockCacheRepo.AssertExpectations(t)
+}
+
+func TestExampleService_Create_Outbox(t *testing.T) {
+	mockRepo := new(MockExampleRepo)
+	mockEventBus := new(MockEventBus)
+	mockOutbox := new(MockOutboxRepo)
+
+	service := NewExampleService(mockRepo, nil)
+	service.EventBus = mockEventBus
+	service.Outbox = mockOutbox
+
+	tx := repo.NewBaseTransaction(context.Background(), repo.MySQLStore, nil)
+	ctx := repo.ContextWithTransaction(context.Background(), tx)
+
+	mockRepo.On("FindByName", mock.Anything, tx, "Test").Return(nil, repo.ErrNotFound)
+	created, err := model.NewExample("Test", "test-alias")
+	assert.NoError(t, err)
+	created.Id = 1
+	mockRepo.On("Create", mock.Anything, tx, mock.AnythingOfType("*model.Example")).Return(created, nil)
+	// The event is recorded in the use case transaction, carrying the ID assigned on insert
+	mockOutbox.On("Save", mock.Anything, tx, mock.MatchedBy(func(messages []*repo.OutboxMessage) bool {
+		return len(messages) == 1 &&
+			messages[0].EventName == event.ExampleCreatedEventName &&
+			messages[0].AggregateID == "1" &&
+			messages[0].EventID != ""
+	})).Return(nil)
+
+	_, err = service.Create(ctx, "Test", "test-alias")
+
+	assert.NoError(t, err)
+	mockEventBus.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+	mockOutbox.AssertExpectations(t)
+}
+
+func TestExampleService_Create_OutboxFailure(t *testing.T) {
+	mockRepo := new(MockExampleRepo)
+	mockOutbox := new(MockOutboxRepo)
+
+	service := NewExampleService(mockRepo, nil)
+	service.Outbox = mockOutbox
+
+	mockRepo.On("FindByName", mock.Anything, mock.Anything, "Test").Return(nil, repo.ErrNotFound)
+	created, err := model.NewExample("Test", "test-alias")
+	assert.NoError(t, err)
+	mockRepo.On("Create", mock.Anything, mock.Anything, mock.AnythingOfType("*model.Example")).Return(created, nil)
+	mockOutbox.On("Save", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("insert failed"))
+
+	// The failure surfaces so the transaction rolls back together with the insert
+	result, err := service.Create(context.Background(), "Test", "test-alias")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	mockOutbox.AssertExpectations(t)
+}
+
 // Test Delete method
 func TestExampleService_Delete(t *testing.T) {
 	mockRepo := new(MockExampleRepo)
@@ -311,6 +486,7 @@ func TestExampleService_Update(t *testing.T) {
 		exampleId   int
 		newName     string
 		newAlias    string
+		version     int
 		wantErr     bool
 		expectedErr error
 	}{
@@ -323,6 +499,7 @@ func TestExampleService_Update(t *testing.T) {
 					Alias: "original-alias",
 				}
 				mockRepo.On("GetByID", mock.Anything, mock.Anything, 1).Return(example, nil)
+				mockRepo.On("FindByName", mock.Anything, mock.Anything, "Updated Name").Return(nil, repo.ErrNotFound)
 				mockRepo.On("Update", mock.Anything, mock.Anything, mock.AnythingOfType("*model.Example")).Return(nil)
 				mockCacheRepo.On("Set", mock.Anything, mock.AnythingOfType("*model.Example")).Return(nil)
 				mockEventBus.On("Publish", mock.Anything, mock.Anything).Return(nil)
@@ -369,6 +546,7 @@ func TestExampleService_Update(t *testing.T) {
 					Alias: "original-alias",
 				}
 				mockRepo.On("GetByID", mock.Anything, mock.Anything, 4).Return(example, nil)
+				mockRepo.On("FindByName", mock.Anything, mock.Anything, "Updated Name").Return(nil, repo.ErrNotFound)
 				mockRepo.On("Update", mock.Anything, mock.Anything, mock.AnythingOfType("*model.Example")).Return(errors.New("update error"))
 			},
 			exampleId: 4,
@@ -376,6 +554,97 @@ func TestExampleService_Update(t *testing.T) {
 			newAlias:  "updated-alias",
 			wantErr:   true,
 		},
+		{
+			name: "Concurrent modification evicts cache",
+			setupMocks: func() {
+				example := &model.Example{
+					Id:      5,
+					Name:    "Original Name",
+					Alias:   "original-alias",
+					Version: 3,
+				}
+				mockRepo.On("GetByID", mock.Anything, mock.Anything, 5).Return(example, nil)
+				mockRepo.On("FindByName", mock.Anything, mock.Anything, "Updated Name").Return(nil, repo.ErrNotFound)
+				mockRepo.On("Update", mock.Anything, mock.Anything, mock.AnythingOfType("*model.Example")).Return(model.ErrExampleModified)
+				mockCacheRepo.On("Delete", mock.Anything, 5).Return(nil)
+			},
+			exampleId:   5,
+			newName:     "Updated Name",
+			newAlias:    "updated-alias",
+			wantErr:     true,
+			expectedErr: model.ErrExampleModified,
+		},
+		{
+			name: "New name already taken",
+			setupMocks: func() {
+				example := &model.Example{
+					Id:    6,
+					Name:  "Original Name",
+					Alias: "original-alias",
+				}
+				mockRepo.On("GetByID", mock.Anything, mock.Anything, 6).Return(example, nil)
+				mockRepo.On("FindByName", mock.Anything, mock.Anything, "Updated Name").Return(&model.Example{Id: 9, Name: "updated name"}, nil)
+			},
+			exampleId:   6,
+			newName:     "Updated Name",
+			newAlias:    "updated-alias",
+			wantErr:     true,
+			expectedErr: model.ErrExampleNameTaken,
+		},
+		{
+			name: "Changing only the case skips the name check",
+			setupMocks: func() {
+				example := &model.Example{
+					Id:    7,
+					Name:  "Original Name",
+					Alias: "original-alias",
+				}
+				mockRepo.On("GetByID", mock.Anything, mock.Anything, 7).Return(example, nil)
+				mockRepo.On("Update", mock.Anything, mock.Anything, mock.AnythingOfType("*model.Example")).Return(nil)
+				mockCacheRepo.On("Set", mock.Anything, mock.AnythingOfType("*model.Example")).Return(nil)
+				mockEventBus.On("Publish", mock.Anything, mock.Anything).Return(nil)
+			},
+			exampleId: 7,
+			newName:   "ORIGINAL NAME",
+			newAlias:  "original-alias",
+		},
+		{
+			name: "Client edited an older version",
+			setupMocks: func() {
+				example := &model.Example{
+					Id:      8,
+					Name:    "Original Name",
+					Alias:   "original-alias",
+					Version: 4,
+				}
+				mockRepo.On("GetByID", mock.Anything, mock.Anything, 8).Return(example, nil)
+			},
+			exampleId:   8,
+			newName:     "Updated Name",
+			newAlias:    "updated-alias",
+			version:     3,
+			wantErr:     true,
+			expectedErr: model.ErrExampleModified,
+		},
+		{
+			name: "Client edited the current version",
+			setupMocks: func() {
+				example := &model.Example{
+					Id:      9,
+					Name:    "Original Name",
+					Alias:   "original-alias",
+					Version: 4,
+				}
+				mockRepo.On("GetByID", mock.Anything, mock.Anything, 9).Return(example, nil)
+				mockRepo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(e *model.Example) bool { return e.Version == 4 })).Return(nil)
+				mockCacheRepo.On("Set", mock.Anything, mock.AnythingOfType("*model.Example")).Return(nil)
+				mockEventBus.On("Publish", mock.Anything, mock.Anything).Return(nil)
+			},
+			exampleId: 9,
+			newName:   "Original Name",
+			newAlias:  "updated-alias",
+			version:   4,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -388,7 +657,7 @@ func TestExampleService_Update(t *testing.T) {
 
 			// Execute test
 			ctx := context.Background()
-			err := service.Update(ctx, tc.exampleId, tc.newName, tc.newAlias)
+			err := service.Update(ctx, tc.exampleId, tc.newName, tc.newAlias, tc.version)
 
 			// Verify results
 			if tc.wantErr {
@@ -604,3 +873,436 @@ func TestExampleService_FindByName(t *testing.T) {
 		})
 	}
 }
+
+// Test List method
+func TestExampleService_List(t *testing.T) {
+	mockRepo := new(MockExampleRepo)
+
+	// Create service instance
+	service := NewExampleService(mockRepo, nil)
+
+	query := repo.ExampleListQuery{
+		Filter: repo.ExampleFilter{Name: "test"},
+		SortBy: repo.ExampleSortByCreatedAt,
+		Offset: 0,
+		Limit:  10,
+	}
+
+	testCases := []struct {
+		name          string
+		query         repo.ExampleListQuery
+		setupMocks    func()
+		expectedTotal int64
+		expectedLen   int
+		wantErr       bool
+	}{
+		{
+			name:  "List examples page",
+			query: query,
+			setupMocks: func() {
+				mockRepo.On("Count", mock.Anything, mock.Anything, query.Filter).Return(int64(2), nil)
+				mockRepo.On("List", mock.Anything, mock.Anything, query).Return([]*model.Example{
+					{Id: 1, Name: "test-1"},
+					{Id: 2, Name: "test-2"},
+				}, nil)
+			},
+			expectedTotal: 2,
+			expectedLen:   2,
+		},
+		{
+			name:  "Offset past the end skips page query",
+			query: repo.ExampleListQuery{Filter: query.Filter, Offset: 20, Limit: 10},
+			setupMocks: func() {
+				mockRepo.On("Count", mock.Anything, mock.Anything, query.Filter).Return(int64(5), nil)
+			},
+			expectedTotal: 5,
+			expectedLen:   0,
+		},
+		{
+			name:  "Count failure",
+			query: query,
+			setupMocks: func() {
+				mockRepo.On("Count", mock.Anything, mock.Anything, query.Filter).Return(int64(0), errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Set mock behavior
+			mockRepo.ExpectedCalls = nil
+			mockRepo.Calls = nil
+			tc.setupMocks()
+
+			// Execute test
+			examples, total, err := service.List(context.Background(), tc.query)
+
+			// Verify results
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, examples)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTotal, total)
+				assert.Len(t, examples, tc.expectedLen)
+			}
+
+			// Verify Mock calls
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// pagingExampleRepo reads a page and its total at once
+type pagingExampleRepo struct {
+	MockExampleRepo
+}
+
+func (m *pagingExampleRepo) ListWithTotal(ctx context.Context, tr repo.Transaction, query repo.ExampleListQuery) ([]*model.Example, int64, error) {
+	args := m.Called(ctx, tr, query)
+	return args.Get(0).([]*model.Example), args.Get(1).(int64), args.Error(2)
+}
+
+func TestExampleService_ListWithTotal(t *testing.T) {
+	mockRepo := new(pagingExampleRepo)
+	service := NewExampleService(mockRepo, nil)
+	query := repo.ExampleListQuery{Offset: 1, Limit: 1}
+
+	// Count and List are not called when the repository reads both at once
+	mockRepo.On("ListWithTotal", mock.Anything, mock.Anything, query).Return([]*model.Example{{Id: 2, Name: "test-2"}}, int64(3), nil)
+
+	examples, total, err := service.List(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, examples, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExampleService_ListByCursor(t *testing.T) {
+	mockRepo := new(MockExampleRepo)
+
+	// Create service instance
+	service := NewExampleService(mockRepo, nil)
+
+	now := time.Now()
+	rows := func(ids ...int) []*model.Example {
+		examples := make([]*model.Example, 0, len(ids))
+		for _, id := range ids {
+			examples = append(examples, &model.Example{Id: id, CreatedAt: now})
+		}
+		return examples
+	}
+	cursor := &repo.ExampleCursor{ID: 3, CreatedAt: now}
+
+	testCases := []struct {
+		name         string
+		query        repo.ExampleKeysetQuery
+		rows         []*model.Example
+		expectedIDs  []int
+		expectedNext *repo.ExampleCursor
+		expectedPrev *repo.ExampleCursor
+		wantErr      bool
+	}{
+		{
+			name:         "First page with more rows",
+			query:        repo.ExampleKeysetQuery{Limit: 2},
+			rows:         rows(1, 2, 3),
+			expectedIDs:  []int{1, 2},
+			expectedNext: &repo.ExampleCursor{ID: 2, CreatedAt: now},
+		},
+		{
+			name:         "Forward to last page",
+			query:        repo.ExampleKeysetQuery{Cursor: cursor, Limit: 2},
+			rows:         rows(4, 5),
+			expectedIDs:  []int{4, 5},
+			expectedPrev: &repo.ExampleCursor{ID: 4, CreatedAt: now},
+		},
+		{
+			name:         "Backward with more rows",
+			query:        repo.ExampleKeysetQuery{Cursor: cursor, Backward: true, Limit: 1},
+			rows:         rows(1, 2),
+			expectedIDs:  []int{2},
+			expectedNext: &repo.ExampleCursor{ID: 2, CreatedAt: now},
+			expectedPrev: &repo.ExampleCursor{ID: 2, CreatedAt: now},
+		},
+		{
+			name:        "Empty page",
+			query:       repo.ExampleKeysetQuery{Cursor: cursor, Limit: 2},
+			rows:        rows(),
+			expectedIDs: []int{},
+		},
+		{
+			name:    "Repository failure",
+			query:   repo.ExampleKeysetQuery{Limit: 2},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Set mock behavior
+			mockRepo.ExpectedCalls = nil
+			mockRepo.Calls = nil
+
+			expectedQuery := tc.query
+			expectedQuery.Limit = tc.query.Limit + 1
+			if tc.wantErr {
+				mockRepo.On("ListByCursor", mock.Anything, mock.Anything, expectedQuery).Return(nil, errors.New("db error"))
+			} else {
+				mockRepo.On("ListByCursor", mock.Anything, mock.Anything, expectedQuery).Return(tc.rows, nil)
+			}
+
+			// Execute test
+			page, err := service.ListByCursor(context.Background(), tc.query)
+
+			// Verify results
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, page)
+			} else {
+				assert.NoError(t, err)
+				ids := make([]int, 0, len(page.Items))
+				for _, item := range page.Items {
+					ids = append(ids, item.Id)
+				}
+				assert.Equal(t, tc.expectedIDs, ids)
+				assert.Equal(t, tc.expectedNext, page.NextCursor)
+				assert.Equal(t, tc.expectedPrev, page.PrevCursor)
+			}
+
+			// Verify Mock calls
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExampleService_Restore(t *testing.T) {
+	mockRepo := new(MockExampleRepo)
+	mockCacheRepo := new(MockExampleCacheRepo)
+	mockEventBus := new(MockEventBus)
+
+	// Create service instance
+	service := NewExampleService(mockRepo, mockCacheRepo)
+	service.EventBus = mockEventBus
+
+	deletedAt := time.Now()
+
+	testCases := []struct {
+		name        string
+		setupMocks  func()
+		exampleId   int
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name: "Successfully restore example",
+			setupMocks: func() {
+				mockRepo.On("GetByIDWithDeleted", mock.Anything, mock.Anything, 1).Return(&model.Example{
+					Id:        1,
+					Name:      "Test Example",
+					DeletedAt: &deletedAt,
+				}, nil)
+				mockRepo.On("FindByName", mock.Anything, mock.Anything, "Test Example").Return(nil, repo.ErrNotFound)
+				mockRepo.On("Restore", mock.Anything, mock.Anything, 1).Return(nil)
+				mockCacheRepo.On("Delete", mock.Anything, 1).Return(nil)
+				mockEventBus.On("Publish", mock.Anything, mock.MatchedBy(func(evt event.Event) bool {
+					return evt.EventName() == event.ExampleRestoredEventName
+				})).Return(nil)
+			},
+			exampleId: 1,
+		},
+		{
+			name: "Example is not deleted",
+			setupMocks: func() {
+				mockRepo.On("GetByIDWithDeleted", mock.Anything, mock.Anything, 2).Return(&model.Example{
+					Id:   2,
+					Name: "Test Example",
+				}, nil)
+			},
+			exampleId:   2,
+			wantErr:     true,
+			expectedErr: model.ErrExampleNotDeleted,
+		},
+		{
+			name: "Example does not exist",
+			setupMocks: func() {
+				mockRepo.On("GetByIDWithDeleted", mock.Anything, mock.Anything, 3).Return(nil, repo.ErrNotFound)
+			},
+			exampleId:   3,
+			wantErr:     true,
+			expectedErr: repo.ErrNotFound,
+		},
+		{
+			name: "Name taken while deleted",
+			setupMocks: func() {
+				mockRepo.On("GetByIDWithDeleted", mock.Anything, mock.Anything, 4).Return(&model.Example{
+					Id:        4,
+					Name:      "Test Example",
+					DeletedAt: &deletedAt,
+				}, nil)
+				mockRepo.On("FindByName", mock.Anything, mock.Anything, "Test Example").Return(&model.Example{Id: 8, Name: "test example"}, nil)
+			},
+			exampleId:   4,
+			wantErr:     true,
+			expectedErr: model.ErrExampleNameTaken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Set mock behavior
+			mockRepo.ExpectedCalls = nil
+			mockCacheRepo.ExpectedCalls = nil
+			mockEventBus.ExpectedCalls = nil
+			tc.setupMocks()
+
+			// Execute test
+			err := service.Restore(context.Background(), tc.exampleId)
+
+			// Verify results
+			if tc.wantErr {
+				assert.Error(t, err)
+				if tc.expectedErr != nil {
+					assert.ErrorIs(t, err, tc.expectedErr)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Verify Mock calls
+			mockRepo.AssertExpectations(t)
+			mockCacheRepo.AssertExpectations(t)
+			mockEventBus.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExampleService_Purge(t *testing.T) {
+	mockRepo := new(MockExampleRepo)
+	mockCacheRepo := new(MockExampleCacheRepo)
+	mockEventBus := new(MockEventBus)
+
+	// Create service instance
+	service := NewExampleService(mockRepo, mockCacheRepo)
+	service.EventBus = mockEventBus
+
+	deletedAt := time.Now()
+
+	testCases := []struct {
+		name       string
+		setupMocks func()
+		exampleId  int
+		silent     bool
+		wantErr    bool
+	}{
+		{
+			name: "Purge live example announces deletion",
+			setupMocks: func() {
+				mockRepo.On("GetByIDWithDeleted", mock.Anything, mock.Anything, 1).Return(&model.Example{Id: 1, Name: "Live"}, nil)
+				mockRepo.On("Purge", mock.Anything, mock.Anything, 1).Return(nil)
+				mockCacheRepo.On("Delete", mock.Anything, 1).Return(nil)
+				mockEventBus.On("Publish", mock.Anything, mock.MatchedBy(func(evt event.Event) bool {
+					return evt.EventName() == event.ExampleDeletedEventName
+				})).Return(nil).Once()
+			},
+			exampleId: 1,
+		},
+		{
+			name: "Purge soft deleted example is silent",
+			setupMocks: func() {
+				mockRepo.On("GetByIDWithDeleted", mock.Anything, mock.Anything, 2).Return(&model.Example{Id: 2, Name: "Gone", DeletedAt: &deletedAt}, nil)
+				mockRepo.On("Purge", mock.Anything, mock.Anything, 2).Return(nil)
+				mockCacheRepo.On("Delete", mock.Anything, 2).Return(nil)
+			},
+			exampleId: 2,
+			silent:    true,
+		},
+		{
+			name: "Purge storage error",
+			setupMocks: func() {
+				mockRepo.On("GetByIDWithDeleted", mock.Anything, mock.Anything, 3).Return(&model.Example{Id: 3, Name: "Live"}, nil)
+				mockRepo.On("Purge", mock.Anything, mock.Anything, 3).Return(errors.New("purge error"))
+			},
+			exampleId: 3,
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Set mock behavior
+			mockRepo.ExpectedCalls = nil
+			mockCacheRepo.ExpectedCalls = nil
+			mockEventBus.ExpectedCalls = nil
+			mockEventBus.Calls = nil
+			tc.setupMocks()
+
+			// Execute test
+			err := service.Purge(context.Background(), tc.exampleId)
+
+			// Verify results
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Verify Mock calls
+			mockRepo.AssertExpectations(t)
+			mockCacheRepo.AssertExpectations(t)
+			mockEventBus.AssertExpectations(t)
+			if tc.silent {
+				mockEventBus.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// laggingExampleRepo writes to the primary and serves reads from a replica that never catches up,
+// unless the context asks for primary reads
+type laggingExampleRepo struct {
+	repo.IExampleRepo
+	replica repo.IExampleRepo
+}
+
+func (r *laggingExampleRepo) reader(ctx context.Context) repo.IExampleRepo {
+	if repo.RequiresPrimaryRead(ctx) {
+		return r.IExampleRepo
+	}
+	return r.replica
+}
+
+func (r *laggingExampleRepo) GetByID(ctx context.Context, tr repo.Transaction, id int) (*model.Example, error) {
+	return r.reader(ctx).GetByID(ctx, tr, id)
+}
+
+func (r *laggingExampleRepo) GetByIDWithDeleted(ctx context.Context, tr repo.Transaction, id int) (*model.Example, error) {
+	return r.reader(ctx).GetByIDWithDeleted(ctx, tr, id)
+}
+
+func (r *laggingExampleRepo) FindByName(ctx context.Context, tr repo.Transaction, name string) (*model.Example, error) {
+	return r.reader(ctx).FindByName(ctx, tr, name)
+}
+
+// TestExampleService_WritesReadFromPrimary checks that writes never decide on what a lagging replica returns
+func TestExampleService_WritesReadFromPrimary(t *testing.T) {
+	ctx := context.Background()
+	examples := &laggingExampleRepo{IExampleRepo: memory.NewExampleRepo(memory.NewStore()), replica: memory.NewExampleRepo(memory.NewStore())}
+	service := NewExampleService(examples, nil)
+
+	created, err := service.Create(ctx, "lagging", "alias")
+	require.NoError(t, err)
+
+	// Plain reads go to the replica, which has not seen the example yet
+	_, err = service.Get(ctx, created.Id)
+	assert.ErrorIs(t, err, repo.ErrNotFound)
+
+	// The name check, the version read of the update and the lookups of the other writes see the primary
+	_, err = service.Create(ctx, "LAGGING", "")
+	assert.True(t, model.IsExampleNameTakenError(err))
+	require.NoError(t, service.Update(ctx, created.Id, "renamed", "alias", 0))
+	require.NoError(t, service.Delete(ctx, created.Id))
+	require.NoError(t, service.Restore(ctx, created.Id))
+	require.NoError(t, service.Purge(ctx, created.Id))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"go-hexagonal/domain/model"
+	"go-hexagonal/domain/repo"
 )
 
 // IExampleService defines the interface for example service
@@ -14,19 +15,40 @@ type IExampleService interface {
 	// Returns the created example or an error if validation or persistence fails
 	Create(ctx context.Context, name string, alias string) (*model.Example, error)
 
-	// Delete deletes an example by ID
+	// Delete soft deletes an example by ID
 	// Returns an error if the example doesn't exist or deletion fails
 	Delete(ctx context.Context, id int) error
 
-	// Update updates an example with the given ID, name and alias
+	// Restore restores a soft deleted example by ID
+	// Returns an error if the example doesn't exist or is not deleted
+	Restore(ctx context.Context, id int) error
+
+	// Purge permanently removes an example by ID, whether soft deleted or not
+	// Returns an error if the example doesn't exist or removal fails
+	Purge(ctx context.Context, id int) error
+
+	// Update updates an example with the given ID, name and alias. A positive expectedVersion is the
+	// version the client read, the update is rejected with ErrExampleModified if the example moved on since.
 	// Returns an error if the example doesn't exist, validation fails, or update fails
-	Update(ctx context.Context, id int, name string, alias string) error
+	Update(ctx context.Context, id int, name string, alias string, expectedVersion int) error
 
 	// Get retrieves an example by ID
 	// Returns the example or an error if not found
 	Get(ctx context.Context, id int) (*model.Example, error)
 
+	// GetWithDeleted retrieves an example by ID even if it is soft deleted
+	// Returns the example or an error if not found
+	GetWithDeleted(ctx context.Context, id int) (*model.Example, error)
+
 	// FindByName finds examples by name
 	// Returns the example or an error if not found
 	FindByName(ctx context.Context, name string) (*model.Example, error)
+
+	// List retrieves a page of examples matching the query
+	// Returns the examples on the page and the total number of matching examples
+	List(ctx context.Context, query repo.ExampleListQuery) ([]*model.Example, int64, error)
+
+	// ListByCursor retrieves a page of examples using keyset pagination
+	// Returns the page with cursors pointing at the neighbouring pages when they exist
+	ListByCursor(ctx context.Context, query repo.ExampleKeysetQuery) (*repo.ExampleCursorPage, error)
 }
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-hexagonal/domain/event"
+	"go-hexagonal/domain/repo"
+)
+
+// Inbox makes event consumers idempotent under at-least-once delivery: every event a handler
+// processed is recorded under the handler's name, and redeliveries of it are skipped.
+type Inbox struct {
+	store     repo.IInboxRepo
+	txFactory repo.TransactionFactory
+	storeType repo.StoreType
+}
+
+// NewInbox creates an inbox recording processed events in the store. With a transaction factory,
+// each event is handled in a transaction of storeType that also records it, so the handler's changes
+// and the record commit together; handlers join it through their context. Without one, e.g. for a
+// Redis inbox, the event is recorded after the handler succeeded.
+func NewInbox(store repo.IInboxRepo, txFactory repo.TransactionFactory, storeType repo.StoreType) *Inbox {
+	return &Inbox{
+		store:     store,
+		txFactory: txFactory,
+		storeType: storeType,
+	}
+}
+
+// Middleware returns the event middleware skipping events the wrapped handler already processed
+func (i *Inbox) Middleware() event.Middleware {
+	return func(next event.EventHandler) event.EventHandler {
+		return &inboxHandler{inbox: i, next: next}
+	}
+}
+
+// Handle runs the handler unless it already processed the event, then records the event.
+// A transaction carried by the context is joined and left to its owner; a duplicate record is
+// then returned, so the owner rolls back the handler's changes.
+func (i *Inbox) Handle(ctx context.Context, evt event.Event, handler event.EventHandler) error {
+	if tx, ok := repo.TransactionFromContext(ctx); ok {
+		_, err := i.handle(ctx, tx, evt, handler)
+		return err
+	}
+	if i.txFactory == nil {
+		_, err := i.handle(ctx, nil, evt, handler)
+		if errors.Is(err, repo.ErrInboxDuplicate) {
+			return nil
+		}
+		return err
+	}
+
+	tx, err := i.txFactory.NewTransaction(ctx, i.storeType, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create inbox transaction: %w", err)
+	}
+	if err := tx.Begin(); err != nil {
+		return fmt.Errorf("failed to begin inbox transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	ctx = repo.ContextWithTransaction(ctx, tx)
+	recorded, err := i.handle(ctx, tx, evt, handler)
+	if errors.Is(err, repo.ErrInboxDuplicate) {
+		// A concurrent delivery processed the event first, its changes are the ones to keep
+		return nil
+	}
+	if err != nil || !recorded {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit inbox transaction: %w", err)
+	}
+	committed = true
+
+	return nil
+}
+
+// handle checks the inbox, runs the handler and records the event within tr. It reports whether
+// the event was recorded, false when the handler already processed it.
+func (i *Inbox) handle(ctx context.Context, tr repo.Transaction, evt event.Event, handler event.EventHandler) (bool, error) {
+	consumer := event.HandlerName(handler)
+
+	processed, err := i.store.Processed(ctx, tr, consumer, evt.EventID())
+	if err != nil {
+		return false, fmt.Errorf("failed to check inbox for event %s: %w", evt.EventID(), err)
+	}
+	if processed {
+		return false, nil
+	}
+
+	if err := handler.HandleEvent(ctx, evt); err != nil {
+		return false, err
+	}
+
+	err = i.store.Save(ctx, tr, &repo.InboxMessage{
+		Consumer:  consumer,
+		EventID:   evt.EventID(),
+		EventName: evt.EventName(),
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrInboxDuplicate) {
+			return false, err
+		}
+		return false, fmt.Errorf("failed to record event %s in inbox: %w", evt.EventID(), err)
+	}
+
+	return true, nil
+}
+
+// inboxHandler applies the inbox to a handler, keeping its subscriptions and name
+type inboxHandler struct {
+	inbox *Inbox
+	next  event.EventHandler
+}
+
+// HandleEvent handles the event through the inbox
+func (h *inboxHandler) HandleEvent(ctx context.Context, evt event.Event) error {
+	return h.inbox.Handle(ctx, evt, h.next)
+}
+
+// InterestedIn delegates to the wrapped handler
+func (h *inboxHandler) InterestedIn(eventName string) bool {
+	return h.next.InterestedIn(eventName)
+}
+
+// HandlerName returns the name of the wrapped handler, which the inbox records events under
+func (h *inboxHandler) HandlerName() string {
+	return event.HandlerName(h.next)
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-hexagonal/domain/event"
+	"go-hexagonal/domain/repo"
+)
+
+// fakeInboxRepo keeps the inbox in memory, records saved within a transaction become visible on commit
+type fakeInboxRepo struct {
+	mu        sync.Mutex
+	committed map[string]*repo.InboxMessage
+	pending   map[*fakeTransaction][]*repo.InboxMessage
+	saveErr   error
+}
+
+func newFakeInboxRepo() *fakeInboxRepo {
+	return &fakeInboxRepo{
+		committed: make(map[string]*repo.InboxMessage),
+		pending:   make(map[*fakeTransaction][]*repo.InboxMessage),
+	}
+}
+
+func (r *fakeInboxRepo) Processed(_ context.Context, _ repo.Transaction, consumer, eventID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.committed[consumer+"/"+eventID]
+	return ok, nil
+}
+
+func (r *fakeInboxRepo) Save(_ context.Context, tr repo.Transaction, message *repo.InboxMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+	if _, ok := r.committed[message.Consumer+"/"+message.EventID]; ok {
+		return repo.ErrInboxDuplicate
+	}
+
+	if tx, ok := tr.(*fakeTransaction); ok && tx != nil {
+		tx.onCommit = func() { r.commit(tx) }
+		r.pending[tx] = append(r.pending[tx], message)
+		return nil
+	}
+	r.committed[message.Consumer+"/"+message.EventID] = message
+	return nil
+}
+
+func (r *fakeInboxRepo) DeleteBefore(_ context.Context, _ repo.Transaction, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeInboxRepo) commit(tx *fakeTransaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, message := range r.pending[tx] {
+		r.committed[message.Consumer+"/"+message.EventID] = message
+	}
+	delete(r.pending, tx)
+}
+
+// fakeTransaction counts commits and rollbacks
+type fakeTransaction struct {
+	*repo.BaseTransaction
+	commits   int
+	rollbacks int
+	onCommit  func()
+}
+
+func (tx *fakeTransaction) Commit() error {
+	tx.commits++
+	if tx.onCommit != nil {
+		tx.onCommit()
+	}
+	return nil
+}
+
+func (tx *fakeTransaction) Rollback() error {
+	tx.rollbacks++
+	return nil
+}
+
+// fakeTransactionFactory hands out fake transactions and keeps them for inspection
+type fakeTransactionFactory struct {
+	transactions []*fakeTransaction
+}
+
+func (f *fakeTransactionFactory) NewTransaction(ctx context.Context, store repo.StoreType, _ any) (repo.Transaction, error) {
+	tx := &fakeTransaction{BaseTransaction: repo.NewBaseTransaction(ctx, store, nil)}
+	f.transactions = append(f.transactions, tx)
+	return tx, nil
+}
+
+// countingHandler counts the events it handled and the transactions it saw
+type countingHandler struct {
+	calls   int
+	sawTx   bool
+	failErr error
+}
+
+func (h *countingHandler) HandleEvent(ctx context.Context, _ event.Event) error {
+	h.calls++
+	_, h.sawTx = repo.TransactionFromContext(ctx)
+	return h.failErr
+}
+
+func (h *countingHandler) InterestedIn(eventName string) bool {
+	return eventName == event.ExampleCreatedEventName
+}
+
+func (h *countingHandler) HandlerName() string {
+	return "counter"
+}
+
+func TestInbox_SkipsProcessedEvents(t *testing.T) {
+	store := newFakeInboxRepo()
+	factory := &fakeTransactionFactory{}
+	handler := &countingHandler{}
+	wrapped := event.Chain(handler, NewInbox(store, factory, repo.MySQLStore).Middleware())
+
+	evt := event.NewExampleCreatedEvent(1, "name", "alias")
+	require.NoError(t, wrapped.HandleEvent(context.Background(), evt))
+	require.NoError(t, wrapped.HandleEvent(context.Background(), evt))
+
+	assert.Equal(t, 1, handler.calls)
+	assert.True(t, handler.sawTx)
+	require.Len(t, factory.transactions, 2)
+	assert.Equal(t, 1, factory.transactions[0].commits)
+	// The redelivery only read the inbox, its transaction is rolled back
+	assert.Equal(t, 0, factory.transactions[1].commits)
+	assert.Equal(t, 1, factory.transactions[1].rollbacks)
+
+	processed, err := store.Processed(context.Background(), nil, "counter", evt.EventID())
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	// The wrapper keeps the handler's subscriptions and name
+	assert.True(t, wrapped.InterestedIn(event.ExampleCreatedEventName))
+	assert.False(t, wrapped.InterestedIn(event.ExampleDeletedEventName))
+	assert.Equal(t, "counter", event.HandlerName(wrapped))
+}
+
+func TestInbox_FailedHandlerIsNotRecorded(t *testing.T) {
+	store := newFakeInboxRepo()
+	factory := &fakeTransactionFactory{}
+	handler := &countingHandler{failErr: errors.New("boom")}
+	wrapped := NewInbox(store, factory, repo.MySQLStore).Middleware()(handler)
+
+	evt := event.NewExampleCreatedEvent(1, "name", "alias")
+	assert.EqualError(t, wrapped.HandleEvent(context.Background(), evt), "boom")
+	assert.Equal(t, 1, factory.transactions[0].rollbacks)
+	assert.Equal(t, 0, factory.transactions[0].commits)
+
+	// The redelivery is handled again
+	handler.failErr = nil
+	require.NoError(t, wrapped.HandleEvent(context.Background(), evt))
+	assert.Equal(t, 2, handler.calls)
+}
+
+func TestInbox_DuplicateRollsBack(t *testing.T) {
+	store := newFakeInboxRepo()
+	factory := &fakeTransactionFactory{}
+	handler := &countingHandler{}
+	wrapped := NewInbox(store, factory, repo.MySQLStore).Middleware()(handler)
+
+	// A concurrent delivery recorded the event between the check and the save
+	store.saveErr = repo.ErrInboxDuplicate
+
+	evt := event.NewExampleCreatedEvent(1, "name", "alias")
+	require.NoError(t, wrapped.HandleEvent(context.Background(), evt))
+	assert.Equal(t, 1, handler.calls)
+	assert.Equal(t, 0, factory.transactions[0].commits)
+	assert.Equal(t, 1, factory.transactions[0].rollbacks)
+}
+
+func TestInbox_JoinsContextTransaction(t *testing.T) {
+	store := newFakeInboxRepo()
+	factory := &fakeTransactionFactory{}
+	handler := &countingHandler{}
+	wrapped := NewInbox(store, factory, repo.MySQLStore).Middleware()(handler)
+
+	owner := &fakeTransaction{BaseTransaction: repo.NewBaseTransaction(context.Background(), repo.MySQLStore, nil)}
+	ctx := repo.ContextWithTransaction(context.Background(), owner)
+
+	evt := event.NewExampleCreatedEvent(1, "name", "alias")
+	require.NoError(t, wrapped.HandleEvent(ctx, evt))
+	assert.Empty(t, factory.transactions)
+	assert.Equal(t, 0, owner.commits)
+
+	// The record becomes visible with the owner's commit
+	processed, err := store.Processed(ctx, owner, "counter", evt.EventID())
+	require.NoError(t, err)
+	assert.False(t, processed)
+
+	require.NoError(t, owner.Commit())
+	processed, err = store.Processed(ctx, owner, "counter", evt.EventID())
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	// Within a joined transaction the duplicate is left to the owner
+	store.saveErr = repo.ErrInboxDuplicate
+	other := event.NewExampleCreatedEvent(2, "other", "alias")
+	assert.ErrorIs(t, wrapped.HandleEvent(ctx, other), repo.ErrInboxDuplicate)
+}
+
+func TestInbox_WithoutTransactions(t *testing.T) {
+	store := newFakeInboxRepo()
+	handler := &countingHandler{}
+	wrapped := NewInbox(store, nil, repo.RedisStore).Middleware()(handler)
+
+	evt := event.NewExampleCreatedEvent(1, "name", "alias")
+	require.NoError(t, wrapped.HandleEvent(context.Background(), evt))
+	require.NoError(t, wrapped.HandleEvent(context.Background(), evt))
+	assert.Equal(t, 1, handler.calls)
+	assert.False(t, handler.sawTx)
+
+	store.saveErr = repo.ErrInboxDuplicate
+	require.NoError(t, wrapped.HandleEvent(context.Background(), event.NewExampleCreatedEvent(2, "other", "alias")))
+}
@@ -113,6 +113,8 @@ func (h *ErrorHandler) HandleAPIError(ctx context.Context, err error, operation
 		return error_code.InvalidParams.WithMessage("Validation error: %s", err.Error())
 	case errors.IsNotFoundError(err):
 		return error_code.NotFound.WithMessage("Resource not found: %s", err.Error())
+	case errors.IsConflictError(err):
+		return error_code.Conflict.WithMessage("Conflict: %s", err.Error())
 	case errors.IsPersistenceError(err):
 		return error_code.ServerError.WithMessage("Database operation failed")
 	case errors.IsSystemError(err):
@@ -137,7 +139,7 @@ func (h *ErrorHandler) convertAppErrorToAPIError(appErr *errors.AppError) *error
 	case errors.ErrorTypeForbidden:
 		apiErr = error_code.UnauthorizedTokenError.WithMessage("Access forbidden")
 	case errors.ErrorTypeConflict:
-		apiErr = error_code.AccountExist.WithMessage("Conflict: %s", appErr.Message)
+		apiErr = error_code.Conflict.WithMessage("Conflict: %s", appErr.Message)
 	case errors.ErrorTypePersistence, errors.ErrorTypeSystem:
 		apiErr = error_code.ServerError.WithMessage("Server error: %s", appErr.Message)
 	case errors.ErrorTypeBusiness:
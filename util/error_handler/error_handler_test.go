@@ -211,6 +211,17 @@ func TestHandleAPIError_BusinessError(t *testing.T) {
 	assert.Equal(t, error_code.ServerError.Code, apiErr.Code)
 }
 
+func TestHandleAPIError_ConflictError(t *testing.T) {
+	handler := New()
+	ctx := context.Background()
+	err := fmt.Errorf("failed to update: %w", util_errors.New(util_errors.ErrorTypeConflict, "modified"))
+
+	apiErr := handler.HandleAPIError(ctx, err, "test-operation")
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, error_code.Conflict.Code, apiErr.Code)
+	assert.Equal(t, 409, apiErr.StatusCode())
+}
+
 func TestHandleAPIError_DefaultError(t *testing.T) {
 	handler := New()
 	ctx := context.Background()
@@ -152,6 +152,15 @@ func IsBusinessError(err error) bool {
 	return false
 }
 
+// IsConflictError checks if the error is a resource conflict error
+func IsConflictError(err error) bool {
+	var appErr *AppError
+	if stderrors.As(err, &appErr) {
+		return appErr.Type == ErrorTypeConflict
+	}
+	return false
+}
+
 // Wrap wraps a standard error as an application error
 func Wrap(err error, errType ErrorType, message string) *AppError {
 	return &AppError{
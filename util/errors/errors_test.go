@@ -2,6 +2,7 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -260,3 +261,10 @@ func TestIsBusinessError(t *testing.T) {
 	assert.True(t, IsBusinessError(err))
 	assert.False(t, IsBusinessError(NewValidationError("invalid", nil)))
 }
+
+func TestIsConflictError(t *testing.T) {
+	err := New(ErrorTypeConflict, "conflict")
+	assert.True(t, IsConflictError(err))
+	assert.True(t, IsConflictError(fmt.Errorf("wrapped: %w", err)))
+	assert.False(t, IsConflictError(NewValidationError("invalid", nil)))
+}
@@ -40,6 +40,9 @@ var (
 
 	// DomainEventTotal counts the total number of domain events
 	DomainEventTotal *prometheus.CounterVec
+
+	// EventQueueDepth tracks the number of events waiting in event bus queues
+	EventQueueDepth *prometheus.GaugeVec
 )
 
 // Initialized returns whether metrics has been initialized
@@ -128,6 +131,14 @@ func Init() {
 		[]string{"event_type", "source"},
 	)
 
+	EventQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "event_queue_depth",
+			Help: "Number of events waiting in event bus queues",
+		},
+		[]string{"bus", "queue"},
+	)
+
 	// Register all metrics
 	registry.MustRegister(
 		RequestDuration,
@@ -138,6 +149,7 @@ func Init() {
 		TransactionDuration,
 		TransactionTotal,
 		DomainEventTotal,
+		EventQueueDepth,
 	)
 
 	initialized = true
@@ -262,6 +274,14 @@ func RecordDomainEvent(eventType, source string) {
 	DomainEventTotal.WithLabelValues(eventType, source).Inc()
 }
 
+// RecordEventQueueDepth records the number of events waiting in an event bus queue
+func RecordEventQueueDepth(bus, queue string, depth int) {
+	if !initialized {
+		return
+	}
+	EventQueueDepth.WithLabelValues(bus, queue).Set(float64(depth))
+}
+
 // RecordError records an error
 func RecordError(errorType, source string) {
 	if !initialized {
@@ -51,6 +51,7 @@ func TestInit(t *testing.T) {
 	assert.NotNil(t, TransactionDuration)
 	assert.NotNil(t, TransactionTotal)
 	assert.NotNil(t, DomainEventTotal)
+	assert.NotNil(t, EventQueueDepth)
 }
 
 func TestInit_AlreadyInitialized(t *testing.T) {
@@ -306,6 +307,27 @@ func RecordTransactionMetrics(operation string, duration time.Duration) {
 	TransactionTotal.WithLabelValues(operation, "test-store").Inc()
 }
 
+func TestRecordEventQueueDepth(t *testing.T) {
+	ResetMetrics()
+
+	RecordEventQueueDepth("test-bus", "0", 3)
+
+	metrics, err := registry.Gather()
+	require.NoError(t, err)
+
+	var depth float64
+	found := false
+	for _, metric := range metrics {
+		if metric.GetName() == "event_queue_depth" {
+			found = true
+			depth = metric.GetMetric()[0].GetGauge().GetValue()
+			break
+		}
+	}
+	assert.True(t, found, "Event queue depth metric should be recorded")
+	assert.Equal(t, float64(3), depth)
+}
+
 func RecordDomainEventMetrics(eventType string) {
 	if !initialized {
 		Init()